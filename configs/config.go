@@ -1,18 +1,71 @@
 package configs
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Validade padrão dos tokens de autenticação
+const (
+	AccessTokenMinutosPadrao = 15 // Token de acesso enviado em cada requisição
+	RefreshTokenDiasPadrao   = 7  // Sessão sem uso; cada renovação estende o prazo
+)
+
+// Limites padrão da proteção do login contra tentativas repetidas
+const (
+	LoginMaxFalhasContaPadrao  = 5  // Falhas seguidas de uma conta até o bloqueio
+	LoginMaxFalhasIPPadrao     = 20 // Falhas de um IP, em qualquer conta, até o bloqueio
+	LoginBloqueioMinutosPadrao = 15 // Duração do bloqueio e janela em que as falhas são somadas
+)
+
+// Padrões da redefinição de senha por e-mail
+const (
+	RedefinicaoSenhaMinutosPadrao = 30       // Validade do link enviado por e-mail
+	MailOutboxDirPadrao           = "outbox" // Sem SMTP configurado, os e-mails são gravados nesta pasta
+)
+
+// Padrões da política de senhas
+const (
+	SenhaTamanhoMinimoPadrao  = 8 // Caracteres
+	SenhaClassesMinimasPadrao = 2 // Entre minúsculas, maiúsculas, números e símbolos
+	SenhaHistoricoPadrao      = 5 // Senhas anteriores que não podem ser reutilizadas
 )
 
 type Config struct {
+	DBDriver    string `mapstructure:"DB_DRIVER"` // mysql (padrão), postgres ou sqlite
 	DBHost      string `mapstructure:"DB_HOST"`
 	DBPort      string `mapstructure:"DB_PORT"`
 	DBUser      string `mapstructure:"DB_USER"`
 	DBPassword  string `mapstructure:"DB_PASSWORD"`
-	DBName      string `mapstructure:"DB_NAME"`
+	DBName      string `mapstructure:"DB_NAME"`    // No SQLite, o caminho do arquivo ou ":memory:"
+	DBSSLMode   string `mapstructure:"DB_SSLMODE"` // Apenas PostgreSQL; "disable" por padrão
 	ServerPort  string `mapstructure:"SERVER_PORT"`
 	JWTSecret   string `mapstructure:"JWT_SECRET"`
 	Environment string `mapstructure:"ENVIRONMENT"`
+
+	AccessTokenMinutos int `mapstructure:"ACCESS_TOKEN_MINUTOS"` // 15 por padrão
+	RefreshTokenDias   int `mapstructure:"REFRESH_TOKEN_DIAS"`   // 7 por padrão
+
+	LoginMaxFalhasConta  int `mapstructure:"LOGIN_MAX_FALHAS_CONTA"` // 5 por padrão
+	LoginMaxFalhasIP     int `mapstructure:"LOGIN_MAX_FALHAS_IP"`    // 20 por padrão
+	LoginBloqueioMinutos int `mapstructure:"LOGIN_BLOQUEIO_MINUTOS"` // 15 por padrão
+
+	SMTPHost                string `mapstructure:"SMTP_HOST"` // Vazio grava os e-mails em MAIL_OUTBOX_DIR em vez de enviá-los
+	SMTPPort                string `mapstructure:"SMTP_PORT"` // 587 por padrão
+	SMTPUser                string `mapstructure:"SMTP_USER"`
+	SMTPPassword            string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom                string `mapstructure:"SMTP_FROM"`
+	MailOutboxDir           string `mapstructure:"MAIL_OUTBOX_DIR"`
+	AppURL                  string `mapstructure:"APP_URL"`                   // Endereço do frontend usado nos links dos e-mails
+	RedefinicaoSenhaMinutos int    `mapstructure:"REDEFINICAO_SENHA_MINUTOS"` // 30 por padrão
+
+	SenhaTamanhoMinimo  int    `mapstructure:"SENHA_TAMANHO_MINIMO"`  // 8 por padrão
+	SenhaClassesMinimas int    `mapstructure:"SENHA_CLASSES_MINIMAS"` // 2 por padrão; de 1 a 4
+	SenhaHistorico      int    `mapstructure:"SENHA_HISTORICO"`       // 5 por padrão; -1 permite reutilizar
+	SenhaListaBloqueada string `mapstructure:"SENHA_LISTA_BLOQUEADA"` // Arquivo opcional com senhas proibidas, uma por linha
+	BcryptCusto         int    `mapstructure:"BCRYPT_CUSTO"`          // Custo do hash das senhas; aumentá-lo recriptografa a senha no próximo login
 }
 
 // LoadConfig carrega configurações do arquivo .env padrão
@@ -36,6 +89,96 @@ func LoadConfig() (config Config, err error) {
 	return
 }
 
+// ValidadeTokenAcesso retorna por quanto tempo o token de acesso é aceito
+func (c Config) ValidadeTokenAcesso() time.Duration {
+	minutos := c.AccessTokenMinutos
+	if minutos <= 0 {
+		minutos = AccessTokenMinutosPadrao
+	}
+	return time.Duration(minutos) * time.Minute
+}
+
+// ValidadeSessao retorna por quanto tempo uma sessão sem uso pode ser renovada
+func (c Config) ValidadeSessao() time.Duration {
+	dias := c.RefreshTokenDias
+	if dias <= 0 {
+		dias = RefreshTokenDiasPadrao
+	}
+	return time.Duration(dias) * 24 * time.Hour
+}
+
+// LimiteFalhasConta retorna quantas falhas seguidas bloqueiam o login de uma conta
+func (c Config) LimiteFalhasConta() int {
+	if c.LoginMaxFalhasConta <= 0 {
+		return LoginMaxFalhasContaPadrao
+	}
+	return c.LoginMaxFalhasConta
+}
+
+// LimiteFalhasIP retorna quantas falhas bloqueiam o login a partir de um IP
+func (c Config) LimiteFalhasIP() int {
+	if c.LoginMaxFalhasIP <= 0 {
+		return LoginMaxFalhasIPPadrao
+	}
+	return c.LoginMaxFalhasIP
+}
+
+// DuracaoBloqueioLogin retorna por quanto tempo o login fica bloqueado após atingir o limite de falhas
+func (c Config) DuracaoBloqueioLogin() time.Duration {
+	minutos := c.LoginBloqueioMinutos
+	if minutos <= 0 {
+		minutos = LoginBloqueioMinutosPadrao
+	}
+	return time.Duration(minutos) * time.Minute
+}
+
+// ValidadeRedefinicaoSenha retorna por quanto tempo o link de redefinição de senha é aceito
+func (c Config) ValidadeRedefinicaoSenha() time.Duration {
+	minutos := c.RedefinicaoSenhaMinutos
+	if minutos <= 0 {
+		minutos = RedefinicaoSenhaMinutosPadrao
+	}
+	return time.Duration(minutos) * time.Minute
+}
+
+// TamanhoMinimoSenha retorna quantos caracteres uma senha nova precisa ter
+func (c Config) TamanhoMinimoSenha() int {
+	if c.SenhaTamanhoMinimo <= 0 {
+		return SenhaTamanhoMinimoPadrao
+	}
+	return c.SenhaTamanhoMinimo
+}
+
+// ClassesMinimasSenha retorna quantos tipos de caractere uma senha nova precisa combinar
+func (c Config) ClassesMinimasSenha() int {
+	if c.SenhaClassesMinimas <= 0 {
+		return SenhaClassesMinimasPadrao
+	}
+	if c.SenhaClassesMinimas > 4 {
+		return 4
+	}
+	return c.SenhaClassesMinimas
+}
+
+// HistoricoSenhas retorna quantas senhas anteriores não podem ser reutilizadas (zero desliga a verificação)
+func (c Config) HistoricoSenhas() int {
+	if c.SenhaHistorico < 0 {
+		return 0
+	}
+	if c.SenhaHistorico == 0 {
+		return SenhaHistoricoPadrao
+	}
+	return c.SenhaHistorico
+}
+
+// CustoBcrypt retorna o custo do hash das senhas, dentro dos limites aceitos pelo bcrypt
+func (c Config) CustoBcrypt() int {
+	if c.BcryptCusto < bcrypt.MinCost || c.BcryptCusto > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+	return c.BcryptCusto
+}
+
 // LoadTestConfig carrega configurações do arquivo test.env
 func LoadTestConfig() error {
 	viper.SetConfigFile("test.env")
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// AgendaController gerencia as requisições HTTP da configuração da agenda: boxes, feriados e expediente
+type AgendaController struct {
+	agendaService services.AgendaService
+}
+
+// NewAgendaController cria uma nova instância do controlador da configuração da agenda
+func NewAgendaController(agendaService services.AgendaService) *AgendaController {
+	return &AgendaController{
+		agendaService: agendaService,
+	}
+}
+
+// BuscarBoxes retorna os boxes da oficina
+// Aceita o parâmetro de consulta "ativos=true" para listar apenas os disponíveis para agendamento
+func (c *AgendaController) BuscarBoxes(ctx *gin.Context) {
+	boxes, err := c.agendaService.BuscarBoxes(ctx.Query("ativos") == "true")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar boxes"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, boxes)
+}
+
+// CriarBox cadastra um novo box
+func (c *AgendaController) CriarBox(ctx *gin.Context) {
+	var box models.Box
+	if err := ctx.ShouldBindJSON(&box); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	boxCriado, err := c.agendaService.CriarBox(&box)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, boxCriado)
+}
+
+// AtualizarBox modifica os dados de um box
+func (c *AgendaController) AtualizarBox(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var box models.Box
+	if err := ctx.ShouldBindJSON(&box); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	box.ID = uint(id)
+
+	boxAtualizado, err := c.agendaService.AtualizarBox(&box)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, boxAtualizado)
+}
+
+// DeletarBox remove um box
+func (c *AgendaController) DeletarBox(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.agendaService.DeletarBox(uint(id)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// BuscarFeriados retorna os feriados cadastrados
+// Aceita o parâmetro de consulta "ano" para filtrar os resultados
+func (c *AgendaController) BuscarFeriados(ctx *gin.Context) {
+	ano := 0
+	if valor := ctx.Query("ano"); valor != "" {
+		var err error
+		if ano, err = strconv.Atoi(valor); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Ano inválido"})
+			return
+		}
+	}
+
+	feriados, err := c.agendaService.BuscarFeriados(ano)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar feriados"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, feriados)
+}
+
+// CriarFeriado cadastra um dia sem expediente
+// Corpo: {"data": "2026-12-25", "descricao": "Natal"}
+func (c *AgendaController) CriarFeriado(ctx *gin.Context) {
+	var dados struct {
+		Data      string `json:"data" binding:"required"`
+		Descricao string `json:"descricao" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	data, err := time.ParseInLocation("2006-01-02", dados.Data, time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida. Use o formato AAAA-MM-DD"})
+		return
+	}
+
+	feriado, err := c.agendaService.CriarFeriado(&models.Feriado{Data: data, Descricao: dados.Descricao})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, feriado)
+}
+
+// DeletarFeriado remove um feriado
+func (c *AgendaController) DeletarFeriado(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.agendaService.DeletarFeriado(uint(id)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// BuscarExpediente retorna o horário de funcionamento da oficina
+func (c *AgendaController) BuscarExpediente(ctx *gin.Context) {
+	config, err := c.agendaService.BuscarConfig()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, config)
+}
+
+// SalvarExpediente altera o horário de funcionamento da oficina
+func (c *AgendaController) SalvarExpediente(ctx *gin.Context) {
+	var config models.AgendaConfig
+	if err := ctx.ShouldBindJSON(&config); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	salvo, err := c.agendaService.SalvarConfig(&config)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, salvo)
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/services"
+)
+
+// AgendamentoController gerencia as requisições HTTP relacionadas à agenda de recebimento de veículos
+type AgendamentoController struct {
+	agendamentoService services.AgendamentoService
+}
+
+// NewAgendamentoController cria uma nova instância do controlador de agendamentos
+func NewAgendamentoController(agendamentoService services.AgendamentoService) *AgendamentoController {
+	return &AgendamentoController{
+		agendamentoService: agendamentoService,
+	}
+}
+
+// BuscarTodos retorna os agendamentos
+// Aceita os parâmetros de consulta "dataInicio" e "dataFim" (AAAA-MM-DD), "status", "boxId" e "funcionarioId"
+func (c *AgendamentoController) BuscarTodos(ctx *gin.Context) {
+	var filtro repositories.FiltroAgendamento
+
+	if dataInicio := ctx.Query("dataInicio"); dataInicio != "" {
+		data, err := time.ParseInLocation("2006-01-02", dataInicio, time.Local)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data de início inválida. Use o formato AAAA-MM-DD"})
+			return
+		}
+		filtro.Inicio = data
+	}
+	if dataFim := ctx.Query("dataFim"); dataFim != "" {
+		data, err := time.ParseInLocation("2006-01-02", dataFim, time.Local)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data de fim inválida. Use o formato AAAA-MM-DD"})
+			return
+		}
+		filtro.Fim = data.AddDate(0, 0, 1) // Inclui o dia inteiro
+	}
+	if boxID := ctx.Query("boxId"); boxID != "" {
+		id, err := strconv.Atoi(boxID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do box inválido"})
+			return
+		}
+		valor := uint(id)
+		filtro.BoxID = &valor
+	}
+	if funcionarioID := ctx.Query("funcionarioId"); funcionarioID != "" {
+		id, err := strconv.Atoi(funcionarioID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do funcionário inválido"})
+			return
+		}
+		valor := uint(id)
+		filtro.FuncionarioID = &valor
+	}
+	filtro.Status = ctx.Query("status")
+
+	agendamentos, err := c.agendamentoService.BuscarTodos(filtro)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agendamentos)
+}
+
+// BuscarPorID retorna um agendamento específico pelo ID
+func (c *AgendamentoController) BuscarPorID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	agendamento, err := c.agendamentoService.BuscarPorID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Agendamento não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agendamento)
+}
+
+// HorariosDisponiveis retorna os horários livres de um dia com os boxes e mecânicos disponíveis
+// Parâmetros de consulta: "data" (AAAA-MM-DD, obrigatório) e "duracao" em minutos (padrão do expediente)
+func (c *AgendamentoController) HorariosDisponiveis(ctx *gin.Context) {
+	data, err := time.ParseInLocation("2006-01-02", ctx.Query("data"), time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida. Use o formato AAAA-MM-DD"})
+		return
+	}
+
+	duracao := 0
+	if valor := ctx.Query("duracao"); valor != "" {
+		if duracao, err = strconv.Atoi(valor); err != nil || duracao <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Duração inválida"})
+			return
+		}
+	}
+
+	horarios, err := c.agendamentoService.HorariosDisponiveis(data, duracao)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, horarios)
+}
+
+// Criar agenda o recebimento de um veículo
+func (c *AgendamentoController) Criar(ctx *gin.Context) {
+	var agendamento models.Agendamento
+	if err := ctx.ShouldBindJSON(&agendamento); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	agendamentoCriado, err := c.agendamentoService.Criar(&agendamento, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, agendamentoCriado)
+}
+
+// Reagendar altera horário, box, mecânico ou descrição de um agendamento
+func (c *AgendamentoController) Reagendar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var agendamento models.Agendamento
+	if err := ctx.ShouldBindJSON(&agendamento); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	agendamento.ID = uint(id)
+
+	agendamentoAtualizado, err := c.agendamentoService.Reagendar(&agendamento)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agendamentoAtualizado)
+}
+
+// AtualizarStatus confirma, cancela ou registra a falta do cliente
+// Corpo: {"status": "cancelado", "motivo": "..."}
+func (c *AgendamentoController) AtualizarStatus(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Status string `json:"status" binding:"required"`
+		Motivo string `json:"motivo"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	agendamento, err := c.agendamentoService.AtualizarStatus(uint(id), dados.Status, dados.Motivo)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agendamento)
+}
+
+// CheckIn recebe o veículo agendado e retorna a ordem de serviço aberta
+func (c *AgendamentoController) CheckIn(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// O km do hodômetro na recepção é opcional
+	var dados struct {
+		KmEntrada int `json:"kmEntrada" binding:"min=0"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&dados); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	os, err := c.agendamentoService.CheckIn(uint(id), dados.KmEntrada, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, os)
+}
+
+// LinkFeed gera o endereço do feed iCalendar do mecânico, para assinatura em aplicativos de calendário.
+// Cada chamada sorteia um novo token e invalida o link anterior, que só é exibido nesta resposta
+func (c *AgendamentoController) LinkFeed(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	token, err := c.agendamentoService.GerarTokenFeed(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"url": fmt.Sprintf("/api/agenda/feed/%d/agenda.ics?token=%s", id, token),
+	})
+}
+
+// RevogarLinkFeed desativa o link do feed iCalendar do mecânico
+func (c *AgendamentoController) RevogarLinkFeed(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.agendamentoService.RevogarTokenFeed(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// FeedICS retorna a agenda do mecânico no formato iCalendar
+// Rota pública: o acesso é validado pelo token gerado em LinkFeed
+func (c *AgendamentoController) FeedICS(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if !c.agendamentoService.ValidarTokenFeed(uint(id), ctx.Query("token")) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		return
+	}
+
+	calendario, err := c.agendamentoService.GerarICS(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", `inline; filename="agenda.ics"`)
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", calendario)
+}
@@ -1,13 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 
+	"OficinaMecanica/middlewares"
 	"OficinaMecanica/models"
 	"OficinaMecanica/services"
 	"OficinaMecanica/utils"
@@ -20,12 +24,16 @@ type RegisterRequest struct {
 }
 
 type AuthController struct {
-	usuarioService services.UsuarioService
+	usuarioService       services.UsuarioService
+	sessaoService        services.SessaoService
+	protecaoLoginService services.ProtecaoLoginService
 }
 
-func NewAuthController(service services.UsuarioService) *AuthController {
+func NewAuthController(service services.UsuarioService, sessaoService services.SessaoService, protecaoLoginService services.ProtecaoLoginService) *AuthController {
 	return &AuthController{
-		usuarioService: service,
+		usuarioService:       service,
+		sessaoService:        sessaoService,
+		protecaoLoginService: protecaoLoginService,
 	}
 }
 
@@ -35,7 +43,7 @@ func (c *AuthController) SetUsuarioService(service services.UsuarioService) {
 	c.usuarioService = service
 }
 
-// Login autentica um usuário e retorna um token JWT
+// Login autentica um usuário e abre uma sessão, retornando o token de acesso e o de renovação
 func (c *AuthController) Login(ctx *gin.Context) {
 	var loginRequest struct {
 		Email string `json:"email" binding:"required,email"`
@@ -48,9 +56,32 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
+	// Contas e IPs com falhas recentes esperam antes de uma nova tentativa
+	ip, userAgent := ctx.ClientIP(), ctx.Request.UserAgent()
+	espera, err := c.protecaoLoginService.Verificar(loginRequest.Email, ip)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if espera > 0 {
+		// A recusa entra no histórico do usuário, se o e-mail tiver cadastro, sem conferir a senha
+		var usuarioID *uint
+		if usuario, err := c.usuarioService.BuscarPorEmail(loginRequest.Email); err == nil {
+			usuarioID = &usuario.ID
+		}
+		c.registrarTentativa(loginRequest.Email, ip, userAgent, usuarioID, models.MotivoAcessoBloqueado)
+		segundos := int(math.Ceil(espera.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(segundos))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{
+			"error": fmt.Sprintf("Muitas tentativas de login; tente novamente em %d segundos", segundos),
+		})
+		return
+	}
+
 	// Busca o usuário pelo email
 	usuario, err := c.usuarioService.BuscarPorEmail(loginRequest.Email)
 	if err != nil {
+		c.registrarTentativa(loginRequest.Email, ip, userAgent, nil, models.MotivoAcessoCredenciaisInvalidas)
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais inválidas"})
 		return
 	}
@@ -58,22 +89,40 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	// Verifica se a senha está correta
 	err = bcrypt.CompareHashAndPassword([]byte(usuario.Senha), []byte(loginRequest.Senha))
 	if err != nil {
+		c.registrarTentativa(loginRequest.Email, ip, userAgent, &usuario.ID, models.MotivoAcessoCredenciaisInvalidas)
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais inválidas"})
 		return
 	}
 
-	// Gera o token JWT
-	token, err := utils.GerarToken(*usuario)
+	// Contas desativadas, excluídas ou desligadas não entram, mesmo com a senha correta
+	if !usuario.PodeAcessar() {
+		c.registrarTentativa(loginRequest.Email, ip, userAgent, &usuario.ID, models.MotivoAcessoUsuarioInativo)
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Usuário inativo"})
+		return
+	}
+
+	// Hashes gerados com um custo bcrypt menor que o configurado são refeitos com a senha recém-conferida
+	if usuario.PrecisaNovoHash() {
+		if err := c.usuarioService.RecriptografarSenha(usuario.ID, loginRequest.Senha); err != nil {
+			log.Printf("Erro ao recriptografar a senha do usuário %d: %v", usuario.ID, err)
+		}
+	}
+
+	// Abre a sessão e gera os tokens
+	tokens, err := c.sessaoService.Iniciar(usuario, ip, userAgent)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
 		return
 	}
+	c.registrarTentativa(loginRequest.Email, ip, userAgent, &usuario.ID, "")
 
 	// Atualiza o timestamp de último login
 	go c.usuarioService.AtualizarUltimoLogin(usuario.ID)
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"token": token,
+		"token":        tokens.Token,
+		"expiraEm":     tokens.ExpiraEm,
+		"refreshToken": tokens.TokenRenovacao,
 		"user": gin.H{
 			"id":    usuario.ID,
 			"nome":  usuario.Nome,
@@ -83,6 +132,22 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	})
 }
 
+// registrarTentativa grava o resultado do login; sem motivo, a tentativa é um sucesso.
+// Uma falha ao gravar não impede a resposta, mas fica no log
+func (c *AuthController) registrarTentativa(email, ip, userAgent string, usuarioID *uint, motivo string) {
+	err := c.protecaoLoginService.Registrar(&models.TentativaAcesso{
+		UsuarioID: usuarioID,
+		Email:     email,
+		IP:        ip,
+		UserAgent: userAgent,
+		Sucesso:   motivo == "",
+		Motivo:    motivo,
+	})
+	if err != nil {
+		log.Printf("Erro ao registrar a tentativa de login de %s: %v", email, err)
+	}
+}
+
 func (c *AuthController) Register(ctx *gin.Context) {
 	var req RegisterRequest
 
@@ -104,25 +169,32 @@ func (c *AuthController) Register(ctx *gin.Context) {
 	usuario := models.Usuario{
 		Nome:  req.Nome,
 		Email: req.Email,
-		Senha: req.Senha, // O hash será gerado pelo hook BeforeCreate
+		Senha: req.Senha, // O serviço valida a senha e grava apenas o hash
 	}
 
 	// Salvar usuário
 	usuarioCriado, err := c.usuarioService.Criar(&usuario)
+	var erroSenha *utils.ErroPoliticaSenha
+	if errors.As(err, &erroSenha) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": erroSenha.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar usuário"})
 		return
 	}
 
-	// Gerar token
-	token, err := utils.GerarToken(*usuarioCriado)
+	// Abrir a sessão e gerar os tokens
+	tokens, err := c.sessaoService.Iniciar(usuarioCriado, ctx.ClientIP(), ctx.Request.UserAgent())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, gin.H{
-		"token": token,
+		"token":        tokens.Token,
+		"expiraEm":     tokens.ExpiraEm,
+		"refreshToken": tokens.TokenRenovacao,
 		"usuario": gin.H{
 			"id":    usuarioCriado.ID,
 			"nome":  usuarioCriado.Nome,
@@ -132,3 +204,149 @@ func (c *AuthController) Register(ctx *gin.Context) {
 	})
 	log.Printf("usuarioService: %+v", c.usuarioService)
 }
+
+// Renovar troca o token de renovação por um novo par de tokens; o token enviado deixa de valer
+func (c *AuthController) Renovar(ctx *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+		return
+	}
+
+	tokens, err := c.sessaoService.Renovar(req.RefreshToken, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout encerra a sessão do token usado na requisição
+func (c *AuthController) Logout(ctx *gin.Context) {
+	chave, ok := middlewares.SessaoDoContexto(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Sessão não identificada"})
+		return
+	}
+
+	if err := c.sessaoService.Encerrar(chave); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// LogoutTodas encerra todas as sessões do usuário autenticado, inclusive a atual
+func (c *AuthController) LogoutTodas(ctx *gin.Context) {
+	usuarioID, ok := middlewares.UsuarioIDDoContexto(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	if err := c.sessaoService.EncerrarTodas(usuarioID, models.MotivoSessaoLogoutGeral); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// MinhasSessoes lista as sessões abertas do usuário autenticado
+func (c *AuthController) MinhasSessoes(ctx *gin.Context) {
+	usuarioID, ok := middlewares.UsuarioIDDoContexto(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	sessoes, err := c.sessaoService.BuscarAtivas(usuarioID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	atual, _ := middlewares.SessaoDoContexto(ctx)
+	resposta := make([]gin.H, 0, len(sessoes))
+	for _, sessao := range sessoes {
+		resposta = append(resposta, gin.H{
+			"id":        sessao.ID,
+			"ip":        sessao.IP,
+			"userAgent": sessao.UserAgent,
+			"criadoEm":  sessao.CreatedAt,
+			"expiraEm":  sessao.ExpiraEm,
+			"atual":     sessao.Chave == atual,
+		})
+	}
+	ctx.JSON(http.StatusOK, resposta)
+}
+
+// EncerrarSessoesDoUsuario encerra todas as sessões de outro usuário (ex.: desligamento de um funcionário)
+func (c *AuthController) EncerrarSessoesDoUsuario(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if _, err := c.usuarioService.BuscarPorID(uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	if err := c.sessaoService.EncerrarTodas(uint(id), models.MotivoSessaoLogoutGeral); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Desbloquear libera o login de um usuário bloqueado por tentativas com a senha errada
+func (c *AuthController) Desbloquear(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.protecaoLoginService.Desbloquear(uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Acessos lista o histórico de logins de um usuário para auditoria.
+// Aceita a paginação das listagens e os filtros sucesso, ip, motivo, dataInicio e dataFim
+func (c *AuthController) Acessos(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	consulta, err := lerConsulta(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := c.usuarioService.BuscarPorID(uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	acessos, err := c.protecaoLoginService.BuscarAcessos(uint(id), consulta)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, acessos)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/middlewares"
+	"OficinaMecanica/services"
+)
+
+// BuscaController gerencia a busca textual do balcão
+type BuscaController struct {
+	buscaService services.BuscaService
+}
+
+// NewBuscaController cria uma nova instância do controlador de busca
+func NewBuscaController(buscaService services.BuscaService) *BuscaController {
+	return &BuscaController{
+		buscaService: buscaService,
+	}
+}
+
+// Buscar procura o texto em clientes, veículos e ordens de serviço
+// Parâmetros de consulta: "q" (ex.: "gol prata joão") e "limite" por grupo (padrão 10, máximo 50)
+func (c *BuscaController) Buscar(ctx *gin.Context) {
+	usuarioID, ok := middlewares.UsuarioIDDoContexto(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	limite := 0
+	if valor := ctx.Query("limite"); valor != "" {
+		convertido, err := strconv.Atoi(valor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Limite inválido"})
+			return
+		}
+		limite = convertido
+	}
+
+	resultado, err := c.buscaService.Buscar(ctx.Query("q"), limite, usuarioID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resultado)
+}
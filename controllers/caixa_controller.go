@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// CaixaController gerencia as requisições HTTP relacionadas ao caixa da recepção
+type CaixaController struct {
+	caixaService services.CaixaService
+}
+
+// NewCaixaController cria uma nova instância do controlador de caixa
+func NewCaixaController(caixaService services.CaixaService) *CaixaController {
+	return &CaixaController{
+		caixaService: caixaService,
+	}
+}
+
+// BuscarTodos retorna os caixas
+// Aceita o parâmetro de consulta "status" (aberto ou fechado)
+func (c *CaixaController) BuscarTodos(ctx *gin.Context) {
+	caixas, err := c.caixaService.BuscarTodos(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, caixas)
+}
+
+// BuscarAtual retorna o caixa aberto do usuário autenticado com o resumo parcial
+func (c *CaixaController) BuscarAtual(ctx *gin.Context) {
+	relatorio, err := c.caixaService.BuscarAtual(usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, relatorio)
+}
+
+// BuscarRelatorio retorna o relatório de conferência de um caixa
+func (c *CaixaController) BuscarRelatorio(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	relatorio, err := c.caixaService.BuscarRelatorio(uint(id), usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, relatorio)
+}
+
+// Abrir abre o caixa do usuário autenticado
+// Corpo: {"valorInicial": 100.00, "observacoes": "..."}
+func (c *CaixaController) Abrir(ctx *gin.Context) {
+	var dados struct {
+		ValorInicial float64 `json:"valorInicial"`
+		Observacoes  string  `json:"observacoes"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	caixa, err := c.caixaService.Abrir(dados.ValorInicial, dados.Observacoes, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, caixa)
+}
+
+// RegistrarSangria retira dinheiro do caixa
+// Corpo: {"valor": 50.00, "descricao": "..."}
+func (c *CaixaController) RegistrarSangria(ctx *gin.Context) {
+	c.movimentar(ctx, c.caixaService.RegistrarSangria)
+}
+
+// RegistrarSuprimento adiciona troco ao caixa
+// Corpo: {"valor": 50.00, "descricao": "..."}
+func (c *CaixaController) RegistrarSuprimento(ctx *gin.Context) {
+	c.movimentar(ctx, c.caixaService.RegistrarSuprimento)
+}
+
+// Fechar encerra o caixa com a contagem por forma de pagamento e retorna o relatório de diferenças
+// Corpo: {"contagens": [{"forma": "dinheiro", "valorContado": 350.00}], "observacoes": "..."}
+func (c *CaixaController) Fechar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Contagens   []services.ContagemCaixa `json:"contagens" binding:"dive"`
+		Observacoes string                   `json:"observacoes"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	relatorio, err := c.caixaService.Fechar(uint(id), dados.Contagens, dados.Observacoes, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, relatorio)
+}
+
+// movimentar trata as requisições de sangria e suprimento, que têm o mesmo formato
+func (c *CaixaController) movimentar(ctx *gin.Context, registrar func(uint, float64, string, *uint) (*models.MovimentacaoCaixa, error)) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Valor     float64 `json:"valor" binding:"required,gt=0"`
+		Descricao string  `json:"descricao"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	movimentacao, err := registrar(uint(id), dados.Valor, dados.Descricao, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, movimentacao)
+}
@@ -25,18 +25,25 @@ func NewClienteController(clienteService services.ClienteService) *ClienteContro
 	}
 }
 
-// BuscarTodos retorna todos os clientes cadastrados
-// Este endpoint não recebe parâmetros e retorna um array com todos os clientes
+// BuscarTodos retorna uma página dos clientes cadastrados
+// Aceita page, pageSize, sort e os filtros nome, email, telefone, documento e tipoPessoa
 func (c *ClienteController) BuscarTodos(ctx *gin.Context) {
-	// Solicita ao serviço que busque todos os clientes
-	clientes, err := c.clienteService.BuscarTodos()
+	// Lê a paginação, a ordenação e os filtros da URL
+	consulta, err := lerConsulta(ctx)
 	if err != nil {
-		// Em caso de erro, retorna status 500 (erro interno)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Solicita ao serviço que busque a página de clientes
+	clientes, err := c.clienteService.BuscarTodos(consulta)
+	if err != nil {
+		// Filtro ou ordenação não suportados são erros do cliente da API
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Retorna a lista de clientes com status 200 (OK)
+	// Retorna a página de clientes com status 200 (OK)
 	ctx.JSON(http.StatusOK, clientes)
 }
 
@@ -162,3 +169,17 @@ func (c *ClienteController) BuscarComVeiculos(ctx *gin.Context) {
 	// Retorna o cliente com seus veículos
 	ctx.JSON(http.StatusOK, clienteDTO)
 }
+
+// BuscarPorDocumento retorna o cliente com o CPF ou CNPJ informado
+// O documento pode vir só com números ou formatado, desde que sem a barra do CNPJ
+func (c *ClienteController) BuscarPorDocumento(ctx *gin.Context) {
+	// Busca o cliente pelo documento usando o serviço
+	cliente, err := c.clienteService.BuscarPorDocumento(ctx.Param("doc"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Retorna os detalhes do cliente com status 200 (OK)
+	ctx.JSON(http.StatusOK, cliente)
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/repositories"
+)
+
+// parametrosPaginacao são os parâmetros de consulta que não são tratados como filtro
+var parametrosPaginacao = map[string]bool{
+	"page":     true,
+	"pageSize": true,
+	"sort":     true,
+	"cursor":   true,
+}
+
+// lerConsulta monta a consulta de uma listagem a partir dos parâmetros da URL:
+// page, pageSize, sort ("nome,-criadoEm") e cursor; os demais parâmetros viram filtros por campo
+func lerConsulta(ctx *gin.Context) (repositories.Consulta, error) {
+	consulta := repositories.Consulta{
+		Ordenacao: ctx.Query("sort"),
+		Filtros:   make(map[string]string),
+	}
+
+	if valor := ctx.Query("page"); valor != "" {
+		pagina, err := strconv.Atoi(valor)
+		if err != nil || pagina < 1 {
+			return consulta, errors.New("page deve ser um número maior que zero")
+		}
+		consulta.Pagina = pagina
+	}
+
+	if valor := ctx.Query("pageSize"); valor != "" {
+		tamanho, err := strconv.Atoi(valor)
+		if err != nil || tamanho < 1 || tamanho > repositories.TamanhoPaginaMaximo {
+			return consulta, errors.New("pageSize deve ser um número entre 1 e " + strconv.Itoa(repositories.TamanhoPaginaMaximo))
+		}
+		consulta.TamanhoPagina = tamanho
+	}
+
+	// "cursor" presente, mesmo vazio, ativa a paginação por cursor nas listagens que a suportam
+	consulta.Cursor, consulta.UsarCursor = ctx.GetQuery("cursor")
+
+	for nome, valores := range ctx.Request.URL.Query() {
+		if parametrosPaginacao[nome] || len(valores) == 0 {
+			continue
+		}
+		consulta.Filtros[nome] = valores[0]
+	}
+
+	return consulta, nil
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/services"
+)
+
+// DocumentoController gerencia a emissão dos documentos impressos
+type DocumentoController struct {
+	documentoService services.DocumentoService
+}
+
+// NewDocumentoController cria uma nova instância do controlador de documentos
+func NewDocumentoController(documentoService services.DocumentoService) *DocumentoController {
+	return &DocumentoController{
+		documentoService: documentoService,
+	}
+}
+
+// GerarPDFOrdemServico retorna a OS em PDF
+// Aceita o parâmetro de consulta "tipo": orcamento, ordem (padrão) ou recibo
+func (c *DocumentoController) GerarPDFOrdemServico(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+		return
+	}
+
+	conteudo, nomeArquivo, err := c.documentoService.GerarPDFOrdemServico(uint(id), ctx.Query("tipo"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", `inline; filename="`+nomeArquivo+`"`)
+	ctx.Data(http.StatusOK, "application/pdf", conteudo)
+}
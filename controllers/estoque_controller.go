@@ -5,37 +5,52 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"OficinaMecanica/middlewares"
 	"OficinaMecanica/models"
 	"OficinaMecanica/services"
 )
 
 // EstoqueController gerencia as requisições HTTP relacionadas ao estoque
 type EstoqueController struct {
-	estoqueService services.EstoqueService
+	estoqueService      services.EstoqueService
+	movimentacaoService services.MovimentacaoEstoqueService
 }
 
 // NewEstoqueController cria uma nova instância do controlador de estoque
-func NewEstoqueController(estoqueService services.EstoqueService) *EstoqueController {
+func NewEstoqueController(estoqueService services.EstoqueService, movimentacaoService services.MovimentacaoEstoqueService) *EstoqueController {
 	return &EstoqueController{
-		estoqueService: estoqueService,
+		estoqueService:      estoqueService,
+		movimentacaoService: movimentacaoService,
 	}
 }
 
-// BuscarTodos retorna todos os itens do estoque
-// @Summary Listar todos os itens do estoque
-// @Description Retorna uma lista com todos os itens cadastrados no estoque
+// BuscarTodos retorna uma página dos itens do estoque
+// @Summary Listar os itens do estoque
+// @Description Retorna uma página dos itens cadastrados no estoque, com ordenação e filtros
 // @Tags estoque
 // @Produce json
-// @Success 200 {array} models.Estoque
-// @Failure 500 {object} map[string]string "Erro ao buscar itens do estoque"
+// @Param page query int false "Página (padrão 1)"
+// @Param pageSize query int false "Itens por página (padrão 20, máximo 100)"
+// @Param sort query string false "Campos de ordenação, ex.: nome,-quantidade"
+// @Param nome query string false "Parte do nome"
+// @Param categoria query string false "Categoria"
+// @Success 200 {object} models.Pagina[models.Estoque]
+// @Failure 400 {object} map[string]string "Paginação, ordenação ou filtro inválidos"
 // @Router /estoque [get]
 func (c *EstoqueController) BuscarTodos(ctx *gin.Context) {
-	itens, err := c.estoqueService.BuscarTodos()
+	consulta, err := lerConsulta(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar itens do estoque"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	itens, err := c.estoqueService.BuscarTodos(consulta)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -87,7 +102,7 @@ func (c *EstoqueController) Criar(ctx *gin.Context) {
 		return
 	}
 
-	itemCriado, err := c.estoqueService.Criar(&item)
+	itemCriado, err := c.estoqueService.Criar(&item, usuarioResponsavel(ctx))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -98,7 +113,7 @@ func (c *EstoqueController) Criar(ctx *gin.Context) {
 
 // Atualizar modifica um item existente no estoque
 // @Summary Atualizar item do estoque
-// @Description Atualiza os dados de um item existente no estoque
+// @Description Atualiza os dados cadastrais de um item existente no estoque (a quantidade só muda por movimentações)
 // @Tags estoque
 // @Accept json
 // @Produce json
@@ -202,14 +217,14 @@ func (c *EstoqueController) BuscarBaixoEstoque(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, itens)
 }
 
-// AtualizarQuantidade incrementa ou decrementa a quantidade de um item no estoque
-// @Summary Atualizar quantidade em estoque
-// @Description Atualiza a quantidade disponível de um item no estoque
+// AtualizarQuantidade ajusta o saldo de um item para a quantidade contada no inventário
+// @Summary Ajustar quantidade em estoque
+// @Description Registra um ajuste de inventário que leva o item à quantidade informada
 // @Tags estoque
 // @Accept json
 // @Produce json
 // @Param id path int true "ID do item"
-// @Param dados body map[string]int true "Dados da atualização"
+// @Param dados body map[string]interface{} true "Quantidade contada e motivo do ajuste"
 // @Success 200 {object} models.Estoque
 // @Failure 400 {object} map[string]string "Erro de validação"
 // @Failure 404 {object} map[string]string "Item não encontrado"
@@ -222,7 +237,8 @@ func (c *EstoqueController) AtualizarQuantidade(ctx *gin.Context) {
 	}
 
 	var dados struct {
-		Quantidade int `json:"quantidade" binding:"required"`
+		Quantidade *int   `json:"quantidade" binding:"required"`
+		Motivo     string `json:"motivo"`
 	}
 
 	if err := ctx.ShouldBindJSON(&dados); err != nil {
@@ -230,22 +246,132 @@ func (c *EstoqueController) AtualizarQuantidade(ctx *gin.Context) {
 		return
 	}
 
-	item, err := c.estoqueService.BuscarPorID(uint(id))
+	if _, err := c.estoqueService.BuscarPorID(uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Item não encontrado"})
+		return
+	}
+
+	// Registra o ajuste como movimentação
+	_, err = c.movimentacaoService.AjustarSaldo(uint(id), *dados.Quantidade, usuarioResponsavel(ctx), dados.Motivo)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	itemAtualizado, err := c.estoqueService.BuscarPorID(uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Item não encontrado"})
 		return
 	}
 
-	// Atualiza a quantidade
-	item.Quantidade = dados.Quantidade
+	ctx.JSON(http.StatusOK, itemAtualizado)
+}
+
+// BuscarMovimentacoes retorna o histórico de movimentações de um item
+// @Summary Listar movimentações do item
+// @Description Retorna as entradas, saídas e ajustes de um item, opcionalmente filtrados por período
+// @Tags estoque
+// @Produce json
+// @Param id path int true "ID do item"
+// @Param dataInicio query string false "Data inicial (AAAA-MM-DD)"
+// @Param dataFim query string false "Data final (AAAA-MM-DD)"
+// @Success 200 {array} models.MovimentacaoEstoque
+// @Failure 400 {object} map[string]string "Parâmetros inválidos"
+// @Failure 404 {object} map[string]string "Item não encontrado"
+// @Router /estoque/{id}/movimentacoes [get]
+func (c *EstoqueController) BuscarMovimentacoes(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var inicio, fim *time.Time
+	if dataInicio := ctx.Query("dataInicio"); dataInicio != "" {
+		data, err := time.Parse("2006-01-02", dataInicio)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data de início inválida. Use o formato AAAA-MM-DD"})
+			return
+		}
+		inicio = &data
+	}
+	if dataFim := ctx.Query("dataFim"); dataFim != "" {
+		data, err := time.Parse("2006-01-02", dataFim)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data de fim inválida. Use o formato AAAA-MM-DD"})
+			return
+		}
+		data = data.Add(24*time.Hour - time.Second) // Final do dia
+		fim = &data
+	}
+
+	if _, err := c.estoqueService.BuscarPorID(uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Item não encontrado"})
+		return
+	}
+
+	movimentacoes, err := c.movimentacaoService.BuscarPorEstoque(uint(id), inicio, fim)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, movimentacoes)
+}
+
+// RegistrarMovimentacao lança uma entrada, saída ou ajuste manual no item
+// @Summary Registrar movimentação
+// @Description Registra uma entrada (ex.: compra), saída (ex.: perda) ou ajuste no saldo do item
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do item"
+// @Param movimentacao body models.MovimentacaoEstoque true "Tipo, quantidade, custo unitário e motivo"
+// @Success 201 {object} models.MovimentacaoEstoque
+// @Failure 400 {object} map[string]string "Erro de validação"
+// @Router /estoque/{id}/movimentacoes [post]
+func (c *EstoqueController) RegistrarMovimentacao(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Tipo          string  `json:"tipo" binding:"required"`
+		Quantidade    int     `json:"quantidade" binding:"required"`
+		CustoUnitario float64 `json:"custoUnitario"`
+		Motivo        string  `json:"motivo"`
+	}
 
-	itemAtualizado, err := c.estoqueService.Atualizar(item)
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	movimentacao, err := c.movimentacaoService.Registrar(&models.MovimentacaoEstoque{
+		EstoqueID:     uint(id),
+		Tipo:          dados.Tipo,
+		Quantidade:    dados.Quantidade,
+		CustoUnitario: dados.CustoUnitario,
+		UsuarioID:     usuarioResponsavel(ctx),
+		Motivo:        dados.Motivo,
+	})
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, itemAtualizado)
+	ctx.JSON(http.StatusCreated, movimentacao)
+}
+
+// usuarioResponsavel retorna o ID do usuário autenticado para registrar quem fez a operação
+func usuarioResponsavel(ctx *gin.Context) *uint {
+	usuarioID, ok := middlewares.UsuarioIDDoContexto(ctx)
+	if !ok {
+		return nil
+	}
+	return &usuarioID
 }
 
 // Estrutura para controle de limites de estoque
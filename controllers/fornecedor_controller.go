@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// FornecedorController gerencia as requisições HTTP relacionadas aos fornecedores
+type FornecedorController struct {
+	fornecedorService services.FornecedorService
+}
+
+// NewFornecedorController cria uma nova instância do controlador de fornecedores
+func NewFornecedorController(fornecedorService services.FornecedorService) *FornecedorController {
+	return &FornecedorController{
+		fornecedorService: fornecedorService,
+	}
+}
+
+// BuscarTodos retorna todos os fornecedores cadastrados
+func (c *FornecedorController) BuscarTodos(ctx *gin.Context) {
+	fornecedores, err := c.fornecedorService.BuscarTodos()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar fornecedores"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fornecedores)
+}
+
+// BuscarPorID retorna um fornecedor específico pelo ID
+func (c *FornecedorController) BuscarPorID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	fornecedor, err := c.fornecedorService.BuscarPorID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Fornecedor não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fornecedor)
+}
+
+// Criar cadastra um novo fornecedor
+func (c *FornecedorController) Criar(ctx *gin.Context) {
+	var fornecedor models.Fornecedor
+	if err := ctx.ShouldBindJSON(&fornecedor); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	fornecedorCriado, err := c.fornecedorService.Criar(&fornecedor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, fornecedorCriado)
+}
+
+// Atualizar modifica os dados de um fornecedor
+func (c *FornecedorController) Atualizar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var fornecedor models.Fornecedor
+	if err := ctx.ShouldBindJSON(&fornecedor); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	fornecedor.ID = uint(id)
+	fornecedorAtualizado, err := c.fornecedorService.Atualizar(&fornecedor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fornecedorAtualizado)
+}
+
+// Deletar remove um fornecedor (soft delete)
+func (c *FornecedorController) Deletar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.fornecedorService.Deletar(uint(id)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// BuscarItens lista as peças do fornecedor com os códigos e preços dele
+func (c *FornecedorController) BuscarItens(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	itens, err := c.fornecedorService.BuscarItens(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, itens)
+}
+
+// SalvarItem cadastra ou altera o código e o preço de uma peça no fornecedor
+// Recebe o ID do fornecedor e o ID do item de estoque na URL
+func (c *FornecedorController) SalvarItem(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	estoqueID, err := strconv.Atoi(ctx.Param("estoqueId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do item inválido"})
+		return
+	}
+
+	var item models.FornecedorItem
+	if err := ctx.ShouldBindJSON(&item); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	item.FornecedorID = uint(id)
+	item.EstoqueID = uint(estoqueID)
+	itemSalvo, err := c.fornecedorService.SalvarItem(&item)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, itemSalvo)
+}
+
+// RemoverItem retira uma peça do catálogo do fornecedor
+func (c *FornecedorController) RemoverItem(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	estoqueID, err := strconv.Atoi(ctx.Param("estoqueId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do item inválido"})
+		return
+	}
+
+	if err := c.fornecedorService.RemoverItem(uint(id), uint(estoqueID)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// BuscarFornecedoresDoItem lista os fornecedores de uma peça do estoque
+func (c *FornecedorController) BuscarFornecedoresDoItem(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	itens, err := c.fornecedorService.BuscarFornecedoresDoItem(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, itens)
+}
+
+// Relatorio retorna o resumo de itens de estoque por fornecedor
+func (c *FornecedorController) Relatorio(ctx *gin.Context) {
+	relatorio, err := c.fornecedorService.Relatorio()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, relatorio)
+}
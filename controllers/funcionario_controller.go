@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// FuncionarioController gerencia as requisições HTTP relacionadas aos funcionários
+// Responsável por receber requisições, validar dados e retornar respostas apropriadas
+type FuncionarioController struct {
+	funcionarioService services.FuncionarioService // Serviço injetado via construtor
+}
+
+// NewFuncionarioController cria uma nova instância do controlador de funcionários
+func NewFuncionarioController(funcionarioService services.FuncionarioService) *FuncionarioController {
+	return &FuncionarioController{
+		funcionarioService: funcionarioService,
+	}
+}
+
+// BuscarTodos retorna todos os funcionários cadastrados
+// Aceita o parâmetro de consulta "cargo" para filtrar os resultados
+func (c *FuncionarioController) BuscarTodos(ctx *gin.Context) {
+	if cargo := ctx.Query("cargo"); cargo != "" {
+		c.listarPorCargo(ctx, cargo)
+		return
+	}
+
+	funcionarios, err := c.funcionarioService.BuscarTodos()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar funcionários"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionarios)
+}
+
+// BuscarPorID retorna um funcionário específico pelo ID
+func (c *FuncionarioController) BuscarPorID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	funcionario, err := c.funcionarioService.BuscarPorID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Funcionário não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionario)
+}
+
+// BuscarPorCargo retorna os funcionários de um cargo informado na URL
+func (c *FuncionarioController) BuscarPorCargo(ctx *gin.Context) {
+	c.listarPorCargo(ctx, ctx.Param("cargo"))
+}
+
+// BuscarExcluidos retorna os funcionários removidos que podem ser restaurados
+func (c *FuncionarioController) BuscarExcluidos(ctx *gin.Context) {
+	funcionarios, err := c.funcionarioService.BuscarExcluidos()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionarios)
+}
+
+// Criar adiciona um novo funcionário
+// Recebe os dados do funcionário no corpo da requisição em formato JSON
+func (c *FuncionarioController) Criar(ctx *gin.Context) {
+	var funcionario models.Funcionario
+
+	if err := ctx.ShouldBindJSON(&funcionario); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	funcionarioCriado, err := c.funcionarioService.Criar(&funcionario)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, funcionarioCriado)
+}
+
+// Atualizar modifica os dados de um funcionário existente
+func (c *FuncionarioController) Atualizar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var funcionario models.Funcionario
+	if err := ctx.ShouldBindJSON(&funcionario); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	funcionario.ID = uint(id)
+	funcionarioAtualizado, err := c.funcionarioService.Atualizar(&funcionario)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionarioAtualizado)
+}
+
+// Deletar remove um funcionário (soft delete)
+func (c *FuncionarioController) Deletar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	err = c.funcionarioService.Deletar(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Restaurar desfaz a exclusão de um funcionário
+func (c *FuncionarioController) Restaurar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	funcionario, err := c.funcionarioService.Restaurar(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionario)
+}
+
+// VincularUsuario associa um usuário existente ao funcionário
+// Recebe o ID do usuário no corpo da requisição
+func (c *FuncionarioController) VincularUsuario(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		UsuarioID uint `json:"usuarioId" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	funcionario, err := c.funcionarioService.VincularUsuario(uint(id), dados.UsuarioID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionario)
+}
+
+// DesvincularUsuario remove o vínculo entre o funcionário e seu usuário
+func (c *FuncionarioController) DesvincularUsuario(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	funcionario, err := c.funcionarioService.DesvincularUsuario(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionario)
+}
+
+// listarPorCargo responde com os funcionários do cargo informado
+func (c *FuncionarioController) listarPorCargo(ctx *gin.Context, cargo string) {
+	funcionarios, err := c.funcionarioService.BuscarPorCargo(cargo)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funcionarios)
+}
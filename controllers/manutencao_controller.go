@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// ManutencaoController gerencia as requisições HTTP de quilometragem e manutenção preventiva dos veículos
+type ManutencaoController struct {
+	manutencaoService services.ManutencaoService
+}
+
+// NewManutencaoController cria uma nova instância do controlador de manutenção preventiva
+func NewManutencaoController(manutencaoService services.ManutencaoService) *ManutencaoController {
+	return &ManutencaoController{
+		manutencaoService: manutencaoService,
+	}
+}
+
+// BuscarLeituras retorna o histórico de quilometragem do veículo
+func (c *ManutencaoController) BuscarLeituras(ctx *gin.Context) {
+	veiculoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do veículo inválido"})
+		return
+	}
+
+	leituras, err := c.manutencaoService.BuscarLeituras(uint(veiculoID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, leituras)
+}
+
+// RegistrarLeitura registra uma leitura de km informada fora de uma OS
+// Corpo: {"km": 45200, "dataLeitura": "2025-03-10T09:00:00-03:00"}; sem data, vale o momento atual
+func (c *ManutencaoController) RegistrarLeitura(ctx *gin.Context) {
+	veiculoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do veículo inválido"})
+		return
+	}
+
+	var dados struct {
+		Km          int        `json:"km" binding:"required,gt=0"`
+		DataLeitura *time.Time `json:"dataLeitura"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	var data time.Time
+	if dados.DataLeitura != nil {
+		data = *dados.DataLeitura
+	}
+
+	leitura, err := c.manutencaoService.RegistrarLeitura(uint(veiculoID), dados.Km, data, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, leitura)
+}
+
+// BuscarPlanos retorna os planos de manutenção do veículo com a previsão de vencimento
+func (c *ManutencaoController) BuscarPlanos(ctx *gin.Context) {
+	veiculoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do veículo inválido"})
+		return
+	}
+
+	planos, err := c.manutencaoService.BuscarPlanos(uint(veiculoID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, planos)
+}
+
+// CriarPlano cadastra um plano de manutenção para o veículo
+func (c *ManutencaoController) CriarPlano(ctx *gin.Context) {
+	veiculoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do veículo inválido"})
+		return
+	}
+
+	var plano models.PlanoManutencao
+	if err := ctx.ShouldBindJSON(&plano); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	plano.VeiculoID = uint(veiculoID)
+
+	criado, err := c.manutencaoService.CriarPlano(&plano)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, criado)
+}
+
+// AtualizarPlano altera um plano de manutenção do veículo
+func (c *ManutencaoController) AtualizarPlano(ctx *gin.Context) {
+	veiculoID, planoID, ok := idsPlanoManutencao(ctx)
+	if !ok {
+		return
+	}
+
+	var plano models.PlanoManutencao
+	if err := ctx.ShouldBindJSON(&plano); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	plano.ID = planoID
+	plano.VeiculoID = veiculoID
+
+	atualizado, err := c.manutencaoService.AtualizarPlano(&plano)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, atualizado)
+}
+
+// DeletarPlano remove um plano de manutenção do veículo
+func (c *ManutencaoController) DeletarPlano(ctx *gin.Context) {
+	veiculoID, planoID, ok := idsPlanoManutencao(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.manutencaoService.DeletarPlano(veiculoID, planoID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Plano de manutenção excluído com sucesso"})
+}
+
+// RegistrarRealizacao informa uma manutenção do plano feita fora da oficina ou antes do cadastro
+// Corpo: {"km": 40000, "data": "2025-01-15T00:00:00-03:00"}
+func (c *ManutencaoController) RegistrarRealizacao(ctx *gin.Context) {
+	veiculoID, planoID, ok := idsPlanoManutencao(ctx)
+	if !ok {
+		return
+	}
+
+	var dados struct {
+		Km   int        `json:"km" binding:"min=0"`
+		Data *time.Time `json:"data"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	var data time.Time
+	if dados.Data != nil {
+		data = *dados.Data
+	}
+
+	plano, err := c.manutencaoService.RegistrarRealizacao(veiculoID, planoID, dados.Km, data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plano)
+}
+
+// BuscarPendentes lista as manutenções vencidas ou a vencer, para as ligações de lembrete
+// Aceita o parâmetro de consulta "dias" (padrão 30): horizonte do vencimento a partir de hoje
+func (c *ManutencaoController) BuscarPendentes(ctx *gin.Context) {
+	dias := 30
+	if valor := ctx.Query("dias"); valor != "" {
+		convertido, err := strconv.Atoi(valor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Número de dias inválido"})
+			return
+		}
+		dias = convertido
+	}
+
+	pendentes, err := c.manutencaoService.BuscarPendentes(dias)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pendentes)
+}
+
+// idsPlanoManutencao lê os IDs do veículo e do plano da URL, respondendo 400 quando inválidos
+func idsPlanoManutencao(ctx *gin.Context) (uint, uint, bool) {
+	veiculoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do veículo inválido"})
+		return 0, 0, false
+	}
+
+	planoID, err := strconv.Atoi(ctx.Param("planoId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do plano inválido"})
+		return 0, 0, false
+	}
+
+	return uint(veiculoID), uint(planoID), true
+}
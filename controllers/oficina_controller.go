@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// OficinaController gerencia os dados cadastrais da oficina usados nos documentos
+type OficinaController struct {
+	oficinaService services.OficinaService
+}
+
+// NewOficinaController cria uma nova instância do controlador da oficina
+func NewOficinaController(oficinaService services.OficinaService) *OficinaController {
+	return &OficinaController{
+		oficinaService: oficinaService,
+	}
+}
+
+// BuscarDados retorna nome, CNPJ, endereço, contatos e logo da oficina
+func (c *OficinaController) BuscarDados(ctx *gin.Context) {
+	dados, err := c.oficinaService.BuscarDados()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dados)
+}
+
+// SalvarDados altera os dados da oficina; o logo é enviado em /oficina/logo
+func (c *OficinaController) SalvarDados(ctx *gin.Context) {
+	var dados models.DadosOficina
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	dadosSalvos, err := c.oficinaService.SalvarDados(&dados)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dadosSalvos)
+}
+
+// UploadLogo recebe a imagem do logo (PNG ou JPG) no campo "logo" do formulário
+func (c *OficinaController) UploadLogo(ctx *gin.Context) {
+	file, err := ctx.FormFile("logo")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não enviado"})
+		return
+	}
+
+	extensao := strings.ToLower(filepath.Ext(file.Filename))
+	if extensao != ".png" && extensao != ".jpg" && extensao != ".jpeg" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O logo deve ser uma imagem PNG ou JPG"})
+		return
+	}
+
+	// Garante que a pasta exista
+	os.MkdirAll("uploads/oficina", os.ModePerm)
+
+	filename := "uploads/oficina/logo" + extensao
+	if err := ctx.SaveUploadedFile(file, filename); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar arquivo"})
+		return
+	}
+
+	dados, err := c.oficinaService.AtualizarLogo(filename)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dados)
+}
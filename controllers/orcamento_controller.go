@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// OrcamentoController gerencia as requisições HTTP relacionadas aos orçamentos
+type OrcamentoController struct {
+	orcamentoService services.OrcamentoService
+}
+
+// NewOrcamentoController cria uma nova instância do controlador de orçamentos
+func NewOrcamentoController(orcamentoService services.OrcamentoService) *OrcamentoController {
+	return &OrcamentoController{
+		orcamentoService: orcamentoService,
+	}
+}
+
+// BuscarTodos retorna os orçamentos
+// Aceita o parâmetro de consulta "status" para filtrar os resultados
+func (c *OrcamentoController) BuscarTodos(ctx *gin.Context) {
+	orcamentos, err := c.orcamentoService.BuscarTodos(ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orcamentos)
+}
+
+// BuscarPorID retorna um orçamento com suas linhas
+func (c *OrcamentoController) BuscarPorID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	orcamento, err := c.orcamentoService.BuscarPorID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Orçamento não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orcamento)
+}
+
+// BuscarVersoes retorna o histórico de versões de um orçamento
+func (c *OrcamentoController) BuscarVersoes(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	versoes, err := c.orcamentoService.BuscarVersoes(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, versoes)
+}
+
+// Criar cadastra um novo orçamento, reservando as peças no estoque
+func (c *OrcamentoController) Criar(ctx *gin.Context) {
+	var orcamento models.Orcamento
+	if err := ctx.ShouldBindJSON(&orcamento); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	orcamentoCriado, err := c.orcamentoService.Criar(&orcamento, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, orcamentoCriado)
+}
+
+// Revisar gera uma nova versão do orçamento
+// Cliente e veículo são mantidos; sem "itens" no corpo, as linhas da versão anterior são repetidas
+func (c *OrcamentoController) Revisar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Descricao     string                 `json:"descricao"`
+		ValidadeAte   *time.Time             `json:"validadeAte"`
+		ValorDesconto float64                `json:"valorDesconto"`
+		Observacoes   string                 `json:"observacoes"`
+		Itens         []models.ItemOrcamento `json:"itens"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	revisao := models.Orcamento{
+		Descricao:     dados.Descricao,
+		ValorDesconto: dados.ValorDesconto,
+		Observacoes:   dados.Observacoes,
+		Itens:         dados.Itens,
+	}
+	if dados.ValidadeAte != nil {
+		revisao.ValidadeAte = *dados.ValidadeAte
+	}
+
+	novaVersao, err := c.orcamentoService.Revisar(uint(id), &revisao, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, novaVersao)
+}
+
+// Aprovar registra a aprovação do cliente
+// Espera no corpo quem autorizou: {"aprovadoPor": "Nome do cliente"}
+func (c *OrcamentoController) Aprovar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		AprovadoPor string `json:"aprovadoPor" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Informe quem aprovou o orçamento"})
+		return
+	}
+
+	orcamento, err := c.orcamentoService.Aprovar(uint(id), dados.AprovadoPor, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orcamento)
+}
+
+// Rejeitar registra a recusa do cliente, liberando as peças reservadas
+// Espera no corpo: {"responsavel": "Nome do cliente", "motivo": "..."}
+func (c *OrcamentoController) Rejeitar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Responsavel string `json:"responsavel"`
+		Motivo      string `json:"motivo"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	orcamento, err := c.orcamentoService.Rejeitar(uint(id), dados.Responsavel, dados.Motivo, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orcamento)
+}
+
+// Converter transforma um orçamento aprovado em ordem de serviço
+// Aceita no corpo a OS de destino: {"ordemServicoId": 1}; sem ela, uma nova OS é aberta
+func (c *OrcamentoController) Converter(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		OrdemServicoID *uint `json:"ordemServicoId"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&dados); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	os, err := c.orcamentoService.ConverterEmOS(uint(id), dados.OrdemServicoID, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, os)
+}
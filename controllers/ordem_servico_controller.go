@@ -25,28 +25,21 @@ func NewOrdemServicoController(osService services.OrdemServicoService) *OrdemSer
     }
 }
 
-// BuscarTodas retorna todas as ordens de serviço cadastradas
-// Este endpoint pode ter parâmetros de consulta para filtrar os resultados
+// BuscarTodas retorna uma página das ordens de serviço cadastradas
+// Aceita page, pageSize, sort e os filtros status, statusFinanceiro, clienteId, veiculoId,
+// funcionarioId, numeroOS, dataInicio e dataFim (AAAA-MM-DD). Com o parâmetro cursor
+// (vazio na primeira página), a paginação segue o proximoCursor devolvido em cada página
 func (c *OrdemServicoController) BuscarTodas(ctx *gin.Context) {
-    // Verificar se há filtros por status
-    status := ctx.Query("status")
-    if status != "" {
-        c.BuscarPorStatus(ctx)
-        return
-    }
-
-    // Verificar filtro por período
-    dataInicio := ctx.Query("dataInicio")
-    dataFim := ctx.Query("dataFim")
-    if dataInicio != "" && dataFim != "" {
-        c.BuscarPorPeriodo(ctx)
+    // Ler paginação, ordenação e filtros da URL
+    consulta, err := lerConsulta(ctx)
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
 
-    // Buscar todas sem filtro
-    ordens, err := c.osService.BuscarTodas()
+    ordens, err := c.osService.BuscarTodas(consulta)
     if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar ordens de serviço"})
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
 
@@ -133,6 +126,28 @@ func (c *OrdemServicoController) BuscarPorVeiculo(ctx *gin.Context) {
     ctx.JSON(http.StatusOK, ordens)
 }
 
+// BuscarPorFuncionario retorna a fila de ordens de serviço de um funcionário
+// Por padrão lista apenas ordens abertas ou em andamento; use ?todas=true para incluir as finalizadas
+func (c *OrdemServicoController) BuscarPorFuncionario(ctx *gin.Context) {
+    // Extrai e converte o ID do funcionário
+    funcionarioID, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do funcionário inválido"})
+        return
+    }
+
+    incluirFinalizadas := ctx.Query("todas") == "true"
+
+    // Busca as ordens atribuídas ao funcionário
+    ordens, err := c.osService.BuscarPorFuncionario(uint(funcionarioID), incluirFinalizadas)
+    if err != nil {
+        ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, ordens)
+}
+
 // BuscarPorStatus retorna as ordens de serviço com um status específico
 // Recebe o status como parâmetro na query e retorna a lista de ordens
 func (c *OrdemServicoController) BuscarPorStatus(ctx *gin.Context) {
@@ -237,7 +252,7 @@ func (c *OrdemServicoController) Atualizar(ctx *gin.Context) {
     os.ID = uint(id)
 
     // Atualiza a ordem de serviço
-    osAtualizada, err := c.osService.Atualizar(&os)
+    osAtualizada, err := c.osService.Atualizar(&os, usuarioResponsavel(ctx))
     if err != nil {
         ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
@@ -276,6 +291,36 @@ func (c *OrdemServicoController) AtualizarStatus(ctx *gin.Context) {
     ctx.JSON(http.StatusOK, osAtualizada)
 }
 
+// AtribuirFuncionario define ou troca o mecânico responsável pela ordem de serviço
+// Recebe o ID da ordem na URL e o ID do funcionário no corpo
+func (c *OrdemServicoController) AtribuirFuncionario(ctx *gin.Context) {
+    // Extrai e converte o ID da URL
+    id, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+        return
+    }
+
+    // Extrai o funcionário do corpo
+    var dados struct {
+        FuncionarioID uint `json:"funcionarioId" binding:"required"`
+    }
+
+    if err := ctx.ShouldBindJSON(&dados); err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Funcionário não informado"})
+        return
+    }
+
+    // Atribui o funcionário
+    osAtualizada, err := c.osService.AtribuirFuncionario(uint(id), dados.FuncionarioID)
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, osAtualizada)
+}
+
 // Deletar remove uma ordem de serviço
 // Recebe o ID da ordem como parâmetro na URL
 func (c *OrdemServicoController) Deletar(ctx *gin.Context) {
@@ -315,7 +360,7 @@ func (c *OrdemServicoController) AdicionarItem(ctx *gin.Context) {
     }
 
     // Adiciona o item à OS
-    itemAdicionado, err := c.osService.AdicionarItem(uint(id), &item)
+    itemAdicionado, err := c.osService.AdicionarItem(uint(id), &item, usuarioResponsavel(ctx))
     if err != nil {
         ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
@@ -342,7 +387,7 @@ func (c *OrdemServicoController) RemoverItem(ctx *gin.Context) {
     }
 
     // Remove o item
-    err = c.osService.RemoverItem(uint(osID), uint(itemID))
+    err = c.osService.RemoverItem(uint(osID), uint(itemID), usuarioResponsavel(ctx))
     if err != nil {
         ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
@@ -381,7 +426,7 @@ func (c *OrdemServicoController) AtualizarItem(ctx *gin.Context) {
     item.OrdemServicoID = uint(osID)
 
     // Atualiza o item
-    itemAtualizado, err := c.osService.AtualizarItem(uint(osID), &item)
+    itemAtualizado, err := c.osService.AtualizarItem(uint(osID), &item, usuarioResponsavel(ctx))
     if err != nil {
         ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
@@ -441,11 +486,172 @@ func (c *OrdemServicoController) CancelarOS(ctx *gin.Context) {
     }
 
     // Cancela a OS
-    osAtualizada, err := c.osService.CancelarOS(uint(id))
+    osAtualizada, err := c.osService.CancelarOS(uint(id), usuarioResponsavel(ctx))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, osAtualizada)
+}
+
+// Entregar registra a retirada do veículo pelo cliente
+// A entrega só é aceita com a OS concluída e sem saldo em aberto
+func (c *OrdemServicoController) Entregar(ctx *gin.Context) {
+    // Extrai e converte o ID da OS
+    id, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+        return
+    }
+
+    // Registra a entrega
+    osAtualizada, err := c.osService.Entregar(uint(id), false, "", usuarioResponsavel(ctx))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, osAtualizada)
+}
+
+// EntregarSemPagamento libera a entrega de uma OS com saldo em aberto
+// Exige o motivo da liberação no corpo: {"motivo": "..."}
+func (c *OrdemServicoController) EntregarSemPagamento(ctx *gin.Context) {
+    // Extrai e converte o ID da OS
+    id, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+        return
+    }
+
+    var dados struct {
+        Motivo string `json:"motivo" binding:"required"`
+    }
+    if err := ctx.ShouldBindJSON(&dados); err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo da liberação"})
+        return
+    }
+
+    // Registra a entrega liberada
+    osAtualizada, err := c.osService.Entregar(uint(id), true, dados.Motivo, usuarioResponsavel(ctx))
     if err != nil {
         ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
 
     ctx.JSON(http.StatusOK, osAtualizada)
-}
\ No newline at end of file
+}
+
+// BuscarServicos retorna as linhas de mão de obra de uma ordem de serviço
+// Recebe o ID da ordem na URL
+func (c *OrdemServicoController) BuscarServicos(ctx *gin.Context) {
+    // Extrai e converte o ID da OS
+    id, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+        return
+    }
+
+    // Busca as linhas de serviço da OS
+    linhas, err := c.osService.BuscarServicos(uint(id))
+    if err != nil {
+        ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, linhas)
+}
+
+// AdicionarServico lança um serviço do catálogo na ordem de serviço
+// Horas e valor da hora, quando omitidos, vêm do catálogo; o mecânico padrão é o responsável pela OS
+func (c *OrdemServicoController) AdicionarServico(ctx *gin.Context) {
+    // Extrai e converte o ID da OS
+    id, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+        return
+    }
+
+    var linha models.ServicoOrdemServico
+
+    // Faz o binding do JSON para o modelo
+    if err := ctx.ShouldBindJSON(&linha); err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados do serviço inválidos: " + err.Error()})
+        return
+    }
+
+    // Adiciona a linha à OS
+    linhaAdicionada, err := c.osService.AdicionarServico(uint(id), &linha)
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusCreated, linhaAdicionada)
+}
+
+// AtualizarServico modifica uma linha de mão de obra da ordem de serviço
+// Recebe os IDs da ordem e da linha na URL e os novos dados no corpo
+func (c *OrdemServicoController) AtualizarServico(ctx *gin.Context) {
+    // Extrai e converte o ID da OS
+    osID, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+        return
+    }
+
+    // Extrai e converte o ID da linha
+    linhaID, err := strconv.Atoi(ctx.Param("linhaId"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do serviço inválido"})
+        return
+    }
+
+    var linha models.ServicoOrdemServico
+
+    // Faz o binding do JSON para o modelo
+    if err := ctx.ShouldBindJSON(&linha); err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados do serviço inválidos: " + err.Error()})
+        return
+    }
+
+    // Define o ID da linha usando o valor da URL
+    linha.ID = uint(linhaID)
+
+    // Atualiza a linha
+    linhaAtualizada, err := c.osService.AtualizarServico(uint(osID), &linha)
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, linhaAtualizada)
+}
+
+// RemoverServico exclui uma linha de mão de obra da ordem de serviço
+// Recebe os IDs da ordem e da linha na URL
+func (c *OrdemServicoController) RemoverServico(ctx *gin.Context) {
+    // Extrai e converte o ID da OS
+    osID, err := strconv.Atoi(ctx.Param("id"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+        return
+    }
+
+    // Extrai e converte o ID da linha
+    linhaID, err := strconv.Atoi(ctx.Param("linhaId"))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do serviço inválido"})
+        return
+    }
+
+    // Remove a linha
+    err = c.osService.RemoverServico(uint(osID), uint(linhaID))
+    if err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// PagamentoController gerencia as requisições HTTP relacionadas aos pagamentos das ordens de serviço
+type PagamentoController struct {
+	pagamentoService services.PagamentoService
+}
+
+// NewPagamentoController cria uma nova instância do controlador de pagamentos
+func NewPagamentoController(pagamentoService services.PagamentoService) *PagamentoController {
+	return &PagamentoController{
+		pagamentoService: pagamentoService,
+	}
+}
+
+// BuscarPorOS retorna os pagamentos de uma ordem de serviço
+func (c *PagamentoController) BuscarPorOS(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+		return
+	}
+
+	pagamentos, err := c.pagamentoService.BuscarPorOS(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pagamentos)
+}
+
+// Registrar lança um pagamento na ordem de serviço e retorna a OS com a situação financeira atualizada
+func (c *PagamentoController) Registrar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+		return
+	}
+
+	var pagamento models.Pagamento
+	if err := ctx.ShouldBindJSON(&pagamento); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados do pagamento inválidos: " + err.Error()})
+		return
+	}
+
+	os, err := c.pagamentoService.Registrar(uint(id), &pagamento, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, os)
+}
+
+// Estornar desfaz um pagamento da ordem de serviço
+// Exige o motivo no corpo: {"motivo": "..."}
+func (c *PagamentoController) Estornar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID da ordem de serviço inválido"})
+		return
+	}
+
+	pagamentoID, err := strconv.Atoi(ctx.Param("pagamentoId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento inválido"})
+		return
+	}
+
+	var dados struct {
+		Motivo string `json:"motivo" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo do estorno"})
+		return
+	}
+
+	os, err := c.pagamentoService.Estornar(uint(id), uint(pagamentoID), dados.Motivo, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, os)
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// PedidoCompraController gerencia as requisições HTTP relacionadas aos pedidos de compra
+type PedidoCompraController struct {
+	pedidoService services.PedidoCompraService
+}
+
+// NewPedidoCompraController cria uma nova instância do controlador de pedidos de compra
+func NewPedidoCompraController(pedidoService services.PedidoCompraService) *PedidoCompraController {
+	return &PedidoCompraController{
+		pedidoService: pedidoService,
+	}
+}
+
+// BuscarTodos retorna os pedidos de compra
+// Aceita o parâmetro de consulta "status" para filtrar os resultados
+func (c *PedidoCompraController) BuscarTodos(ctx *gin.Context) {
+	var (
+		pedidos []models.PedidoCompra
+		err     error
+	)
+
+	if status := ctx.Query("status"); status != "" {
+		pedidos, err = c.pedidoService.BuscarPorStatus(status)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		pedidos, err = c.pedidoService.BuscarTodos()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pedidos de compra"})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, pedidos)
+}
+
+// BuscarPorID retorna um pedido de compra com suas linhas
+func (c *PedidoCompraController) BuscarPorID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	pedido, err := c.pedidoService.BuscarPorID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Pedido de compra não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pedido)
+}
+
+// Criar cadastra um novo pedido de compra em rascunho
+func (c *PedidoCompraController) Criar(ctx *gin.Context) {
+	var pedido models.PedidoCompra
+	if err := ctx.ShouldBindJSON(&pedido); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	pedidoCriado, err := c.pedidoService.Criar(&pedido, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, pedidoCriado)
+}
+
+// Atualizar altera um pedido em rascunho
+func (c *PedidoCompraController) Atualizar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var pedido models.PedidoCompra
+	if err := ctx.ShouldBindJSON(&pedido); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	pedido.ID = uint(id)
+	pedidoAtualizado, err := c.pedidoService.Atualizar(&pedido)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pedidoAtualizado)
+}
+
+// Deletar remove um pedido em rascunho
+func (c *PedidoCompraController) Deletar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.pedidoService.Deletar(uint(id)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Enviar marca o pedido como enviado ao fornecedor
+func (c *PedidoCompraController) Enviar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	pedido, err := c.pedidoService.Enviar(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pedido)
+}
+
+// Receber registra a chegada de itens do pedido
+// Espera no corpo a lista de itens recebidos: {"itens": [{"itemId": 1, "quantidade": 5, "custoUnitario": 12.5}]}
+func (c *PedidoCompraController) Receber(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var dados struct {
+		Itens []services.RecebimentoItem `json:"itens" binding:"required,dive"`
+	}
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	pedido, err := c.pedidoService.Receber(uint(id), dados.Itens, usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pedido)
+}
+
+// Cancelar cancela um pedido de compra
+func (c *PedidoCompraController) Cancelar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	pedido, err := c.pedidoService.Cancelar(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pedido)
+}
+
+// GerarDeBaixoEstoque cria pedidos em rascunho, um por fornecedor, com os itens abaixo do mínimo
+func (c *PedidoCompraController) GerarDeBaixoEstoque(ctx *gin.Context) {
+	pedidos, err := c.pedidoService.GerarDeBaixoEstoque(usuarioResponsavel(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, pedidos)
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/middlewares"
+	"OficinaMecanica/services"
+)
+
+// PermissaoController gerencia as requisições HTTP relacionadas às permissões dos cargos
+type PermissaoController struct {
+	permissaoService services.PermissaoService
+	usuarioService   services.UsuarioService
+}
+
+// NewPermissaoController cria uma nova instância do controlador de permissões
+func NewPermissaoController(permissaoService services.PermissaoService, usuarioService services.UsuarioService) *PermissaoController {
+	return &PermissaoController{
+		permissaoService: permissaoService,
+		usuarioService:   usuarioService,
+	}
+}
+
+// BuscarCatalogo retorna todas as permissões existentes no sistema
+func (c *PermissaoController) BuscarCatalogo(ctx *gin.Context) {
+	permissoes, err := c.permissaoService.BuscarCatalogo()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, permissoes)
+}
+
+// BuscarMapeamentos retorna as permissões concedidas a cada cargo
+func (c *PermissaoController) BuscarMapeamentos(ctx *gin.Context) {
+	mapa, err := c.permissaoService.BuscarMapeamentos()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapa)
+}
+
+// BuscarPorCargo retorna as permissões de um cargo específico
+func (c *PermissaoController) BuscarPorCargo(ctx *gin.Context) {
+	cargo := ctx.Param("cargo")
+
+	permissoes, err := c.permissaoService.BuscarPorCargo(cargo)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"cargo": cargo, "permissoes": permissoes})
+}
+
+// AtualizarCargo substitui as permissões de um cargo
+// Recebe no corpo a lista completa de códigos que o cargo deve possuir
+func (c *PermissaoController) AtualizarCargo(ctx *gin.Context) {
+	cargo := ctx.Param("cargo")
+
+	var dados struct {
+		Permissoes []string `json:"permissoes" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&dados); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	permissoes, err := c.permissaoService.AtualizarCargo(cargo, dados.Permissoes)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"cargo": cargo, "permissoes": permissoes})
+}
+
+// MinhasPermissoes retorna as permissões do usuário autenticado
+// Útil para o frontend decidir quais telas e ações exibir
+func (c *PermissaoController) MinhasPermissoes(ctx *gin.Context) {
+	usuarioID, ok := middlewares.UsuarioIDDoContexto(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	usuario, err := c.usuarioService.BuscarPorID(usuarioID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	permissoes, err := c.permissaoService.BuscarPorCargo(usuario.Cargo)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"cargo": usuario.Cargo, "permissoes": permissoes})
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/services"
+)
+
+// RedefinicaoSenhaController trata a troca da senha esquecida, sem autenticação
+type RedefinicaoSenhaController struct {
+	redefinicaoService services.RedefinicaoSenhaService
+}
+
+// NewRedefinicaoSenhaController cria uma nova instância do controller de redefinição de senha
+func NewRedefinicaoSenhaController(redefinicaoService services.RedefinicaoSenhaService) *RedefinicaoSenhaController {
+	return &RedefinicaoSenhaController{redefinicaoService: redefinicaoService}
+}
+
+// Solicitar agenda o envio do link de redefinição. A resposta e o tempo dela são os mesmos para e-mails com ou sem cadastro
+func (c *RedefinicaoSenhaController) Solicitar(ctx *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+		return
+	}
+
+	c.redefinicaoService.Solicitar(req.Email, ctx.ClientIP())
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Se o e-mail estiver cadastrado, enviaremos as instruções para redefinir a senha"})
+}
+
+// Redefinir troca a senha usando o token do link recebido por e-mail
+func (c *RedefinicaoSenhaController) Redefinir(ctx *gin.Context) {
+	var req struct {
+		Token     string `json:"token" binding:"required"`
+		NovaSenha string `json:"nova_senha" binding:"required"` // Conferida pela política de senhas do serviço
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+		return
+	}
+
+	if err := c.redefinicaoService.Redefinir(req.Token, req.NovaSenha); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// ServicoController gerencia as requisições HTTP relacionadas ao catálogo de serviços
+type ServicoController struct {
+	servicoService services.ServicoService
+}
+
+// NewServicoController cria uma nova instância do controlador do catálogo de serviços
+func NewServicoController(servicoService services.ServicoService) *ServicoController {
+	return &ServicoController{
+		servicoService: servicoService,
+	}
+}
+
+// BuscarTodos retorna os serviços do catálogo
+// Aceita o parâmetro de consulta "categoria" para filtrar os resultados
+func (c *ServicoController) BuscarTodos(ctx *gin.Context) {
+	servicos, err := c.servicoService.BuscarTodos(ctx.Query("categoria"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar serviços"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, servicos)
+}
+
+// BuscarPorID retorna um serviço específico pelo ID
+func (c *ServicoController) BuscarPorID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	servico, err := c.servicoService.BuscarPorID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, servico)
+}
+
+// Criar cadastra um novo serviço no catálogo
+func (c *ServicoController) Criar(ctx *gin.Context) {
+	var servico models.Servico
+	if err := ctx.ShouldBindJSON(&servico); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	servicoCriado, err := c.servicoService.Criar(&servico)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, servicoCriado)
+}
+
+// Atualizar modifica os dados de um serviço
+func (c *ServicoController) Atualizar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var servico models.Servico
+	if err := ctx.ShouldBindJSON(&servico); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	servico.ID = uint(id)
+	servicoAtualizado, err := c.servicoService.Atualizar(&servico)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, servicoAtualizado)
+}
+
+// Deletar remove um serviço do catálogo (soft delete)
+func (c *ServicoController) Deletar(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := c.servicoService.Deletar(uint(id)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
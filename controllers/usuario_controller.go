@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -8,31 +9,49 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"OficinaMecanica/middlewares"
 	"OficinaMecanica/models"
 	"OficinaMecanica/services"
+	"OficinaMecanica/utils"
 )
 
 type UsuarioController struct {
-	usuarioService services.UsuarioService
+	usuarioService   services.UsuarioService
+	permissaoService services.PermissaoService
 }
 
-func NewUsuarioController(usuarioService services.UsuarioService) *UsuarioController {
+func NewUsuarioController(usuarioService services.UsuarioService, permissaoService services.PermissaoService) *UsuarioController {
 	return &UsuarioController{
-		usuarioService: usuarioService,
+		usuarioService:   usuarioService,
+		permissaoService: permissaoService,
 	}
 }
 
+// CriarUsuarioRequest são os dados aceitos na criação de um usuário pela administração.
+// Os demais campos do modelo (status, datas, avatar) não podem ser definidos pelo cliente
+type CriarUsuarioRequest struct {
+	Nome  string `json:"nome" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	Senha string `json:"senha" binding:"required"`
+	Cargo string `json:"cargo"` // Opcional; exige a permissão de gerenciar permissões
+}
+
 func (c *UsuarioController) BuscarTodos(ctx *gin.Context) {
-	usuarios, err := c.usuarioService.BuscarTodos()
+	consulta, err := lerConsulta(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	usuarios, err := c.usuarioService.BuscarTodos(consulta)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuários"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Remover senhas dos resultados
-	usuariosSemSenha := make([]gin.H, len(usuarios))
-	for i, u := range usuarios {
-		usuariosSemSenha[i] = gin.H{
+	usuariosSemSenha := models.MapearPagina(usuarios, func(u models.Usuario) gin.H {
+		return gin.H{
 			"id":               u.ID,
 			"nome":             u.Nome,
 			"email":            u.Email,
@@ -41,7 +60,7 @@ func (c *UsuarioController) BuscarTodos(ctx *gin.Context) {
 			"data_criacao":     u.CreatedAt,
 			"data_atualizacao": u.UpdatedAt,
 		}
-	}
+	})
 
 	ctx.JSON(http.StatusOK, usuariosSemSenha)
 }
@@ -72,13 +91,33 @@ func (c *UsuarioController) BuscarPorID(ctx *gin.Context) {
 }
 
 func (c *UsuarioController) Criar(ctx *gin.Context) {
-	var usuario models.Usuario
+	var req CriarUsuarioRequest
 
-	if err := ctx.ShouldBindJSON(&usuario); err != nil {
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
 		return
 	}
 
+	usuario := models.Usuario{
+		Nome:  req.Nome,
+		Email: req.Email,
+		Senha: req.Senha, // O serviço valida a senha e grava apenas o hash
+	}
+
+	// Sem cargo informado vale o padrão do modelo; atribuir um cargo depende de quem está criando
+	if cargo := models.NormalizarCargo(req.Cargo); cargo != "" {
+		usuarioID, ok := middlewares.UsuarioIDDoContexto(ctx)
+		if !ok {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+			return
+		}
+		if err := c.permissaoService.VerificarAtribuicaoCargo(usuarioID, cargo); err != nil {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		usuario.Cargo = cargo
+	}
+
 	// Verificar se o email já está em uso
 	_, err := c.usuarioService.BuscarPorEmail(usuario.Email)
 	if err == nil {
@@ -87,6 +126,11 @@ func (c *UsuarioController) Criar(ctx *gin.Context) {
 	}
 
 	usuarioCriado, err := c.usuarioService.Criar(&usuario)
+	var erroSenha *utils.ErroPoliticaSenha
+	if errors.As(err, &erroSenha) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": erroSenha.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar usuário"})
 		return
@@ -160,7 +204,7 @@ func (c *UsuarioController) AlterarSenha(ctx *gin.Context) {
 
 	type SenhaRequest struct {
 		SenhaAtual string `json:"senha_atual" binding:"required"`
-		NovaSenha  string `json:"nova_senha" binding:"required,min=6"`
+		NovaSenha  string `json:"nova_senha" binding:"required"` // Conferida pela política de senhas do serviço
 	}
 
 	var req SenhaRequest
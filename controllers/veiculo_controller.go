@@ -24,18 +24,25 @@ func NewVeiculoController(veiculoService services.VeiculoService) *VeiculoContro
 	}
 }
 
-// BuscarTodos retorna todos os veículos cadastrados
-// Este endpoint não recebe parâmetros e retorna um array com todos os veículos
+// BuscarTodos retorna uma página dos veículos cadastrados
+// Aceita page, pageSize, sort e os filtros placa, marca, modelo, anoModelo, chassi e clienteId
 func (c *VeiculoController) BuscarTodos(ctx *gin.Context) {
-	// Solicita ao serviço que busque todos os veículos
-	veiculos, err := c.veiculoService.BuscarTodos()
+	// Lê a paginação, a ordenação e os filtros da URL
+	consulta, err := lerConsulta(ctx)
 	if err != nil {
-		// Em caso de erro, retorna status 500 (erro interno)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar veículos"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Solicita ao serviço que busque a página de veículos
+	veiculos, err := c.veiculoService.BuscarTodos(consulta)
+	if err != nil {
+		// Filtro ou ordenação não suportados são erros do cliente da API
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Retorna a lista de veículos com status 200 (OK)
+	// Retorna a página de veículos com status 200 (OK)
 	ctx.JSON(http.StatusOK, veiculos)
 }
 
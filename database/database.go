@@ -2,30 +2,91 @@ package database
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/glebarez/sqlite"
 	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// Bancos de dados suportados (DB_DRIVER)
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 // ConnectDB estabelece uma conexão com o banco de dados usando as configurações do viper
 func ConnectDB() (*gorm.DB, error) {
 	// Usar as configurações já carregadas pelo viper
-	host := viper.GetString("DB_HOST")
-	port := viper.GetString("DB_PORT")
-	user := viper.GetString("DB_USER")
-	password := viper.GetString("DB_PASSWORD")
-	dbname := viper.GetString("DB_NAME")
+	driver := strings.ToLower(viper.GetString("DB_DRIVER"))
+	if driver == "" {
+		driver = DriverMySQL
+	}
 
-	// Construir a string de conexão DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&parseTime=True&loc=Local",
-		user, password, host, port, dbname)
+	dialector, err := abrirDialector(driver)
+	if err != nil {
+		return nil, err
+	}
 
 	// Conectar ao banco de dados
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
 
+	if driver == DriverSQLite && memoriaSQLite(viper.GetString("DB_NAME")) {
+		// Cada conexão com ":memory:" abre um banco novo e vazio; uma única conexão mantém todos no mesmo banco
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
 	return db, nil
 }
+
+// abrirDialector monta a string de conexão do banco escolhido em DB_DRIVER
+func abrirDialector(driver string) (gorm.Dialector, error) {
+	host := viper.GetString("DB_HOST")
+	port := viper.GetString("DB_PORT")
+	user := viper.GetString("DB_USER")
+	password := viper.GetString("DB_PASSWORD")
+	dbname := viper.GetString("DB_NAME")
+
+	switch driver {
+	case DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&parseTime=True&loc=Local",
+			user, password, host, port, dbname)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		sslmode := viper.GetString("DB_SSLMODE")
+		if sslmode == "" {
+			sslmode = "disable"
+		}
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			host, port, user, password, dbname, sslmode)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		// DB_NAME é o caminho do arquivo (ou ":memory:"); as chaves estrangeiras ficam desligadas por padrão
+		// no SQLite, e a espera evita "database is locked" quando duas requisições gravam juntas
+		if dbname == "" {
+			dbname = "oficina.db"
+		}
+		dsn := dbname + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+		if memoriaSQLite(dbname) {
+			dsn = ":memory:?_pragma=foreign_keys(1)"
+		}
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("DB_DRIVER inválido: %s (use mysql, postgres ou sqlite)", driver)
+	}
+}
+
+// memoriaSQLite indica se o banco SQLite fica apenas em memória
+func memoriaSQLite(dbname string) bool {
+	return dbname == ":memory:"
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"OficinaMecanica/models"
+
+	"github.com/glebarez/sqlite"
+	"github.com/spf13/viper"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// configurar grava as variáveis de conexão no viper e as descarta ao fim do teste
+func configurar(t *testing.T, valores map[string]string) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	for chave, valor := range valores {
+		viper.Set(chave, valor)
+	}
+}
+
+// conectar abre e migra o banco configurado, fechando a conexão ao fim do teste
+func conectar(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := ConnectDB()
+	if err != nil {
+		t.Fatalf("erro ao conectar: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("erro ao obter a conexão: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := SetupMigrations(db); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+	return db
+}
+
+func TestAbrirDialectorPorDriver(t *testing.T) {
+	casos := []struct {
+		nome     string
+		valores  map[string]string
+		driver   string
+		dsn      string
+		invalido bool
+	}{
+		{
+			nome:    "mysql",
+			valores: map[string]string{"DB_HOST": "db", "DB_PORT": "3306", "DB_USER": "oficina", "DB_PASSWORD": "segredo", "DB_NAME": "oficina"},
+			driver:  DriverMySQL,
+			dsn:     "oficina:segredo@tcp(db:3306)/oficina?charset=utf8&parseTime=True&loc=Local",
+		},
+		{
+			nome:    "postgres sem sslmode",
+			valores: map[string]string{"DB_HOST": "db", "DB_PORT": "5432", "DB_USER": "oficina", "DB_PASSWORD": "segredo", "DB_NAME": "oficina"},
+			driver:  DriverPostgres,
+			dsn:     "host=db port=5432 user=oficina password=segredo dbname=oficina sslmode=disable",
+		},
+		{
+			nome:    "postgres com sslmode",
+			valores: map[string]string{"DB_HOST": "db", "DB_NAME": "oficina", "DB_SSLMODE": "require"},
+			driver:  DriverPostgres,
+			dsn:     "sslmode=require",
+		},
+		{
+			nome:    "sqlite sem nome usa oficina.db",
+			valores: map[string]string{},
+			driver:  DriverSQLite,
+			dsn:     "oficina.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate",
+		},
+		{
+			nome:    "sqlite em memória",
+			valores: map[string]string{"DB_NAME": ":memory:"},
+			driver:  DriverSQLite,
+			dsn:     ":memory:?_pragma=foreign_keys(1)",
+		},
+		{
+			nome:     "driver desconhecido",
+			valores:  map[string]string{},
+			driver:   "oracle",
+			invalido: true,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			configurar(t, caso.valores)
+			dialector, err := abrirDialector(caso.driver)
+			if caso.invalido {
+				if err == nil {
+					t.Fatal("o driver desconhecido deveria ser recusado")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro ao montar o dialector: %v", err)
+			}
+
+			var dsn string
+			switch d := dialector.(type) {
+			case *mysql.Dialector:
+				dsn = d.DSN
+			case *postgres.Dialector:
+				dsn = d.DSN
+			case *sqlite.Dialector:
+				dsn = d.DSN
+			}
+			if dialector.Name() != caso.driver || !strings.Contains(dsn, caso.dsn) {
+				t.Errorf("dialector %s com DSN %q, esperado %s com %q", dialector.Name(), dsn, caso.driver, caso.dsn)
+			}
+		})
+	}
+}
+
+func TestConnectDBRecusaDriverInvalido(t *testing.T) {
+	configurar(t, map[string]string{"DB_DRIVER": "Oracle"})
+	if _, err := ConnectDB(); err == nil {
+		t.Error("um DB_DRIVER desconhecido deveria impedir a conexão")
+	}
+}
+
+func TestSQLiteEmMemoriaMigraNumaUnicaConexao(t *testing.T) {
+	configurar(t, map[string]string{"DB_DRIVER": "SQLite", "DB_NAME": ":memory:"})
+	db := conectar(t)
+
+	sqlDB, _ := db.DB()
+	if maximo := sqlDB.Stats().MaxOpenConnections; maximo != 1 {
+		t.Errorf("máximo de conexões = %d, esperado 1 para todas enxergarem o mesmo banco", maximo)
+	}
+	for _, tabela := range []string{"usuarios", "ordens_servico", "leituras_km", "feeds_agenda"} {
+		if !db.Migrator().HasTable(tabela) {
+			t.Errorf("a tabela %s deveria existir depois das migrações", tabela)
+		}
+	}
+
+	var chavesEstrangeiras int
+	db.Raw("PRAGMA foreign_keys").Scan(&chavesEstrangeiras)
+	if chavesEstrangeiras != 1 {
+		t.Error("as chaves estrangeiras deveriam estar ligadas no SQLite")
+	}
+}
+
+func TestSQLiteEmArquivoMantemOsDados(t *testing.T) {
+	arquivo := filepath.Join(t.TempDir(), "oficina.db")
+	configurar(t, map[string]string{"DB_DRIVER": DriverSQLite, "DB_NAME": arquivo})
+
+	db := conectar(t)
+	if err := db.Create(&models.Cliente{Nome: "João Prata", TipoPessoa: models.TipoPessoaFisica}).Error; err != nil {
+		t.Fatalf("erro ao gravar o cliente: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+
+	// Reabrir o arquivo não reaplica as migrações nem perde os registros
+	db = conectar(t)
+	var total int64
+	db.Model(&models.Cliente{}).Count(&total)
+	if total != 1 {
+		t.Errorf("clientes = %d depois de reabrir o arquivo, esperado 1", total)
+	}
+
+	for _, args := range [][]string{{"sideways"}, {"up", "zero"}, {"down", "0"}} {
+		if err := ExecutarComandoMigracao(db, args); err == nil {
+			t.Errorf("o comando migrate %v deveria ser recusado", args)
+		}
+	}
+	if err := ExecutarComandoMigracao(db, []string{"up"}); err != nil {
+		t.Errorf("migrate up sem pendências deveria passar: %v", err)
+	}
+}
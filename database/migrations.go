@@ -1,119 +1,104 @@
 package database
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
 	"time"
 
-	"OficinaMecanica/models"
+	"OficinaMecanica/migrations"
 
 	"gorm.io/gorm"
 )
 
-// VerifyTablesExist verifica se as principais tabelas já existem no banco de dados
-func VerifyTablesExist(db *gorm.DB) (bool, error) {
-	var count int64
-	requiredTables := []string{"usuarios", "clientes", "funcionarios", "veiculos", "estoques", "ordem_servicos", "item_ordem_servicos"}
-
-	for _, table := range requiredTables {
-		result := db.Raw("SELECT COUNT(1) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", table).Scan(&count)
-		if result.Error != nil {
-			return false, result.Error
-		}
-
-		// Se alguma tabela não existir, retorna false
-		if count == 0 {
-			return false, nil
-		}
-	}
-
-	// Todas as tabelas existem
-	return true, nil
-}
-
-// SetupMigrations configura e executa as migrações do banco de dados
+// SetupMigrations aplica as migrações pendentes do banco de dados
 func SetupMigrations(db *gorm.DB) error {
-	// Verificar se as tabelas já existem
-	tablesExist, err := VerifyTablesExist(db)
-	if err != nil {
-		return err
-	}
-
-	if tablesExist {
-		log.Println("Tabelas já existem. Ignorando migrações.")
-		return nil
-	}
-
-	// Configurações para otimização das migrações
-	db = db.Set("gorm:table_options", "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci")
-
-	// Log de início
 	start := time.Now()
-	log.Println("Iniciando migrações do banco de dados...")
-
-	// Executando migrações em ordem apropriada para respeitar dependências
-	err = db.AutoMigrate(
-		// 1. Tabelas independentes primeiro
-		&models.Usuario{},
-		&models.Cliente{},
-		&models.Estoque{},
-
-		// 2. Tabelas com dependências
-		&models.Funcionario{},
-		&models.Veiculo{},
-
-		// 3. Tabelas que dependem das anteriores
-		&models.OrdemServico{},
-		&models.ItemOrdemServico{},
-	)
+	log.Println("Verificando migrações do banco de dados...")
 
-	if err != nil {
+	if err := migrations.NewMigrador(db, migrations.Todas()).Migrar(0); err != nil {
 		log.Printf("Erro nas migrações: %v", err)
 		return err
 	}
 
-	// Adicionar índices para otimização de consultas frequentes
-	err = addOptimizationIndexes(db)
-	if err != nil {
-		log.Printf("Erro ao adicionar índices: %v", err)
-		return err
-	}
-
-	// Log de conclusão
 	log.Printf("Migrações concluídas em %v", time.Since(start))
 	return nil
 }
 
-// addOptimizationIndexes adiciona índices para otimização de consultas comuns
-func addOptimizationIndexes(db *gorm.DB) error {
-	// Verificar se índice já existe antes de criar
-	createIndexIfNotExists := func(indexName, tableName, columns string) error {
-		var count int64
-		// Verificar se o índice já existe
-		db.Raw("SELECT COUNT(1) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?",
-			tableName, indexName).Count(&count)
+// ExecutarComandoMigracao executa o subcomando "migrate" da linha de comando:
+//
+//	migrate up [versao]   aplica as migrações pendentes (até a versão, se informada)
+//	migrate down [passos] desfaz as últimas migrações aplicadas (1 por padrão)
+//	migrate status        lista as migrações e a situação de cada uma
+func ExecutarComandoMigracao(db *gorm.DB, args []string) error {
+	migrador := migrations.NewMigrador(db, migrations.Todas())
+
+	comando := "up"
+	if len(args) > 0 {
+		comando = args[0]
+	}
 
-		if count == 0 {
-			// Índice não existe, então cria
-			sql := "CREATE INDEX " + indexName + " ON " + tableName + "(" + columns + ")"
-			if err := db.Exec(sql).Error; err != nil {
-				return err
+	switch comando {
+	case "up":
+		var versao int64
+		if len(args) > 1 {
+			v, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || v < 1 {
+				return errors.New("versão inválida")
 			}
+			versao = v
 		}
+		return migrador.Migrar(versao)
+	case "down":
+		passos := 1
+		if len(args) > 1 {
+			p, err := strconv.Atoi(args[1])
+			if err != nil || p < 1 {
+				return errors.New("quantidade de migrações inválida")
+			}
+			passos = p
+		}
+		return migrador.Reverter(passos)
+	case "status":
+		situacoes, err := migrador.Situacao()
+		if err != nil {
+			return err
+		}
+		imprimirSituacaoMigracoes(situacoes)
 		return nil
+	default:
+		return fmt.Errorf("comando de migração desconhecido: %s (use up, down ou status)", comando)
 	}
+}
 
-	// Criar os índices se não existirem
-	if err := createIndexIfNotExists("idx_veiculos_placa", "veiculos", "placa"); err != nil {
-		return err
-	}
+// imprimirSituacaoMigracoes mostra a tabela de migrações no terminal
+func imprimirSituacaoMigracoes(situacoes []migrations.SituacaoMigracao) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSÃO\tDESCRIÇÃO\tSITUAÇÃO\tAPLICADA EM\tREVERSÍVEL")
+	for _, s := range situacoes {
+		situacao := "pendente"
+		switch {
+		case s.Desconhecida:
+			situacao = "desconhecida"
+		case s.ChecksumDivergente:
+			situacao = "alterada"
+		case s.Aplicada:
+			situacao = "aplicada"
+		}
 
-	if err := createIndexIfNotExists("idx_clientes_nome", "clientes", "nome"); err != nil {
-		return err
-	}
+		aplicadaEm := "-"
+		if s.AplicadaEm != nil {
+			aplicadaEm = s.AplicadaEm.Format("02/01/2006 15:04:05")
+		}
 
-	if err := createIndexIfNotExists("idx_usuarios_email", "usuarios", "email"); err != nil {
-		return err
+		reversivel := "não"
+		if s.Reversivel {
+			reversivel = "sim"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.Versao, s.Descricao, situacao, aplicadaEm, reversivel)
 	}
-
-	return nil
+	w.Flush()
 }
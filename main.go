@@ -5,6 +5,7 @@ import (
 	"OficinaMecanica/database"
 	"OficinaMecanica/routes"
 	"log"
+	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -32,6 +33,14 @@ func main() {
 		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
 	}
 
+	// Subcomando "migrate": executa apenas as migrações (up, down, status) sem subir o servidor
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.ExecutarComandoMigracao(db, os.Args[2:]); err != nil {
+			log.Fatalf("Erro ao executar migrações: %v", err)
+		}
+		return
+	}
+
 	// 5. Executar migrations
 	if err := database.SetupMigrations(db); err != nil {
 		log.Fatalf("Erro ao executar migrações: %v", err)
@@ -54,7 +63,7 @@ func main() {
 	log.Printf("Ambiente: %s, Servidor na porta: %s", config.Environment, config.ServerPort)
 
 	// 9. Configurar rotas
-	routes.SetupRoutes(r)
+	routes.SetupRoutes(r, db)
 
 	// 10. Servir arquivos estáticos para uploads de avatar
 	r.Static("/uploads", "./uploads")
@@ -77,7 +86,7 @@ func setupLogs(env string) {
 		log.Println("======================================================")
 		log.Println("📌 RESUMO DO PROJETO:")
 		log.Println(" • Backend em Go com Gin Framework")
-		log.Println(" • Banco de dados MySQL com GORM ORM")
+		log.Println(" • Banco de dados MySQL, PostgreSQL ou SQLite com GORM ORM")
 		log.Println(" • Autenticação via JWT")
 		log.Println(" • API RESTful para gestão de oficina mecânica")
 		log.Println("======================================================")
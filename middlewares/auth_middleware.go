@@ -5,12 +5,16 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 
-	"OficinaMecanica/configs"
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+	"OficinaMecanica/utils"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware exige um token de acesso válido cuja sessão continue aberta: o logout,
+// a desativação do usuário e o encerramento das sessões invalidam o token antes da expiração.
+// O usuário do token é carregado e recusado se a conta não puder mais acessar o sistema
+func AuthMiddleware(sessaoService services.SessaoService, usuarioService services.UsuarioService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -21,27 +25,61 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 
-		config, _ := configs.LoadConfig()
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.JWTSecret), nil
-		})
-
+		token, err := utils.ValidarToken(tokenString)
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Não foi possível obter as claims do token"})
+		// Tokens emitidos antes das sessões não têm a chave e exigem um novo login
+		usuarioID, okUsuario := utils.ExtrairUserID(token)
+		chaveSessao, okSessao := utils.ExtrairSessao(token)
+		if !okUsuario || !okSessao || usuarioID <= 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+			c.Abort()
+			return
+		}
+		if err := sessaoService.Validar(chaveSessao, uint(usuarioID)); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sessão encerrada; faça login novamente"})
+			c.Abort()
+			return
+		}
+
+		usuario, err := usuarioService.BuscarAutenticado(uint(usuarioID))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário inativo ou inexistente"})
 			c.Abort()
 			return
 		}
 
-		// Armazenar o ID do usuário no contexto para acesso posterior
-		c.Set("userID", claims["user_id"])
+		// Armazenar o usuário e a sessão no contexto para acesso posterior
+		c.Set("usuario", usuario)
+		c.Set("sessao", chaveSessao)
 
 		c.Next()
 	}
 }
+
+// UsuarioDoContexto retorna o usuário autenticado carregado pelo AuthMiddleware
+func UsuarioDoContexto(c *gin.Context) (*models.Usuario, bool) {
+	valor, existe := c.Get("usuario")
+	if !existe {
+		return nil, false
+	}
+	usuario, ok := valor.(*models.Usuario)
+	if !ok || usuario == nil || usuario.ID == 0 {
+		return nil, false
+	}
+	return usuario, true
+}
+
+// SessaoDoContexto retorna a chave da sessão do token de acesso, armazenada pelo AuthMiddleware
+func SessaoDoContexto(c *gin.Context) (string, bool) {
+	valor, existe := c.Get("sessao")
+	if !existe {
+		return "", false
+	}
+	chave, ok := valor.(string)
+	return chave, ok && chave != ""
+}
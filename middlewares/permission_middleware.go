@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/services"
+)
+
+// RequirePermission garante que o usuário autenticado possua a permissão informada.
+// Deve ser usado depois do AuthMiddleware, que define o usuário autenticado no contexto.
+func RequirePermission(permissaoService services.PermissaoService, permissao string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usuarioID, ok := UsuarioIDDoContexto(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+			c.Abort()
+			return
+		}
+
+		permitido, err := permissaoService.UsuarioTemPermissao(usuarioID, permissao)
+		if err != nil || !permitido {
+			negarAcesso(c, permissao)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermissionOrSelf libera a rota quando o usuário possui a permissão
+// ou quando o parâmetro de rota informado corresponde ao seu próprio ID
+// (ex.: um usuário pode trocar o próprio avatar sem poder editar os demais)
+func RequirePermissionOrSelf(permissaoService services.PermissaoService, permissao, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usuarioID, ok := UsuarioIDDoContexto(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+			c.Abort()
+			return
+		}
+
+		if id, err := strconv.Atoi(c.Param(param)); err == nil && uint(id) == usuarioID {
+			c.Next()
+			return
+		}
+
+		permitido, err := permissaoService.UsuarioTemPermissao(usuarioID, permissao)
+		if err != nil || !permitido {
+			negarAcesso(c, permissao)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireGestaoUsuario impede que quem não é admin altere a conta de um admin indicada pelo parâmetro de rota.
+// Complementa RequirePermission, que confere apenas a permissão sobre usuários em geral
+func RequireGestaoUsuario(permissaoService services.PermissaoService, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usuarioID, ok := UsuarioIDDoContexto(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+			c.Abort()
+			return
+		}
+
+		// Um ID inválido é recusado pelo próprio handler
+		id, err := strconv.Atoi(c.Param(param))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		if err := permissaoService.VerificarGestaoUsuario(usuarioID, uint(id)); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// UsuarioIDDoContexto extrai o ID do usuário autenticado armazenado pelo AuthMiddleware
+func UsuarioIDDoContexto(c *gin.Context) (uint, bool) {
+	usuario, ok := UsuarioDoContexto(c)
+	if !ok {
+		return 0, false
+	}
+	return usuario.ID, true
+}
+
+// negarAcesso interrompe a requisição com status 403
+func negarAcesso(c *gin.Context, permissao string) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":     "Acesso negado: permissão insuficiente",
+		"permissao": permissao,
+	})
+	c.Abort()
+}
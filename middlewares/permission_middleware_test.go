@@ -0,0 +1,149 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/services"
+)
+
+// permissaoServiceFalso concede as permissões listadas por usuário
+type permissaoServiceFalso struct {
+	services.PermissaoService
+	permissoes map[uint][]string
+	admins     map[uint]bool
+}
+
+func (f *permissaoServiceFalso) UsuarioTemPermissao(usuarioID uint, permissao string) (bool, error) {
+	codigos, ok := f.permissoes[usuarioID]
+	if !ok {
+		return false, errors.New("usuário não encontrado")
+	}
+	for _, codigo := range codigos {
+		if codigo == permissao {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// VerificarGestaoUsuario recusa que quem não é admin altere um admin
+func (f *permissaoServiceFalso) VerificarGestaoUsuario(usuarioID, alvoID uint) error {
+	if usuarioID != alvoID && f.admins[alvoID] && !f.admins[usuarioID] {
+		return errors.New("apenas um administrador pode alterar a conta de outro administrador")
+	}
+	return nil
+}
+
+// executarRota monta uma rota com o middleware informado e simula o AuthMiddleware
+// definindo o usuário autenticado no contexto
+func executarRota(t *testing.T, middleware gin.HandlerFunc, rota, caminho string, usuario interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET(rota, func(c *gin.Context) {
+		if usuario != nil {
+			c.Set("usuario", usuario)
+		}
+		c.Next()
+	}, middleware, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, caminho, nil))
+	return w
+}
+
+func TestRequirePermission(t *testing.T) {
+	servico := &permissaoServiceFalso{permissoes: map[uint][]string{
+		1: {models.PermClientesLer},
+		2: {},
+	}}
+	middleware := RequirePermission(servico, models.PermClientesLer)
+
+	casos := []struct {
+		nome     string
+		usuario  interface{}
+		esperado int
+	}{
+		{"com permissão", &models.Usuario{ID: 1}, http.StatusOK},
+		{"sem permissão", &models.Usuario{ID: 2}, http.StatusForbidden},
+		{"usuário inexistente", &models.Usuario{ID: 3}, http.StatusForbidden},
+		{"sem usuário no contexto", nil, http.StatusUnauthorized},
+		{"usuário com tipo inesperado", float64(1), http.StatusUnauthorized},
+		{"usuário sem ID", &models.Usuario{ID: 0}, http.StatusUnauthorized},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			w := executarRota(t, middleware, "/clientes", "/clientes", caso.usuario)
+			if w.Code != caso.esperado {
+				t.Errorf("status = %d, esperado %d", w.Code, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestRequirePermissionOrSelf(t *testing.T) {
+	servico := &permissaoServiceFalso{permissoes: map[uint][]string{
+		1: {models.PermUsuariosEscrever},
+		2: {},
+	}}
+	middleware := RequirePermissionOrSelf(servico, models.PermUsuariosEscrever, "id")
+
+	casos := []struct {
+		nome     string
+		usuario  interface{}
+		caminho  string
+		esperado int
+	}{
+		{"o próprio usuário", &models.Usuario{ID: 2}, "/usuarios/2", http.StatusOK},
+		{"outro usuário sem permissão", &models.Usuario{ID: 2}, "/usuarios/1", http.StatusForbidden},
+		{"outro usuário com permissão", &models.Usuario{ID: 1}, "/usuarios/2", http.StatusOK},
+		{"parâmetro inválido sem permissão", &models.Usuario{ID: 2}, "/usuarios/abc", http.StatusForbidden},
+		{"sem usuário no contexto", nil, "/usuarios/2", http.StatusUnauthorized},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			w := executarRota(t, middleware, "/usuarios/:id", caso.caminho, caso.usuario)
+			if w.Code != caso.esperado {
+				t.Errorf("status = %d, esperado %d", w.Code, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestRequireGestaoUsuario(t *testing.T) {
+	servico := &permissaoServiceFalso{admins: map[uint]bool{1: true, 3: true}}
+	middleware := RequireGestaoUsuario(servico, "id")
+
+	casos := []struct {
+		nome     string
+		usuario  interface{}
+		caminho  string
+		esperado int
+	}{
+		{"admin altera outro admin", &models.Usuario{ID: 1}, "/usuarios/3", http.StatusOK},
+		{"gerente altera atendente", &models.Usuario{ID: 2}, "/usuarios/4", http.StatusOK},
+		{"gerente altera admin", &models.Usuario{ID: 2}, "/usuarios/1", http.StatusForbidden},
+		{"parâmetro inválido fica para o handler", &models.Usuario{ID: 2}, "/usuarios/abc", http.StatusOK},
+		{"sem usuário no contexto", nil, "/usuarios/1", http.StatusUnauthorized},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			w := executarRota(t, middleware, "/usuarios/:id", caso.caminho, caso.usuario)
+			if w.Code != caso.esperado {
+				t.Errorf("status = %d, esperado %d", w.Code, caso.esperado)
+			}
+		})
+	}
+}
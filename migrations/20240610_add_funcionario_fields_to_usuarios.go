@@ -1,19 +1,39 @@
 package migrations
 
 import (
+	"OficinaMecanica/models"
+
 	"gorm.io/gorm"
 )
 
+// camposFuncionarioUsuarios são as colunas de dados funcionais adicionadas à tabela usuarios
+var camposFuncionarioUsuarios = []string{"DataAdmissao", "Status", "Ferias"}
+
+// AddFuncionarioFieldsToUsuarios adiciona as colunas de admissão, status e férias aos usuários.
+// Usa o Migrator do GORM em vez de "ADD COLUMN IF NOT EXISTS", que só existe no MariaDB
 func AddFuncionarioFieldsToUsuarios(db *gorm.DB) error {
-	// Adiciona as colunas se não existirem
-	if err := db.Exec(`ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS data_admissao TIMESTAMP NULL`).Error; err != nil {
-		return err
-	}
-	if err := db.Exec(`ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS status VARCHAR(50) NULL`).Error; err != nil {
-		return err
+	migrator := db.Migrator()
+	for _, campo := range camposFuncionarioUsuarios {
+		if migrator.HasColumn(&models.Usuario{}, campo) {
+			continue
+		}
+		if err := migrator.AddColumn(&models.Usuario{}, campo); err != nil {
+			return err
+		}
 	}
-	if err := db.Exec(`ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS ferias BOOLEAN DEFAULT FALSE`).Error; err != nil {
-		return err
+	return nil
+}
+
+// RemoveFuncionarioFieldsFromUsuarios desfaz AddFuncionarioFieldsToUsuarios
+func RemoveFuncionarioFieldsFromUsuarios(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, campo := range camposFuncionarioUsuarios {
+		if !migrator.HasColumn(&models.Usuario{}, campo) {
+			continue
+		}
+		if err := migrator.DropColumn(&models.Usuario{}, campo); err != nil {
+			return err
+		}
 	}
 	return nil
 }
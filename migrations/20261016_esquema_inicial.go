@@ -0,0 +1,534 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CriarEsquemaInicial cria as tabelas do sistema como estavam na versão 1 do esquema. Em bancos já
+// existentes o AutoMigrate apenas acrescenta as tabelas e colunas que faltarem, sem apagar dados.
+// As estruturas abaixo são cópias congeladas dos modelos: a soma de verificação deste arquivo cobre
+// o esquema criado, e mudanças posteriores nos modelos precisam entrar como novas migrações
+func CriarEsquemaInicial(db *gorm.DB) error {
+	return db.AutoMigrate(
+		// 1. Tabelas independentes primeiro
+		&usuarioV1{},
+		&clienteV1{},
+		&fornecedorV1{},
+		&servicoV1{},
+		&estoqueV1{},
+		&permissaoV1{},
+		&cargoPermissaoV1{},
+		&boxV1{},
+		&feriadoV1{},
+
+		// 2. Tabelas com dependências
+		&funcionarioV1{},
+		&veiculoV1{},
+		&leituraKmV1{},
+		&planoManutencaoV1{},
+
+		// 3. Tabelas que dependem das anteriores
+		&ordemServicoV1{},
+		&itemOrdemServicoV1{},
+		&servicoOrdemServicoV1{},
+		&pagamentoV1{},
+		&caixaV1{},
+		&movimentacaoCaixaV1{},
+		&conferenciaCaixaV1{},
+		&agendamentoV1{},
+		&orcamentoV1{},
+		&itemOrcamentoV1{},
+		&movimentacaoEstoqueV1{},
+		&pedidoCompraV1{},
+		&itemPedidoCompraV1{},
+		&fornecedorItemV1{},
+	)
+}
+
+// Modelos na versão 1 do esquema; não altere estas estruturas
+
+type usuarioV1 struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement;not null"`
+	Nome         string `gorm:"not null;size:100"`
+	Email        string `gorm:"not null;unique;size:100"`
+	Senha        string `gorm:"not null;size:100"`
+	Cargo        string `gorm:"size:20;default:'usuário'"`
+	Ativo        bool   `gorm:"default:true"`
+	UltimoLogin  *time.Time
+	CreatedAt    time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	Avatar       string
+	DataAdmissao *time.Time     `gorm:"column:data_admissao"`
+	Status       string         `gorm:"column:status"`
+	Ferias       bool           `gorm:"column:ferias"`
+	Funcionario  *funcionarioV1 `gorm:"foreignKey:UsuarioID"`
+}
+
+func (usuarioV1) TableName() string { return "usuarios" }
+
+type clienteV1 struct {
+	ID                uint           `gorm:"primaryKey;autoIncrement;not null"`
+	Nome              string         `gorm:"not null;size:100;index"`
+	TipoPessoa        string         `gorm:"not null;default:'fisica';size:10"`
+	Documento         *string        `gorm:"size:18;uniqueIndex"`
+	RazaoSocial       string         `gorm:"size:150"`
+	InscricaoEstadual string         `gorm:"size:20"`
+	Email             *string        `gorm:"size:100"`
+	Telefone          *string        `gorm:"size:20"`
+	Endereco          string         `gorm:"size:255"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+	Veiculos          []veiculoV1    `gorm:"foreignKey:ClienteID"`
+}
+
+func (clienteV1) TableName() string { return "clientes" }
+
+type fornecedorV1 struct {
+	ID               uint           `gorm:"primaryKey;autoIncrement;not null"`
+	Nome             string         `gorm:"not null;size:100;uniqueIndex"`
+	CNPJ             *string        `gorm:"size:18;uniqueIndex"`
+	Contato          string         `gorm:"size:100"`
+	Email            string         `gorm:"size:100"`
+	Telefone         string         `gorm:"size:20"`
+	PrazoEntregaDias int            `gorm:"default:0"`
+	Observacoes      string         `gorm:"type:text"`
+	Ativo            bool           `gorm:"default:true"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
+}
+
+func (fornecedorV1) TableName() string { return "fornecedores" }
+
+type servicoV1 struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement;not null"`
+	Nome        string         `gorm:"not null;size:100;uniqueIndex"`
+	Descricao   string         `gorm:"type:text"`
+	Categoria   string         `gorm:"size:50;index"`
+	HorasPadrao float64        `gorm:"type:decimal(6,2);not null;default:1"`
+	ValorHora   float64        `gorm:"type:decimal(10,2);not null;default:0.00"`
+	Ativo       bool           `gorm:"default:true"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (servicoV1) TableName() string { return "servicos" }
+
+type estoqueV1 struct {
+	ID                  uint           `gorm:"primaryKey;autoIncrement;not null"`
+	Nome                string         `gorm:"not null;size:100;index"`
+	Codigo              string         `gorm:"size:100;uniqueIndex"`
+	Descricao           string         `gorm:"type:text"`
+	Categoria           string         `gorm:"size:50;index"`
+	Quantidade          int            `gorm:"default:0;not null"`
+	QuantidadeReservada int            `gorm:"default:0;not null"`
+	EstoqueMinimo       int            `gorm:"default:5"`
+	PrecoUnitario       float64        `gorm:"type:decimal(10,2);not null;default:0.00"`
+	PrecoVenda          float64        `gorm:"type:decimal(10,2);not null;default:0.00"`
+	FornecedorID        *uint          `gorm:"index"`
+	Fornecedor          *fornecedorV1  `gorm:"foreignKey:FornecedorID"`
+	Status              string         `gorm:"size:20;default:'disponível';index"`
+	Observacoes         string         `gorm:"type:text"`
+	CriadoEm            time.Time      `gorm:"autoCreateTime"`
+	AtualizadoEm        time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+}
+
+func (estoqueV1) TableName() string { return "estoque" }
+
+type permissaoV1 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;not null"`
+	Codigo    string    `gorm:"not null;size:100;uniqueIndex"`
+	Descricao string    `gorm:"size:255"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (permissaoV1) TableName() string { return "permissoes" }
+
+type cargoPermissaoV1 struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement;not null"`
+	Cargo           string    `gorm:"not null;size:20;uniqueIndex:idx_cargo_permissao"`
+	PermissaoCodigo string    `gorm:"not null;size:100;uniqueIndex:idx_cargo_permissao"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+func (cargoPermissaoV1) TableName() string { return "cargo_permissoes" }
+
+type boxV1 struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement;not null"`
+	Nome      string         `gorm:"not null;size:50;uniqueIndex"`
+	Descricao string         `gorm:"size:255"`
+	Ativo     bool           `gorm:"not null;default:true"`
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (boxV1) TableName() string { return "boxes" }
+
+type feriadoV1 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;not null"`
+	Data      time.Time `gorm:"type:date;not null;uniqueIndex"`
+	Descricao string    `gorm:"not null;size:100"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (feriadoV1) TableName() string { return "feriados" }
+
+type funcionarioV1 struct {
+	ID              uint            `gorm:"primaryKey;autoIncrement;not null"`
+	Nome            string          `gorm:"not null;size:100;index"`
+	Telefone        string          `gorm:"not null;size:20"`
+	TelefoneReserva *string         `gorm:"size:20"`
+	CPF             string          `gorm:"not null;unique;size:14;index"`
+	Endereco        string          `gorm:"size:255"`
+	DataNascimento  time.Time       `gorm:"column:data_nascimento"`
+	DataAdmissao    time.Time       `gorm:"column:data_admissao"`
+	Salario         decimal.Decimal `gorm:"type:decimal(10,2)"`
+	Observacoes     string          `gorm:"type:text"`
+	Cargo           string          `gorm:"size:50;index"`
+	UsuarioID       *uint           `gorm:"uniqueIndex"`
+	Usuario         *usuarioV1      `gorm:"foreignKey:UsuarioID"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt  `gorm:"index"`
+}
+
+func (funcionarioV1) TableName() string { return "funcionarios" }
+
+type veiculoV1 struct {
+	ID           uint           `gorm:"primaryKey;autoIncrement;not null"`
+	Marca        string         `gorm:"size:50;index"`
+	Modelo       string         `gorm:"size:100;index"`
+	Placa        string         `gorm:"not null;unique;size:10;index"`
+	Cor          string         `gorm:"size:30"`
+	AnoModelo    string         `gorm:"column:ano_modelo;size:10"`
+	Chassi       *string        `gorm:"size:17;uniqueIndex"`
+	KmAtual      int            `gorm:"column:km_atual;not null;default:0"`
+	ClienteID    uint           `gorm:"not null;index"`
+	OrdemServico string         `gorm:"column:ordem_servico;size:30;not null"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (veiculoV1) TableName() string { return "veiculos" }
+
+type leituraKmV1 struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement;not null"`
+	VeiculoID      uint      `gorm:"not null;index:idx_leitura_km_veiculo_data"`
+	Km             int       `gorm:"not null"`
+	DataLeitura    time.Time `gorm:"not null;index:idx_leitura_km_veiculo_data"`
+	Origem         string    `gorm:"not null;size:20"`
+	OrdemServicoID *uint     `gorm:"index"`
+	UsuarioID      *uint
+	CriadoEm       time.Time `gorm:"autoCreateTime"`
+}
+
+func (leituraKmV1) TableName() string { return "leituras_km" }
+
+type planoManutencaoV1 struct {
+	ID                   uint       `gorm:"primaryKey;autoIncrement;not null"`
+	VeiculoID            uint       `gorm:"not null;index"`
+	Veiculo              *veiculoV1 `gorm:"foreignKey:VeiculoID"`
+	Descricao            string     `gorm:"not null;size:100"`
+	ServicoID            *uint      `gorm:"index"`
+	IntervaloKm          int        `gorm:"not null;default:0"`
+	IntervaloMeses       int        `gorm:"not null;default:0"`
+	UltimaRealizacaoKm   int        `gorm:"not null;default:0"`
+	UltimaRealizacaoData *time.Time
+	Ativo                bool           `gorm:"not null;default:true"`
+	Observacoes          string         `gorm:"type:text"`
+	CreatedAt            time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt `gorm:"index"`
+}
+
+func (planoManutencaoV1) TableName() string { return "planos_manutencao" }
+
+type ordemServicoV1 struct {
+	ID                 uint           `gorm:"primaryKey;autoIncrement;not null"`
+	VeiculoID          uint           `gorm:"not null;index"`
+	Veiculo            veiculoV1      `gorm:"foreignKey:VeiculoID"`
+	ClienteID          uint           `gorm:"not null;index"`
+	Cliente            clienteV1      `gorm:"foreignKey:ClienteID"`
+	FuncionarioID      *uint          `gorm:"index"`
+	Funcionario        *funcionarioV1 `gorm:"foreignKey:FuncionarioID"`
+	NumeroOS           string         `gorm:"size:20;unique;index"`
+	DataEntrada        time.Time      `gorm:"not null"`
+	DataPrevisao       time.Time
+	DataConclusao      *time.Time
+	KmEntrada          int     `gorm:"default:0"`
+	Status             string  `gorm:"not null;default:'aberta';size:20;index"`
+	Descricao          string  `gorm:"type:text"`
+	Diagnostico        string  `gorm:"type:text"`
+	ValorPecas         float64 `gorm:"type:decimal(10,2);default:0"`
+	ValorServico       float64 `gorm:"type:decimal(10,2);default:0"`
+	ValorDesconto      float64 `gorm:"type:decimal(10,2);default:0"`
+	ValorTotal         float64 `gorm:"type:decimal(10,2);default:0"`
+	ValorPago          float64 `gorm:"type:decimal(10,2);default:0"`
+	StatusFinanceiro   string  `gorm:"not null;default:'pendente';size:20;index"`
+	FormaPagamento     string  `gorm:"size:50"`
+	DataEntrega        *time.Time
+	EntregaLiberadaPor *uint
+	MotivoLiberacao    string                  `gorm:"type:text"`
+	Observacoes        string                  `gorm:"type:text"`
+	ServicosRealizados string                  `gorm:"type:text"`
+	CreatedAt          time.Time               `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time               `gorm:"autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt          `gorm:"index"`
+	ItensUtilizados    []itemOrdemServicoV1    `gorm:"foreignKey:OrdemServicoID"`
+	Servicos           []servicoOrdemServicoV1 `gorm:"foreignKey:OrdemServicoID"`
+	Pagamentos         []pagamentoV1           `gorm:"foreignKey:OrdemServicoID"`
+}
+
+func (ordemServicoV1) TableName() string { return "ordens_servico" }
+
+type itemOrdemServicoV1 struct {
+	ID             uint           `gorm:"primaryKey;autoIncrement;not null"`
+	OrdemServicoID uint           `gorm:"not null;index"`
+	OrdemServico   ordemServicoV1 `gorm:"foreignKey:OrdemServicoID"`
+	EstoqueID      uint           `gorm:"not null;index"`
+	Item           estoqueV1      `gorm:"foreignKey:EstoqueID"`
+	Quantidade     int            `gorm:"not null;default:1"`
+	ValorUnitario  float64        `gorm:"type:decimal(10,2);not null"`
+	ValorTotal     float64        `gorm:"type:decimal(10,2);not null"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+}
+
+func (itemOrdemServicoV1) TableName() string { return "itens_ordem_servico" }
+
+type servicoOrdemServicoV1 struct {
+	ID             uint           `gorm:"primaryKey;autoIncrement;not null"`
+	OrdemServicoID uint           `gorm:"not null;index"`
+	ServicoID      *uint          `gorm:"index"`
+	Servico        *servicoV1     `gorm:"foreignKey:ServicoID"`
+	FuncionarioID  *uint          `gorm:"index"`
+	Funcionario    *funcionarioV1 `gorm:"foreignKey:FuncionarioID"`
+	Descricao      string         `gorm:"size:255"`
+	Horas          float64        `gorm:"type:decimal(6,2);not null;default:0"`
+	ValorHora      float64        `gorm:"type:decimal(10,2);not null;default:0.00"`
+	ValorTotal     float64        `gorm:"type:decimal(10,2);not null;default:0.00"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+}
+
+func (servicoOrdemServicoV1) TableName() string { return "servicos_ordem_servico" }
+
+type pagamentoV1 struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement;not null"`
+	OrdemServicoID uint      `gorm:"not null;index"`
+	Forma          string    `gorm:"not null;size:30"`
+	Valor          float64   `gorm:"type:decimal(10,2);not null"`
+	DataPagamento  time.Time `gorm:"not null;index"`
+	Parcelas       int       `gorm:"not null;default:1"`
+	BandeiraCartao string    `gorm:"size:30"`
+	NSU            string    `gorm:"size:50"`
+	RecebidoPorID  *uint     `gorm:"index"`
+	CaixaID        *uint     `gorm:"index"`
+	Observacoes    string    `gorm:"type:text"`
+	MotivoEstorno  string    `gorm:"type:text"`
+	EstornadoPorID *uint
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (pagamentoV1) TableName() string { return "pagamentos" }
+
+type caixaV1 struct {
+	ID                    uint      `gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID             uint      `gorm:"not null;index"`
+	Status                string    `gorm:"not null;default:'aberto';size:20;index"`
+	DataAbertura          time.Time `gorm:"not null;index"`
+	ValorInicial          float64   `gorm:"type:decimal(10,2);not null;default:0"`
+	ObservacoesAbertura   string    `gorm:"type:text"`
+	DataFechamento        *time.Time
+	FechadoPorID          *uint
+	ObservacoesFechamento string                `gorm:"type:text"`
+	TotalEsperado         float64               `gorm:"type:decimal(10,2);default:0"`
+	TotalContado          float64               `gorm:"type:decimal(10,2);default:0"`
+	Diferenca             float64               `gorm:"type:decimal(10,2);default:0"`
+	CreatedAt             time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt             time.Time             `gorm:"autoUpdateTime"`
+	DeletedAt             gorm.DeletedAt        `gorm:"index"`
+	Movimentacoes         []movimentacaoCaixaV1 `gorm:"foreignKey:CaixaID"`
+	Conferencias          []conferenciaCaixaV1  `gorm:"foreignKey:CaixaID"`
+}
+
+func (caixaV1) TableName() string { return "caixas" }
+
+type movimentacaoCaixaV1 struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement;not null"`
+	CaixaID        uint      `gorm:"not null;index"`
+	Tipo           string    `gorm:"not null;size:20;index"`
+	Forma          string    `gorm:"not null;size:30"`
+	Valor          float64   `gorm:"type:decimal(10,2);not null"`
+	PagamentoID    *uint     `gorm:"index"`
+	OrdemServicoID *uint     `gorm:"index"`
+	UsuarioID      *uint     `gorm:"index"`
+	Descricao      string    `gorm:"size:255"`
+	CriadoEm       time.Time `gorm:"autoCreateTime"`
+}
+
+func (movimentacaoCaixaV1) TableName() string { return "movimentacoes_caixa" }
+
+type conferenciaCaixaV1 struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement;not null"`
+	CaixaID       uint      `gorm:"not null;uniqueIndex:idx_conferencia_caixa_forma"`
+	Forma         string    `gorm:"not null;size:30;uniqueIndex:idx_conferencia_caixa_forma"`
+	ValorEsperado float64   `gorm:"type:decimal(10,2);not null"`
+	ValorContado  float64   `gorm:"type:decimal(10,2);not null"`
+	Diferenca     float64   `gorm:"type:decimal(10,2);not null"`
+	CriadoEm      time.Time `gorm:"autoCreateTime"`
+}
+
+func (conferenciaCaixaV1) TableName() string { return "conferencias_caixa" }
+
+type agendamentoV1 struct {
+	ID                 uint           `gorm:"primaryKey;autoIncrement;not null"`
+	ClienteID          uint           `gorm:"not null;index"`
+	Cliente            *clienteV1     `gorm:"foreignKey:ClienteID"`
+	VeiculoID          uint           `gorm:"not null;index"`
+	Veiculo            *veiculoV1     `gorm:"foreignKey:VeiculoID"`
+	BoxID              uint           `gorm:"not null;index:idx_agendamento_box_periodo"`
+	Box                *boxV1         `gorm:"foreignKey:BoxID"`
+	FuncionarioID      *uint          `gorm:"index:idx_agendamento_funcionario_periodo"`
+	Funcionario        *funcionarioV1 `gorm:"foreignKey:FuncionarioID"`
+	DataInicio         time.Time      `gorm:"not null;index:idx_agendamento_box_periodo;index:idx_agendamento_funcionario_periodo"`
+	DataFim            time.Time      `gorm:"not null"`
+	Status             string         `gorm:"not null;default:'agendado';size:20;index"`
+	Descricao          string         `gorm:"type:text"`
+	Observacoes        string         `gorm:"type:text"`
+	MotivoCancelamento string         `gorm:"type:text"`
+	OrdemServicoID     *uint          `gorm:"index"`
+	CriadoPorID        *uint
+	CreatedAt          time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt `gorm:"index"`
+}
+
+func (agendamentoV1) TableName() string { return "agendamentos" }
+
+type orcamentoV1 struct {
+	ID               uint       `gorm:"primaryKey;autoIncrement;not null"`
+	Numero           string     `gorm:"size:20;not null;uniqueIndex:idx_orcamento_versao"`
+	Versao           int        `gorm:"not null;default:1;uniqueIndex:idx_orcamento_versao"`
+	ClienteID        uint       `gorm:"not null;index"`
+	Cliente          *clienteV1 `gorm:"foreignKey:ClienteID"`
+	VeiculoID        uint       `gorm:"not null;index"`
+	Veiculo          *veiculoV1 `gorm:"foreignKey:VeiculoID"`
+	OrdemServicoID   *uint      `gorm:"index"`
+	Status           string     `gorm:"not null;default:'pendente';size:20;index"`
+	Descricao        string     `gorm:"type:text"`
+	ValidadeAte      time.Time  `gorm:"index"`
+	ValorServicos    float64    `gorm:"type:decimal(10,2);default:0"`
+	ValorPecas       float64    `gorm:"type:decimal(10,2);default:0"`
+	ValorDesconto    float64    `gorm:"type:decimal(10,2);default:0"`
+	ValorTotal       float64    `gorm:"type:decimal(10,2);default:0"`
+	AprovadoPor      string     `gorm:"size:100"`
+	UsuarioDecisaoID *uint
+	DataDecisao      *time.Time
+	MotivoRejeicao   string            `gorm:"type:text"`
+	UsuarioID        *uint             `gorm:"index"`
+	Observacoes      string            `gorm:"type:text"`
+	Itens            []itemOrcamentoV1 `gorm:"foreignKey:OrcamentoID"`
+	CreatedAt        time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time         `gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt    `gorm:"index"`
+}
+
+func (orcamentoV1) TableName() string { return "orcamentos" }
+
+type itemOrcamentoV1 struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement;not null"`
+	OrcamentoID   uint       `gorm:"not null;index"`
+	Tipo          string     `gorm:"not null;size:20"`
+	EstoqueID     *uint      `gorm:"index"`
+	Item          *estoqueV1 `gorm:"foreignKey:EstoqueID"`
+	ServicoID     *uint      `gorm:"index"`
+	Servico       *servicoV1 `gorm:"foreignKey:ServicoID"`
+	Descricao     string     `gorm:"size:255"`
+	Quantidade    int        `gorm:"not null;default:1"`
+	ValorUnitario float64    `gorm:"type:decimal(10,2);not null;default:0.00"`
+	ValorTotal    float64    `gorm:"type:decimal(10,2);not null;default:0.00"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`
+}
+
+func (itemOrcamentoV1) TableName() string { return "itens_orcamento" }
+
+type movimentacaoEstoqueV1 struct {
+	ID                 uint       `gorm:"primaryKey;autoIncrement;not null"`
+	EstoqueID          uint       `gorm:"not null;index:idx_movimentacao_estoque_data"`
+	Estoque            *estoqueV1 `gorm:"foreignKey:EstoqueID"`
+	Tipo               string     `gorm:"not null;size:20;index"`
+	Quantidade         int        `gorm:"not null"`
+	SaldoAnterior      int        `gorm:"not null"`
+	SaldoPosterior     int        `gorm:"not null"`
+	CustoUnitario      float64    `gorm:"type:decimal(10,2);not null;default:0.00"`
+	UsuarioID          *uint      `gorm:"index"`
+	Motivo             string     `gorm:"size:255"`
+	OrdemServicoID     *uint      `gorm:"index"`
+	ItemOrdemServicoID *uint      `gorm:"index"`
+	PedidoCompraID     *uint      `gorm:"index"`
+	CriadoEm           time.Time  `gorm:"autoCreateTime;index:idx_movimentacao_estoque_data"`
+}
+
+func (movimentacaoEstoqueV1) TableName() string { return "movimentacoes_estoque" }
+
+type pedidoCompraV1 struct {
+	ID              uint          `gorm:"primaryKey;autoIncrement;not null"`
+	Numero          string        `gorm:"size:20;unique;index"`
+	FornecedorID    *uint         `gorm:"index"`
+	Fornecedor      *fornecedorV1 `gorm:"foreignKey:FornecedorID"`
+	Status          string        `gorm:"not null;default:'rascunho';size:20;index"`
+	DataEnvio       *time.Time
+	DataPrevisao    *time.Time
+	DataRecebimento *time.Time
+	ValorTotal      float64              `gorm:"type:decimal(10,2);default:0"`
+	Observacoes     string               `gorm:"type:text"`
+	UsuarioID       *uint                `gorm:"index"`
+	Itens           []itemPedidoCompraV1 `gorm:"foreignKey:PedidoCompraID"`
+	CreatedAt       time.Time            `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time            `gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt       `gorm:"index"`
+}
+
+func (pedidoCompraV1) TableName() string { return "pedidos_compra" }
+
+type itemPedidoCompraV1 struct {
+	ID                 uint       `gorm:"primaryKey;autoIncrement;not null"`
+	PedidoCompraID     uint       `gorm:"not null;index"`
+	EstoqueID          uint       `gorm:"not null;index"`
+	Item               *estoqueV1 `gorm:"foreignKey:EstoqueID"`
+	Quantidade         int        `gorm:"not null"`
+	QuantidadeRecebida int        `gorm:"not null;default:0"`
+	CustoUnitario      float64    `gorm:"type:decimal(10,2);not null;default:0.00"`
+	ValorTotal         float64    `gorm:"type:decimal(10,2);not null;default:0.00"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (itemPedidoCompraV1) TableName() string { return "itens_pedido_compra" }
+
+type fornecedorItemV1 struct {
+	ID               uint          `gorm:"primaryKey;autoIncrement;not null"`
+	FornecedorID     uint          `gorm:"not null;uniqueIndex:idx_fornecedor_item"`
+	Fornecedor       *fornecedorV1 `gorm:"foreignKey:FornecedorID"`
+	EstoqueID        uint          `gorm:"not null;uniqueIndex:idx_fornecedor_item;index"`
+	Item             *estoqueV1    `gorm:"foreignKey:EstoqueID"`
+	CodigoFornecedor string        `gorm:"size:100"`
+	Preco            float64       `gorm:"type:decimal(10,2);not null;default:0.00"`
+	PrazoEntregaDias *int
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+func (fornecedorItemV1) TableName() string { return "fornecedor_itens" }
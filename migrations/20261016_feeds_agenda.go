@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CriarFeedsAgenda cria a tabela com o segredo do feed iCalendar de cada funcionário.
+// Os links assinados com o JWT_SECRET deixam de valer: cada funcionário precisa gerar um novo
+func CriarFeedsAgenda(db *gorm.DB) error {
+	return db.AutoMigrate(&feedAgendaV13{})
+}
+
+// RemoverFeedsAgenda desfaz CriarFeedsAgenda
+func RemoverFeedsAgenda(db *gorm.DB) error {
+	return db.Migrator().DropTable(&feedAgendaV13{})
+}
+
+// Modelo na versão 13 do esquema; não altere esta estrutura
+
+type feedAgendaV13 struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement;not null"`
+	FuncionarioID uint      `gorm:"not null;uniqueIndex"`
+	TokenHash     string    `gorm:"not null;size:64"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (feedAgendaV13) TableName() string { return "feeds_agenda" }
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CriarHistoricoSenhas cria a tabela com os hashes das senhas anteriores dos usuários
+func CriarHistoricoSenhas(db *gorm.DB) error {
+	return db.AutoMigrate(&historicoSenhaV11{})
+}
+
+// RemoverHistoricoSenhas desfaz CriarHistoricoSenhas
+func RemoverHistoricoSenhas(db *gorm.DB) error {
+	return db.Migrator().DropTable(&historicoSenhaV11{})
+}
+
+// Modelo na versão 11 do esquema; não altere esta estrutura
+
+type historicoSenhaV11 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID uint      `gorm:"not null;index"`
+	SenhaHash string    `gorm:"not null;size:60"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (historicoSenhaV11) TableName() string { return "historico_senhas" }
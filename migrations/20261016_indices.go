@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// indiceConsulta é um índice criado fora das tags dos modelos
+type indiceConsulta struct {
+	nome, tabela, colunas string
+	fullText              bool
+}
+
+// indicesConsulta são os índices para consultas frequentes e os índices FULLTEXT usados pela busca
+// textual (/api/busca). As colunas FULLTEXT precisam ser as mesmas usadas no MATCH do BuscaRepository
+var indicesConsulta = []indiceConsulta{
+	{nome: "idx_veiculos_placa", tabela: "veiculos", colunas: "placa"},
+	{nome: "idx_clientes_nome", tabela: "clientes", colunas: "nome"},
+	{nome: "idx_usuarios_email", tabela: "usuarios", colunas: "email"},
+	{nome: "ft_clientes_busca", tabela: "clientes", colunas: "nome, telefone, email", fullText: true},
+	{nome: "ft_veiculos_busca", tabela: "veiculos", colunas: "placa, marca, modelo, cor", fullText: true},
+	{nome: "ft_ordens_servico_busca", tabela: "ordens_servico", colunas: "numero_os, descricao", fullText: true},
+}
+
+// CriarIndicesConsulta cria os índices que ainda não existirem
+func CriarIndicesConsulta(db *gorm.DB) error {
+	for _, indice := range indicesConsulta {
+		if db.Migrator().HasIndex(indice.tabela, indice.nome) {
+			continue
+		}
+
+		tipo := "INDEX"
+		if indice.fullText {
+			tipo = "FULLTEXT INDEX"
+		}
+		sql := "CREATE " + tipo + " " + indice.nome + " ON " + indice.tabela + "(" + indice.colunas + ")"
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoverIndicesConsulta desfaz CriarIndicesConsulta
+func RemoverIndicesConsulta(db *gorm.DB) error {
+	for i := len(indicesConsulta) - 1; i >= 0; i-- {
+		indice := indicesConsulta[i]
+		if !db.Migrator().HasIndex(indice.tabela, indice.nome) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(indice.tabela, indice.nome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
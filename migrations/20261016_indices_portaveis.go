@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// indicesPortaveis são os índices de consulta frequente nos bancos sem FULLTEXT;
+// neles a busca textual compara os termos com LIKE e dispensa índices próprios
+var indicesPortaveis = []indiceConsulta{
+	{nome: "idx_veiculos_placa", tabela: "veiculos", colunas: "placa"},
+	{nome: "idx_clientes_nome", tabela: "clientes", colunas: "nome"},
+	{nome: "idx_usuarios_email", tabela: "usuarios", colunas: "email"},
+}
+
+// CriarIndicesPortaveis cria os índices que ainda não existirem
+func CriarIndicesPortaveis(db *gorm.DB) error {
+	for _, indice := range indicesPortaveis {
+		if db.Migrator().HasIndex(indice.tabela, indice.nome) {
+			continue
+		}
+		if err := db.Exec("CREATE INDEX " + indice.nome + " ON " + indice.tabela + "(" + indice.colunas + ")").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoverIndicesPortaveis desfaz CriarIndicesPortaveis
+func RemoverIndicesPortaveis(db *gorm.DB) error {
+	for i := len(indicesPortaveis) - 1; i >= 0; i-- {
+		indice := indicesPortaveis[i]
+		if !db.Migrator().HasIndex(indice.tabela, indice.nome) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(indice.tabela, indice.nome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// indiceControlesLoginUltimaFalha atende a limpeza periódica dos controles de login fora da janela de falhas
+const indiceControlesLoginUltimaFalha = "idx_controles_login_ultima_falha"
+
+// CriarIndiceLimpezaControlesLogin cria o índice pela data da última falha e descarta os controles criados
+// pela versão anterior para alvos que nunca falharam
+func CriarIndiceLimpezaControlesLogin(db *gorm.DB) error {
+	if !db.Migrator().HasIndex("controles_login", indiceControlesLoginUltimaFalha) {
+		if err := db.Exec("CREATE INDEX " + indiceControlesLoginUltimaFalha + " ON controles_login(ultima_falha)").Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec("DELETE FROM controles_login WHERE ultima_falha IS NULL AND bloqueado_ate IS NULL").Error
+}
+
+// RemoverIndiceLimpezaControlesLogin desfaz o índice de CriarIndiceLimpezaControlesLogin; os controles descartados não voltam
+func RemoverIndiceLimpezaControlesLogin(db *gorm.DB) error {
+	if !db.Migrator().HasIndex("controles_login", indiceControlesLoginUltimaFalha) {
+		return nil
+	}
+	return db.Migrator().DropIndex("controles_login", indiceControlesLoginUltimaFalha)
+}
@@ -0,0 +1,132 @@
+package migrations
+
+import (
+	"log"
+	"strings"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrarFornecedores converte o antigo campo texto "fornecedor" do estoque (e dos pedidos de compra)
+// em registros da tabela fornecedores, ligando cada linha pelo fornecedor_id.
+// Grafias diferentes do mesmo nome ("Bosch Ltda.", "bosch ltda") viram um único fornecedor,
+// usando a grafia mais frequente. Depois da conversão a coluna antiga é removida,
+// então a migração só faz algo na primeira execução.
+func MigrarFornecedores(db *gorm.DB) error {
+	for _, tabela := range []string{"estoque", "pedidos_compra"} {
+		if !db.Migrator().HasColumn(tabela, "fornecedor") {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return vincularFornecedores(tx, tabela)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := removerColunaFornecedor(db, tabela); err != nil {
+			return err
+		}
+		log.Printf("Coluna %s.fornecedor convertida para fornecedor_id", tabela)
+	}
+	return nil
+}
+
+// removerColunaFornecedor apaga a coluna texto e o índice que ela tinha.
+// Usa SQL direto porque o Migrator do SQLite recria a tabela para remover a coluna, perdendo os demais índices,
+// e o SQLite não remove uma coluna que ainda está em um índice
+func removerColunaFornecedor(db *gorm.DB, tabela string) error {
+	indice := "idx_" + tabela + "_fornecedor"
+	if db.Migrator().HasIndex(tabela, indice) {
+		if err := db.Migrator().DropIndex(tabela, indice); err != nil {
+			return err
+		}
+	}
+	return db.Exec("ALTER TABLE " + tabela + " DROP COLUMN fornecedor").Error
+}
+
+// vincularFornecedores cria os fornecedores encontrados na coluna texto e preenche fornecedor_id
+func vincularFornecedores(tx *gorm.DB, tabela string) error {
+	var grafias []struct {
+		Fornecedor string
+		Total      int
+	}
+	err := tx.Table(tabela).
+		Select("fornecedor, COUNT(*) AS total").
+		Where("fornecedor IS NOT NULL AND TRIM(fornecedor) <> ''").
+		Group("fornecedor").
+		Order("total DESC, fornecedor").
+		Scan(&grafias).Error
+	if err != nil {
+		return err
+	}
+
+	// Fornecedores já cadastrados, indexados pelo nome normalizado
+	var existentes []models.Fornecedor
+	if err := tx.Unscoped().Find(&existentes).Error; err != nil {
+		return err
+	}
+	idPorChave := make(map[string]uint, len(existentes))
+	for _, fornecedor := range existentes {
+		idPorChave[chaveFornecedor(fornecedor.Nome)] = fornecedor.ID
+	}
+
+	// Agrupar as grafias pela chave; a primeira de cada grupo é a mais frequente
+	variantes := make(map[string][]string)
+	var chaves []string
+	for _, grafia := range grafias {
+		chave := chaveFornecedor(grafia.Fornecedor)
+		if _, ok := variantes[chave]; !ok {
+			chaves = append(chaves, chave)
+		}
+		variantes[chave] = append(variantes[chave], grafia.Fornecedor)
+	}
+
+	for _, chave := range chaves {
+		id, ok := idPorChave[chave]
+		if !ok {
+			fornecedor := models.Fornecedor{
+				Nome:  strings.Join(strings.Fields(variantes[chave][0]), " "),
+				Ativo: true,
+			}
+			if err := tx.Create(&fornecedor).Error; err != nil {
+				return err
+			}
+			id = fornecedor.ID
+			idPorChave[chave] = id
+		}
+
+		err := tx.Table(tabela).
+			Where("fornecedor IN ? AND fornecedor_id IS NULL", variantes[chave]).
+			Update("fornecedor_id", id).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chaveFornecedor normaliza o nome para comparação: minúsculas, sem acentos,
+// sem pontuação e com espaços simples
+func chaveFornecedor(nome string) string {
+	semAcentos := strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a",
+		"é", "e", "ê", "e",
+		"í", "i",
+		"ó", "o", "ô", "o", "õ", "o",
+		"ú", "u", "ü", "u",
+		"ç", "c",
+	)
+	nome = semAcentos.Replace(strings.ToLower(nome))
+	nome = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return ' '
+	}, nome)
+	return strings.Join(strings.Fields(nome), " ")
+}
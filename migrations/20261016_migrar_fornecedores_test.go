@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// bancoComFornecedorTexto aplica o esquema anterior à migração 3 e recria a antiga coluna texto "fornecedor", com seu índice
+func bancoComFornecedorTexto(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := novoBanco(t)
+	if err := NewMigrador(db, Todas()).Migrar(2); err != nil {
+		t.Fatalf("erro ao aplicar o esquema: %v", err)
+	}
+	for _, tabela := range []string{"estoque", "pedidos_compra"} {
+		if err := db.Exec("ALTER TABLE " + tabela + " ADD COLUMN fornecedor VARCHAR(100)").Error; err != nil {
+			t.Fatalf("erro ao recriar %s.fornecedor: %v", tabela, err)
+		}
+		if err := db.Exec("CREATE INDEX idx_" + tabela + "_fornecedor ON " + tabela + " (fornecedor)").Error; err != nil {
+			t.Fatalf("erro ao recriar o índice de %s.fornecedor: %v", tabela, err)
+		}
+	}
+	return db
+}
+
+// fornecedorDoItem retorna o fornecedor_id gravado na peça com o código informado
+func fornecedorDoItem(t *testing.T, db *gorm.DB, codigo string) *uint {
+	t.Helper()
+	var fornecedorID *uint
+	if err := db.Table("estoque").Where("codigo = ?", codigo).Select("fornecedor_id").Row().Scan(&fornecedorID); err != nil {
+		t.Fatalf("erro ao ler a peça %s: %v", codigo, err)
+	}
+	return fornecedorID
+}
+
+func TestMigrarFornecedoresUneAsGrafias(t *testing.T) {
+	db := bancoComFornecedorTexto(t)
+
+	// Um fornecedor já cadastrado é reaproveitado mesmo com outra pontuação
+	if err := db.Exec("INSERT INTO fornecedores (nome, ativo) VALUES ('Magneti-Marelli', 1)").Error; err != nil {
+		t.Fatalf("erro ao cadastrar o fornecedor: %v", err)
+	}
+	var marelli uint
+	db.Table("fornecedores").Select("id").Where("nome = ?", "Magneti-Marelli").Row().Scan(&marelli)
+
+	pecas := []struct {
+		codigo     string
+		fornecedor interface{}
+	}{
+		{"P1", "Bosch Ltda."},
+		{"P2", "Bosch Ltda."},
+		{"P3", "bosch ltda"},
+		{"P4", "  BOSCH   LTDA "},
+		{"P5", "Magneti Marelli"},
+		{"P6", "   "},
+		{"P7", nil},
+	}
+	for _, peca := range pecas {
+		if err := db.Exec("INSERT INTO estoque (nome, codigo, fornecedor) VALUES ('Peça', ?, ?)", peca.codigo, peca.fornecedor).Error; err != nil {
+			t.Fatalf("erro ao gravar a peça %s: %v", peca.codigo, err)
+		}
+	}
+	if err := db.Exec("INSERT INTO pedidos_compra (numero, status, fornecedor) VALUES ('PC-1', 'rascunho', 'BOSCH LTDA')").Error; err != nil {
+		t.Fatalf("erro ao gravar o pedido: %v", err)
+	}
+
+	if err := NewMigrador(db, Todas()).Migrar(3); err != nil {
+		t.Fatalf("erro ao migrar os fornecedores: %v", err)
+	}
+
+	var nomes []string
+	db.Table("fornecedores").Order("id").Pluck("nome", &nomes)
+	if len(nomes) != 2 || nomes[1] != "Bosch Ltda." {
+		t.Fatalf("fornecedores = %v, esperado o existente e Bosch com a grafia mais frequente", nomes)
+	}
+	var bosch uint
+	db.Table("fornecedores").Select("id").Where("nome = ?", "Bosch Ltda.").Row().Scan(&bosch)
+
+	for _, codigo := range []string{"P1", "P2", "P3", "P4"} {
+		if id := fornecedorDoItem(t, db, codigo); id == nil || *id != bosch {
+			t.Errorf("peça %s ligada a %v, esperado %d", codigo, id, bosch)
+		}
+	}
+	if id := fornecedorDoItem(t, db, "P5"); id == nil || *id != marelli {
+		t.Errorf("peça P5 ligada a %v, esperado o fornecedor já cadastrado %d", id, marelli)
+	}
+	for _, codigo := range []string{"P6", "P7"} {
+		if id := fornecedorDoItem(t, db, codigo); id != nil {
+			t.Errorf("peça %s sem fornecedor ficou ligada a %d", codigo, *id)
+		}
+	}
+
+	var pedidoFornecedor *uint
+	db.Table("pedidos_compra").Select("fornecedor_id").Where("numero = ?", "PC-1").Row().Scan(&pedidoFornecedor)
+	if pedidoFornecedor == nil || *pedidoFornecedor != bosch {
+		t.Errorf("pedido ligado a %v, esperado %d", pedidoFornecedor, bosch)
+	}
+
+	for _, tabela := range []string{"estoque", "pedidos_compra"} {
+		if db.Migrator().HasColumn(tabela, "fornecedor") {
+			t.Errorf("a coluna %s.fornecedor deveria ter sido removida", tabela)
+		}
+	}
+	// Os demais índices da tabela continuam lá
+	if !db.Migrator().HasIndex("estoque", "idx_estoque_codigo") {
+		t.Error("remover a coluna não deveria apagar os outros índices do estoque")
+	}
+	if err := MigrarFornecedores(db); err != nil {
+		t.Errorf("sem a coluna antiga, a migração não deveria fazer nada: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"log"
+	"strings"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// descricaoMaoDeObraAnterior identifica as linhas criadas a partir do antigo valor digitado
+const descricaoMaoDeObraAnterior = "Mão de obra lançada antes do catálogo de serviços"
+
+// MigrarValorServico preserva o valor de serviços digitado manualmente nas OS antigas.
+// Como o ValorServico passou a ser a soma das linhas de mão de obra, cada OS com valor
+// e sem linhas ganha uma linha avulsa de 1 hora com esse valor; assim o total não muda
+// quando novas linhas forem lançadas. OS que já possuem linhas são ignoradas, então
+// a migração pode ser executada mais de uma vez.
+func MigrarValorServico(db *gorm.DB) error {
+	var ordens []models.OrdemServico
+	err := db.Where("valor_servico > 0").
+		Where("NOT EXISTS (SELECT 1 FROM servicos_ordem_servico s WHERE s.ordem_servico_id = ordens_servico.id)").
+		Find(&ordens).Error
+	if err != nil {
+		return err
+	}
+
+	if len(ordens) == 0 {
+		return nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, os := range ordens {
+			descricao := strings.TrimSpace(os.ServicosRealizados)
+			if descricao == "" || len([]rune(descricao)) > 255 {
+				descricao = descricaoMaoDeObraAnterior
+			}
+
+			linha := models.ServicoOrdemServico{
+				OrdemServicoID: os.ID,
+				FuncionarioID:  os.FuncionarioID,
+				Descricao:      descricao,
+				Horas:          1,
+				ValorHora:      os.ValorServico,
+			}
+			if err := tx.Create(&linha).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Valor de serviços de %d ordens convertido em linhas de mão de obra", len(ordens))
+	return nil
+}
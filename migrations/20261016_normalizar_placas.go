@@ -0,0 +1,102 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/utils"
+
+	"gorm.io/gorm"
+)
+
+// NormalizarPlacas grava as placas existentes no formato normalizado (maiúsculas, sem hífen ou espaços),
+// o mesmo usado pelo cadastro de veículos. Placas que passariam a ser iguais a outra
+// ("abc-1234" e "ABC1234") não são alteradas, porque a coluna é única: elas são listadas
+// no log junto com as que só coincidem na conversão para o Mercosul (ABC1234 e ABC1C34),
+// para que os cadastros duplicados sejam unificados manualmente. Placas fora dos dois padrões
+// também são listadas. Como só atualiza o que ainda não está normalizado, pode ser executada mais de uma vez.
+func NormalizarPlacas(db *gorm.DB) error {
+	var veiculos []models.Veiculo
+	if err := db.Unscoped().Select("id", "placa").Order("id").Find(&veiculos).Error; err != nil {
+		return err
+	}
+
+	// Quantos veículos chegam a cada placa normalizada e a cada placa no padrão Mercosul
+	porNormalizada := make(map[string][]models.Veiculo)
+	porChave := make(map[string][]models.Veiculo)
+	for _, veiculo := range veiculos {
+		normalizada := utils.NormalizarPlaca(veiculo.Placa)
+		porNormalizada[normalizada] = append(porNormalizada[normalizada], veiculo)
+
+		chave := chavePlaca(normalizada)
+		porChave[chave] = append(porChave[chave], veiculo)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, veiculo := range veiculos {
+			normalizada := utils.NormalizarPlaca(veiculo.Placa)
+			if normalizada == veiculo.Placa || normalizada == "" || len(porNormalizada[normalizada]) > 1 {
+				continue
+			}
+
+			err := tx.Unscoped().Model(&models.Veiculo{}).
+				Where("id = ?", veiculo.ID).
+				UpdateColumn("placa", normalizada).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	relatarPlacas(porChave)
+	return nil
+}
+
+// chavePlaca agrupa as duas grafias da mesma placa convertendo o padrão antigo para o Mercosul
+func chavePlaca(normalizada string) string {
+	if _, err := utils.ValidarPlaca(normalizada); err != nil {
+		return normalizada
+	}
+	if equivalente := utils.PlacaEquivalente(normalizada); equivalente != "" && equivalente[4] >= 'A' {
+		return equivalente
+	}
+	return normalizada
+}
+
+// relatarPlacas registra no log as placas duplicadas e as que não seguem nenhum padrão
+func relatarPlacas(porChave map[string][]models.Veiculo) {
+	chaves := make([]string, 0, len(porChave))
+	for chave := range porChave {
+		chaves = append(chaves, chave)
+	}
+	sort.Strings(chaves)
+
+	for _, chave := range chaves {
+		grupo := porChave[chave]
+		if len(grupo) > 1 {
+			descricoes := make([]string, 0, len(grupo))
+			for _, veiculo := range grupo {
+				descricoes = append(descricoes, veiculoNoLog(veiculo))
+			}
+			log.Printf("Placas duplicadas (%s): %s", chave, strings.Join(descricoes, ", "))
+		}
+
+		for _, veiculo := range grupo {
+			if _, err := utils.ValidarPlaca(veiculo.Placa); err != nil {
+				log.Printf("Placa fora dos padrões antigo e Mercosul: %s", veiculoNoLog(veiculo))
+			}
+		}
+	}
+}
+
+// veiculoNoLog identifica o veículo nas mensagens da migração
+func veiculoNoLog(veiculo models.Veiculo) string {
+	return fmt.Sprintf("id %d (%q)", veiculo.ID, veiculo.Placa)
+}
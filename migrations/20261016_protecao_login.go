@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CriarProtecaoLogin cria o histórico de tentativas de login e o controle de falhas por conta e por IP
+func CriarProtecaoLogin(db *gorm.DB) error {
+	return db.AutoMigrate(&tentativaAcessoV9{}, &controleLoginV9{})
+}
+
+// RemoverProtecaoLogin desfaz CriarProtecaoLogin
+func RemoverProtecaoLogin(db *gorm.DB) error {
+	return db.Migrator().DropTable(&controleLoginV9{}, &tentativaAcessoV9{})
+}
+
+// Modelos na versão 9 do esquema; não altere estas estruturas
+
+type tentativaAcessoV9 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID *uint     `gorm:"index"`
+	Email     string    `gorm:"not null;size:100;index"`
+	IP        string    `gorm:"size:45;index"`
+	UserAgent string    `gorm:"size:255"`
+	Sucesso   bool      `gorm:"not null;default:false"`
+	Motivo    string    `gorm:"size:30"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (tentativaAcessoV9) TableName() string { return "tentativas_acesso" }
+
+type controleLoginV9 struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement;not null"`
+	Tipo             string `gorm:"not null;size:10;uniqueIndex:idx_controle_login"`
+	Chave            string `gorm:"not null;size:100;uniqueIndex:idx_controle_login"`
+	Falhas           int    `gorm:"not null;default:0"`
+	UltimaFalha      *time.Time
+	ProximaTentativa *time.Time
+	BloqueadoAte     *time.Time
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+func (controleLoginV9) TableName() string { return "controles_login" }
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CriarRedefinicoesSenha cria a tabela dos pedidos de redefinição de senha
+func CriarRedefinicoesSenha(db *gorm.DB) error {
+	return db.AutoMigrate(&redefinicaoSenhaV10{})
+}
+
+// RemoverRedefinicoesSenha desfaz CriarRedefinicoesSenha
+func RemoverRedefinicoesSenha(db *gorm.DB) error {
+	return db.Migrator().DropTable(&redefinicaoSenhaV10{})
+}
+
+// Modelo na versão 10 do esquema; não altere esta estrutura
+
+type redefinicaoSenhaV10 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;size:64;uniqueIndex"`
+	ExpiraEm  time.Time `gorm:"not null"`
+	UsadoEm   *time.Time
+	IP        string    `gorm:"size:45"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (redefinicaoSenhaV10) TableName() string { return "redefinicoes_senha" }
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CriarSessoes cria as tabelas das sessões de login e dos tokens de renovação
+func CriarSessoes(db *gorm.DB) error {
+	return db.AutoMigrate(&sessaoV8{}, &tokenRenovacaoV8{})
+}
+
+// RemoverSessoes desfaz CriarSessoes
+func RemoverSessoes(db *gorm.DB) error {
+	return db.Migrator().DropTable(&tokenRenovacaoV8{}, &sessaoV8{})
+}
+
+// Modelos na versão 8 do esquema; não altere estas estruturas
+
+type sessaoV8 struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement;not null"`
+	Chave           string    `gorm:"not null;size:32;uniqueIndex"`
+	UsuarioID       uint      `gorm:"not null;index"`
+	IP              string    `gorm:"size:45"`
+	UserAgent       string    `gorm:"size:255"`
+	ExpiraEm        time.Time `gorm:"not null"`
+	RevogadaEm      *time.Time
+	MotivoRevogacao string    `gorm:"size:30"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (sessaoV8) TableName() string { return "sessoes" }
+
+type tokenRenovacaoV8 struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;not null"`
+	SessaoID  uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;size:64;uniqueIndex"`
+	ExpiraEm  time.Time `gorm:"not null"`
+	UsadoEm   *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (tokenRenovacaoV8) TableName() string { return "tokens_renovacao" }
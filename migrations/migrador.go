@@ -0,0 +1,398 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fontes guarda o código das migrações; a soma de verificação de cada uma é calculada sobre o seu arquivo,
+// de modo que editar uma migração já aplicada é detectado na próxima execução
+//
+//go:embed *.go
+var fontes embed.FS
+
+// esperaTrava é quanto uma instância aguarda outra terminar as migrações antes de desistir
+const esperaTrava = 60
+
+// opcoesTabela são as opções usadas na criação das tabelas no MySQL; os demais bancos não as aceitam
+const opcoesTabela = "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci"
+
+// Migracao é um passo versionado do esquema ou dos dados.
+// O MySQL confirma comandos DDL implicitamente, então o passo não roda dentro de uma transação:
+// migrações de dados devem abrir a própria transação
+type Migracao struct {
+	Versao    int64
+	Descricao string
+	Arquivo   string                  // Arquivo deste pacote com o código da migração
+	Up        func(db *gorm.DB) error // Aplica a migração
+	Down      func(db *gorm.DB) error // Desfaz a migração; nil quando ela não pode ser desfeita
+	Bancos    []string                // Bancos (nome do dialeto do GORM) em que a migração roda; vazio roda em todos
+
+	// ChecksumsAnteriores são somas de verificação de versões anteriores do arquivo que produzem o mesmo
+	// esquema; bancos que aplicaram uma delas continuam aceitos
+	ChecksumsAnteriores []string
+}
+
+// registroMigracao é uma linha da tabela schema_migrations
+type registroMigracao struct {
+	Versao     int64     `gorm:"primaryKey;autoIncrement:false"`
+	Descricao  string    `gorm:"size:255;not null"`
+	Checksum   string    `gorm:"size:64;not null"`
+	AplicadaEm time.Time `gorm:"not null"`
+	DuracaoMs  int64     `gorm:"not null;default:0"`
+}
+
+// TableName especifica o nome da tabela de controle das migrações
+func (registroMigracao) TableName() string {
+	return "schema_migrations"
+}
+
+// SituacaoMigracao descreve uma migração no comando "migrate status"
+type SituacaoMigracao struct {
+	Versao             int64
+	Descricao          string
+	Aplicada           bool
+	AplicadaEm         *time.Time
+	Reversivel         bool
+	ChecksumDivergente bool // O arquivo mudou depois de a migração ser aplicada
+	Desconhecida       bool // Aplicada no banco, mas ausente do código (versão mais nova do sistema)
+}
+
+// Migrador aplica e desfaz as migrações em ordem, registrando cada uma na tabela schema_migrations
+type Migrador struct {
+	db        *gorm.DB
+	migracoes []Migracao
+}
+
+// NewMigrador cria o migrador para a lista de migrações, que deve estar em ordem crescente de versão.
+// As migrações restritas a outros bancos ficam de fora, como se não existissem
+func NewMigrador(db *gorm.DB, migracoes []Migracao) *Migrador {
+	banco := db.Dialector.Name()
+	doBanco := make([]Migracao, 0, len(migracoes))
+	for _, migracao := range migracoes {
+		if migracao.rodaNoBanco(banco) {
+			doBanco = append(doBanco, migracao)
+		}
+	}
+	return &Migrador{db: db, migracoes: doBanco}
+}
+
+// Migrar aplica as migrações pendentes até a versão informada (0 aplica todas)
+func (m *Migrador) Migrar(ateVersao int64) error {
+	return m.comTrava(func(conn *gorm.DB) error {
+		aplicadas, err := m.verificarAplicadas(conn)
+		if err != nil {
+			return err
+		}
+
+		pendentes := 0
+		for _, migracao := range m.migracoes {
+			if ateVersao > 0 && migracao.Versao > ateVersao {
+				break
+			}
+			if _, ok := aplicadas[migracao.Versao]; ok {
+				continue
+			}
+
+			if err := m.aplicar(conn, migracao); err != nil {
+				return err
+			}
+			pendentes++
+		}
+
+		if pendentes == 0 {
+			log.Println("Banco de dados atualizado; nenhuma migração pendente")
+		}
+		return nil
+	})
+}
+
+// Reverter desfaz as últimas migrações aplicadas, da mais nova para a mais antiga
+func (m *Migrador) Reverter(passos int) error {
+	if passos < 1 {
+		return errors.New("informe ao menos uma migração para desfazer")
+	}
+
+	return m.comTrava(func(conn *gorm.DB) error {
+		if _, err := m.verificarAplicadas(conn); err != nil {
+			return err
+		}
+
+		var registros []registroMigracao
+		if err := conn.Order("versao DESC").Limit(passos).Find(&registros).Error; err != nil {
+			return err
+		}
+		if len(registros) == 0 {
+			return errors.New("nenhuma migração aplicada para desfazer")
+		}
+
+		for _, registro := range registros {
+			migracao, _ := m.buscar(registro.Versao)
+			if migracao.Down == nil {
+				return fmt.Errorf("a migração %d (%s) não pode ser desfeita", migracao.Versao, migracao.Descricao)
+			}
+
+			log.Printf("Desfazendo migração %d: %s", migracao.Versao, migracao.Descricao)
+			if err := migracao.Down(conn); err != nil {
+				return fmt.Errorf("erro ao desfazer a migração %d: %w", migracao.Versao, err)
+			}
+			if err := conn.Delete(&registroMigracao{}, registro.Versao).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Situacao lista todas as migrações do código e as aplicadas no banco, indicando divergências
+func (m *Migrador) Situacao() ([]SituacaoMigracao, error) {
+	if err := m.validarLista(); err != nil {
+		return nil, err
+	}
+	if err := m.db.AutoMigrate(&registroMigracao{}); err != nil {
+		return nil, err
+	}
+
+	var registros []registroMigracao
+	if err := m.db.Order("versao").Find(&registros).Error; err != nil {
+		return nil, err
+	}
+	aplicadas := make(map[int64]registroMigracao, len(registros))
+	for _, registro := range registros {
+		aplicadas[registro.Versao] = registro
+	}
+
+	situacoes := make([]SituacaoMigracao, 0, len(m.migracoes))
+	for _, migracao := range m.migracoes {
+		situacao := SituacaoMigracao{
+			Versao:     migracao.Versao,
+			Descricao:  migracao.Descricao,
+			Reversivel: migracao.Down != nil,
+		}
+		if registro, ok := aplicadas[migracao.Versao]; ok {
+			aplicadaEm := registro.AplicadaEm
+			situacao.Aplicada = true
+			situacao.AplicadaEm = &aplicadaEm
+			checksum, err := checksumMigracao(migracao)
+			if err != nil {
+				return nil, err
+			}
+			situacao.ChecksumDivergente = !migracao.aceitaChecksum(checksum, registro.Checksum)
+			delete(aplicadas, migracao.Versao)
+		}
+		situacoes = append(situacoes, situacao)
+	}
+
+	for _, registro := range registros {
+		if _, ok := aplicadas[registro.Versao]; ok {
+			aplicadaEm := registro.AplicadaEm
+			situacoes = append(situacoes, SituacaoMigracao{
+				Versao:       registro.Versao,
+				Descricao:    registro.Descricao,
+				Aplicada:     true,
+				AplicadaEm:   &aplicadaEm,
+				Desconhecida: true,
+			})
+		}
+	}
+	return situacoes, nil
+}
+
+// aplicar executa uma migração e registra a versão com a soma de verificação do arquivo
+func (m *Migrador) aplicar(conn *gorm.DB, migracao Migracao) error {
+	checksum, err := checksumMigracao(migracao)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Aplicando migração %d: %s", migracao.Versao, migracao.Descricao)
+	inicio := time.Now()
+	db := conn
+	if conn.Dialector.Name() == "mysql" {
+		db = conn.Set("gorm:table_options", opcoesTabela).Session(&gorm.Session{})
+	}
+	if err := migracao.Up(db); err != nil {
+		return fmt.Errorf("erro na migração %d (%s): %w", migracao.Versao, migracao.Descricao, err)
+	}
+
+	return conn.Create(&registroMigracao{
+		Versao:     migracao.Versao,
+		Descricao:  migracao.Descricao,
+		Checksum:   checksum,
+		AplicadaEm: time.Now(),
+		DuracaoMs:  time.Since(inicio).Milliseconds(),
+	}).Error
+}
+
+// verificarAplicadas cria a tabela de controle, se necessário, e recusa seguir quando uma migração aplicada
+// foi editada ou quando o banco tem migrações que este código não conhece
+func (m *Migrador) verificarAplicadas(conn *gorm.DB) (map[int64]registroMigracao, error) {
+	if err := m.validarLista(); err != nil {
+		return nil, err
+	}
+	if err := conn.AutoMigrate(&registroMigracao{}); err != nil {
+		return nil, err
+	}
+
+	var registros []registroMigracao
+	if err := conn.Order("versao").Find(&registros).Error; err != nil {
+		return nil, err
+	}
+
+	aplicadas := make(map[int64]registroMigracao, len(registros))
+	var problemas []string
+	for _, registro := range registros {
+		aplicadas[registro.Versao] = registro
+
+		migracao, ok := m.buscar(registro.Versao)
+		if !ok {
+			problemas = append(problemas, fmt.Sprintf("migração %d (%s) aplicada no banco não existe nesta versão do sistema",
+				registro.Versao, registro.Descricao))
+			continue
+		}
+
+		checksum, err := checksumMigracao(migracao)
+		if err != nil {
+			return nil, err
+		}
+		if !migracao.aceitaChecksum(checksum, registro.Checksum) {
+			problemas = append(problemas, fmt.Sprintf("migração %d (%s) foi alterada depois de aplicada; crie uma nova migração em vez de editar %s",
+				migracao.Versao, migracao.Descricao, migracao.Arquivo))
+		}
+	}
+
+	if len(problemas) > 0 {
+		return nil, errors.New(strings.Join(problemas, "; "))
+	}
+	return aplicadas, nil
+}
+
+// validarLista garante versões positivas, únicas e em ordem, e que todo arquivo exista
+func (m *Migrador) validarLista() error {
+	var anterior int64
+	for _, migracao := range m.migracoes {
+		if migracao.Versao <= anterior {
+			return fmt.Errorf("a migração %d está fora de ordem ou repetida", migracao.Versao)
+		}
+		if migracao.Up == nil {
+			return fmt.Errorf("a migração %d não possui o passo de aplicação", migracao.Versao)
+		}
+		if _, err := checksumMigracao(migracao); err != nil {
+			return err
+		}
+		anterior = migracao.Versao
+	}
+	return nil
+}
+
+// buscar localiza a migração da versão informada
+func (m *Migrador) buscar(versao int64) (Migracao, bool) {
+	for _, migracao := range m.migracoes {
+		if migracao.Versao == versao {
+			return migracao, true
+		}
+	}
+	return Migracao{}, false
+}
+
+// comTrava executa a função com uma trava do banco, para que duas instâncias iniciando juntas
+// não apliquem as mesmas migrações. A trava pertence à conexão, então tudo roda na mesma conexão
+func (m *Migrador) comTrava(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		// A instância entregue por Connection acumula as condições de cada chamada encadeada;
+		// a sessão faz cada consulta começar limpa, mantendo a mesma conexão
+		conn = conn.Session(&gorm.Session{})
+
+		liberar, err := travarMigracoes(conn)
+		if err != nil {
+			return err
+		}
+		defer liberar()
+
+		return fn(conn)
+	})
+}
+
+// travarMigracoes obtém a trava de migrações conforme o banco e retorna a função que a libera
+func travarMigracoes(conn *gorm.DB) (func(), error) {
+	var (
+		obtida sql.NullBool
+		err    error
+	)
+
+	switch conn.Dialector.Name() {
+	case "mysql":
+		err = conn.Raw("SELECT GET_LOCK(CONCAT('migracoes:', DATABASE()), ?) = 1", esperaTrava).Row().Scan(&obtida)
+		if err == nil && obtida.Valid && obtida.Bool {
+			return func() { conn.Exec("SELECT RELEASE_LOCK(CONCAT('migracoes:', DATABASE()))") }, nil
+		}
+	case "postgres":
+		// pg_advisory_lock esperaria indefinidamente; tenta a cada segundo até o limite
+		for tentativa := 0; tentativa < esperaTrava; tentativa++ {
+			err = conn.Raw("SELECT pg_try_advisory_lock(hashtext('migracoes:' || current_database()))").Row().Scan(&obtida)
+			if err != nil || obtida.Bool {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+		if err == nil && obtida.Bool {
+			return func() { conn.Exec("SELECT pg_advisory_unlock(hashtext('migracoes:' || current_database()))") }, nil
+		}
+	default:
+		// SQLite: o banco é um arquivo local usado por uma única instância, e cada migração que grava
+		// dados já abre a própria transação, que trava o arquivo inteiro
+		return func() {}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter a trava das migrações: %w", err)
+	}
+	return nil, errors.New("outra instância está executando as migrações; tente novamente em instantes")
+}
+
+// rodaNoBanco indica se a migração se aplica ao banco informado
+func (migracao Migracao) rodaNoBanco(banco string) bool {
+	if len(migracao.Bancos) == 0 {
+		return true
+	}
+	for _, permitido := range migracao.Bancos {
+		if permitido == banco {
+			return true
+		}
+	}
+	return false
+}
+
+// aceitaChecksum indica se a soma registrada no banco corresponde ao arquivo atual ou a uma versão anterior aceita
+func (migracao Migracao) aceitaChecksum(atual, registrado string) bool {
+	if atual == registrado {
+		return true
+	}
+	for _, anterior := range migracao.ChecksumsAnteriores {
+		if anterior == registrado {
+			return true
+		}
+	}
+	return false
+}
+
+// checksumMigracao calcula o SHA-256 do arquivo da migração, ignorando a diferença de quebra de linha entre sistemas
+func checksumMigracao(migracao Migracao) (string, error) {
+	conteudo, err := fontes.ReadFile(migracao.Arquivo)
+	if err != nil {
+		return "", fmt.Errorf("arquivo da migração %d não encontrado: %s", migracao.Versao, migracao.Arquivo)
+	}
+
+	normalizado := strings.ReplaceAll(string(conteudo), "\r\n", "\n")
+	soma := sha256.Sum256([]byte(normalizado))
+	return hex.EncodeToString(soma[:]), nil
+}
@@ -0,0 +1,290 @@
+package migrations
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestMain silencia o log do migrador
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// novoBanco abre um SQLite em memória vazio para o teste
+func novoBanco(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("erro ao abrir o banco: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("erro ao obter a conexão: %v", err)
+	}
+	// Cada conexão com ":memory:" abre um banco novo; uma única conexão mantém todos no mesmo banco
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// migracaoTabela cria (e desfaz) uma tabela simples; o arquivo informado só serve para a soma de verificação
+func migracaoTabela(versao int64, tabela, arquivo string) Migracao {
+	return Migracao{
+		Versao:    versao,
+		Descricao: "tabela " + tabela,
+		Arquivo:   arquivo,
+		Up: func(db *gorm.DB) error {
+			return db.Exec("CREATE TABLE " + tabela + " (id INTEGER PRIMARY KEY)").Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Exec("DROP TABLE " + tabela).Error
+		},
+	}
+}
+
+// migracoesTeste são três migrações reversíveis em arquivos distintos
+func migracoesTeste() []Migracao {
+	return []Migracao{
+		migracaoTabela(1, "pecas", "migrador.go"),
+		migracaoTabela(2, "marcas", "registro.go"),
+		migracaoTabela(3, "modelos", "20261016_indices.go"),
+	}
+}
+
+// versoesAplicadas lista as versões registradas em schema_migrations
+func versoesAplicadas(t *testing.T, db *gorm.DB) []int64 {
+	t.Helper()
+	var versoes []int64
+	if err := db.Model(&registroMigracao{}).Order("versao").Pluck("versao", &versoes).Error; err != nil {
+		t.Fatalf("erro ao ler as versões aplicadas: %v", err)
+	}
+	return versoes
+}
+
+func TestMigrarAplicaEmOrdemERegistraChecksum(t *testing.T) {
+	db := novoBanco(t)
+	migracoes := migracoesTeste()
+	migrador := NewMigrador(db, migracoes)
+
+	if err := migrador.Migrar(2); err != nil {
+		t.Fatalf("erro ao migrar até a versão 2: %v", err)
+	}
+	if versoes := versoesAplicadas(t, db); len(versoes) != 2 || versoes[1] != 2 {
+		t.Fatalf("versões aplicadas = %v, esperado [1 2]", versoes)
+	}
+	if db.Migrator().HasTable("modelos") {
+		t.Error("a versão 3 não deveria ter sido aplicada")
+	}
+
+	if err := migrador.Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+	if !db.Migrator().HasTable("modelos") {
+		t.Error("a versão 3 deveria ter sido aplicada")
+	}
+
+	var registro registroMigracao
+	db.First(&registro, 1)
+	esperado, _ := checksumMigracao(migracoes[0])
+	if registro.Checksum != esperado || len(registro.Checksum) != 64 {
+		t.Errorf("checksum = %q, esperado o SHA-256 do arquivo %q", registro.Checksum, esperado)
+	}
+
+	// Sem pendências, uma nova execução não reaplica nada
+	if err := migrador.Migrar(0); err != nil {
+		t.Errorf("a segunda execução deveria passar sem alterações: %v", err)
+	}
+}
+
+func TestMigrarRecusaMigracaoAlterada(t *testing.T) {
+	db := novoBanco(t)
+	if err := NewMigrador(db, migracoesTeste()).Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+
+	// Simula a edição do arquivo da versão 2 depois de aplicada
+	const antigo = "0000000000000000000000000000000000000000000000000000000000000000"
+	db.Model(&registroMigracao{}).Where("versao = ?", 2).Update("checksum", antigo)
+
+	err := NewMigrador(db, migracoesTeste()).Migrar(0)
+	if err == nil || !strings.Contains(err.Error(), "registro.go") {
+		t.Fatalf("erro = %v, esperado a recusa apontando o arquivo alterado", err)
+	}
+	if err := NewMigrador(db, migracoesTeste()).Reverter(1); err == nil {
+		t.Error("o rollback também deveria recusar um banco com migração alterada")
+	}
+
+	situacoes, err := NewMigrador(db, migracoesTeste()).Situacao()
+	if err != nil {
+		t.Fatalf("erro ao consultar a situação: %v", err)
+	}
+	if !situacoes[1].ChecksumDivergente || situacoes[0].ChecksumDivergente {
+		t.Errorf("divergências = %v, %v; esperado apenas a versão 2", situacoes[0].ChecksumDivergente, situacoes[1].ChecksumDivergente)
+	}
+
+	// Declarar a soma antiga como equivalente volta a aceitar o banco
+	migracoes := migracoesTeste()
+	migracoes[1].ChecksumsAnteriores = []string{antigo}
+	if err := NewMigrador(db, migracoes).Migrar(0); err != nil {
+		t.Errorf("a soma anterior declarada deveria ser aceita: %v", err)
+	}
+}
+
+func TestMigrarRecusaVersaoDesconhecida(t *testing.T) {
+	db := novoBanco(t)
+	if err := NewMigrador(db, migracoesTeste()).Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+
+	// Um código mais antigo, que só conhece as duas primeiras migrações
+	antigo := NewMigrador(db, migracoesTeste()[:2])
+	if err := antigo.Migrar(0); err == nil {
+		t.Error("o banco com uma migração mais nova que o código deveria ser recusado")
+	}
+
+	situacoes, err := antigo.Situacao()
+	if err != nil {
+		t.Fatalf("erro ao consultar a situação: %v", err)
+	}
+	if len(situacoes) != 3 || !situacoes[2].Desconhecida || situacoes[2].Versao != 3 {
+		t.Errorf("situação = %+v, esperado a versão 3 marcada como desconhecida", situacoes)
+	}
+}
+
+func TestReverterDesfazDaMaisNova(t *testing.T) {
+	db := novoBanco(t)
+	migrador := NewMigrador(db, migracoesTeste())
+	if err := migrador.Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+
+	if err := migrador.Reverter(0); err == nil {
+		t.Error("reverter zero passos deveria ser recusado")
+	}
+	if err := migrador.Reverter(2); err != nil {
+		t.Fatalf("erro ao reverter: %v", err)
+	}
+	if versoes := versoesAplicadas(t, db); len(versoes) != 1 || versoes[0] != 1 {
+		t.Errorf("versões aplicadas = %v, esperado [1]", versoes)
+	}
+	if db.Migrator().HasTable("marcas") || db.Migrator().HasTable("modelos") {
+		t.Error("as tabelas das versões desfeitas deveriam ter sido removidas")
+	}
+
+	// A reaplicação volta a criar o que foi desfeito
+	if err := migrador.Migrar(0); err != nil {
+		t.Fatalf("erro ao reaplicar: %v", err)
+	}
+	if !db.Migrator().HasTable("modelos") {
+		t.Error("a versão 3 deveria ter sido reaplicada")
+	}
+
+	if err := migrador.Reverter(3); err != nil {
+		t.Fatalf("erro ao reverter tudo: %v", err)
+	}
+	if err := migrador.Reverter(1); err == nil {
+		t.Error("sem migrações aplicadas não há o que desfazer")
+	}
+}
+
+func TestReverterParaNaMigracaoIrreversivel(t *testing.T) {
+	db := novoBanco(t)
+	migracoes := migracoesTeste()
+	migracoes[1].Down = nil
+	migrador := NewMigrador(db, migracoes)
+	if err := migrador.Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+
+	if err := migrador.Reverter(3); err == nil {
+		t.Fatal("a migração sem passo de reversão deveria interromper o rollback")
+	}
+	// A versão 3 foi desfeita antes da parada; a 2 continua aplicada
+	if versoes := versoesAplicadas(t, db); len(versoes) != 2 || versoes[1] != 2 {
+		t.Errorf("versões aplicadas = %v, esperado [1 2]", versoes)
+	}
+}
+
+func TestMigrarInterrompeNaFalha(t *testing.T) {
+	db := novoBanco(t)
+	migracoes := migracoesTeste()
+	migracoes[1].Up = func(db *gorm.DB) error { return errors.New("falha simulada") }
+
+	err := NewMigrador(db, migracoes).Migrar(0)
+	if err == nil || !strings.Contains(err.Error(), "falha simulada") {
+		t.Fatalf("erro = %v, esperado a falha da versão 2", err)
+	}
+	if versoes := versoesAplicadas(t, db); len(versoes) != 1 {
+		t.Errorf("versões aplicadas = %v, esperado apenas a 1", versoes)
+	}
+	if db.Migrator().HasTable("modelos") {
+		t.Error("as migrações após a falha não deveriam ser aplicadas")
+	}
+}
+
+func TestMigradorFiltraPorBanco(t *testing.T) {
+	db := novoBanco(t)
+	migracoes := migracoesTeste()
+	migracoes[1].Bancos = []string{"mysql", "postgres"}
+	migracoes[2].Bancos = []string{"sqlite"}
+	migrador := NewMigrador(db, migracoes)
+
+	if err := migrador.Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+	if versoes := versoesAplicadas(t, db); len(versoes) != 2 || versoes[1] != 3 {
+		t.Errorf("versões aplicadas = %v, esperado [1 3]", versoes)
+	}
+
+	situacoes, _ := migrador.Situacao()
+	if len(situacoes) != 2 {
+		t.Errorf("situação com %d migrações, esperado apenas as do SQLite", len(situacoes))
+	}
+}
+
+func TestMigradorValidaALista(t *testing.T) {
+	invalidas := map[string]func([]Migracao){
+		"fora de ordem":       func(m []Migracao) { m[1].Versao = 5 },
+		"versão repetida":     func(m []Migracao) { m[2].Versao = 2 },
+		"sem passo de subida": func(m []Migracao) { m[0].Up = nil },
+		"arquivo inexistente": func(m []Migracao) { m[2].Arquivo = "20991231_inexistente.go" },
+	}
+	for nome, alterar := range invalidas {
+		migracoes := migracoesTeste()
+		alterar(migracoes)
+		db := novoBanco(t)
+		if err := NewMigrador(db, migracoes).Migrar(0); err == nil {
+			t.Errorf("%s: a lista deveria ser recusada", nome)
+		}
+		if db.Migrator().HasTable("pecas") {
+			t.Errorf("%s: nada deveria ser aplicado com a lista inválida", nome)
+		}
+	}
+}
+
+func TestTodasAplicamNoSQLite(t *testing.T) {
+	db := novoBanco(t)
+	migrador := NewMigrador(db, Todas())
+	if err := migrador.Migrar(0); err != nil {
+		t.Fatalf("erro ao aplicar as migrações do sistema: %v", err)
+	}
+
+	situacoes, err := migrador.Situacao()
+	if err != nil {
+		t.Fatalf("erro ao consultar a situação: %v", err)
+	}
+	for _, situacao := range situacoes {
+		if !situacao.Aplicada || situacao.ChecksumDivergente || situacao.Desconhecida {
+			t.Errorf("migração %d: %+v", situacao.Versao, situacao)
+		}
+	}
+}
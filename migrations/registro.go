@@ -0,0 +1,102 @@
+package migrations
+
+// Todas retorna as migrações do sistema em ordem de versão.
+// Uma migração aplicada não deve ser editada (a soma de verificação do arquivo é conferida a cada execução):
+// mudanças no esquema entram como uma nova migração no fim da lista
+func Todas() []Migracao {
+	return []Migracao{
+		{
+			Versao:    1,
+			Descricao: "esquema inicial",
+			Arquivo:   "20261016_esquema_inicial.go",
+			Up:        CriarEsquemaInicial,
+			// Versão que criava as tabelas a partir dos modelos atuais, antes de o esquema ser congelado
+			ChecksumsAnteriores: []string{"0c19e45a748a0f7708ef990bbe6f14678ec4b6a6f505e2c12c8d145439918e6b"},
+		},
+		{
+			Versao:    2,
+			Descricao: "campos de funcionário em usuários",
+			Arquivo:   "20240610_add_funcionario_fields_to_usuarios.go",
+			Up:        AddFuncionarioFieldsToUsuarios,
+			Down:      RemoveFuncionarioFieldsFromUsuarios,
+		},
+		{
+			Versao:    3,
+			Descricao: "fornecedores do estoque em tabela própria",
+			Arquivo:   "20261016_migrar_fornecedores.go",
+			Up:        MigrarFornecedores,
+			// Versão que removia a coluna pelo Migrator, o que falhava no SQLite; o resultado nos demais bancos é o mesmo
+			ChecksumsAnteriores: []string{"6bf2c3ea6df11e28e6d357626b3a97fb9a348a299347935e7c287aafd12b52b9"},
+		},
+		{
+			Versao:    4,
+			Descricao: "valor de serviço das OS em linhas de mão de obra",
+			Arquivo:   "20261016_migrar_valor_servico.go",
+			Up:        MigrarValorServico,
+		},
+		{
+			Versao:    5,
+			Descricao: "normalização das placas",
+			Arquivo:   "20261016_normalizar_placas.go",
+			Up:        NormalizarPlacas,
+		},
+		{
+			Versao:    6,
+			Descricao: "índices de consulta e de busca textual",
+			Arquivo:   "20261016_indices.go",
+			Up:        CriarIndicesConsulta,
+			Down:      RemoverIndicesConsulta,
+			Bancos:    []string{"mysql"},
+		},
+		{
+			Versao:    7,
+			Descricao: "índices de consulta sem busca textual (PostgreSQL e SQLite)",
+			Arquivo:   "20261016_indices_portaveis.go",
+			Up:        CriarIndicesPortaveis,
+			Down:      RemoverIndicesPortaveis,
+			Bancos:    []string{"postgres", "sqlite"},
+		},
+		{
+			Versao:    8,
+			Descricao: "sessões de login e tokens de renovação",
+			Arquivo:   "20261016_sessoes.go",
+			Up:        CriarSessoes,
+			Down:      RemoverSessoes,
+		},
+		{
+			Versao:    9,
+			Descricao: "histórico de logins e bloqueio por tentativas",
+			Arquivo:   "20261016_protecao_login.go",
+			Up:        CriarProtecaoLogin,
+			Down:      RemoverProtecaoLogin,
+		},
+		{
+			Versao:    10,
+			Descricao: "pedidos de redefinição de senha",
+			Arquivo:   "20261016_redefinicao_senha.go",
+			Up:        CriarRedefinicoesSenha,
+			Down:      RemoverRedefinicoesSenha,
+		},
+		{
+			Versao:    11,
+			Descricao: "histórico de senhas",
+			Arquivo:   "20261016_historico_senhas.go",
+			Up:        CriarHistoricoSenhas,
+			Down:      RemoverHistoricoSenhas,
+		},
+		{
+			Versao:    12,
+			Descricao: "limpeza dos controles de login",
+			Arquivo:   "20261016_limpeza_controles_login.go",
+			Up:        CriarIndiceLimpezaControlesLogin,
+			Down:      RemoverIndiceLimpezaControlesLogin,
+		},
+		{
+			Versao:    13,
+			Descricao: "segredo do feed da agenda por funcionário",
+			Arquivo:   "20261016_feeds_agenda.go",
+			Up:        CriarFeedsAgenda,
+			Down:      RemoverFeedsAgenda,
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Motivos registrados no histórico de tentativas de login
+const (
+	MotivoAcessoCredenciaisInvalidas = "credenciais_invalidas" // E-mail sem cadastro ou senha incorreta; conta para o bloqueio
+	MotivoAcessoUsuarioInativo       = "usuario_inativo"       // Senha correta, mas a conta não pode acessar
+	MotivoAcessoBloqueado            = "bloqueado"             // Recusada antes de conferir a senha
+)
+
+// Alvos do controle de falhas de login
+const (
+	ControleLoginConta = "conta" // Pelo e-mail informado, exista ou não o usuário
+	ControleLoginIP    = "ip"
+)
+
+// TentativaAcesso é o histórico de logins, com sucesso ou não, para auditoria
+type TentativaAcesso struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID *uint     `json:"usuarioId,omitempty" gorm:"index"` // Vazio quando o e-mail não tem cadastro
+	Email     string    `json:"email" gorm:"not null;size:100;index"`
+	IP        string    `json:"ip" gorm:"size:45;index"`
+	UserAgent string    `json:"userAgent" gorm:"size:255"`
+	Sucesso   bool      `json:"sucesso" gorm:"not null;default:false"`
+	Motivo    string    `json:"motivo,omitempty" gorm:"size:30"`
+	CreatedAt time.Time `json:"criadoEm" gorm:"autoCreateTime;index"`
+}
+
+// ControleLogin acumula as falhas recentes de uma conta ou de um IP. Cada falha aumenta a espera até a
+// próxima tentativa; ao atingir o limite, o alvo fica bloqueado por um período
+type ControleLogin struct {
+	ID               uint       `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Tipo             string     `json:"tipo" gorm:"not null;size:10;uniqueIndex:idx_controle_login"`
+	Chave            string     `json:"chave" gorm:"not null;size:100;uniqueIndex:idx_controle_login"` // E-mail em minúsculas ou IP
+	Falhas           int        `json:"falhas" gorm:"not null;default:0"`
+	UltimaFalha      *time.Time `json:"ultimaFalha,omitempty"`
+	ProximaTentativa *time.Time `json:"proximaTentativa,omitempty"`
+	BloqueadoAte     *time.Time `json:"bloqueadoAte,omitempty"`
+	UpdatedAt        time.Time  `json:"atualizadoEm" gorm:"autoUpdateTime"`
+}
+
+// TableName especifica o nome da tabela para TentativaAcesso
+func (TentativaAcesso) TableName() string {
+	return "tentativas_acesso"
+}
+
+// TableName especifica o nome da tabela para ControleLogin
+func (ControleLogin) TableName() string {
+	return "controles_login"
+}
+
+// Espera retorna quanto falta para o alvo poder tentar o login de novo (zero se já pode)
+func (c *ControleLogin) Espera(agora time.Time) time.Duration {
+	var espera time.Duration
+	for _, limite := range []*time.Time{c.BloqueadoAte, c.ProximaTentativa} {
+		if limite != nil && limite.Sub(agora) > espera {
+			espera = limite.Sub(agora)
+		}
+	}
+	return espera
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status do agendamento
+const (
+	StatusAgendamentoAgendado      = "agendado"
+	StatusAgendamentoConfirmado    = "confirmado"
+	StatusAgendamentoAtendido      = "atendido" // Check-in feito; o veículo entrou com uma OS
+	StatusAgendamentoCancelado     = "cancelado"
+	StatusAgendamentoNaoCompareceu = "nao_compareceu"
+)
+
+// Box representa um elevador ou vaga de trabalho da oficina, recurso disputado pelos agendamentos
+type Box struct {
+	ID        uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Nome      string         `json:"nome" gorm:"not null;size:50;uniqueIndex" binding:"required"`
+	Descricao string         `json:"descricao" gorm:"size:255"`
+	Ativo     bool           `json:"ativo" gorm:"not null;default:true"`
+	CreatedAt time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Agendamento reserva um box (e, opcionalmente, um mecânico) para receber o veículo de um cliente
+type Agendamento struct {
+	ID                 uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	ClienteID          uint           `json:"clienteId" gorm:"not null;index" binding:"required"`
+	Cliente            *Cliente       `json:"cliente,omitempty" gorm:"foreignKey:ClienteID"`
+	VeiculoID          uint           `json:"veiculoId" gorm:"not null;index" binding:"required"`
+	Veiculo            *Veiculo       `json:"veiculo,omitempty" gorm:"foreignKey:VeiculoID"`
+	BoxID              uint           `json:"boxId" gorm:"not null;index:idx_agendamento_box_periodo" binding:"required"`
+	Box                *Box           `json:"box,omitempty" gorm:"foreignKey:BoxID"`
+	FuncionarioID      *uint          `json:"funcionarioId" gorm:"index:idx_agendamento_funcionario_periodo"`
+	Funcionario        *Funcionario   `json:"funcionario,omitempty" gorm:"foreignKey:FuncionarioID"`
+	DataInicio         time.Time      `json:"dataInicio" gorm:"not null;index:idx_agendamento_box_periodo;index:idx_agendamento_funcionario_periodo" binding:"required"`
+	DataFim            time.Time      `json:"dataFim" gorm:"not null" binding:"required"`
+	Status             string         `json:"status" gorm:"not null;default:'agendado';size:20;index"`
+	Descricao          string         `json:"descricao" gorm:"type:text"` // Serviço pedido pelo cliente; vira a descrição da OS no check-in
+	Observacoes        string         `json:"observacoes" gorm:"type:text"`
+	MotivoCancelamento string         `json:"motivoCancelamento,omitempty" gorm:"type:text"`
+	OrdemServicoID     *uint          `json:"ordemServicoId" gorm:"index"` // OS aberta no check-in
+	CriadoPorID        *uint          `json:"criadoPorId"`
+	CreatedAt          time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Feriado é um dia em que a oficina não recebe agendamentos
+type Feriado struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Data      time.Time `json:"data" gorm:"type:date;not null;uniqueIndex" binding:"required"`
+	Descricao string    `json:"descricao" gorm:"not null;size:100" binding:"required"`
+	CreatedAt time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// TableName especifica o nome da tabela para Box
+func (Box) TableName() string {
+	return "boxes"
+}
+
+// TableName especifica o nome da tabela para Agendamento
+func (Agendamento) TableName() string {
+	return "agendamentos"
+}
+
+// TableName especifica o nome da tabela para Feriado
+func (Feriado) TableName() string {
+	return "feriados"
+}
+
+// BeforeCreate define o status padrão do agendamento
+func (a *Agendamento) BeforeCreate(tx *gorm.DB) error {
+	if a.Status == "" {
+		a.Status = StatusAgendamentoAgendado
+	}
+	return nil
+}
+
+// Ativo indica se o agendamento ainda ocupa o box e o mecânico no horário reservado
+func (a *Agendamento) Ativo() bool {
+	return a.Status == StatusAgendamentoAgendado || a.Status == StatusAgendamentoConfirmado
+}
+
+// HorarioFuncionamento define o expediente de um dia da semana (0 = domingo), no formato HH:MM
+type HorarioFuncionamento struct {
+	DiaSemana  int    `json:"diaSemana"`
+	Abertura   string `json:"abertura"`
+	Fechamento string `json:"fechamento"`
+}
+
+// AgendaConfig guarda o expediente da oficina e a duração padrão dos horários oferecidos.
+// Dias da semana ausentes em Horarios são dias sem expediente
+type AgendaConfig struct {
+	DuracaoSlotMinutos int                    `json:"duracaoSlotMinutos"`
+	Horarios           []HorarioFuncionamento `json:"horarios"`
+}
+
+// RecursoAgenda identifica um box ou mecânico livre em um horário
+type RecursoAgenda struct {
+	ID   uint   `json:"id"`
+	Nome string `json:"nome"`
+}
+
+// HorarioDisponivel é um intervalo do expediente com os boxes e mecânicos livres nele
+type HorarioDisponivel struct {
+	Inicio    time.Time       `json:"inicio"`
+	Fim       time.Time       `json:"fim"`
+	Boxes     []RecursoAgenda `json:"boxes"`
+	Mecanicos []RecursoAgenda `json:"mecanicos"`
+}
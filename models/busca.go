@@ -0,0 +1,31 @@
+package models
+
+// ClienteEncontrado é um cliente retornado pela busca textual com a sua relevância
+type ClienteEncontrado struct {
+	Relevancia float64 `json:"relevancia"`
+	Cliente    Cliente `json:"cliente"`
+}
+
+// VeiculoEncontrado é um veículo retornado pela busca textual; a relevância soma
+// o que casou no veículo e no nome do dono
+type VeiculoEncontrado struct {
+	Relevancia float64  `json:"relevancia"`
+	Veiculo    Veiculo  `json:"veiculo"`
+	Cliente    *Cliente `json:"cliente,omitempty"`
+}
+
+// OrdemServicoEncontrada é uma OS retornada pela busca textual; a relevância soma
+// o que casou na OS, no veículo e no cliente, para que "gol prata joão" encontre a OS certa
+type OrdemServicoEncontrada struct {
+	Relevancia   float64      `json:"relevancia"`
+	OrdemServico OrdemServico `json:"ordemServico"`
+}
+
+// ResultadoBusca agrupa por tipo os registros encontrados, cada grupo do mais ao menos relevante.
+// Grupos que o usuário não tem permissão para ver vêm vazios
+type ResultadoBusca struct {
+	Consulta      string                   `json:"consulta"`
+	Clientes      []ClienteEncontrado      `json:"clientes"`
+	Veiculos      []VeiculoEncontrado      `json:"veiculos"`
+	OrdensServico []OrdemServicoEncontrada `json:"ordensServico"`
+}
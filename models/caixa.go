@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Situação do caixa
+const (
+	StatusCaixaAberto  = "aberto"
+	StatusCaixaFechado = "fechado"
+)
+
+// Tipos de movimentação de caixa
+const (
+	TipoMovimentacaoCaixaRecebimento = "recebimento" // Pagamento de OS recebido no caixa
+	TipoMovimentacaoCaixaEstorno     = "estorno"     // Devolução de um pagamento recebido
+	TipoMovimentacaoCaixaSangria     = "sangria"     // Retirada de dinheiro da gaveta
+	TipoMovimentacaoCaixaSuprimento  = "suprimento"  // Reforço de troco na gaveta
+)
+
+// Caixa representa um turno de caixa da recepção, aberto por um operador com o troco inicial
+// e fechado com a contagem dos valores por forma de pagamento
+type Caixa struct {
+	ID                    uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID             uint           `json:"usuarioId" gorm:"not null;index"` // Operador que abriu o caixa
+	Status                string         `json:"status" gorm:"not null;default:'aberto';size:20;index"`
+	DataAbertura          time.Time      `json:"dataAbertura" gorm:"not null;index"`
+	ValorInicial          float64        `json:"valorInicial" gorm:"type:decimal(10,2);not null;default:0"` // Troco inicial em dinheiro
+	ObservacoesAbertura   string         `json:"observacoesAbertura" gorm:"type:text"`
+	DataFechamento        *time.Time     `json:"dataFechamento"`
+	FechadoPorID          *uint          `json:"fechadoPorId"`
+	ObservacoesFechamento string         `json:"observacoesFechamento" gorm:"type:text"`
+	TotalEsperado         float64        `json:"totalEsperado" gorm:"type:decimal(10,2);default:0"`
+	TotalContado          float64        `json:"totalContado" gorm:"type:decimal(10,2);default:0"`
+	Diferenca             float64        `json:"diferenca" gorm:"type:decimal(10,2);default:0"` // Contado menos esperado; negativo indica falta
+	CreatedAt             time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt             time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt             gorm.DeletedAt `json:"-" gorm:"index"`
+
+	Movimentacoes []MovimentacaoCaixa `json:"movimentacoes,omitempty" gorm:"foreignKey:CaixaID"`
+	Conferencias  []ConferenciaCaixa  `json:"conferencias,omitempty" gorm:"foreignKey:CaixaID"`
+}
+
+// MovimentacaoCaixa registra cada entrada ou saída de valores do caixa.
+// Valor é sempre positivo; o tipo define se soma ou subtrai do saldo
+type MovimentacaoCaixa struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	CaixaID        uint      `json:"caixaId" gorm:"not null;index"`
+	Tipo           string    `json:"tipo" gorm:"not null;size:20;index"`
+	Forma          string    `json:"forma" gorm:"not null;size:30"`
+	Valor          float64   `json:"valor" gorm:"type:decimal(10,2);not null"`
+	PagamentoID    *uint     `json:"pagamentoId" gorm:"index"`
+	OrdemServicoID *uint     `json:"ordemServicoId" gorm:"index"`
+	UsuarioID      *uint     `json:"usuarioId" gorm:"index"`
+	Descricao      string    `json:"descricao" gorm:"size:255"`
+	CriadoEm       time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// ConferenciaCaixa guarda, para cada forma de pagamento, o valor esperado pelo sistema
+// e o valor contado pelo operador no fechamento
+type ConferenciaCaixa struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	CaixaID       uint      `json:"caixaId" gorm:"not null;uniqueIndex:idx_conferencia_caixa_forma"`
+	Forma         string    `json:"forma" gorm:"not null;size:30;uniqueIndex:idx_conferencia_caixa_forma"`
+	ValorEsperado float64   `json:"valorEsperado" gorm:"type:decimal(10,2);not null"`
+	ValorContado  float64   `json:"valorContado" gorm:"type:decimal(10,2);not null"`
+	Diferenca     float64   `json:"diferenca" gorm:"type:decimal(10,2);not null"`
+	CriadoEm      time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// TableName especifica o nome da tabela para Caixa
+func (Caixa) TableName() string {
+	return "caixas"
+}
+
+// TableName especifica o nome da tabela para MovimentacaoCaixa
+func (MovimentacaoCaixa) TableName() string {
+	return "movimentacoes_caixa"
+}
+
+// TableName especifica o nome da tabela para ConferenciaCaixa
+func (ConferenciaCaixa) TableName() string {
+	return "conferencias_caixa"
+}
+
+// BeforeCreate define a data de abertura e o status inicial do caixa
+func (c *Caixa) BeforeCreate(tx *gorm.DB) error {
+	if c.DataAbertura.IsZero() {
+		c.DataAbertura = time.Now()
+	}
+	if c.Status == "" {
+		c.Status = StatusCaixaAberto
+	}
+	return nil
+}
+
+// Aberto indica se o caixa ainda aceita movimentações
+func (c *Caixa) Aberto() bool {
+	return c.Status == StatusCaixaAberto
+}
+
+// Variacao retorna o efeito da movimentação sobre o saldo da forma de pagamento
+func (m *MovimentacaoCaixa) Variacao() float64 {
+	if m.Tipo == TipoMovimentacaoCaixaSangria || m.Tipo == TipoMovimentacaoCaixaEstorno {
+		return -m.Valor
+	}
+	return m.Valor
+}
+
+// ResumoFormaCaixa consolida as movimentações de uma forma de pagamento.
+// Contado e Diferenca só são preenchidos depois do fechamento
+type ResumoFormaCaixa struct {
+	Forma        string   `json:"forma"`
+	Recebimentos float64  `json:"recebimentos"`
+	Estornos     float64  `json:"estornos"`
+	Suprimentos  float64  `json:"suprimentos"`
+	Sangrias     float64  `json:"sangrias"`
+	Esperado     float64  `json:"esperado"`
+	Contado      *float64 `json:"contado,omitempty"`
+	Diferenca    *float64 `json:"diferenca,omitempty"`
+}
+
+// RelatorioCaixa é o DTO do relatório de conferência de um caixa
+type RelatorioCaixa struct {
+	Caixa          Caixa              `json:"caixa"`
+	Formas         []ResumoFormaCaixa `json:"formas"`
+	TotalEsperado  float64            `json:"totalEsperado"`
+	TotalContado   *float64           `json:"totalContado,omitempty"`
+	DiferencaTotal *float64           `json:"diferencaTotal,omitempty"`
+}
@@ -6,17 +6,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// Tipos de pessoa do cliente
+const (
+	TipoPessoaFisica   = "fisica"
+	TipoPessoaJuridica = "juridica"
+)
+
 // Cliente representa a tabela clientes no banco de dados
 type Cliente struct {
-	ID             uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
-	Nome           string         `json:"nome" gorm:"not null;size:100;index" binding:"required"`
-	Email          *string        `json:"email" gorm:"size:100"`
-	Telefone       *string        `json:"telefone" gorm:"size:20"`
-	Endereco       string         `json:"endereco" gorm:"size:255"`
-	CreatedAt      time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
-	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`                                 // Suporte a soft delete
-	Veiculos       []Veiculo      `json:"veiculos,omitempty" gorm:"foreignKey:ClienteID"` // Relacionamento um para muitos
+	ID                uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Nome              string         `json:"nome" gorm:"not null;size:100;index" binding:"required"`
+	TipoPessoa        string         `json:"tipoPessoa" gorm:"not null;default:'fisica';size:10"` // Física ou jurídica
+	Documento         *string        `json:"documento" gorm:"size:18;uniqueIndex"`                // CPF ou CNPJ formatado, conforme o tipo de pessoa
+	RazaoSocial       string         `json:"razaoSocial" gorm:"size:150"`                         // Apenas pessoa jurídica
+	InscricaoEstadual string         `json:"inscricaoEstadual" gorm:"size:20"`                    // Apenas pessoa jurídica; "ISENTO" quando dispensada
+	Email             *string        `json:"email" gorm:"size:100"`
+	Telefone          *string        `json:"telefone" gorm:"size:20"`
+	Endereco          string         `json:"endereco" gorm:"size:255"`
+	CreatedAt         time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`                                 // Suporte a soft delete
+	Veiculos          []Veiculo      `json:"veiculos,omitempty" gorm:"foreignKey:ClienteID"` // Relacionamento um para muitos
 }
 
 // TableName especifica o nome da tabela a ser usada
@@ -7,21 +7,23 @@ import (
 )
 
 type Estoque struct {
-	ID            uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
-	Nome          string         `json:"nome" gorm:"not null;size:100;index" binding:"required"`
-	Codigo        string         `json:"codigo" gorm:"size:100;uniqueIndex"`
-	Descricao     string         `json:"descricao" gorm:"type:text"`
-	Categoria     string         `json:"categoria" gorm:"size:50;index"`
-	Quantidade    int            `json:"quantidade" gorm:"default:0;not null"`
-	EstoqueMinimo int            `json:"estoque_minimo" gorm:"default:5"`
-	PrecoUnitario float64        `json:"preco_unitario" gorm:"type:decimal(10,2);not null;default:0.00"`
-	PrecoVenda    float64        `json:"preco_venda" gorm:"type:decimal(10,2);not null;default:0.00"`
-	Fornecedor    string         `json:"fornecedor" gorm:"size:100;index"`
-	Status        string         `json:"status" gorm:"size:20;default:'disponível';index"`
-	Observacoes   string         `json:"observacoes" gorm:"type:text"`
-	CriadoEm      time.Time      `json:"criado_em" gorm:"autoCreateTime"`
-	AtualizadoEm  time.Time      `json:"atualizado_em" gorm:"autoUpdateTime"`
-	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID                  uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Nome                string         `json:"nome" gorm:"not null;size:100;index" binding:"required"`
+	Codigo              string         `json:"codigo" gorm:"size:100;uniqueIndex"`
+	Descricao           string         `json:"descricao" gorm:"type:text"`
+	Categoria           string         `json:"categoria" gorm:"size:50;index"`
+	Quantidade          int            `json:"quantidade" gorm:"default:0;not null"`
+	QuantidadeReservada int            `json:"quantidadeReservada" gorm:"default:0;not null"` // Reservada por orçamentos pendentes ou aprovados
+	EstoqueMinimo       int            `json:"estoque_minimo" gorm:"default:5"`
+	PrecoUnitario       float64        `json:"preco_unitario" gorm:"type:decimal(10,2);not null;default:0.00"`
+	PrecoVenda          float64        `json:"preco_venda" gorm:"type:decimal(10,2);not null;default:0.00"`
+	FornecedorID        *uint          `json:"fornecedorId" gorm:"index"` // Fornecedor principal, usado nos pedidos de reposição
+	Fornecedor          *Fornecedor    `json:"fornecedor,omitempty" gorm:"foreignKey:FornecedorID"`
+	Status              string         `json:"status" gorm:"size:20;default:'disponível';index"`
+	Observacoes         string         `json:"observacoes" gorm:"type:text"`
+	CriadoEm            time.Time      `json:"criado_em" gorm:"autoCreateTime"`
+	AtualizadoEm        time.Time      `json:"atualizado_em" gorm:"autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 func (Estoque) TableName() string {
@@ -54,6 +56,11 @@ func (e *Estoque) CalcularValorTotal() float64 {
 	return float64(e.Quantidade) * e.PrecoVenda
 }
 
+// QuantidadeDisponivel retorna o saldo que não está reservado por orçamentos
+func (e *Estoque) QuantidadeDisponivel() int {
+	return e.Quantidade - e.QuantidadeReservada
+}
+
 // PrecisaReposicao verifica se o estoque está abaixo do mínimo
 func (e *Estoque) PrecisaReposicao() bool {
 	return e.Quantidade < e.EstoqueMinimo
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// FeedAgenda guarda o segredo do link público do feed iCalendar de um funcionário.
+// Só o hash SHA-256 do token é gravado; gerar um novo link invalida o anterior
+type FeedAgenda struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	FuncionarioID uint      `json:"funcionarioId" gorm:"not null;uniqueIndex"`
+	TokenHash     string    `json:"-" gorm:"not null;size:64"`
+	CreatedAt     time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"atualizadoEm" gorm:"autoUpdateTime"`
+}
+
+// TableName especifica o nome da tabela para FeedAgenda
+func (FeedAgenda) TableName() string {
+	return "feeds_agenda"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Fornecedor representa uma empresa que fornece peças para a oficina
+type Fornecedor struct {
+	ID               uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Nome             string         `json:"nome" gorm:"not null;size:100;uniqueIndex" binding:"required"`
+	CNPJ             *string        `json:"cnpj" gorm:"size:18;uniqueIndex"` // Opcional: NULL é permitido pelo índice único
+	Contato          string         `json:"contato" gorm:"size:100"`
+	Email            string         `json:"email" gorm:"size:100"`
+	Telefone         string         `json:"telefone" gorm:"size:20"`
+	PrazoEntregaDias int            `json:"prazoEntregaDias" gorm:"default:0"` // Prazo médio de entrega, em dias
+	Observacoes      string         `json:"observacoes" gorm:"type:text"`
+	Ativo            bool           `json:"ativo" gorm:"default:true"`
+	CreatedAt        time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName especifica o nome da tabela para Fornecedor
+func (Fornecedor) TableName() string {
+	return "fornecedores"
+}
+
+// FornecedorItem guarda o código e o preço de uma peça em um fornecedor específico
+type FornecedorItem struct {
+	ID               uint        `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	FornecedorID     uint        `json:"fornecedorId" gorm:"not null;uniqueIndex:idx_fornecedor_item"`
+	Fornecedor       *Fornecedor `json:"fornecedor,omitempty" gorm:"foreignKey:FornecedorID"`
+	EstoqueID        uint        `json:"estoqueId" gorm:"not null;uniqueIndex:idx_fornecedor_item;index" binding:"required"`
+	Item             *Estoque    `json:"item,omitempty" gorm:"foreignKey:EstoqueID"`
+	CodigoFornecedor string      `json:"codigoFornecedor" gorm:"size:100"`
+	Preco            float64     `json:"preco" gorm:"type:decimal(10,2);not null;default:0.00"`
+	PrazoEntregaDias *int        `json:"prazoEntregaDias"` // Quando vazio, vale o prazo do fornecedor
+	CreatedAt        time.Time   `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time   `json:"atualizadoEm" gorm:"autoUpdateTime"`
+}
+
+// TableName especifica o nome da tabela para FornecedorItem
+func (FornecedorItem) TableName() string {
+	return "fornecedor_itens"
+}
+
+// RelatorioFornecedor resume os itens de estoque de um fornecedor
+type RelatorioFornecedor struct {
+	FornecedorID      uint    `json:"fornecedorId"`
+	Nome              string  `json:"nome"`
+	QuantidadeItens   int     `json:"quantidadeItens"`
+	QuantidadeEstoque int     `json:"quantidadeEstoque"`
+	ValorEstoque      float64 `json:"valorEstoque"`
+	ItensBaixoEstoque int     `json:"itensBaixoEstoque"`
+}
@@ -21,8 +21,9 @@ type Funcionario struct {
 	Cargo           string          `json:"cargo" gorm:"size:50;index"`
 
 	// Chave estrangeira para Usuário
-	UsuarioID uint    `json:"usuarioId" gorm:"uniqueIndex"` // Um funcionário tem exatamente um usuário
-	Usuario   Usuario `json:"usuario,omitempty" gorm:"foreignKey:UsuarioID"`
+	// Opcional: funcionários sem acesso ao sistema ficam com NULL, permitido pelo índice único
+	UsuarioID *uint    `json:"usuarioId" gorm:"uniqueIndex"` // Um funcionário tem no máximo um usuário
+	Usuario   *Usuario `json:"usuario,omitempty" gorm:"foreignKey:UsuarioID"`
 
 	CreatedAt time.Time       `json:"criadoEm" gorm:"autoCreateTime"`
 	UpdatedAt time.Time       `json:"atualizadoEm" gorm:"autoUpdateTime"`
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// HistoricoSenha guarda o hash de cada senha definida pelo usuário, para impedir a reutilização das últimas
+type HistoricoSenha struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID uint      `json:"usuarioId" gorm:"not null;index"`
+	SenhaHash string    `json:"-" gorm:"not null;size:60"`
+	CreatedAt time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// TableName especifica o nome da tabela para HistoricoSenha
+func (HistoricoSenha) TableName() string {
+	return "historico_senhas"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Origens de uma leitura de quilometragem
+const (
+	OrigemLeituraKmOrdemServico = "ordem_servico" // Registrada na entrada do veículo
+	OrigemLeituraKmManual       = "manual"
+)
+
+// LeituraKm registra o hodômetro do veículo em uma data; a sequência de leituras
+// permite estimar o uso médio diário
+type LeituraKm struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	VeiculoID      uint      `json:"veiculoId" gorm:"not null;index:idx_leitura_km_veiculo_data"`
+	Km             int       `json:"km" gorm:"not null"`
+	DataLeitura    time.Time `json:"dataLeitura" gorm:"not null;index:idx_leitura_km_veiculo_data"`
+	Origem         string    `json:"origem" gorm:"not null;size:20"`
+	OrdemServicoID *uint     `json:"ordemServicoId" gorm:"index"`
+	UsuarioID      *uint     `json:"usuarioId"`
+	CriadoEm       time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// PlanoManutencao define um serviço preventivo do veículo e o intervalo em km e/ou meses
+// entre uma realização e a próxima
+type PlanoManutencao struct {
+	ID                   uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	VeiculoID            uint           `json:"veiculoId" gorm:"not null;index"`
+	Veiculo              *Veiculo       `json:"veiculo,omitempty" gorm:"foreignKey:VeiculoID"`
+	Descricao            string         `json:"descricao" gorm:"not null;size:100" binding:"required"` // Ex.: troca de óleo
+	ServicoID            *uint          `json:"servicoId" gorm:"index"`                                // Serviço do catálogo que, concluído em uma OS, renova o plano
+	IntervaloKm          int            `json:"intervaloKm" gorm:"not null;default:0"`                 // Zero quando o plano é só por tempo
+	IntervaloMeses       int            `json:"intervaloMeses" gorm:"not null;default:0"`              // Zero quando o plano é só por km
+	UltimaRealizacaoKm   int            `json:"ultimaRealizacaoKm" gorm:"not null;default:0"`
+	UltimaRealizacaoData *time.Time     `json:"ultimaRealizacaoData"`
+	Ativo                bool           `json:"ativo" gorm:"not null;default:true"`
+	Observacoes          string         `json:"observacoes" gorm:"type:text"`
+	CreatedAt            time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt            time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName especifica o nome da tabela para LeituraKm
+func (LeituraKm) TableName() string {
+	return "leituras_km"
+}
+
+// TableName especifica o nome da tabela para PlanoManutencao
+func (PlanoManutencao) TableName() string {
+	return "planos_manutencao"
+}
+
+// SituacaoManutencao é o DTO com o vencimento calculado de um plano de manutenção.
+// A data prevista por km é projetada a partir da média de uso do veículo; o vencimento
+// é a primeira entre ela e a data limite por tempo
+type SituacaoManutencao struct {
+	Plano          PlanoManutencao `json:"plano"`
+	Veiculo        *Veiculo        `json:"veiculo,omitempty"`
+	Cliente        *Cliente        `json:"cliente,omitempty"`
+	KmAtual        int             `json:"kmAtual"`
+	MediaKmDia     *float64        `json:"mediaKmDia"`
+	ProximaKm      *int            `json:"proximaKm"`
+	DataPrevistaKm *time.Time      `json:"dataPrevistaKm"`
+	ProximaData    *time.Time      `json:"proximaData"`
+	Vencimento     *time.Time      `json:"vencimento"`
+	DiasRestantes  *int            `json:"diasRestantes"`
+	Vencida        bool            `json:"vencida"`
+}
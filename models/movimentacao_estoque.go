@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// Tipos de movimentação de estoque
+const (
+	TipoMovimentacaoEntrada = "entrada"
+	TipoMovimentacaoSaida   = "saida"
+	TipoMovimentacaoAjuste  = "ajuste"
+)
+
+// MovimentacaoEstoque registra cada alteração na quantidade de um item do estoque.
+// Para entradas e saídas, Quantidade é sempre positiva; para ajustes, é a diferença
+// (positiva ou negativa) aplicada ao saldo.
+type MovimentacaoEstoque struct {
+	ID                 uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	EstoqueID          uint      `json:"estoqueId" gorm:"not null;index:idx_movimentacao_estoque_data"`
+	Estoque            *Estoque  `json:"estoque,omitempty" gorm:"foreignKey:EstoqueID"`
+	Tipo               string    `json:"tipo" gorm:"not null;size:20;index"`
+	Quantidade         int       `json:"quantidade" gorm:"not null"`
+	SaldoAnterior      int       `json:"saldoAnterior" gorm:"not null"`
+	SaldoPosterior     int       `json:"saldoPosterior" gorm:"not null"`
+	CustoUnitario      float64   `json:"custoUnitario" gorm:"type:decimal(10,2);not null;default:0.00"`
+	UsuarioID          *uint     `json:"usuarioId" gorm:"index"`
+	Motivo             string    `json:"motivo" gorm:"size:255"`
+	OrdemServicoID     *uint     `json:"ordemServicoId" gorm:"index"`
+	ItemOrdemServicoID *uint     `json:"itemOrdemServicoId" gorm:"index"` // Sem chave estrangeira: o item pode ser removido da OS depois
+	PedidoCompraID     *uint     `json:"pedidoCompraId" gorm:"index"`
+	CriadoEm           time.Time `json:"criadoEm" gorm:"autoCreateTime;index:idx_movimentacao_estoque_data"`
+}
+
+// TableName especifica o nome da tabela para MovimentacaoEstoque
+func (MovimentacaoEstoque) TableName() string {
+	return "movimentacoes_estoque"
+}
+
+// Variacao retorna o efeito da movimentação sobre o saldo do item
+func (m *MovimentacaoEstoque) Variacao() int {
+	if m.Tipo == TipoMovimentacaoSaida {
+		return -m.Quantidade
+	}
+	return m.Quantidade
+}
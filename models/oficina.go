@@ -0,0 +1,11 @@
+package models
+
+// DadosOficina reúne as informações da oficina impressas no cabeçalho dos documentos
+type DadosOficina struct {
+	Nome     string `json:"nome"`
+	CNPJ     string `json:"cnpj"`
+	Endereco string `json:"endereco"`
+	Telefone string `json:"telefone"`
+	Email    string `json:"email"`
+	Logo     string `json:"logo"` // Caminho da imagem (PNG ou JPG) enviada em /oficina/logo
+}
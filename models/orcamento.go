@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status possíveis de um orçamento
+const (
+	StatusOrcamentoPendente   = "pendente"
+	StatusOrcamentoAprovado   = "aprovado"
+	StatusOrcamentoRejeitado  = "rejeitado"
+	StatusOrcamentoExpirado   = "expirado"
+	StatusOrcamentoRevisado   = "revisado" // Substituído por uma versão mais nova
+	StatusOrcamentoConvertido = "convertido"
+)
+
+// Tipos de linha do orçamento
+const (
+	TipoItemOrcamentoPeca    = "peca"
+	TipoItemOrcamentoServico = "servico"
+)
+
+// ValidadePadraoOrcamentoDias é o prazo de validade usado quando o orçamento não informa um
+const ValidadePadraoOrcamentoDias = 15
+
+// Orcamento representa a proposta de serviços e peças apresentada ao cliente antes do início do trabalho.
+// Cada revisão gera uma nova versão com o mesmo número; as versões anteriores ficam com status "revisado"
+type Orcamento struct {
+	ID               uint            `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Numero           string          `json:"numero" gorm:"size:20;not null;uniqueIndex:idx_orcamento_versao"`
+	Versao           int             `json:"versao" gorm:"not null;default:1;uniqueIndex:idx_orcamento_versao"`
+	ClienteID        uint            `json:"clienteId" gorm:"not null;index" binding:"required"`
+	Cliente          *Cliente        `json:"cliente,omitempty" gorm:"foreignKey:ClienteID"`
+	VeiculoID        uint            `json:"veiculoId" gorm:"not null;index" binding:"required"`
+	Veiculo          *Veiculo        `json:"veiculo,omitempty" gorm:"foreignKey:VeiculoID"`
+	OrdemServicoID   *uint           `json:"ordemServicoId" gorm:"index"` // OS de origem ou gerada na conversão
+	Status           string          `json:"status" gorm:"not null;default:'pendente';size:20;index"`
+	Descricao        string          `json:"descricao" gorm:"type:text"`
+	ValidadeAte      time.Time       `json:"validadeAte" gorm:"index"`
+	ValorServicos    float64         `json:"valorServicos" gorm:"type:decimal(10,2);default:0"`
+	ValorPecas       float64         `json:"valorPecas" gorm:"type:decimal(10,2);default:0"`
+	ValorDesconto    float64         `json:"valorDesconto" gorm:"type:decimal(10,2);default:0"`
+	ValorTotal       float64         `json:"valorTotal" gorm:"type:decimal(10,2);default:0"`
+	AprovadoPor      string          `json:"aprovadoPor" gorm:"size:100"` // Quem autorizou ou recusou do lado do cliente
+	UsuarioDecisaoID *uint           `json:"usuarioDecisaoId"`            // Usuário que registrou a decisão
+	DataDecisao      *time.Time      `json:"dataDecisao"`
+	MotivoRejeicao   string          `json:"motivoRejeicao" gorm:"type:text"`
+	UsuarioID        *uint           `json:"usuarioId" gorm:"index"` // Usuário que elaborou a versão
+	Observacoes      string          `json:"observacoes" gorm:"type:text"`
+	Itens            []ItemOrcamento `json:"itens" gorm:"foreignKey:OrcamentoID"`
+	CreatedAt        time.Time       `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time       `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// ItemOrcamento representa uma linha do orçamento: uma peça do estoque ou um serviço
+type ItemOrcamento struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	OrcamentoID   uint      `json:"orcamentoId" gorm:"not null;index"`
+	Tipo          string    `json:"tipo" gorm:"not null;size:20" binding:"required"`
+	EstoqueID     *uint     `json:"estoqueId" gorm:"index"` // Obrigatório para peças
+	Item          *Estoque  `json:"item,omitempty" gorm:"foreignKey:EstoqueID"`
+	ServicoID     *uint     `json:"servicoId" gorm:"index"` // Serviço do catálogo, opcional para serviços
+	Servico       *Servico  `json:"servico,omitempty" gorm:"foreignKey:ServicoID"`
+	Descricao     string    `json:"descricao" gorm:"size:255"`
+	Quantidade    int       `json:"quantidade" gorm:"not null;default:1" binding:"required,min=1"`
+	ValorUnitario float64   `json:"valorUnitario" gorm:"type:decimal(10,2);not null;default:0.00"`
+	ValorTotal    float64   `json:"valorTotal" gorm:"type:decimal(10,2);not null;default:0.00"`
+	CreatedAt     time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// TableName especifica o nome da tabela para Orcamento
+func (Orcamento) TableName() string {
+	return "orcamentos"
+}
+
+// TableName especifica o nome da tabela para ItemOrcamento
+func (ItemOrcamento) TableName() string {
+	return "itens_orcamento"
+}
+
+// BeforeCreate gera o número do orçamento na primeira versão e a validade padrão
+func (o *Orcamento) BeforeCreate(tx *gorm.DB) error {
+	if o.Numero == "" {
+		ano, mes, dia := time.Now().Date()
+		var contador int64
+		tx.Model(&Orcamento{}).Unscoped().Where("versao = 1").Count(&contador)
+		o.Numero = fmt.Sprintf("ORC%d%02d%02d-%04d", ano, int(mes), dia, contador+1)
+	}
+
+	if o.Versao == 0 {
+		o.Versao = 1
+	}
+
+	if o.ValidadeAte.IsZero() {
+		o.ValidadeAte = time.Now().AddDate(0, 0, ValidadePadraoOrcamentoDias)
+	}
+
+	if o.Status == "" {
+		o.Status = StatusOrcamentoPendente
+	}
+
+	return nil
+}
+
+// BeforeSave calcula o valor total
+func (o *Orcamento) BeforeSave(tx *gorm.DB) error {
+	o.ValorTotal = o.ValorPecas + o.ValorServicos - o.ValorDesconto
+	return nil
+}
+
+// BeforeSave calcula o valor total da linha
+func (item *ItemOrcamento) BeforeSave(tx *gorm.DB) error {
+	item.ValorTotal = float64(item.Quantidade) * item.ValorUnitario
+	return nil
+}
+
+// Vencido indica se o prazo de validade já passou
+func (o *Orcamento) Vencido(agora time.Time) bool {
+	return !o.ValidadeAte.IsZero() && agora.After(o.ValidadeAte)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,23 +12,30 @@ import (
 type OrdemServico struct {
 	ID                 uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
 	VeiculoID          uint           `json:"veiculoId" gorm:"not null;index" binding:"required"`
-	Veiculo            Veiculo        `json:"veiculo,omitempty" gorm:"foreignKey:VeiculoID"`
+	Veiculo            Veiculo        `json:"veiculo,omitempty" gorm:"foreignKey:VeiculoID" binding:"-"` // Carregado pelo ID; não é validado no corpo da requisição
 	ClienteID          uint           `json:"clienteId" gorm:"not null;index" binding:"required"`
-	Cliente            Cliente        `json:"cliente,omitempty" gorm:"foreignKey:ClienteID"`
-	FuncionarioID      uint           `json:"funcionarioId" gorm:"index"`
-	Funcionario        Funcionario    `json:"funcionario,omitempty" gorm:"foreignKey:FuncionarioID"`
+	Cliente            Cliente        `json:"cliente,omitempty" gorm:"foreignKey:ClienteID" binding:"-"` // Carregado pelo ID; não é validado no corpo da requisição
+	FuncionarioID      *uint          `json:"funcionarioId" gorm:"index"`                                // Mecânico responsável; obrigatório para iniciar o serviço
+	Funcionario        *Funcionario   `json:"funcionario,omitempty" gorm:"foreignKey:FuncionarioID"`
 	NumeroOS           string         `json:"numeroOS" gorm:"size:20;unique;index"`
 	DataEntrada        time.Time      `json:"dataEntrada" gorm:"not null"`
 	DataPrevisao       time.Time      `json:"dataPrevisao"`
 	DataConclusao      *time.Time     `json:"dataConclusao"`
-	Status             string         `json:"status" gorm:"not null;default:'aberta';size:20;index"` // Aberta, EmAndamento, Concluida, Cancelada
+	KmEntrada          int            `json:"kmEntrada" gorm:"default:0"`                            // Hodômetro na entrada; gera uma leitura de km do veículo
+	Status             string         `json:"status" gorm:"not null;default:'aberta';size:20;index"` // Aberta, EmAndamento, Concluida, Entregue, Cancelada
 	Descricao          string         `json:"descricao" gorm:"type:text" binding:"required"`
 	Diagnostico        string         `json:"diagnostico" gorm:"type:text"`
 	ValorPecas         float64        `json:"valorPecas" gorm:"type:decimal(10,2);default:0"`
 	ValorServico       float64        `json:"valorServico" gorm:"type:decimal(10,2);default:0"`
 	ValorDesconto      float64        `json:"valorDesconto" gorm:"type:decimal(10,2);default:0"`
 	ValorTotal         float64        `json:"valorTotal" gorm:"type:decimal(10,2);default:0"`
-	FormaPagamento     string         `json:"formaPagamento" gorm:"size:50"`
+	ValorPago          float64        `json:"valorPago" gorm:"type:decimal(10,2);default:0"` // Soma dos pagamentos não estornados
+	SaldoDevedor       float64        `json:"saldoDevedor" gorm:"-"`
+	StatusFinanceiro   string         `json:"statusFinanceiro" gorm:"not null;default:'pendente';size:20;index"` // Pendente, Parcial, Pago
+	FormaPagamento     string         `json:"formaPagamento" gorm:"size:50"`                                     // Forma combinada; os recebimentos ficam em Pagamentos
+	DataEntrega        *time.Time     `json:"dataEntrega"`
+	EntregaLiberadaPor *uint          `json:"entregaLiberadaPor"` // Usuário que autorizou a entrega com saldo em aberto
+	MotivoLiberacao    string         `json:"motivoLiberacao" gorm:"type:text"`
 	Observacoes        string         `json:"observacoes" gorm:"type:text"`
 	ServicosRealizados string         `json:"servicosRealizados" gorm:"type:text"`
 	CreatedAt          time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
@@ -36,6 +44,12 @@ type OrdemServico struct {
 
 	// Relacionamento com itens utilizados
 	ItensUtilizados []ItemOrdemServico `json:"itensUtilizados,omitempty" gorm:"foreignKey:OrdemServicoID"`
+
+	// Relacionamento com a mão de obra executada; a soma das linhas forma o ValorServico
+	Servicos []ServicoOrdemServico `json:"servicos,omitempty" gorm:"foreignKey:OrdemServicoID"`
+
+	// Relacionamento com os pagamentos recebidos
+	Pagamentos []Pagamento `json:"pagamentos,omitempty" gorm:"foreignKey:OrdemServicoID"`
 }
 
 // ItemOrdemServico representa um item de estoque utilizado em uma ordem de serviço
@@ -85,12 +99,46 @@ func (os *OrdemServico) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// BeforeSave calcula o valor total
+// BeforeSave calcula o valor total e a situação financeira
 func (os *OrdemServico) BeforeSave(tx *gorm.DB) error {
 	os.ValorTotal = os.ValorPecas + os.ValorServico - os.ValorDesconto
+	os.atualizarSituacaoFinanceira()
+	return nil
+}
+
+// AfterFind calcula o saldo devedor, que não é gravado no banco
+func (os *OrdemServico) AfterFind(tx *gorm.DB) error {
+	os.SaldoDevedor = os.CalcularSaldoDevedor()
 	return nil
 }
 
+// CalcularSaldoDevedor retorna quanto falta o cliente pagar
+func (os *OrdemServico) CalcularSaldoDevedor() float64 {
+	saldo := math.Round((os.ValorTotal-os.ValorPago)*100) / 100
+	if saldo < 0 {
+		return 0
+	}
+	return saldo
+}
+
+// Finalizada indica se a OS não aceita mais alterações de itens e serviços
+func (os *OrdemServico) Finalizada() bool {
+	return os.Status == "concluida" || os.Status == "entregue" || os.Status == "cancelada"
+}
+
+// atualizarSituacaoFinanceira define o status financeiro a partir do total e do valor pago
+func (os *OrdemServico) atualizarSituacaoFinanceira() {
+	os.SaldoDevedor = os.CalcularSaldoDevedor()
+	switch {
+	case os.ValorTotal > 0 && os.SaldoDevedor == 0:
+		os.StatusFinanceiro = StatusFinanceiroPago
+	case os.ValorPago > 0:
+		os.StatusFinanceiro = StatusFinanceiroParcial
+	default:
+		os.StatusFinanceiro = StatusFinanceiroPendente
+	}
+}
+
 // BeforeSave calcula o valor total do item
 func (item *ItemOrdemServico) BeforeSave(tx *gorm.DB) error {
 	item.ValorTotal = float64(item.Quantidade) * item.ValorUnitario
@@ -99,9 +147,27 @@ func (item *ItemOrdemServico) BeforeSave(tx *gorm.DB) error {
 
 // OrdemServicoDTO é um DTO para retornar uma ordem de serviço completa com seus relacionamentos
 type OrdemServicoDTO struct {
-	OrdemServico    OrdemServico       `json:"ordemServico"`
-	Cliente         Cliente            `json:"cliente"`
-	Veiculo         Veiculo            `json:"veiculo"`
-	Funcionario     Funcionario        `json:"funcionario,omitempty"`
-	ItensUtilizados []ItemOrdemServico `json:"itensUtilizados"`
+	OrdemServico    OrdemServico          `json:"ordemServico"`
+	Cliente         Cliente               `json:"cliente"`
+	Veiculo         Veiculo               `json:"veiculo"`
+	Funcionario     Funcionario           `json:"funcionario,omitempty"`
+	ItensUtilizados []ItemOrdemServico    `json:"itensUtilizados"`
+	Servicos        []ServicoOrdemServico `json:"servicos"`
+	Pagamentos      []Pagamento           `json:"pagamentos"`
+}
+
+// NovoOrdemServicoDTO monta o DTO a partir de uma OS carregada com seus relacionamentos
+func NovoOrdemServicoDTO(os *OrdemServico) OrdemServicoDTO {
+	dto := OrdemServicoDTO{
+		OrdemServico:    *os,
+		Cliente:         os.Cliente,
+		Veiculo:         os.Veiculo,
+		ItensUtilizados: os.ItensUtilizados,
+		Servicos:        os.Servicos,
+		Pagamentos:      os.Pagamentos,
+	}
+	if os.Funcionario != nil {
+		dto.Funcionario = *os.Funcionario
+	}
+	return dto
 }
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Formas de pagamento aceitas
+const (
+	FormaPagamentoDinheiro      = "dinheiro"
+	FormaPagamentoPix           = "pix"
+	FormaPagamentoCartaoCredito = "cartao_credito"
+	FormaPagamentoCartaoDebito  = "cartao_debito"
+	FormaPagamentoBoleto        = "boleto"
+	FormaPagamentoTransferencia = "transferencia"
+)
+
+// Situação financeira da OS, independente do andamento do serviço
+const (
+	StatusFinanceiroPendente = "pendente"
+	StatusFinanceiroParcial  = "parcial"
+	StatusFinanceiroPago     = "pago"
+)
+
+// Pagamento representa um valor recebido do cliente por uma ordem de serviço.
+// Uma OS pode ter vários pagamentos (sinal, parcial, formas diferentes)
+type Pagamento struct {
+	ID             uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	OrdemServicoID uint           `json:"ordemServicoId" gorm:"not null;index"`
+	Forma          string         `json:"forma" gorm:"not null;size:30" binding:"required"`
+	Valor          float64        `json:"valor" gorm:"type:decimal(10,2);not null" binding:"required,gt=0"`
+	DataPagamento  time.Time      `json:"dataPagamento" gorm:"not null;index"`
+	Parcelas       int            `json:"parcelas" gorm:"not null;default:1"` // Parcelamento no cartão de crédito
+	BandeiraCartao string         `json:"bandeiraCartao" gorm:"size:30"`
+	NSU            string         `json:"nsu" gorm:"size:50"`         // Comprovante da maquininha
+	RecebidoPorID  *uint          `json:"recebidoPorId" gorm:"index"` // Usuário que registrou o recebimento
+	CaixaID        *uint          `json:"caixaId" gorm:"index"`       // Caixa aberto em que o valor entrou
+	Observacoes    string         `json:"observacoes" gorm:"type:text"`
+	MotivoEstorno  string         `json:"motivoEstorno,omitempty" gorm:"type:text"`
+	EstornadoPorID *uint          `json:"estornadoPorId,omitempty"`
+	CreatedAt      time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"` // Pagamentos estornados ficam excluídos logicamente
+}
+
+// TableName especifica o nome da tabela para Pagamento
+func (Pagamento) TableName() string {
+	return "pagamentos"
+}
+
+// BeforeCreate define a data do pagamento e o número de parcelas padrão
+func (p *Pagamento) BeforeCreate(tx *gorm.DB) error {
+	if p.DataPagamento.IsZero() {
+		p.DataPagamento = time.Now()
+	}
+	if p.Parcelas <= 0 {
+		p.Parcelas = 1
+	}
+	return nil
+}
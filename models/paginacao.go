@@ -0,0 +1,29 @@
+package models
+
+// Pagina é o envelope devolvido pelas listagens paginadas.
+// Na paginação por cursor, Pagina e TotalPaginas não são informados
+// e a próxima página é pedida com o ProximoCursor
+type Pagina[T any] struct {
+	Itens         []T    `json:"itens"`
+	Total         int64  `json:"total"` // Registros que atendem aos filtros, em todas as páginas
+	Pagina        int    `json:"pagina,omitempty"`
+	TamanhoPagina int    `json:"tamanhoPagina"`
+	TotalPaginas  int    `json:"totalPaginas,omitempty"`
+	ProximoCursor string `json:"proximoCursor,omitempty"` // Vazio na última página
+}
+
+// MapearPagina converte os itens da página mantendo os totais, para respostas que não expõem o modelo inteiro
+func MapearPagina[T any, R any](pagina *Pagina[T], converter func(T) R) *Pagina[R] {
+	itens := make([]R, len(pagina.Itens))
+	for i, item := range pagina.Itens {
+		itens[i] = converter(item)
+	}
+	return &Pagina[R]{
+		Itens:         itens,
+		Total:         pagina.Total,
+		Pagina:        pagina.Pagina,
+		TamanhoPagina: pagina.TamanhoPagina,
+		TotalPaginas:  pagina.TotalPaginas,
+		ProximoCursor: pagina.ProximoCursor,
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status possíveis de um pedido de compra
+const (
+	StatusPedidoRascunho             = "rascunho"
+	StatusPedidoEnviado              = "enviado"
+	StatusPedidoParcialmenteRecebido = "parcialmenterecebido"
+	StatusPedidoRecebido             = "recebido"
+	StatusPedidoCancelado            = "cancelado"
+)
+
+// PedidoCompra representa um pedido de reposição de peças feito a um fornecedor
+type PedidoCompra struct {
+	ID              uint               `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Numero          string             `json:"numero" gorm:"size:20;unique;index"`
+	FornecedorID    *uint              `json:"fornecedorId" gorm:"index"`
+	Fornecedor      *Fornecedor        `json:"fornecedor,omitempty" gorm:"foreignKey:FornecedorID"`
+	Status          string             `json:"status" gorm:"not null;default:'rascunho';size:20;index"` // Rascunho, Enviado, ParcialmenteRecebido, Recebido, Cancelado
+	DataEnvio       *time.Time         `json:"dataEnvio"`
+	DataPrevisao    *time.Time         `json:"dataPrevisao"`
+	DataRecebimento *time.Time         `json:"dataRecebimento"`
+	ValorTotal      float64            `json:"valorTotal" gorm:"type:decimal(10,2);default:0"`
+	Observacoes     string             `json:"observacoes" gorm:"type:text"`
+	UsuarioID       *uint              `json:"usuarioId" gorm:"index"`
+	Itens           []ItemPedidoCompra `json:"itens" gorm:"foreignKey:PedidoCompraID"`
+	CreatedAt       time.Time          `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time          `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+// ItemPedidoCompra representa uma linha do pedido de compra
+type ItemPedidoCompra struct {
+	ID                 uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	PedidoCompraID     uint      `json:"pedidoCompraId" gorm:"not null;index"`
+	EstoqueID          uint      `json:"estoqueId" gorm:"not null;index" binding:"required"`
+	Item               *Estoque  `json:"item,omitempty" gorm:"foreignKey:EstoqueID"`
+	Quantidade         int       `json:"quantidade" gorm:"not null" binding:"required,min=1"`
+	QuantidadeRecebida int       `json:"quantidadeRecebida" gorm:"not null;default:0"`
+	CustoUnitario      float64   `json:"custoUnitario" gorm:"type:decimal(10,2);not null;default:0.00"`
+	ValorTotal         float64   `json:"valorTotal" gorm:"type:decimal(10,2);not null;default:0.00"`
+	CreatedAt          time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `json:"atualizadoEm" gorm:"autoUpdateTime"`
+}
+
+// TableName especifica o nome da tabela para PedidoCompra
+func (PedidoCompra) TableName() string {
+	return "pedidos_compra"
+}
+
+// TableName especifica o nome da tabela para ItemPedidoCompra
+func (ItemPedidoCompra) TableName() string {
+	return "itens_pedido_compra"
+}
+
+// BeforeCreate gera o número do pedido e define o status inicial
+func (p *PedidoCompra) BeforeCreate(tx *gorm.DB) error {
+	if p.Numero == "" {
+		ano, mes, dia := time.Now().Date()
+		var contador int64
+		tx.Model(&PedidoCompra{}).Unscoped().Count(&contador)
+		p.Numero = fmt.Sprintf("PC%d%02d%02d-%04d", ano, int(mes), dia, contador+1)
+	}
+
+	if p.Status == "" {
+		p.Status = StatusPedidoRascunho
+	}
+
+	return nil
+}
+
+// BeforeSave calcula o valor total da linha
+func (item *ItemPedidoCompra) BeforeSave(tx *gorm.DB) error {
+	item.ValorTotal = float64(item.Quantidade) * item.CustoUnitario
+	return nil
+}
+
+// QuantidadePendente retorna quanto ainda falta receber da linha
+func (item *ItemPedidoCompra) QuantidadePendente() int {
+	pendente := item.Quantidade - item.QuantidadeRecebida
+	if pendente < 0 {
+		return 0
+	}
+	return pendente
+}
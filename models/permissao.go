@@ -0,0 +1,208 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Cargos conhecidos pelo sistema de permissões
+const (
+	CargoAdmin     = "admin"
+	CargoGerente   = "gerente"
+	CargoMecanico  = "mecanico"
+	CargoAtendente = "atendente"
+	CargoUsuario   = "usuario"
+)
+
+// Códigos das permissões verificadas pelas rotas protegidas
+const (
+	PermUsuariosLer      = "usuarios:ler"
+	PermUsuariosEscrever = "usuarios:escrever"
+	PermUsuariosDeletar  = "usuarios:deletar"
+
+	PermClientesLer      = "clientes:ler"
+	PermClientesEscrever = "clientes:escrever"
+	PermClientesDeletar  = "clientes:deletar"
+
+	PermVeiculosLer      = "veiculos:ler"
+	PermVeiculosEscrever = "veiculos:escrever"
+	PermVeiculosDeletar  = "veiculos:deletar"
+
+	PermEstoqueLer        = "estoque:ler"
+	PermEstoqueEscrever   = "estoque:escrever"
+	PermEstoqueDeletar    = "estoque:deletar"
+	PermEstoqueConfigurar = "estoque:configurar"
+	PermEstoqueMovimentar = "estoque:movimentar"
+
+	PermFuncionariosLer      = "funcionarios:ler"
+	PermFuncionariosEscrever = "funcionarios:escrever"
+	PermFuncionariosDeletar  = "funcionarios:deletar"
+
+	PermOrdensServicoLer      = "ordens_servico:ler"
+	PermOrdensServicoEscrever = "ordens_servico:escrever"
+	PermOrdensServicoDeletar  = "ordens_servico:deletar"
+	PermOrdensServicoDesconto = "ordens_servico:desconto"
+
+	PermServicosLer      = "servicos:ler"
+	PermServicosEscrever = "servicos:escrever"
+	PermServicosDeletar  = "servicos:deletar"
+
+	PermOrcamentosLer      = "orcamentos:ler"
+	PermOrcamentosEscrever = "orcamentos:escrever"
+	PermOrcamentosAprovar  = "orcamentos:aprovar"
+
+	PermFornecedoresLer      = "fornecedores:ler"
+	PermFornecedoresEscrever = "fornecedores:escrever"
+	PermFornecedoresDeletar  = "fornecedores:deletar"
+
+	PermComprasLer      = "compras:ler"
+	PermComprasEscrever = "compras:escrever"
+	PermComprasReceber  = "compras:receber"
+
+	PermFinanceiroLer            = "financeiro:ler"
+	PermFinanceiroReceber        = "financeiro:receber"
+	PermFinanceiroEstornar       = "financeiro:estornar"
+	PermFinanceiroLiberarEntrega = "financeiro:liberar_entrega"
+
+	PermAgendaLer        = "agenda:ler"
+	PermAgendaEscrever   = "agenda:escrever"
+	PermAgendaConfigurar = "agenda:configurar"
+
+	PermCaixaOperar    = "caixa:operar"
+	PermCaixaGerenciar = "caixa:gerenciar"
+
+	PermOficinaConfigurar = "oficina:configurar"
+
+	PermPermissoesGerenciar = "permissoes:gerenciar"
+)
+
+// Permissao representa uma ação protegida do sistema (ex.: "usuarios:deletar")
+type Permissao struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Codigo    string    `json:"codigo" gorm:"not null;size:100;uniqueIndex"`
+	Descricao string    `json:"descricao" gorm:"size:255"`
+	CreatedAt time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+func (Permissao) TableName() string {
+	return "permissoes"
+}
+
+// CargoPermissao associa um cargo a uma permissão concedida
+type CargoPermissao struct {
+	ID              uint      `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Cargo           string    `json:"cargo" gorm:"not null;size:20;uniqueIndex:idx_cargo_permissao"`
+	PermissaoCodigo string    `json:"permissao" gorm:"not null;size:100;uniqueIndex:idx_cargo_permissao"`
+	CreatedAt       time.Time `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+func (CargoPermissao) TableName() string {
+	return "cargo_permissoes"
+}
+
+// CatalogoPermissoes lista todas as permissões conhecidas pelo sistema.
+// Permissões novas adicionadas aqui são cadastradas no banco na inicialização.
+var CatalogoPermissoes = []Permissao{
+	{Codigo: PermUsuariosLer, Descricao: "Visualizar usuários"},
+	{Codigo: PermUsuariosEscrever, Descricao: "Cadastrar e editar usuários"},
+	{Codigo: PermUsuariosDeletar, Descricao: "Excluir usuários"},
+	{Codigo: PermClientesLer, Descricao: "Visualizar clientes"},
+	{Codigo: PermClientesEscrever, Descricao: "Cadastrar e editar clientes"},
+	{Codigo: PermClientesDeletar, Descricao: "Excluir clientes"},
+	{Codigo: PermVeiculosLer, Descricao: "Visualizar veículos"},
+	{Codigo: PermVeiculosEscrever, Descricao: "Cadastrar e editar veículos"},
+	{Codigo: PermVeiculosDeletar, Descricao: "Excluir veículos"},
+	{Codigo: PermEstoqueLer, Descricao: "Visualizar estoque"},
+	{Codigo: PermEstoqueEscrever, Descricao: "Cadastrar e editar itens do estoque"},
+	{Codigo: PermEstoqueDeletar, Descricao: "Excluir itens do estoque"},
+	{Codigo: PermEstoqueConfigurar, Descricao: "Alterar limites de controle do estoque"},
+	{Codigo: PermEstoqueMovimentar, Descricao: "Registrar entradas, saídas e ajustes de estoque"},
+	{Codigo: PermFuncionariosLer, Descricao: "Visualizar funcionários"},
+	{Codigo: PermFuncionariosEscrever, Descricao: "Cadastrar e editar funcionários"},
+	{Codigo: PermFuncionariosDeletar, Descricao: "Excluir e restaurar funcionários"},
+	{Codigo: PermOrdensServicoLer, Descricao: "Visualizar ordens de serviço"},
+	{Codigo: PermOrdensServicoEscrever, Descricao: "Abrir e editar ordens de serviço"},
+	{Codigo: PermOrdensServicoDeletar, Descricao: "Excluir ordens de serviço"},
+	{Codigo: PermOrdensServicoDesconto, Descricao: "Conceder descontos em ordens de serviço"},
+	{Codigo: PermServicosLer, Descricao: "Visualizar o catálogo de serviços"},
+	{Codigo: PermServicosEscrever, Descricao: "Cadastrar e editar serviços e valores de mão de obra"},
+	{Codigo: PermServicosDeletar, Descricao: "Excluir serviços do catálogo"},
+	{Codigo: PermOrcamentosLer, Descricao: "Visualizar orçamentos"},
+	{Codigo: PermOrcamentosEscrever, Descricao: "Elaborar e revisar orçamentos"},
+	{Codigo: PermOrcamentosAprovar, Descricao: "Registrar aprovação ou recusa de orçamentos e convertê-los em OS"},
+	{Codigo: PermFornecedoresLer, Descricao: "Visualizar fornecedores"},
+	{Codigo: PermFornecedoresEscrever, Descricao: "Cadastrar e editar fornecedores e seus preços"},
+	{Codigo: PermFornecedoresDeletar, Descricao: "Excluir fornecedores"},
+	{Codigo: PermComprasLer, Descricao: "Visualizar pedidos de compra"},
+	{Codigo: PermComprasEscrever, Descricao: "Criar, enviar e cancelar pedidos de compra"},
+	{Codigo: PermComprasReceber, Descricao: "Registrar o recebimento de pedidos de compra"},
+	{Codigo: PermFinanceiroLer, Descricao: "Visualizar pagamentos das ordens de serviço"},
+	{Codigo: PermFinanceiroReceber, Descricao: "Registrar pagamentos das ordens de serviço"},
+	{Codigo: PermFinanceiroEstornar, Descricao: "Estornar pagamentos das ordens de serviço"},
+	{Codigo: PermFinanceiroLiberarEntrega, Descricao: "Liberar a entrega de veículos com saldo em aberto"},
+	{Codigo: PermAgendaLer, Descricao: "Visualizar a agenda e os horários disponíveis"},
+	{Codigo: PermAgendaEscrever, Descricao: "Agendar, reagendar e cancelar recebimentos de veículos"},
+	{Codigo: PermAgendaConfigurar, Descricao: "Configurar boxes, feriados e expediente da oficina"},
+	{Codigo: PermCaixaOperar, Descricao: "Abrir, movimentar e fechar o próprio caixa"},
+	{Codigo: PermCaixaGerenciar, Descricao: "Consultar e fechar os caixas de outros operadores"},
+	{Codigo: PermOficinaConfigurar, Descricao: "Alterar os dados da oficina impressos nos documentos"},
+	{Codigo: PermPermissoesGerenciar, Descricao: "Gerenciar permissões dos cargos"},
+}
+
+// PermissoesPadraoPorCargo define as permissões concedidas a cada cargo quando
+// a permissão é cadastrada pela primeira vez. O cargo admin possui todas as
+// permissões implicitamente e não aparece aqui.
+var PermissoesPadraoPorCargo = map[string][]string{
+	CargoGerente: {
+		PermUsuariosLer, PermUsuariosEscrever,
+		PermClientesLer, PermClientesEscrever, PermClientesDeletar,
+		PermVeiculosLer, PermVeiculosEscrever, PermVeiculosDeletar,
+		PermEstoqueLer, PermEstoqueEscrever, PermEstoqueDeletar, PermEstoqueConfigurar, PermEstoqueMovimentar,
+		PermFuncionariosLer, PermFuncionariosEscrever, PermFuncionariosDeletar,
+		PermOrdensServicoLer, PermOrdensServicoEscrever, PermOrdensServicoDeletar, PermOrdensServicoDesconto,
+		PermServicosLer, PermServicosEscrever, PermServicosDeletar,
+		PermOrcamentosLer, PermOrcamentosEscrever, PermOrcamentosAprovar,
+		PermFornecedoresLer, PermFornecedoresEscrever, PermFornecedoresDeletar,
+		PermComprasLer, PermComprasEscrever, PermComprasReceber,
+		PermFinanceiroLer, PermFinanceiroReceber, PermFinanceiroEstornar, PermFinanceiroLiberarEntrega,
+		PermAgendaLer, PermAgendaEscrever, PermAgendaConfigurar,
+		PermCaixaOperar, PermCaixaGerenciar,
+		PermOficinaConfigurar,
+	},
+	CargoMecanico: {
+		PermClientesLer,
+		PermVeiculosLer,
+		PermEstoqueLer,
+		PermFuncionariosLer,
+		PermOrdensServicoLer, PermOrdensServicoEscrever,
+		PermServicosLer,
+		PermOrcamentosLer, PermOrcamentosEscrever,
+		PermAgendaLer,
+	},
+	CargoAtendente: {
+		PermClientesLer, PermClientesEscrever,
+		PermVeiculosLer, PermVeiculosEscrever,
+		PermEstoqueLer,
+		PermFuncionariosLer,
+		PermOrdensServicoLer, PermOrdensServicoEscrever,
+		PermServicosLer,
+		PermOrcamentosLer, PermOrcamentosEscrever, PermOrcamentosAprovar,
+		PermFinanceiroLer, PermFinanceiroReceber,
+		PermAgendaLer, PermAgendaEscrever,
+		PermCaixaOperar,
+	},
+}
+
+// NormalizarCargo converte o cargo para a forma usada nas permissões
+// (minúsculas e sem acentos), de modo que "Mecânico" e "mecanico" sejam equivalentes
+func NormalizarCargo(cargo string) string {
+	substituicoes := strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a",
+		"é", "e", "ê", "e",
+		"í", "i",
+		"ó", "o", "ô", "o", "õ", "o",
+		"ú", "u", "ü", "u",
+		"ç", "c",
+	)
+	return substituicoes.Replace(strings.ToLower(strings.TrimSpace(cargo)))
+}
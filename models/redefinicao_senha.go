@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RedefinicaoSenha é um pedido de troca de senha enviado por e-mail. Só o hash SHA-256 do token é gravado;
+// o link vale uma única vez e até a expiração
+type RedefinicaoSenha struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	UsuarioID uint       `json:"usuarioId" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	ExpiraEm  time.Time  `json:"expiraEm" gorm:"not null"`
+	UsadoEm   *time.Time `json:"usadoEm,omitempty"` // Também preenchido quando um pedido mais novo ou a troca o invalida
+	IP        string     `json:"ip" gorm:"size:45"` // De onde o pedido foi feito
+	CreatedAt time.Time  `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// TableName especifica o nome da tabela para RedefinicaoSenha
+func (RedefinicaoSenha) TableName() string {
+	return "redefinicoes_senha"
+}
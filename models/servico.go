@@ -0,0 +1,59 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Servico representa um serviço de mão de obra do catálogo da oficina (ex.: troca de óleo, alinhamento)
+type Servico struct {
+	ID          uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Nome        string         `json:"nome" gorm:"not null;size:100;uniqueIndex" binding:"required"`
+	Descricao   string         `json:"descricao" gorm:"type:text"`
+	Categoria   string         `json:"categoria" gorm:"size:50;index"`
+	HorasPadrao float64        `json:"horasPadrao" gorm:"type:decimal(6,2);not null;default:1"` // Tempo de referência para execução
+	ValorHora   float64        `json:"valorHora" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Ativo       bool           `json:"ativo" gorm:"default:true"`
+	CreatedAt   time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"atualizadoEm" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// ServicoOrdemServico representa uma linha de mão de obra executada em uma ordem de serviço
+type ServicoOrdemServico struct {
+	ID             uint         `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	OrdemServicoID uint         `json:"ordemServicoId" gorm:"not null;index"`
+	ServicoID      *uint        `json:"servicoId" gorm:"index"` // Vazio apenas para serviços avulsos vindos de orçamentos
+	Servico        *Servico     `json:"servico,omitempty" gorm:"foreignKey:ServicoID"`
+	FuncionarioID  *uint        `json:"funcionarioId" gorm:"index"` // Mecânico que executou o serviço
+	Funcionario    *Funcionario `json:"funcionario,omitempty" gorm:"foreignKey:FuncionarioID"`
+	Descricao      string       `json:"descricao" gorm:"size:255"`
+	Horas          float64      `json:"horas" gorm:"type:decimal(6,2);not null;default:0"`
+	ValorHora      float64      `json:"valorHora" gorm:"type:decimal(10,2);not null;default:0.00"`
+	ValorTotal     float64      `json:"valorTotal" gorm:"type:decimal(10,2);not null;default:0.00"`
+	CreatedAt      time.Time    `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time    `json:"atualizadoEm" gorm:"autoUpdateTime"`
+}
+
+// TableName especifica o nome da tabela para Servico
+func (Servico) TableName() string {
+	return "servicos"
+}
+
+// TableName especifica o nome da tabela para ServicoOrdemServico
+func (ServicoOrdemServico) TableName() string {
+	return "servicos_ordem_servico"
+}
+
+// ValorPadrao retorna o preço de referência do serviço (horas padrão x valor da hora)
+func (s *Servico) ValorPadrao() float64 {
+	return math.Round(s.HorasPadrao*s.ValorHora*100) / 100
+}
+
+// BeforeSave calcula o valor total da linha, arredondado em centavos
+func (linha *ServicoOrdemServico) BeforeSave(tx *gorm.DB) error {
+	linha.ValorTotal = math.Round(linha.Horas*linha.ValorHora*100) / 100
+	return nil
+}
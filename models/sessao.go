@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Motivos do encerramento de uma sessão
+const (
+	MotivoSessaoLogout          = "logout"
+	MotivoSessaoLogoutGeral     = "logout_geral"     // O usuário (ou a administração) encerrou todas as sessões
+	MotivoSessaoUsuarioInativo  = "usuario_inativo"  // A conta foi desativada ou excluída
+	MotivoSessaoReusoToken      = "reuso_token"      // Um token de renovação já usado foi apresentado de novo
+	MotivoSessaoSenhaRedefinida = "senha_redefinida" // A senha foi trocada pelo link enviado por e-mail
+)
+
+// Sessao é um login do usuário. O token de acesso carrega a chave da sessão, então encerrá-la invalida
+// o token de acesso e os tokens de renovação de uma vez, sem esperar a expiração
+type Sessao struct {
+	ID              uint       `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	Chave           string     `json:"-" gorm:"not null;size:32;uniqueIndex"`
+	UsuarioID       uint       `json:"usuarioId" gorm:"not null;index"`
+	IP              string     `json:"ip" gorm:"size:45"`
+	UserAgent       string     `json:"userAgent" gorm:"size:255"`
+	ExpiraEm        time.Time  `json:"expiraEm" gorm:"not null"` // Prazo para a próxima renovação
+	RevogadaEm      *time.Time `json:"revogadaEm,omitempty"`
+	MotivoRevogacao string     `json:"motivoRevogacao,omitempty" gorm:"size:30"`
+	CreatedAt       time.Time  `json:"criadoEm" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"atualizadoEm" gorm:"autoUpdateTime"`
+}
+
+// TokenRenovacao é um refresh token da sessão. Só o hash SHA-256 é gravado; cada token vale para uma
+// única renovação, que o marca como usado e emite o próximo
+type TokenRenovacao struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement;not null"`
+	SessaoID  uint       `json:"sessaoId" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	ExpiraEm  time.Time  `json:"expiraEm" gorm:"not null"`
+	UsadoEm   *time.Time `json:"usadoEm,omitempty"`
+	CreatedAt time.Time  `json:"criadoEm" gorm:"autoCreateTime"`
+}
+
+// ParTokens é a resposta do login e da renovação
+type ParTokens struct {
+	Token          string    `json:"token"`        // Token de acesso (JWT) para o cabeçalho Authorization
+	ExpiraEm       time.Time `json:"expiraEm"`     // Expiração do token de acesso
+	TokenRenovacao string    `json:"refreshToken"` // Usado uma única vez em /api/token/renovar
+}
+
+// TableName especifica o nome da tabela para Sessao
+func (Sessao) TableName() string {
+	return "sessoes"
+}
+
+// TableName especifica o nome da tabela para TokenRenovacao
+func (TokenRenovacao) TableName() string {
+	return "tokens_renovacao"
+}
+
+// Ativa indica se a sessão ainda aceita requisições e renovações
+func (s *Sessao) Ativa(agora time.Time) bool {
+	return s.RevogadaEm == nil && agora.Before(s.ExpiraEm)
+}
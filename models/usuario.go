@@ -1,12 +1,23 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// Situações funcionais (coluna status) que impedem o acesso ao sistema; vazio ou qualquer outra situação libera
+const (
+	StatusUsuarioInativo   = "inativo"
+	StatusUsuarioDesligado = "desligado"
+)
+
+// CustoSenha é o custo bcrypt dos hashes gerados por DefinirSenha, definido a partir de BCRYPT_CUSTO ao montar as rotas.
+// Hashes com custo menor são refeitos no próximo login (PrecisaNovoHash)
+var CustoSenha = bcrypt.DefaultCost
+
 type Usuario struct {
 	ID           uint           `json:"id" gorm:"primaryKey;autoIncrement;not null"`
 	Nome         string         `json:"nome" gorm:"not null;size:100" binding:"required"`
@@ -27,8 +38,11 @@ type Usuario struct {
 	Funcionario *Funcionario `json:"funcionario,omitempty" gorm:"foreignKey:UsuarioID"`
 }
 
-func (u *Usuario) BeforeCreate(tx *gorm.DB) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Senha), bcrypt.DefaultCost)
+// DefinirSenha troca a senha do usuário pelo hash bcrypt da senha em texto informada.
+// Os serviços chamam este método antes de gravar; o modelo não tenta adivinhar se o valor já é um hash,
+// o que permitiria gravar um hash escolhido pelo próprio usuário sem passar pela política de senhas
+func (u *Usuario) DefinirSenha(senha string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(senha), CustoSenha)
 	if err != nil {
 		return err
 	}
@@ -36,16 +50,10 @@ func (u *Usuario) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-func (u *Usuario) BeforeUpdate(tx *gorm.DB) error {
-	// Só criptografa a senha se ela foi alterada
-	if tx.Statement.Changed("Senha") {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Senha), bcrypt.DefaultCost)
-		if err != nil {
-			return err
-		}
-		tx.Statement.SetColumn("Senha", string(hashedPassword))
-	}
-	return nil
+// PrecisaNovoHash indica se o hash da senha foi gerado com um custo menor que o configurado
+func (u *Usuario) PrecisaNovoHash() bool {
+	custo, err := bcrypt.Cost([]byte(u.Senha))
+	return err == nil && custo < CustoSenha
 }
 
 // CompareSenha verifica se a senha fornecida corresponde à senha hash armazenada
@@ -57,3 +65,16 @@ func (u *Usuario) CompareSenha(senha string) bool {
 func (Usuario) TableName() string {
 	return "usuarios"
 }
+
+// PodeAcessar indica se a conta pode fazer login e usar a API: precisa estar ativa, não excluída
+// e fora das situações funcionais que bloqueiam o acesso
+func (u *Usuario) PodeAcessar() bool {
+	if !u.Ativo || u.DeletedAt.Valid {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(u.Status)) {
+	case StatusUsuarioInativo, StatusUsuarioDesligado:
+		return false
+	}
+	return true
+}
@@ -12,6 +12,8 @@ type Veiculo struct {
 	Placa         string         `json:"placa" gorm:"not null;unique;size:10;index" binding:"required"`
 	Cor           string         `json:"cor" gorm:"size:30"`
 	AnoModelo     string         `json:"anoModelo" gorm:"column:ano_modelo;size:10"`
+	Chassi        *string        `json:"chassi" gorm:"size:17;uniqueIndex"`                 // VIN opcional, gravado normalizado
+	KmAtual       int            `json:"kmAtual" gorm:"column:km_atual;not null;default:0"` // Última leitura do hodômetro
 	ClienteID     uint           `json:"clienteId" gorm:"not null;index"`
 	OrdemServico  string         `json:"ordemServico" gorm:"column:ordem_servico;size:30;not null" binding:"required"`
 	CreatedAt     time.Time      `json:"criadoEm" gorm:"autoCreateTime"`
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"time"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AcessoRepository define a interface para o histórico de logins e o controle de falhas por conta e por IP
+type AcessoRepository interface {
+	CreateTentativa(tentativa *models.TentativaAcesso) error
+	FindTentativasPorUsuario(usuarioID uint, consulta Consulta) (*models.Pagina[models.TentativaAcesso], error)
+	FindControleForUpdate(tipo, chave string) (*models.ControleLogin, error)
+	FindOrCreateControleForUpdate(tipo, chave string) (*models.ControleLogin, error)
+	UpdateControle(controle *models.ControleLogin) error
+	DeleteControle(tipo, chave string) error
+	DeleteControlesExpirados(limite time.Time) (int64, error)
+	WithTx(tx *gorm.DB) AcessoRepository
+}
+
+// AcessoRepositoryImpl implementa a interface AcessoRepository
+type AcessoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAcessoRepository cria uma nova instância de AcessoRepository
+func NewAcessoRepository(db *gorm.DB) AcessoRepository {
+	return &AcessoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *AcessoRepositoryImpl) WithTx(tx *gorm.DB) AcessoRepository {
+	return &AcessoRepositoryImpl{db: tx}
+}
+
+// camposConsultaTentativaAcesso define a ordenação e os filtros aceitos no histórico de logins
+var camposConsultaTentativaAcesso = CamposConsulta{
+	Ordenacao: map[string]string{
+		"id":       "id",
+		"criadoEm": "created_at",
+	},
+	Filtros: map[string]CampoFiltro{
+		"sucesso":    {Coluna: "sucesso", Operador: FiltroBooleano},
+		"ip":         {Coluna: "ip", Operador: FiltroIgual},
+		"motivo":     {Coluna: "motivo", Operador: FiltroIgual},
+		"dataInicio": {Coluna: "created_at", Operador: FiltroDataDe},
+		"dataFim":    {Coluna: "created_at", Operador: FiltroDataAte},
+	},
+	OrdenacaoPadrao: "-criadoEm",
+}
+
+// CreateTentativa registra uma tentativa de login no histórico
+func (r *AcessoRepositoryImpl) CreateTentativa(tentativa *models.TentativaAcesso) error {
+	return r.db.Create(tentativa).Error
+}
+
+// FindTentativasPorUsuario busca uma página do histórico de logins do usuário
+func (r *AcessoRepositoryImpl) FindTentativasPorUsuario(usuarioID uint, consulta Consulta) (*models.Pagina[models.TentativaAcesso], error) {
+	return paginar[models.TentativaAcesso](r.db.Where("usuario_id = ?", usuarioID), consulta, camposConsultaTentativaAcesso)
+}
+
+// FindControleForUpdate busca o controle de falhas do alvo, bloqueando a linha até o fim da transação.
+// Alvos que ainda não falharam não têm controle e retornam gorm.ErrRecordNotFound
+func (r *AcessoRepositoryImpl) FindControleForUpdate(tipo, chave string) (*models.ControleLogin, error) {
+	var controle models.ControleLogin
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tipo = ? AND chave = ?", tipo, chave).First(&controle)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &controle, nil
+}
+
+// FindOrCreateControleForUpdate busca o controle de falhas do alvo, criando-o se ainda não existir,
+// e bloqueia a linha até o fim da transação para que tentativas simultâneas sejam contadas em ordem
+func (r *AcessoRepositoryImpl) FindOrCreateControleForUpdate(tipo, chave string) (*models.ControleLogin, error) {
+	novo := &models.ControleLogin{Tipo: tipo, Chave: chave}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(novo).Error; err != nil {
+		return nil, err
+	}
+
+	var controle models.ControleLogin
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tipo = ? AND chave = ?", tipo, chave).First(&controle)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &controle, nil
+}
+
+// UpdateControle atualiza o controle de falhas
+func (r *AcessoRepositoryImpl) UpdateControle(controle *models.ControleLogin) error {
+	return r.db.Save(controle).Error
+}
+
+// DeleteControle zera o controle de falhas do alvo, desfazendo o bloqueio
+func (r *AcessoRepositoryImpl) DeleteControle(tipo, chave string) error {
+	return r.db.Where("tipo = ? AND chave = ?", tipo, chave).Delete(&models.ControleLogin{}).Error
+}
+
+// DeleteControlesExpirados remove os controles cuja última falha é anterior ao limite, já sem efeito
+// sobre o login, e os que nunca registraram falha. Retorna quantos foram removidos
+func (r *AcessoRepositoryImpl) DeleteControlesExpirados(limite time.Time) (int64, error) {
+	result := r.db.Where("ultima_falha < ? OR (ultima_falha IS NULL AND updated_at < ?)", limite, limite).
+		Delete(&models.ControleLogin{})
+	return result.RowsAffected, result.Error
+}
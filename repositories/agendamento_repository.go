@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"time"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FiltroAgendamento reúne os filtros opcionais da consulta de agendamentos
+type FiltroAgendamento struct {
+	Inicio        time.Time
+	Fim           time.Time
+	Status        string
+	BoxID         *uint
+	FuncionarioID *uint
+}
+
+// AgendamentoRepository define a interface para operações de repositório da agenda
+type AgendamentoRepository interface {
+	FindAll(filtro FiltroAgendamento) ([]models.Agendamento, error)
+	FindByID(id uint) (*models.Agendamento, error)
+	FindByIDForUpdate(id uint) (*models.Agendamento, error)
+	FindConflitos(inicio, fim time.Time, boxID uint, funcionarioID *uint, ignorarID uint) ([]models.Agendamento, error)
+	FindAtivosNoPeriodo(inicio, fim time.Time) ([]models.Agendamento, error)
+	Create(agendamento *models.Agendamento) error
+	Update(agendamento *models.Agendamento) error
+	WithTx(tx *gorm.DB) AgendamentoRepository
+}
+
+// AgendamentoRepositoryImpl implementa a interface AgendamentoRepository
+type AgendamentoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAgendamentoRepository cria uma nova instância de AgendamentoRepository
+func NewAgendamentoRepository(db *gorm.DB) AgendamentoRepository {
+	return &AgendamentoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *AgendamentoRepositoryImpl) WithTx(tx *gorm.DB) AgendamentoRepository {
+	return &AgendamentoRepositoryImpl{db: tx}
+}
+
+// preloadAgendamento carrega cliente, veículo, box e mecânico do agendamento
+func preloadAgendamento(db *gorm.DB) *gorm.DB {
+	return db.Preload("Cliente").Preload("Veiculo").Preload("Box").Preload("Funcionario")
+}
+
+// FindAll busca os agendamentos que começam no período informado, aplicando os filtros opcionais
+func (r *AgendamentoRepositoryImpl) FindAll(filtro FiltroAgendamento) ([]models.Agendamento, error) {
+	var agendamentos []models.Agendamento
+	query := preloadAgendamento(r.db).Order("data_inicio")
+	if !filtro.Inicio.IsZero() {
+		query = query.Where("data_inicio >= ?", filtro.Inicio)
+	}
+	if !filtro.Fim.IsZero() {
+		query = query.Where("data_inicio < ?", filtro.Fim)
+	}
+	if filtro.Status != "" {
+		query = query.Where("status = ?", filtro.Status)
+	}
+	if filtro.BoxID != nil {
+		query = query.Where("box_id = ?", *filtro.BoxID)
+	}
+	if filtro.FuncionarioID != nil {
+		query = query.Where("funcionario_id = ?", *filtro.FuncionarioID)
+	}
+	result := query.Find(&agendamentos)
+	return agendamentos, result.Error
+}
+
+// FindByID busca um agendamento com seus relacionamentos
+func (r *AgendamentoRepositoryImpl) FindByID(id uint) (*models.Agendamento, error) {
+	var agendamento models.Agendamento
+	result := preloadAgendamento(r.db).First(&agendamento, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &agendamento, nil
+}
+
+// FindByIDForUpdate busca o agendamento (sem relacionamentos) bloqueando a linha até o fim da transação
+func (r *AgendamentoRepositoryImpl) FindByIDForUpdate(id uint) (*models.Agendamento, error) {
+	var agendamento models.Agendamento
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&agendamento, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &agendamento, nil
+}
+
+// FindConflitos busca os agendamentos ativos que se sobrepõem ao período no mesmo box ou com o mesmo mecânico
+func (r *AgendamentoRepositoryImpl) FindConflitos(inicio, fim time.Time, boxID uint, funcionarioID *uint, ignorarID uint) ([]models.Agendamento, error) {
+	var agendamentos []models.Agendamento
+	query := r.db.
+		Where("status IN ?", []string{models.StatusAgendamentoAgendado, models.StatusAgendamentoConfirmado}).
+		Where("data_inicio < ? AND data_fim > ?", fim, inicio).
+		Where("id <> ?", ignorarID)
+	if funcionarioID != nil {
+		query = query.Where("(box_id = ? OR funcionario_id = ?)", boxID, *funcionarioID)
+	} else {
+		query = query.Where("box_id = ?", boxID)
+	}
+	result := query.Find(&agendamentos)
+	return agendamentos, result.Error
+}
+
+// FindAtivosNoPeriodo busca os agendamentos ativos que ocupam algum trecho do período
+func (r *AgendamentoRepositoryImpl) FindAtivosNoPeriodo(inicio, fim time.Time) ([]models.Agendamento, error) {
+	var agendamentos []models.Agendamento
+	result := r.db.
+		Where("status IN ?", []string{models.StatusAgendamentoAgendado, models.StatusAgendamentoConfirmado}).
+		Where("data_inicio < ? AND data_fim > ?", fim, inicio).
+		Find(&agendamentos)
+	return agendamentos, result.Error
+}
+
+// Create grava um novo agendamento sem criar os relacionamentos enviados no JSON
+func (r *AgendamentoRepositoryImpl) Create(agendamento *models.Agendamento) error {
+	return r.db.Omit(clause.Associations).Create(agendamento).Error
+}
+
+// Update atualiza o agendamento sem tocar nos relacionamentos
+func (r *AgendamentoRepositoryImpl) Update(agendamento *models.Agendamento) error {
+	return r.db.Omit(clause.Associations).Save(agendamento).Error
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BoxRepository define a interface para operações de repositório dos boxes da oficina
+type BoxRepository interface {
+	FindAll() ([]models.Box, error)
+	FindAtivos() ([]models.Box, error)
+	FindByID(id uint) (*models.Box, error)
+	FindByIDForUpdate(id uint) (*models.Box, error)
+	FindByNome(nome string) (*models.Box, error)
+	Create(box *models.Box) error
+	Update(box *models.Box) error
+	Delete(id uint) error
+	WithTx(tx *gorm.DB) BoxRepository
+}
+
+// BoxRepositoryImpl implementa a interface BoxRepository
+type BoxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBoxRepository cria uma nova instância de BoxRepository
+func NewBoxRepository(db *gorm.DB) BoxRepository {
+	return &BoxRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *BoxRepositoryImpl) WithTx(tx *gorm.DB) BoxRepository {
+	return &BoxRepositoryImpl{db: tx}
+}
+
+// FindAll busca todos os boxes
+func (r *BoxRepositoryImpl) FindAll() ([]models.Box, error) {
+	var boxes []models.Box
+	result := r.db.Order("nome").Find(&boxes)
+	return boxes, result.Error
+}
+
+// FindAtivos busca os boxes disponíveis para agendamento
+func (r *BoxRepositoryImpl) FindAtivos() ([]models.Box, error) {
+	var boxes []models.Box
+	result := r.db.Where("ativo = ?", true).Order("nome").Find(&boxes)
+	return boxes, result.Error
+}
+
+// FindByID busca um box pelo ID
+func (r *BoxRepositoryImpl) FindByID(id uint) (*models.Box, error) {
+	var box models.Box
+	result := r.db.First(&box, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &box, nil
+}
+
+// FindByIDForUpdate busca o box bloqueando a linha até o fim da transação,
+// serializando os agendamentos concorrentes do mesmo box
+func (r *BoxRepositoryImpl) FindByIDForUpdate(id uint) (*models.Box, error) {
+	var box models.Box
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&box, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &box, nil
+}
+
+// FindByNome busca um box pelo nome, incluindo os excluídos (o nome é único)
+func (r *BoxRepositoryImpl) FindByNome(nome string) (*models.Box, error) {
+	var box models.Box
+	result := r.db.Unscoped().Where("nome = ?", nome).First(&box)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &box, nil
+}
+
+// Create cadastra um novo box
+func (r *BoxRepositoryImpl) Create(box *models.Box) error {
+	return r.db.Create(box).Error
+}
+
+// Update atualiza um box existente
+func (r *BoxRepositoryImpl) Update(box *models.Box) error {
+	return r.db.Save(box).Error
+}
+
+// Delete remove um box pelo ID (soft delete); os agendamentos mantêm a referência
+func (r *BoxRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Box{}, id).Error
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// Colunas de cada índice FULLTEXT; a ordem precisa ser a mesma da criação do índice na migração.
+// Nos bancos sem FULLTEXT as mesmas colunas são comparadas com LIKE
+const (
+	colunasBuscaCliente      = "clientes.nome, clientes.telefone, clientes.email"
+	colunasBuscaVeiculo      = "veiculos.placa, veiculos.marca, veiculos.modelo, veiculos.cor"
+	colunasBuscaOrdemServico = "ordens_servico.numero_os, ordens_servico.descricao"
+)
+
+// BuscaRepository define a interface para a busca textual (índices FULLTEXT no MySQL)
+type BuscaRepository interface {
+	BuscarClientes(termos []string, limite int) ([]models.ClienteEncontrado, error)
+	BuscarVeiculos(termos []string, limite int) ([]models.VeiculoEncontrado, error)
+	BuscarOrdensServico(termos []string, limite int) ([]models.OrdemServicoEncontrada, error)
+}
+
+// BuscaRepositoryImpl implementa a interface BuscaRepository
+type BuscaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBuscaRepository cria uma nova instância de BuscaRepository
+func NewBuscaRepository(db *gorm.DB) BuscaRepository {
+	return &BuscaRepositoryImpl{db: db}
+}
+
+// relevanciaEncontrada é o ID de um registro com a pontuação calculada pelo banco
+type relevanciaEncontrada struct {
+	ID         uint
+	Relevancia float64
+}
+
+// BuscarClientes busca os clientes pelo nome, telefone e e-mail
+func (r *BuscaRepositoryImpl) BuscarClientes(termos []string, limite int) ([]models.ClienteEncontrado, error) {
+	relevancia, args := r.relevancia(colunasBuscaCliente, termos)
+
+	var encontrados []relevanciaEncontrada
+	err := r.db.Table("clientes").
+		Select("clientes.id, "+relevancia+" AS relevancia", args...).
+		Where("clientes.deleted_at IS NULL").
+		Where(relevancia+" > 0", args...).
+		Order("relevancia DESC, clientes.nome").
+		Limit(limite).
+		Scan(&encontrados).Error
+	if err != nil || len(encontrados) == 0 {
+		return []models.ClienteEncontrado{}, err
+	}
+
+	var clientes []models.Cliente
+	if err := r.db.Where("id IN ?", idsEncontrados(encontrados)).Find(&clientes).Error; err != nil {
+		return nil, err
+	}
+	porID := make(map[uint]models.Cliente, len(clientes))
+	for _, cliente := range clientes {
+		porID[cliente.ID] = cliente
+	}
+
+	resultado := make([]models.ClienteEncontrado, 0, len(encontrados))
+	for _, encontrado := range encontrados {
+		if cliente, ok := porID[encontrado.ID]; ok {
+			resultado = append(resultado, models.ClienteEncontrado{Relevancia: encontrado.Relevancia, Cliente: cliente})
+		}
+	}
+	return resultado, nil
+}
+
+// BuscarVeiculos busca os veículos pela placa, marca, modelo e cor, somando a relevância do dono
+func (r *BuscaRepositoryImpl) BuscarVeiculos(termos []string, limite int) ([]models.VeiculoEncontrado, error) {
+	relevanciaVeiculo, argsVeiculo := r.relevancia(colunasBuscaVeiculo, termos)
+	relevanciaCliente, argsCliente := r.relevancia(colunasBuscaCliente, termos)
+
+	var encontrados []relevanciaEncontrada
+	err := r.db.Table("veiculos").
+		Select("veiculos.id, "+relevanciaVeiculo+" + COALESCE("+relevanciaCliente+", 0) AS relevancia",
+			juntarArgs(argsVeiculo, argsCliente)...).
+		Joins("LEFT JOIN clientes ON clientes.id = veiculos.cliente_id AND clientes.deleted_at IS NULL").
+		Where("veiculos.deleted_at IS NULL").
+		Where(relevanciaVeiculo+" > 0", argsVeiculo...).
+		Order("relevancia DESC, veiculos.placa").
+		Limit(limite).
+		Scan(&encontrados).Error
+	if err != nil || len(encontrados) == 0 {
+		return []models.VeiculoEncontrado{}, err
+	}
+
+	var veiculos []models.Veiculo
+	if err := r.db.Where("id IN ?", idsEncontrados(encontrados)).Find(&veiculos).Error; err != nil {
+		return nil, err
+	}
+	porID := make(map[uint]models.Veiculo, len(veiculos))
+	idsClientes := make([]uint, 0, len(veiculos))
+	for _, veiculo := range veiculos {
+		porID[veiculo.ID] = veiculo
+		idsClientes = append(idsClientes, veiculo.ClienteID)
+	}
+
+	var clientes []models.Cliente
+	if err := r.db.Where("id IN ?", idsClientes).Find(&clientes).Error; err != nil {
+		return nil, err
+	}
+	clientePorID := make(map[uint]models.Cliente, len(clientes))
+	for _, cliente := range clientes {
+		clientePorID[cliente.ID] = cliente
+	}
+
+	resultado := make([]models.VeiculoEncontrado, 0, len(encontrados))
+	for _, encontrado := range encontrados {
+		veiculo, ok := porID[encontrado.ID]
+		if !ok {
+			continue
+		}
+		item := models.VeiculoEncontrado{Relevancia: encontrado.Relevancia, Veiculo: veiculo}
+		if cliente, ok := clientePorID[veiculo.ClienteID]; ok {
+			item.Cliente = &cliente
+		}
+		resultado = append(resultado, item)
+	}
+	return resultado, nil
+}
+
+// BuscarOrdensServico busca as OS pelo número e pela descrição e também pelo veículo e pelo cliente,
+// somando as três relevâncias; entre OS igualmente relevantes, as mais recentes vêm primeiro
+func (r *BuscaRepositoryImpl) BuscarOrdensServico(termos []string, limite int) ([]models.OrdemServicoEncontrada, error) {
+	relevanciaOS, argsOS := r.relevancia(colunasBuscaOrdemServico, termos)
+	relevanciaVeiculo, argsVeiculo := r.relevancia(colunasBuscaVeiculo, termos)
+	relevanciaCliente, argsCliente := r.relevancia(colunasBuscaCliente, termos)
+	args := juntarArgs(argsOS, argsVeiculo, argsCliente)
+
+	var encontrados []relevanciaEncontrada
+	err := r.db.Table("ordens_servico").
+		Select("ordens_servico.id, "+relevanciaOS+" + "+relevanciaVeiculo+" + "+relevanciaCliente+" AS relevancia", args...).
+		Joins("JOIN veiculos ON veiculos.id = ordens_servico.veiculo_id").
+		Joins("JOIN clientes ON clientes.id = ordens_servico.cliente_id").
+		Where("ordens_servico.deleted_at IS NULL").
+		Where("("+relevanciaOS+" > 0 OR "+relevanciaVeiculo+" > 0 OR "+relevanciaCliente+" > 0)", args...).
+		Order("relevancia DESC, ordens_servico.data_entrada DESC").
+		Limit(limite).
+		Scan(&encontrados).Error
+	if err != nil || len(encontrados) == 0 {
+		return []models.OrdemServicoEncontrada{}, err
+	}
+
+	var ordens []models.OrdemServico
+	err = r.db.Preload("Veiculo").Preload("Cliente").Preload("Funcionario").
+		Where("id IN ?", idsEncontrados(encontrados)).
+		Find(&ordens).Error
+	if err != nil {
+		return nil, err
+	}
+	porID := make(map[uint]models.OrdemServico, len(ordens))
+	for _, os := range ordens {
+		porID[os.ID] = os
+	}
+
+	resultado := make([]models.OrdemServicoEncontrada, 0, len(encontrados))
+	for _, encontrado := range encontrados {
+		if os, ok := porID[encontrado.ID]; ok {
+			resultado = append(resultado, models.OrdemServicoEncontrada{Relevancia: encontrado.Relevancia, OrdemServico: os})
+		}
+	}
+	return resultado, nil
+}
+
+// relevancia monta a expressão que pontua as colunas para os termos, com os seus argumentos.
+// No MySQL usa o índice FULLTEXT; nos demais bancos soma um ponto por termo encontrado em alguma coluna
+func (r *BuscaRepositoryImpl) relevancia(colunas string, termos []string) (string, []interface{}) {
+	if r.db.Dialector.Name() == "mysql" {
+		return "MATCH(" + colunas + ") AGAINST(? IN BOOLEAN MODE)", []interface{}{expressaoFullText(termos)}
+	}
+
+	limpos := limparTermos(termos)
+	if len(limpos) == 0 {
+		return "0", nil
+	}
+
+	comparacao := " " + operadorLike(r.db) + " ? ESCAPE '!'"
+	partes := make([]string, 0, len(limpos))
+	var args []interface{}
+	for _, termo := range limpos {
+		condicoes := make([]string, 0, 4)
+		for _, coluna := range strings.Split(colunas, ", ") {
+			condicoes = append(condicoes, coluna+comparacao)
+			args = append(args, "%"+escaparLike(termo)+"%")
+		}
+		partes = append(partes, "(CASE WHEN "+strings.Join(condicoes, " OR ")+" THEN 1 ELSE 0 END)")
+	}
+	return "(" + strings.Join(partes, " + ") + ")", args
+}
+
+// expressaoFullText monta a expressão do modo booleano: cada termo vira um prefixo opcional ("gol*"),
+// de modo que registros com mais termos em comum ficam mais relevantes
+func expressaoFullText(termos []string) string {
+	partes := limparTermos(termos)
+	for i, termo := range partes {
+		partes[i] = termo + "*"
+	}
+	return strings.Join(partes, " ")
+}
+
+// limparTermos mantém só letras e dígitos, removendo os operadores do modo booleano digitados pelo usuário.
+// Os separadores dividem o termo em partes, como o índice FULLTEXT faz com o texto gravado,
+// para que "OS20261016-0001" e "ana@email.com" encontrem o número e o e-mail
+func limparTermos(termos []string) []string {
+	limpos := make([]string, 0, len(termos))
+	for _, termo := range termos {
+		limpos = append(limpos, strings.FieldsFunc(termo, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	return limpos
+}
+
+// juntarArgs concatena os argumentos de várias expressões na ordem em que aparecem na consulta
+func juntarArgs(listas ...[]interface{}) []interface{} {
+	var args []interface{}
+	for _, lista := range listas {
+		args = append(args, lista...)
+	}
+	return args
+}
+
+// idsEncontrados extrai os IDs na ordem de relevância
+func idsEncontrados(encontrados []relevanciaEncontrada) []uint {
+	ids := make([]uint, len(encontrados))
+	for i, encontrado := range encontrados {
+		ids[i] = encontrado.ID
+	}
+	return ids
+}
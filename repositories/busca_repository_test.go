@@ -0,0 +1,21 @@
+package repositories
+
+import "testing"
+
+func TestExpressaoFullText(t *testing.T) {
+	casos := map[string]struct {
+		termos   []string
+		esperado string
+	}{
+		"prefixo em cada termo":       {[]string{"gol", "prata"}, "gol* prata*"},
+		"operadores removidos":        {[]string{"+gol", "-prata", "\"joão\"", "(uno)"}, "gol* prata* joão* uno*"},
+		"separadores dividem o termo": {[]string{"OS20261016-0001", "ana@email.com"}, "OS20261016* 0001* ana* email* com*"},
+		"termos só com símbolos":      {[]string{"***", "@", "gol"}, "gol*"},
+		"nenhum termo aproveitável":   {[]string{"%", "_"}, ""},
+	}
+	for nome, caso := range casos {
+		if got := expressaoFullText(caso.termos); got != caso.esperado {
+			t.Errorf("%s: expressaoFullText(%q) = %q, esperado %q", nome, caso.termos, got, caso.esperado)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CaixaRepository define a interface para operações de repositório do caixa da recepção
+type CaixaRepository interface {
+	FindAll(status string) ([]models.Caixa, error)
+	FindByID(id uint) (*models.Caixa, error)
+	FindByIDForUpdate(id uint) (*models.Caixa, error)
+	FindAbertoPorUsuario(usuarioID uint) (*models.Caixa, error)
+	FindAbertoPorUsuarioForUpdate(usuarioID uint) (*models.Caixa, error)
+	TravarOperador(usuarioID uint) error
+	Create(caixa *models.Caixa) error
+	Update(caixa *models.Caixa) error
+	AddMovimentacao(movimentacao *models.MovimentacaoCaixa) error
+	FindMovimentacoes(caixaID uint) ([]models.MovimentacaoCaixa, error)
+	AddConferencia(conferencia *models.ConferenciaCaixa) error
+	WithTx(tx *gorm.DB) CaixaRepository
+}
+
+// CaixaRepositoryImpl implementa a interface CaixaRepository
+type CaixaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCaixaRepository cria uma nova instância de CaixaRepository
+func NewCaixaRepository(db *gorm.DB) CaixaRepository {
+	return &CaixaRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *CaixaRepositoryImpl) WithTx(tx *gorm.DB) CaixaRepository {
+	return &CaixaRepositoryImpl{db: tx}
+}
+
+// FindAll busca os caixas, opcionalmente filtrando pelo status, dos mais recentes para os mais antigos
+func (r *CaixaRepositoryImpl) FindAll(status string) ([]models.Caixa, error) {
+	var caixas []models.Caixa
+	query := r.db.Order("data_abertura DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Find(&caixas)
+	return caixas, result.Error
+}
+
+// FindByID busca um caixa com suas movimentações e conferências
+func (r *CaixaRepositoryImpl) FindByID(id uint) (*models.Caixa, error) {
+	var caixa models.Caixa
+	result := r.db.
+		Preload("Movimentacoes", func(db *gorm.DB) *gorm.DB { return db.Order("criado_em, id") }).
+		Preload("Conferencias").
+		First(&caixa, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &caixa, nil
+}
+
+// FindByIDForUpdate busca o caixa (sem relacionamentos) bloqueando a linha até o fim da transação
+func (r *CaixaRepositoryImpl) FindByIDForUpdate(id uint) (*models.Caixa, error) {
+	var caixa models.Caixa
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&caixa, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &caixa, nil
+}
+
+// FindAbertoPorUsuario busca o caixa aberto do operador
+func (r *CaixaRepositoryImpl) FindAbertoPorUsuario(usuarioID uint) (*models.Caixa, error) {
+	var caixa models.Caixa
+	result := r.db.Where("usuario_id = ? AND status = ?", usuarioID, models.StatusCaixaAberto).First(&caixa)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &caixa, nil
+}
+
+// FindAbertoPorUsuarioForUpdate busca o caixa aberto do operador bloqueando a linha até o fim da transação
+func (r *CaixaRepositoryImpl) FindAbertoPorUsuarioForUpdate(usuarioID uint) (*models.Caixa, error) {
+	var caixa models.Caixa
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("usuario_id = ? AND status = ?", usuarioID, models.StatusCaixaAberto).First(&caixa)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &caixa, nil
+}
+
+// TravarOperador bloqueia a linha do usuário até o fim da transação, serializando as aberturas de caixa
+// do mesmo operador (a ausência de caixa aberto não tem linha para ser bloqueada)
+func (r *CaixaRepositoryImpl) TravarOperador(usuarioID uint) error {
+	var usuario models.Usuario
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&usuario, usuarioID).Error
+}
+
+// Create abre um novo caixa
+func (r *CaixaRepositoryImpl) Create(caixa *models.Caixa) error {
+	return r.db.Omit(clause.Associations).Create(caixa).Error
+}
+
+// Update atualiza os dados do caixa sem tocar nas movimentações
+func (r *CaixaRepositoryImpl) Update(caixa *models.Caixa) error {
+	return r.db.Omit(clause.Associations).Save(caixa).Error
+}
+
+// AddMovimentacao registra uma movimentação no caixa
+func (r *CaixaRepositoryImpl) AddMovimentacao(movimentacao *models.MovimentacaoCaixa) error {
+	return r.db.Create(movimentacao).Error
+}
+
+// FindMovimentacoes busca as movimentações do caixa em ordem cronológica
+func (r *CaixaRepositoryImpl) FindMovimentacoes(caixaID uint) ([]models.MovimentacaoCaixa, error) {
+	var movimentacoes []models.MovimentacaoCaixa
+	result := r.db.Where("caixa_id = ?", caixaID).Order("criado_em, id").Find(&movimentacoes)
+	return movimentacoes, result.Error
+}
+
+// AddConferencia grava a contagem de uma forma de pagamento no fechamento
+func (r *CaixaRepositoryImpl) AddConferencia(conferencia *models.ConferenciaCaixa) error {
+	return r.db.Create(conferencia).Error
+}
@@ -8,11 +8,14 @@ import (
 
 type ClienteRepositoryGorm interface {
 	FindAll() ([]models.Cliente, error)
+	FindPaginado(consulta Consulta) (*models.Pagina[models.Cliente], error)
 	FindByID(id uint) (*models.Cliente, error)
 	Create(cliente *models.Cliente) error
 	Update(cliente *models.Cliente) error
 	Delete(id uint) error
 	FindWithVeiculos(id uint) (*models.Cliente, error)
+	FindByDocumento(documento string) (*models.Cliente, error)
+	WithTx(tx *gorm.DB) ClienteRepositoryGorm
 }
 
 type ClienteRepositoryGormImpl struct {
@@ -23,12 +26,40 @@ func NewClienteRepositoryGorm(db *gorm.DB) ClienteRepositoryGorm {
 	return &ClienteRepositoryGormImpl{db: db}
 }
 
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *ClienteRepositoryGormImpl) WithTx(tx *gorm.DB) ClienteRepositoryGorm {
+	return &ClienteRepositoryGormImpl{db: tx}
+}
+
 func (r *ClienteRepositoryGormImpl) FindAll() ([]models.Cliente, error) {
 	var clientes []models.Cliente
 	result := r.db.Find(&clientes)
 	return clientes, result.Error
 }
 
+// camposConsultaCliente define a ordenação e os filtros aceitos na listagem de clientes
+var camposConsultaCliente = CamposConsulta{
+	Ordenacao: map[string]string{
+		"id":         "id",
+		"nome":       "nome",
+		"tipoPessoa": "tipo_pessoa",
+		"criadoEm":   "created_at",
+	},
+	Filtros: map[string]CampoFiltro{
+		"nome":       {Coluna: "nome", Operador: FiltroContem},
+		"email":      {Coluna: "email", Operador: FiltroContem},
+		"telefone":   {Coluna: "telefone", Operador: FiltroContem},
+		"documento":  {Coluna: "documento", Operador: FiltroContem},
+		"tipoPessoa": {Coluna: "tipo_pessoa", Operador: FiltroIgual},
+	},
+	OrdenacaoPadrao: "nome",
+}
+
+// FindPaginado busca uma página de clientes conforme a ordenação e os filtros da consulta
+func (r *ClienteRepositoryGormImpl) FindPaginado(consulta Consulta) (*models.Pagina[models.Cliente], error) {
+	return paginar[models.Cliente](r.db, consulta, camposConsultaCliente)
+}
+
 func (r *ClienteRepositoryGormImpl) FindByID(id uint) (*models.Cliente, error) {
 	var cliente models.Cliente
 	result := r.db.First(&cliente, id)
@@ -58,3 +89,14 @@ func (r *ClienteRepositoryGormImpl) FindWithVeiculos(id uint) (*models.Cliente,
 	}
 	return &cliente, nil
 }
+
+// FindByDocumento busca um cliente pelo CPF ou CNPJ formatado, incluindo os excluídos,
+// já que o índice único do documento também considera os registros com soft delete
+func (r *ClienteRepositoryGormImpl) FindByDocumento(documento string) (*models.Cliente, error) {
+	var cliente models.Cliente
+	result := r.db.Unscoped().Where("documento = ?", documento).First(&cliente)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &cliente, nil
+}
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// Limites do tamanho de página das listagens
+const (
+	TamanhoPaginaPadrao = 20
+	TamanhoPaginaMaximo = 100
+)
+
+// Operadores dos filtros de listagem
+const (
+	FiltroIgual    = "igual"    // coluna = valor
+	FiltroContem   = "contem"   // coluna LIKE %valor%
+	FiltroBooleano = "booleano" // true/false, 1/0
+	FiltroDataDe   = "data_de"  // coluna >= data (AAAA-MM-DD)
+	FiltroDataAte  = "data_ate" // coluna < dia seguinte à data (AAAA-MM-DD), incluindo o dia inteiro
+)
+
+// Consulta reúne os parâmetros de paginação, ordenação e filtro de uma listagem
+type Consulta struct {
+	Pagina        int
+	TamanhoPagina int
+	Ordenacao     string            // Campos separados por vírgula; "-" na frente ordena de forma decrescente
+	Filtros       map[string]string // Nome do filtro na API -> valor
+	Cursor        string            // Paginação por cursor, quando suportada pela listagem
+	UsarCursor    bool
+}
+
+// CampoFiltro liga um filtro da API a uma coluna da tabela
+type CampoFiltro struct {
+	Coluna   string
+	Operador string
+}
+
+// CamposConsulta declara, para cada listagem, os campos aceitos na ordenação e nos filtros.
+// Só os nomes declarados chegam ao SQL, o que impede injeção pelos parâmetros da URL
+type CamposConsulta struct {
+	Ordenacao       map[string]string // Nome do campo na API -> coluna
+	Filtros         map[string]CampoFiltro
+	OrdenacaoPadrao string
+}
+
+// normalizarPaginacao aplica os valores padrão e o limite de tamanho de página
+func (c *Consulta) normalizarPaginacao() {
+	if c.Pagina < 1 {
+		c.Pagina = 1
+	}
+	if c.TamanhoPagina < 1 {
+		c.TamanhoPagina = TamanhoPaginaPadrao
+	}
+	if c.TamanhoPagina > TamanhoPaginaMaximo {
+		c.TamanhoPagina = TamanhoPaginaMaximo
+	}
+}
+
+// aplicarFiltros adiciona ao SQL as condições dos filtros informados
+func aplicarFiltros(db *gorm.DB, consulta Consulta, campos CamposConsulta) (*gorm.DB, error) {
+	for nome, valor := range consulta.Filtros {
+		campo, ok := campos.Filtros[nome]
+		if !ok {
+			return nil, fmt.Errorf("filtro não suportado: %s", nome)
+		}
+
+		valor = strings.TrimSpace(valor)
+		if valor == "" {
+			continue
+		}
+
+		switch campo.Operador {
+		case FiltroIgual:
+			db = db.Where(campo.Coluna+" = ?", valor)
+		case FiltroContem:
+			db = db.Where(campo.Coluna+" "+operadorLike(db)+" ? ESCAPE '!'", "%"+escaparLike(valor)+"%")
+		case FiltroBooleano:
+			booleano, err := strconv.ParseBool(valor)
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido para o filtro %s: use true ou false", nome)
+			}
+			db = db.Where(campo.Coluna+" = ?", booleano)
+		case FiltroDataDe, FiltroDataAte:
+			data, err := time.ParseInLocation("2006-01-02", valor, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("data inválida para o filtro %s: use o formato AAAA-MM-DD", nome)
+			}
+			if campo.Operador == FiltroDataDe {
+				db = db.Where(campo.Coluna+" >= ?", data)
+			} else {
+				db = db.Where(campo.Coluna+" < ?", data.AddDate(0, 0, 1))
+			}
+		default:
+			return nil, fmt.Errorf("operador de filtro desconhecido: %s", campo.Operador)
+		}
+	}
+	return db, nil
+}
+
+// ordenacaoSQL converte a ordenação da API ("nome,-criadoEm") em ORDER BY, sempre desempatando pelo id
+func ordenacaoSQL(ordenacao string, campos CamposConsulta) (string, error) {
+	if strings.TrimSpace(ordenacao) == "" {
+		ordenacao = campos.OrdenacaoPadrao
+	}
+
+	var partes []string
+	temID := false
+	for _, campo := range strings.Split(ordenacao, ",") {
+		campo = strings.TrimSpace(campo)
+		if campo == "" {
+			continue
+		}
+
+		direcao := "ASC"
+		if strings.HasPrefix(campo, "-") {
+			direcao = "DESC"
+			campo = campo[1:]
+		}
+
+		coluna, ok := campos.Ordenacao[campo]
+		if !ok {
+			return "", fmt.Errorf("campo de ordenação não suportado: %s", campo)
+		}
+		if coluna == "id" {
+			temID = true
+		}
+		partes = append(partes, coluna+" "+direcao)
+	}
+
+	if !temID {
+		partes = append(partes, "id ASC")
+	}
+	return strings.Join(partes, ", "), nil
+}
+
+// paginar executa a listagem paginada por número de página: conta o total com os filtros
+// e busca apenas os registros da página pedida
+func paginar[T any](db *gorm.DB, consulta Consulta, campos CamposConsulta) (*models.Pagina[T], error) {
+	consulta.normalizarPaginacao()
+
+	filtrado, err := aplicarFiltros(db.Model(new(T)), consulta, campos)
+	if err != nil {
+		return nil, err
+	}
+
+	ordem, err := ordenacaoSQL(consulta.Ordenacao, campos)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := filtrado.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	itens := make([]T, 0, consulta.TamanhoPagina)
+	err = filtrado.Session(&gorm.Session{}).
+		Order(ordem).
+		Offset((consulta.Pagina - 1) * consulta.TamanhoPagina).
+		Limit(consulta.TamanhoPagina).
+		Find(&itens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalPaginas := int((total + int64(consulta.TamanhoPagina) - 1) / int64(consulta.TamanhoPagina))
+	return &models.Pagina[T]{
+		Itens:         itens,
+		Total:         total,
+		Pagina:        consulta.Pagina,
+		TamanhoPagina: consulta.TamanhoPagina,
+		TotalPaginas:  totalPaginas,
+	}, nil
+}
+
+// cursorLista identifica o último registro entregue na paginação por cursor
+type cursorLista struct {
+	Valor int64 `json:"v"` // Valor da coluna de ordenação (datas em Unix nanossegundos)
+	ID    uint  `json:"id"`
+}
+
+// codificarCursor gera o cursor opaco devolvido ao cliente
+func codificarCursor(cursor cursorLista) string {
+	dados, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(dados)
+}
+
+// decodificarCursor lê o cursor recebido na URL
+func decodificarCursor(valor string) (*cursorLista, error) {
+	dados, err := base64.RawURLEncoding.DecodeString(valor)
+	if err != nil {
+		return nil, errors.New("cursor inválido")
+	}
+
+	var cursor cursorLista
+	if err := json.Unmarshal(dados, &cursor); err != nil || cursor.ID == 0 {
+		return nil, errors.New("cursor inválido")
+	}
+	return &cursor, nil
+}
+
+// escaparLike impede que % e _ digitados pelo usuário funcionem como curingas.
+// O escape é "!" porque a barra invertida é tratada de forma diferente em cada banco
+func escaparLike(valor string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(valor)
+}
+
+// operadorLike retorna a comparação sem diferenciar maiúsculas: no MySQL e no SQLite o LIKE já ignora
+// a caixa, mas no PostgreSQL é preciso o ILIKE
+func operadorLike(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
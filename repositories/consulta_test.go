@@ -0,0 +1,79 @@
+package repositories
+
+import "testing"
+
+func TestNormalizarPaginacao(t *testing.T) {
+	casos := []struct {
+		pagina, tamanho                 int
+		paginaEsperada, tamanhoEsperado int
+	}{
+		{0, 0, 1, TamanhoPaginaPadrao},
+		{-3, -1, 1, TamanhoPaginaPadrao},
+		{2, 50, 2, 50},
+		{1, 1000, 1, TamanhoPaginaMaximo},
+	}
+	for _, caso := range casos {
+		consulta := Consulta{Pagina: caso.pagina, TamanhoPagina: caso.tamanho}
+		consulta.normalizarPaginacao()
+		if consulta.Pagina != caso.paginaEsperada || consulta.TamanhoPagina != caso.tamanhoEsperado {
+			t.Errorf("(%d, %d) normalizado para (%d, %d), esperado (%d, %d)", caso.pagina, caso.tamanho,
+				consulta.Pagina, consulta.TamanhoPagina, caso.paginaEsperada, caso.tamanhoEsperado)
+		}
+	}
+}
+
+func TestOrdenacaoSQL(t *testing.T) {
+	campos := CamposConsulta{
+		Ordenacao:       map[string]string{"id": "id", "nome": "nome", "criadoEm": "created_at"},
+		OrdenacaoPadrao: "-criadoEm",
+	}
+	casos := []struct {
+		ordenacao, esperado string
+		valida              bool
+	}{
+		{"", "created_at DESC, id ASC", true},
+		{"nome", "nome ASC, id ASC", true},
+		{" nome , -criadoEm ", "nome ASC, created_at DESC, id ASC", true},
+		{"-id", "id DESC", true},
+		{"senha", "", false},
+		{"nome; DROP TABLE usuarios", "", false},
+	}
+	for _, caso := range casos {
+		ordem, err := ordenacaoSQL(caso.ordenacao, campos)
+		if (err == nil) != caso.valida || ordem != caso.esperado {
+			t.Errorf("ordenacaoSQL(%q) = %q, %v; esperado %q", caso.ordenacao, ordem, err, caso.esperado)
+		}
+	}
+}
+
+func TestCursorIdaEVolta(t *testing.T) {
+	original := cursorLista{Valor: 1741608000000000000, ID: 42}
+	cursor, err := decodificarCursor(codificarCursor(original))
+	if err != nil {
+		t.Fatalf("erro ao decodificar: %v", err)
+	}
+	if *cursor != original {
+		t.Errorf("cursor = %+v, esperado %+v", *cursor, original)
+	}
+
+	for _, invalido := range []string{"", "***", "bm90LWpzb24", "eyJ2IjoxfQ"} {
+		if _, err := decodificarCursor(invalido); err == nil {
+			t.Errorf("o cursor %q deveria ser rejeitado", invalido)
+		}
+	}
+}
+
+func TestEscaparLike(t *testing.T) {
+	casos := map[string]string{
+		"freio": "freio",
+		"100%":  "100!%",
+		"a_b":   "a!_b",
+		"sim!":  "sim!!",
+		"!%_":   "!!!%!_",
+	}
+	for valor, esperado := range casos {
+		if got := escaparLike(valor); got != esperado {
+			t.Errorf("escaparLike(%q) = %q, esperado %q", valor, got, esperado)
+		}
+	}
+}
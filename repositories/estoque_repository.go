@@ -4,16 +4,22 @@ import (
 	"OficinaMecanica/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EstoqueRepository interface {
 	FindAll() ([]models.Estoque, error)
+	FindPaginado(consulta Consulta) (*models.Pagina[models.Estoque], error)
 	FindByID(id uint) (*models.Estoque, error)
+	FindByIDForUpdate(id uint) (*models.Estoque, error)
 	Create(estoque *models.Estoque) error
 	Update(estoque *models.Estoque) error
+	UpdateQuantidade(id uint, quantidade int) error
+	UpdateQuantidadeReservada(id uint, reservada int) error
 	Delete(id uint) error
 	FindByCategoria(categoria string) ([]models.Estoque, error)
 	FindBaixoEstoque() ([]models.Estoque, error)
+	WithTx(tx *gorm.DB) EstoqueRepository
 }
 
 type EstoqueRepositoryImpl struct {
@@ -24,15 +30,58 @@ func NewEstoqueRepository(db *gorm.DB) EstoqueRepository {
 	return &EstoqueRepositoryImpl{db: db}
 }
 
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *EstoqueRepositoryImpl) WithTx(tx *gorm.DB) EstoqueRepository {
+	return &EstoqueRepositoryImpl{db: tx}
+}
+
 func (r *EstoqueRepositoryImpl) FindAll() ([]models.Estoque, error) {
 	var itens []models.Estoque
-	result := r.db.Find(&itens)
+	result := r.db.Preload("Fornecedor").Find(&itens)
 	return itens, result.Error
 }
 
+// camposConsultaEstoque define a ordenação e os filtros aceitos na listagem do estoque
+var camposConsultaEstoque = CamposConsulta{
+	Ordenacao: map[string]string{
+		"id":            "id",
+		"nome":          "nome",
+		"codigo":        "codigo",
+		"categoria":     "categoria",
+		"quantidade":    "quantidade",
+		"precoVenda":    "preco_venda",
+		"precoUnitario": "preco_unitario",
+		"criadoEm":      "criado_em",
+	},
+	Filtros: map[string]CampoFiltro{
+		"nome":         {Coluna: "nome", Operador: FiltroContem},
+		"codigo":       {Coluna: "codigo", Operador: FiltroIgual},
+		"categoria":    {Coluna: "categoria", Operador: FiltroIgual},
+		"status":       {Coluna: "status", Operador: FiltroIgual},
+		"fornecedorId": {Coluna: "fornecedor_id", Operador: FiltroIgual},
+	},
+	OrdenacaoPadrao: "nome",
+}
+
+// FindPaginado busca uma página de itens do estoque conforme a ordenação e os filtros da consulta
+func (r *EstoqueRepositoryImpl) FindPaginado(consulta Consulta) (*models.Pagina[models.Estoque], error) {
+	return paginar[models.Estoque](r.db.Preload("Fornecedor"), consulta, camposConsultaEstoque)
+}
+
 func (r *EstoqueRepositoryImpl) FindByID(id uint) (*models.Estoque, error) {
 	var item models.Estoque
-	result := r.db.First(&item, id)
+	result := r.db.Preload("Fornecedor").First(&item, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// FindByIDForUpdate busca o item bloqueando a linha até o fim da transação (SELECT ... FOR UPDATE),
+// impedindo que duas requisições simultâneas baixem o mesmo saldo
+func (r *EstoqueRepositoryImpl) FindByIDForUpdate(id uint) (*models.Estoque, error) {
+	var item models.Estoque
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -40,11 +89,23 @@ func (r *EstoqueRepositoryImpl) FindByID(id uint) (*models.Estoque, error) {
 }
 
 func (r *EstoqueRepositoryImpl) Create(estoque *models.Estoque) error {
-	return r.db.Create(estoque).Error
+	return r.db.Omit(clause.Associations).Create(estoque).Error
 }
 
+// Update grava os dados cadastrais do item; a quantidade só muda por UpdateQuantidade,
+// chamado pelo serviço de movimentações, e a reserva por UpdateQuantidadeReservada
 func (r *EstoqueRepositoryImpl) Update(estoque *models.Estoque) error {
-	return r.db.Save(estoque).Error
+	return r.db.Omit("Quantidade", "QuantidadeReservada", clause.Associations).Save(estoque).Error
+}
+
+// UpdateQuantidade grava o novo saldo do item
+func (r *EstoqueRepositoryImpl) UpdateQuantidade(id uint, quantidade int) error {
+	return r.db.Model(&models.Estoque{}).Where("id = ?", id).Update("quantidade", quantidade).Error
+}
+
+// UpdateQuantidadeReservada grava o total reservado do item
+func (r *EstoqueRepositoryImpl) UpdateQuantidadeReservada(id uint, reservada int) error {
+	return r.db.Model(&models.Estoque{}).Where("id = ?", id).Update("quantidade_reservada", reservada).Error
 }
 
 func (r *EstoqueRepositoryImpl) Delete(id uint) error {
@@ -53,12 +114,12 @@ func (r *EstoqueRepositoryImpl) Delete(id uint) error {
 
 func (r *EstoqueRepositoryImpl) FindByCategoria(categoria string) ([]models.Estoque, error) {
 	var itens []models.Estoque
-	result := r.db.Where("categoria = ?", categoria).Find(&itens)
+	result := r.db.Preload("Fornecedor").Where("categoria = ?", categoria).Find(&itens)
 	return itens, result.Error
 }
 
 func (r *EstoqueRepositoryImpl) FindBaixoEstoque() ([]models.Estoque, error) {
 	var itens []models.Estoque
-	result := r.db.Where("quantidade < estoque_minimo").Find(&itens)
+	result := r.db.Preload("Fornecedor").Where("quantidade < estoque_minimo").Find(&itens)
 	return itens, result.Error
 }
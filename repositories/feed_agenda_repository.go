@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeedAgendaRepository define a interface para os segredos dos feeds iCalendar dos funcionários
+type FeedAgendaRepository interface {
+	FindByFuncionario(funcionarioID uint) (*models.FeedAgenda, error)
+	Salvar(feed *models.FeedAgenda) error
+	DeleteByFuncionario(funcionarioID uint) error
+	WithTx(tx *gorm.DB) FeedAgendaRepository
+}
+
+// FeedAgendaRepositoryImpl implementa a interface FeedAgendaRepository
+type FeedAgendaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFeedAgendaRepository cria uma nova instância de FeedAgendaRepository
+func NewFeedAgendaRepository(db *gorm.DB) FeedAgendaRepository {
+	return &FeedAgendaRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *FeedAgendaRepositoryImpl) WithTx(tx *gorm.DB) FeedAgendaRepository {
+	return &FeedAgendaRepositoryImpl{db: tx}
+}
+
+// FindByFuncionario busca o segredo do feed do funcionário
+func (r *FeedAgendaRepositoryImpl) FindByFuncionario(funcionarioID uint) (*models.FeedAgenda, error) {
+	var feed models.FeedAgenda
+	result := r.db.Where("funcionario_id = ?", funcionarioID).First(&feed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &feed, nil
+}
+
+// Salvar grava o segredo do feed, substituindo o anterior do mesmo funcionário
+func (r *FeedAgendaRepositoryImpl) Salvar(feed *models.FeedAgenda) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "funcionario_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "updated_at"}),
+	}).Create(feed).Error
+}
+
+// DeleteByFuncionario remove o segredo do feed, desativando o link público do funcionário
+func (r *FeedAgendaRepositoryImpl) DeleteByFuncionario(funcionarioID uint) error {
+	return r.db.Where("funcionario_id = ?", funcionarioID).Delete(&models.FeedAgenda{}).Error
+}
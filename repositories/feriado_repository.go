@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// FeriadoRepository define a interface para operações de repositório dos feriados da oficina
+type FeriadoRepository interface {
+	FindAll(ano int) ([]models.Feriado, error)
+	FindByID(id uint) (*models.Feriado, error)
+	ExisteNaData(data time.Time) (bool, error)
+	Create(feriado *models.Feriado) error
+	Delete(id uint) error
+}
+
+// FeriadoRepositoryImpl implementa a interface FeriadoRepository
+type FeriadoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFeriadoRepository cria uma nova instância de FeriadoRepository
+func NewFeriadoRepository(db *gorm.DB) FeriadoRepository {
+	return &FeriadoRepositoryImpl{db: db}
+}
+
+// FindAll busca os feriados cadastrados, opcionalmente de um ano
+func (r *FeriadoRepositoryImpl) FindAll(ano int) ([]models.Feriado, error) {
+	var feriados []models.Feriado
+	query := r.db.Order("data")
+	if ano > 0 {
+		inicio := time.Date(ano, time.January, 1, 0, 0, 0, 0, time.Local)
+		query = query.Where("data >= ? AND data < ?", inicio, inicio.AddDate(1, 0, 0))
+	}
+	result := query.Find(&feriados)
+	return feriados, result.Error
+}
+
+// FindByID busca um feriado pelo ID
+func (r *FeriadoRepositoryImpl) FindByID(id uint) (*models.Feriado, error) {
+	var feriado models.Feriado
+	result := r.db.First(&feriado, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &feriado, nil
+}
+
+// ExisteNaData verifica se o dia informado é feriado; a busca por intervalo funciona mesmo quando o
+// driver grava a data com horário (SQLite)
+func (r *FeriadoRepositoryImpl) ExisteNaData(data time.Time) (bool, error) {
+	ano, mes, dia := data.Date()
+	inicio := time.Date(ano, mes, dia, 0, 0, 0, 0, data.Location())
+	var total int64
+	result := r.db.Model(&models.Feriado{}).Where("data >= ? AND data < ?", inicio, inicio.AddDate(0, 0, 1)).Count(&total)
+	return total > 0, result.Error
+}
+
+// Create cadastra um novo feriado
+func (r *FeriadoRepositoryImpl) Create(feriado *models.Feriado) error {
+	return r.db.Create(feriado).Error
+}
+
+// Delete remove um feriado pelo ID
+func (r *FeriadoRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Feriado{}, id).Error
+}
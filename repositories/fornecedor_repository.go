@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FornecedorRepository define a interface para operações de repositório dos fornecedores
+type FornecedorRepository interface {
+	FindAll() ([]models.Fornecedor, error)
+	FindByID(id uint) (*models.Fornecedor, error)
+	FindByNome(nome string) (*models.Fornecedor, error)
+	FindByCNPJ(cnpj string) (*models.Fornecedor, error)
+	Create(fornecedor *models.Fornecedor) error
+	Update(fornecedor *models.Fornecedor) error
+	Delete(id uint) error
+	FindItens(fornecedorID uint) ([]models.FornecedorItem, error)
+	FindItensByEstoqueID(estoqueID uint) ([]models.FornecedorItem, error)
+	FindItem(fornecedorID uint, estoqueID uint) (*models.FornecedorItem, error)
+	SaveItem(item *models.FornecedorItem) error
+	RemoveItem(fornecedorID uint, estoqueID uint) error
+	Relatorio() ([]models.RelatorioFornecedor, error)
+	WithTx(tx *gorm.DB) FornecedorRepository
+}
+
+// FornecedorRepositoryImpl implementa a interface FornecedorRepository
+type FornecedorRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFornecedorRepository cria uma nova instância de FornecedorRepository
+func NewFornecedorRepository(db *gorm.DB) FornecedorRepository {
+	return &FornecedorRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *FornecedorRepositoryImpl) WithTx(tx *gorm.DB) FornecedorRepository {
+	return &FornecedorRepositoryImpl{db: tx}
+}
+
+// FindAll busca todos os fornecedores
+func (r *FornecedorRepositoryImpl) FindAll() ([]models.Fornecedor, error) {
+	var fornecedores []models.Fornecedor
+	result := r.db.Order("nome").Find(&fornecedores)
+	return fornecedores, result.Error
+}
+
+// FindByID busca um fornecedor pelo ID
+func (r *FornecedorRepositoryImpl) FindByID(id uint) (*models.Fornecedor, error) {
+	var fornecedor models.Fornecedor
+	result := r.db.First(&fornecedor, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &fornecedor, nil
+}
+
+// FindByNome busca um fornecedor pelo nome, incluindo os excluídos (o nome é único)
+func (r *FornecedorRepositoryImpl) FindByNome(nome string) (*models.Fornecedor, error) {
+	var fornecedor models.Fornecedor
+	result := r.db.Unscoped().Where("nome = ?", nome).First(&fornecedor)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &fornecedor, nil
+}
+
+// FindByCNPJ busca um fornecedor pelo CNPJ, incluindo os excluídos (o CNPJ é único)
+func (r *FornecedorRepositoryImpl) FindByCNPJ(cnpj string) (*models.Fornecedor, error) {
+	var fornecedor models.Fornecedor
+	result := r.db.Unscoped().Where("cnpj = ?", cnpj).First(&fornecedor)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &fornecedor, nil
+}
+
+// Create cria um novo fornecedor
+func (r *FornecedorRepositoryImpl) Create(fornecedor *models.Fornecedor) error {
+	return r.db.Create(fornecedor).Error
+}
+
+// Update atualiza um fornecedor existente
+func (r *FornecedorRepositoryImpl) Update(fornecedor *models.Fornecedor) error {
+	return r.db.Save(fornecedor).Error
+}
+
+// Delete remove um fornecedor pelo ID (soft delete)
+func (r *FornecedorRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Fornecedor{}, id).Error
+}
+
+// FindItens busca os códigos e preços das peças de um fornecedor
+func (r *FornecedorRepositoryImpl) FindItens(fornecedorID uint) ([]models.FornecedorItem, error) {
+	var itens []models.FornecedorItem
+	result := r.db.Preload("Item").Where("fornecedor_id = ?", fornecedorID).Order("estoque_id").Find(&itens)
+	return itens, result.Error
+}
+
+// FindItensByEstoqueID busca os fornecedores que vendem uma peça, do menor para o maior preço
+func (r *FornecedorRepositoryImpl) FindItensByEstoqueID(estoqueID uint) ([]models.FornecedorItem, error) {
+	var itens []models.FornecedorItem
+	result := r.db.Preload("Fornecedor").Where("estoque_id = ?", estoqueID).Order("preco").Find(&itens)
+	return itens, result.Error
+}
+
+// FindItem busca o código e o preço de uma peça em um fornecedor
+func (r *FornecedorRepositoryImpl) FindItem(fornecedorID uint, estoqueID uint) (*models.FornecedorItem, error) {
+	var item models.FornecedorItem
+	result := r.db.Where("fornecedor_id = ? AND estoque_id = ?", fornecedorID, estoqueID).First(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// SaveItem cria ou atualiza o código e o preço de uma peça em um fornecedor
+func (r *FornecedorRepositoryImpl) SaveItem(item *models.FornecedorItem) error {
+	return r.db.Omit(clause.Associations).Save(item).Error
+}
+
+// RemoveItem remove o vínculo de uma peça com um fornecedor
+func (r *FornecedorRepositoryImpl) RemoveItem(fornecedorID uint, estoqueID uint) error {
+	result := r.db.Where("fornecedor_id = ? AND estoque_id = ?", fornecedorID, estoqueID).Delete(&models.FornecedorItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Relatorio resume, por fornecedor, os itens de estoque vinculados a ele
+func (r *FornecedorRepositoryImpl) Relatorio() ([]models.RelatorioFornecedor, error) {
+	var relatorio []models.RelatorioFornecedor
+	result := r.db.Model(&models.Fornecedor{}).
+		Select(`fornecedores.id AS fornecedor_id, fornecedores.nome,
+			COUNT(estoque.id) AS quantidade_itens,
+			COALESCE(SUM(estoque.quantidade), 0) AS quantidade_estoque,
+			COALESCE(SUM(estoque.quantidade * estoque.preco_unitario), 0) AS valor_estoque,
+			COALESCE(SUM(CASE WHEN estoque.quantidade < estoque.estoque_minimo THEN 1 ELSE 0 END), 0) AS itens_baixo_estoque`).
+		Joins("LEFT JOIN estoque ON estoque.fornecedor_id = fornecedores.id AND estoque.deleted_at IS NULL").
+		Group("fornecedores.id, fornecedores.nome").
+		Order("fornecedores.nome").
+		Scan(&relatorio)
+	return relatorio, result.Error
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FuncionarioRepository define a interface para operações de repositório do funcionário
+type FuncionarioRepository interface {
+	FindAll() ([]models.Funcionario, error)
+	FindByID(id uint) (*models.Funcionario, error)
+	FindByCPF(cpf string) (*models.Funcionario, error)
+	FindByUsuarioID(usuarioID uint) (*models.Funcionario, error)
+	FindByCargo(cargo string) ([]models.Funcionario, error)
+	FindDeleted() ([]models.Funcionario, error)
+	Create(funcionario *models.Funcionario) error
+	Update(funcionario *models.Funcionario) error
+	Delete(id uint) error
+	Restore(id uint) error
+	WithTx(tx *gorm.DB) FuncionarioRepository
+}
+
+// FuncionarioRepositoryImpl implementa a interface FuncionarioRepository
+type FuncionarioRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFuncionarioRepository cria uma nova instância de FuncionarioRepository
+func NewFuncionarioRepository(db *gorm.DB) FuncionarioRepository {
+	return &FuncionarioRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *FuncionarioRepositoryImpl) WithTx(tx *gorm.DB) FuncionarioRepository {
+	return &FuncionarioRepositoryImpl{db: tx}
+}
+
+// preloadUsuario carrega o usuário vinculado sem trazer o hash da senha
+func preloadUsuario(db *gorm.DB) *gorm.DB {
+	return db.Preload("Usuario", func(tx *gorm.DB) *gorm.DB {
+		return tx.Select("id", "nome", "email", "cargo", "ativo", "avatar", "status", "ferias")
+	})
+}
+
+// FindAll busca todos os funcionários ativos (não excluídos)
+func (r *FuncionarioRepositoryImpl) FindAll() ([]models.Funcionario, error) {
+	var funcionarios []models.Funcionario
+	result := preloadUsuario(r.db).Order("nome").Find(&funcionarios)
+	return funcionarios, result.Error
+}
+
+// FindByID busca um funcionário pelo ID
+func (r *FuncionarioRepositoryImpl) FindByID(id uint) (*models.Funcionario, error) {
+	var funcionario models.Funcionario
+	result := preloadUsuario(r.db).First(&funcionario, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &funcionario, nil
+}
+
+// FindByCPF busca um funcionário pelo CPF, incluindo os excluídos,
+// já que o índice único do CPF também considera os registros com soft delete
+func (r *FuncionarioRepositoryImpl) FindByCPF(cpf string) (*models.Funcionario, error) {
+	var funcionario models.Funcionario
+	result := r.db.Unscoped().Where("cpf = ?", cpf).First(&funcionario)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &funcionario, nil
+}
+
+// FindByUsuarioID busca o funcionário vinculado a um usuário, incluindo os excluídos
+func (r *FuncionarioRepositoryImpl) FindByUsuarioID(usuarioID uint) (*models.Funcionario, error) {
+	var funcionario models.Funcionario
+	result := r.db.Unscoped().Where("usuario_id = ?", usuarioID).First(&funcionario)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &funcionario, nil
+}
+
+// FindByCargo busca os funcionários de um cargo
+func (r *FuncionarioRepositoryImpl) FindByCargo(cargo string) ([]models.Funcionario, error) {
+	var funcionarios []models.Funcionario
+	result := preloadUsuario(r.db).Where("cargo = ?", cargo).Order("nome").Find(&funcionarios)
+	return funcionarios, result.Error
+}
+
+// FindDeleted busca os funcionários excluídos (soft delete)
+func (r *FuncionarioRepositoryImpl) FindDeleted() ([]models.Funcionario, error) {
+	var funcionarios []models.Funcionario
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("nome").Find(&funcionarios)
+	return funcionarios, result.Error
+}
+
+// Create cria um novo funcionário
+func (r *FuncionarioRepositoryImpl) Create(funcionario *models.Funcionario) error {
+	return r.db.Omit(clause.Associations).Create(funcionario).Error
+}
+
+// Update atualiza um funcionário existente sem regravar o usuário vinculado
+func (r *FuncionarioRepositoryImpl) Update(funcionario *models.Funcionario) error {
+	return r.db.Omit(clause.Associations).Save(funcionario).Error
+}
+
+// Delete remove um funcionário pelo ID (soft delete)
+func (r *FuncionarioRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Funcionario{}, id).Error
+}
+
+// Restore desfaz o soft delete de um funcionário
+func (r *FuncionarioRepositoryImpl) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.Funcionario{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
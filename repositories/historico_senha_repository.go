@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// HistoricoSenhaRepository define a interface para o histórico de senhas dos usuários
+type HistoricoSenhaRepository interface {
+	Create(historico *models.HistoricoSenha) error
+	FindUltimos(usuarioID uint, quantidade int) ([]models.HistoricoSenha, error)
+	DeleteAlemDe(usuarioID uint, manter int) error
+	WithTx(tx *gorm.DB) HistoricoSenhaRepository
+}
+
+// HistoricoSenhaRepositoryImpl implementa a interface HistoricoSenhaRepository
+type HistoricoSenhaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewHistoricoSenhaRepository cria uma nova instância de HistoricoSenhaRepository
+func NewHistoricoSenhaRepository(db *gorm.DB) HistoricoSenhaRepository {
+	return &HistoricoSenhaRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *HistoricoSenhaRepositoryImpl) WithTx(tx *gorm.DB) HistoricoSenhaRepository {
+	return &HistoricoSenhaRepositoryImpl{db: tx}
+}
+
+// Create registra o hash de uma senha definida pelo usuário
+func (r *HistoricoSenhaRepositoryImpl) Create(historico *models.HistoricoSenha) error {
+	return r.db.Create(historico).Error
+}
+
+// FindUltimos busca os hashes das senhas mais recentes do usuário
+func (r *HistoricoSenhaRepositoryImpl) FindUltimos(usuarioID uint, quantidade int) ([]models.HistoricoSenha, error) {
+	var historico []models.HistoricoSenha
+	result := r.db.Where("usuario_id = ?", usuarioID).Order("id DESC").Limit(quantidade).Find(&historico)
+	return historico, result.Error
+}
+
+// DeleteAlemDe remove os hashes mais antigos, mantendo apenas as últimas senhas do usuário
+func (r *HistoricoSenhaRepositoryImpl) DeleteAlemDe(usuarioID uint, manter int) error {
+	// O MySQL não aceita LIMIT em subconsultas com IN, então os IDs antigos são buscados antes
+	var antigos []uint
+	err := r.db.Model(&models.HistoricoSenha{}).
+		Where("usuario_id = ?", usuarioID).Order("id DESC").Offset(manter).Limit(1000).
+		Pluck("id", &antigos).Error
+	if err != nil || len(antigos) == 0 {
+		return err
+	}
+	return r.db.Where("id IN ?", antigos).Delete(&models.HistoricoSenha{}).Error
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ManutencaoRepository define a interface para as leituras de km e os planos de manutenção dos veículos
+type ManutencaoRepository interface {
+	FindLeituras(veiculoID uint) ([]models.LeituraKm, error)
+	FindUltimaLeitura(veiculoID uint) (*models.LeituraKm, error)
+	CreateLeitura(leitura *models.LeituraKm) error
+	AtualizarKmVeiculo(veiculoID uint, km int) error
+	FindPlanos(veiculoID uint) ([]models.PlanoManutencao, error)
+	FindPlanosAtivos() ([]models.PlanoManutencao, error)
+	FindPlano(veiculoID uint, planoID uint) (*models.PlanoManutencao, error)
+	CreatePlano(plano *models.PlanoManutencao) error
+	UpdatePlano(plano *models.PlanoManutencao) error
+	DeletePlano(planoID uint) error
+	WithTx(tx *gorm.DB) ManutencaoRepository
+}
+
+// ManutencaoRepositoryImpl implementa a interface ManutencaoRepository
+type ManutencaoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewManutencaoRepository cria uma nova instância de ManutencaoRepository
+func NewManutencaoRepository(db *gorm.DB) ManutencaoRepository {
+	return &ManutencaoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *ManutencaoRepositoryImpl) WithTx(tx *gorm.DB) ManutencaoRepository {
+	return &ManutencaoRepositoryImpl{db: tx}
+}
+
+// FindLeituras busca as leituras de km do veículo em ordem cronológica
+func (r *ManutencaoRepositoryImpl) FindLeituras(veiculoID uint) ([]models.LeituraKm, error) {
+	var leituras []models.LeituraKm
+	result := r.db.Where("veiculo_id = ?", veiculoID).Order("data_leitura, id").Find(&leituras)
+	return leituras, result.Error
+}
+
+// FindUltimaLeitura busca a leitura mais recente do veículo
+func (r *ManutencaoRepositoryImpl) FindUltimaLeitura(veiculoID uint) (*models.LeituraKm, error) {
+	var leitura models.LeituraKm
+	result := r.db.Where("veiculo_id = ?", veiculoID).Order("data_leitura DESC, id DESC").First(&leitura)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &leitura, nil
+}
+
+// CreateLeitura registra uma leitura de km
+func (r *ManutencaoRepositoryImpl) CreateLeitura(leitura *models.LeituraKm) error {
+	return r.db.Create(leitura).Error
+}
+
+// AtualizarKmVeiculo grava a quilometragem atual no cadastro do veículo sem alterar os demais campos
+func (r *ManutencaoRepositoryImpl) AtualizarKmVeiculo(veiculoID uint, km int) error {
+	return r.db.Model(&models.Veiculo{}).Where("id = ?", veiculoID).UpdateColumn("km_atual", km).Error
+}
+
+// FindPlanos busca os planos de manutenção do veículo
+func (r *ManutencaoRepositoryImpl) FindPlanos(veiculoID uint) ([]models.PlanoManutencao, error) {
+	var planos []models.PlanoManutencao
+	result := r.db.Where("veiculo_id = ?", veiculoID).Order("descricao").Find(&planos)
+	return planos, result.Error
+}
+
+// FindPlanosAtivos busca os planos ativos de todos os veículos, com o veículo carregado
+func (r *ManutencaoRepositoryImpl) FindPlanosAtivos() ([]models.PlanoManutencao, error) {
+	var planos []models.PlanoManutencao
+	result := r.db.Joins("Veiculo").Where("planos_manutencao.ativo = ?", true).Find(&planos)
+	return planos, result.Error
+}
+
+// FindPlano busca um plano garantindo que pertence ao veículo informado
+func (r *ManutencaoRepositoryImpl) FindPlano(veiculoID uint, planoID uint) (*models.PlanoManutencao, error) {
+	var plano models.PlanoManutencao
+	result := r.db.Where("veiculo_id = ?", veiculoID).First(&plano, planoID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &plano, nil
+}
+
+// CreatePlano cadastra um plano de manutenção
+func (r *ManutencaoRepositoryImpl) CreatePlano(plano *models.PlanoManutencao) error {
+	return r.db.Omit(clause.Associations).Create(plano).Error
+}
+
+// UpdatePlano atualiza um plano de manutenção
+func (r *ManutencaoRepositoryImpl) UpdatePlano(plano *models.PlanoManutencao) error {
+	return r.db.Omit(clause.Associations).Save(plano).Error
+}
+
+// DeletePlano remove um plano de manutenção (soft delete)
+func (r *ManutencaoRepositoryImpl) DeletePlano(planoID uint) error {
+	return r.db.Delete(&models.PlanoManutencao{}, planoID).Error
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MovimentacaoEstoqueRepository define a interface para o histórico de movimentações do estoque
+type MovimentacaoEstoqueRepository interface {
+	Create(movimentacao *models.MovimentacaoEstoque) error
+	FindByEstoqueID(estoqueID uint, inicio, fim *time.Time) ([]models.MovimentacaoEstoque, error)
+	WithTx(tx *gorm.DB) MovimentacaoEstoqueRepository
+}
+
+// MovimentacaoEstoqueRepositoryImpl implementa a interface MovimentacaoEstoqueRepository
+type MovimentacaoEstoqueRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMovimentacaoEstoqueRepository cria uma nova instância de MovimentacaoEstoqueRepository
+func NewMovimentacaoEstoqueRepository(db *gorm.DB) MovimentacaoEstoqueRepository {
+	return &MovimentacaoEstoqueRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *MovimentacaoEstoqueRepositoryImpl) WithTx(tx *gorm.DB) MovimentacaoEstoqueRepository {
+	return &MovimentacaoEstoqueRepositoryImpl{db: tx}
+}
+
+// Create registra uma movimentação; as movimentações nunca são alteradas depois de gravadas
+func (r *MovimentacaoEstoqueRepositoryImpl) Create(movimentacao *models.MovimentacaoEstoque) error {
+	return r.db.Omit("Estoque").Create(movimentacao).Error
+}
+
+// FindByEstoqueID busca as movimentações de um item, opcionalmente limitadas a um período
+func (r *MovimentacaoEstoqueRepositoryImpl) FindByEstoqueID(estoqueID uint, inicio, fim *time.Time) ([]models.MovimentacaoEstoque, error) {
+	var movimentacoes []models.MovimentacaoEstoque
+	query := r.db.Where("estoque_id = ?", estoqueID)
+	if inicio != nil {
+		query = query.Where("criado_em >= ?", *inicio)
+	}
+	if fim != nil {
+		query = query.Where("criado_em <= ?", *fim)
+	}
+	result := query.Order("criado_em DESC, id DESC").Find(&movimentacoes)
+	return movimentacoes, result.Error
+}
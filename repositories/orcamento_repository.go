@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrcamentoRepository define a interface para operações de repositório dos orçamentos
+type OrcamentoRepository interface {
+	FindAll() ([]models.Orcamento, error)
+	FindByStatus(status string) ([]models.Orcamento, error)
+	FindByID(id uint) (*models.Orcamento, error)
+	FindByIDForUpdate(id uint) (*models.Orcamento, error)
+	FindVersoes(numero string) ([]models.Orcamento, error)
+	FindByOrdemServicoID(osID uint) ([]models.Orcamento, error)
+	FindVencidos(agora time.Time) ([]models.Orcamento, error)
+	ExisteAprovadoParaOS(osID uint) (bool, error)
+	Create(orcamento *models.Orcamento) error
+	Update(orcamento *models.Orcamento) error
+	FindItens(orcamentoID uint) ([]models.ItemOrcamento, error)
+	AddItem(item *models.ItemOrcamento) error
+	WithTx(tx *gorm.DB) OrcamentoRepository
+}
+
+// OrcamentoRepositoryImpl implementa a interface OrcamentoRepository
+type OrcamentoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrcamentoRepository cria uma nova instância de OrcamentoRepository
+func NewOrcamentoRepository(db *gorm.DB) OrcamentoRepository {
+	return &OrcamentoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *OrcamentoRepositoryImpl) WithTx(tx *gorm.DB) OrcamentoRepository {
+	return &OrcamentoRepositoryImpl{db: tx}
+}
+
+// FindAll busca todos os orçamentos, dos mais recentes para os mais antigos
+func (r *OrcamentoRepositoryImpl) FindAll() ([]models.Orcamento, error) {
+	var orcamentos []models.Orcamento
+	result := r.db.Preload("Cliente").Preload("Veiculo").Order("created_at DESC").Find(&orcamentos)
+	return orcamentos, result.Error
+}
+
+// FindByStatus busca os orçamentos em um status
+func (r *OrcamentoRepositoryImpl) FindByStatus(status string) ([]models.Orcamento, error) {
+	var orcamentos []models.Orcamento
+	result := r.db.Preload("Cliente").Preload("Veiculo").Where("status = ?", status).Order("created_at DESC").Find(&orcamentos)
+	return orcamentos, result.Error
+}
+
+// FindByID busca um orçamento com cliente, veículo e linhas
+func (r *OrcamentoRepositoryImpl) FindByID(id uint) (*models.Orcamento, error) {
+	var orcamento models.Orcamento
+	result := r.db.Preload("Cliente").Preload("Veiculo").Preload("Itens").Preload("Itens.Item").Preload("Itens.Servico").First(&orcamento, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &orcamento, nil
+}
+
+// FindByIDForUpdate busca o orçamento (sem relacionamentos) bloqueando a linha até o fim da transação
+func (r *OrcamentoRepositoryImpl) FindByIDForUpdate(id uint) (*models.Orcamento, error) {
+	var orcamento models.Orcamento
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&orcamento, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &orcamento, nil
+}
+
+// FindVersoes busca todas as versões de um orçamento, da primeira para a última
+func (r *OrcamentoRepositoryImpl) FindVersoes(numero string) ([]models.Orcamento, error) {
+	var orcamentos []models.Orcamento
+	result := r.db.Preload("Itens").Where("numero = ?", numero).Order("versao").Find(&orcamentos)
+	return orcamentos, result.Error
+}
+
+// FindByOrdemServicoID busca os orçamentos ligados a uma OS
+func (r *OrcamentoRepositoryImpl) FindByOrdemServicoID(osID uint) ([]models.Orcamento, error) {
+	var orcamentos []models.Orcamento
+	result := r.db.Where("ordem_servico_id = ?", osID).Order("numero, versao").Find(&orcamentos)
+	return orcamentos, result.Error
+}
+
+// FindVencidos busca os orçamentos pendentes cuja validade já passou
+func (r *OrcamentoRepositoryImpl) FindVencidos(agora time.Time) ([]models.Orcamento, error) {
+	var orcamentos []models.Orcamento
+	result := r.db.Where("status = ? AND validade_ate < ?", models.StatusOrcamentoPendente, agora).Find(&orcamentos)
+	return orcamentos, result.Error
+}
+
+// ExisteAprovadoParaOS verifica se a OS possui um orçamento aprovado (ou já convertido)
+func (r *OrcamentoRepositoryImpl) ExisteAprovadoParaOS(osID uint) (bool, error) {
+	var total int64
+	result := r.db.Model(&models.Orcamento{}).
+		Where("ordem_servico_id = ? AND status IN ?", osID, []string{models.StatusOrcamentoAprovado, models.StatusOrcamentoConvertido}).
+		Count(&total)
+	return total > 0, result.Error
+}
+
+// Create grava o orçamento; as linhas são gravadas por AddItem
+func (r *OrcamentoRepositoryImpl) Create(orcamento *models.Orcamento) error {
+	return r.db.Omit(clause.Associations).Create(orcamento).Error
+}
+
+// Update grava apenas o próprio orçamento, sem regravar as linhas
+func (r *OrcamentoRepositoryImpl) Update(orcamento *models.Orcamento) error {
+	return r.db.Omit(clause.Associations).Save(orcamento).Error
+}
+
+// FindItens busca as linhas de um orçamento
+func (r *OrcamentoRepositoryImpl) FindItens(orcamentoID uint) ([]models.ItemOrcamento, error) {
+	var itens []models.ItemOrcamento
+	result := r.db.Where("orcamento_id = ?", orcamentoID).Order("id").Find(&itens)
+	return itens, result.Error
+}
+
+// AddItem grava uma nova linha do orçamento
+func (r *OrcamentoRepositoryImpl) AddItem(item *models.ItemOrcamento) error {
+	return r.db.Omit(clause.Associations).Create(item).Error
+}
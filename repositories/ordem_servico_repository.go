@@ -1,15 +1,21 @@
 package repositories
 
 import (
-	"OficinaMecanica/models"
+	"errors"
+	"strings"
 	"time"
 
+	"OficinaMecanica/models"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrdemServicoRepository interface {
 	FindAll() ([]models.OrdemServico, error)
+	FindPaginado(consulta Consulta) (*models.Pagina[models.OrdemServico], error)
 	FindByID(id uint) (*models.OrdemServico, error)
+	FindByIDForUpdate(id uint) (*models.OrdemServico, error)
 	Create(os *models.OrdemServico) error
 	Update(os *models.OrdemServico) error
 	Delete(id uint) error
@@ -18,10 +24,19 @@ type OrdemServicoRepository interface {
 	FindByStatus(status string) ([]models.OrdemServico, error)
 	FindByPeriodo(inicio, fim time.Time) ([]models.OrdemServico, error)
 	FindByNumeroOS(numeroOS string) (*models.OrdemServico, error)
+	FindByFuncionarioID(funcionarioID uint, status []string) ([]models.OrdemServico, error)
 	AddItem(item *models.ItemOrdemServico) error
 	RemoveItem(itemID uint) error
 	UpdateItem(item *models.ItemOrdemServico) error
 	FindItens(osID uint) ([]models.ItemOrdemServico, error)
+	FindItem(osID uint, itemID uint) (*models.ItemOrdemServico, error)
+	AddServico(linha *models.ServicoOrdemServico) error
+	UpdateServico(linha *models.ServicoOrdemServico) error
+	RemoveServico(linhaID uint) error
+	FindServicos(osID uint) ([]models.ServicoOrdemServico, error)
+	FindServico(osID uint, linhaID uint) (*models.ServicoOrdemServico, error)
+	SomarServicos(osID uint) (float64, error)
+	WithTx(tx *gorm.DB) OrdemServicoRepository
 }
 
 type OrdemServicoRepositoryImpl struct {
@@ -32,26 +47,147 @@ func NewOrdemServicoRepository(db *gorm.DB) OrdemServicoRepository {
 	return &OrdemServicoRepositoryImpl{db: db}
 }
 
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *OrdemServicoRepositoryImpl) WithTx(tx *gorm.DB) OrdemServicoRepository {
+	return &OrdemServicoRepositoryImpl{db: tx}
+}
+
 func (r *OrdemServicoRepositoryImpl) FindAll() ([]models.OrdemServico, error) {
 	var ordens []models.OrdemServico
 	result := r.db.Preload("Veiculo").Preload("Cliente").Preload("Funcionario").Find(&ordens)
 	return ordens, result.Error
 }
 
+// camposConsultaOrdemServico define a ordenação e os filtros aceitos na listagem de ordens de serviço
+var camposConsultaOrdemServico = CamposConsulta{
+	Ordenacao: map[string]string{
+		"id":           "id",
+		"numeroOS":     "numero_os",
+		"dataEntrada":  "data_entrada",
+		"dataPrevisao": "data_previsao",
+		"status":       "status",
+		"valorTotal":   "valor_total",
+	},
+	Filtros: map[string]CampoFiltro{
+		"status":           {Coluna: "status", Operador: FiltroIgual},
+		"statusFinanceiro": {Coluna: "status_financeiro", Operador: FiltroIgual},
+		"clienteId":        {Coluna: "cliente_id", Operador: FiltroIgual},
+		"veiculoId":        {Coluna: "veiculo_id", Operador: FiltroIgual},
+		"funcionarioId":    {Coluna: "funcionario_id", Operador: FiltroIgual},
+		"numeroOS":         {Coluna: "numero_os", Operador: FiltroContem},
+		"dataInicio":       {Coluna: "data_entrada", Operador: FiltroDataDe},
+		"dataFim":          {Coluna: "data_entrada", Operador: FiltroDataAte},
+	},
+	OrdenacaoPadrao: "-dataEntrada",
+}
+
+// FindPaginado busca uma página de ordens de serviço conforme a ordenação e os filtros da consulta.
+// Com UsarCursor, a página seguinte parte do último registro entregue em vez de usar OFFSET,
+// o que mantém a listagem rápida e estável mesmo com OS sendo abertas durante a navegação
+func (r *OrdemServicoRepositoryImpl) FindPaginado(consulta Consulta) (*models.Pagina[models.OrdemServico], error) {
+	db := r.db.Preload("Veiculo").Preload("Cliente").Preload("Funcionario")
+	if consulta.UsarCursor {
+		return r.findPorCursor(db, consulta)
+	}
+	return paginar[models.OrdemServico](db, consulta, camposConsultaOrdemServico)
+}
+
+// findPorCursor pagina por data de entrada ou por id, desempatando pelo id
+func (r *OrdemServicoRepositoryImpl) findPorCursor(db *gorm.DB, consulta Consulta) (*models.Pagina[models.OrdemServico], error) {
+	consulta.normalizarPaginacao()
+
+	ordenacao := strings.TrimSpace(consulta.Ordenacao)
+	if ordenacao == "" {
+		ordenacao = camposConsultaOrdemServico.OrdenacaoPadrao
+	}
+	decrescente := strings.HasPrefix(ordenacao, "-")
+	campo := strings.TrimPrefix(ordenacao, "-")
+	if campo != "dataEntrada" && campo != "id" {
+		return nil, errors.New("a paginação por cursor aceita apenas a ordenação por dataEntrada ou id")
+	}
+
+	filtrado, err := aplicarFiltros(db.Model(&models.OrdemServico{}), consulta, camposConsultaOrdemServico)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := filtrado.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	comparacao, direcao := ">", "ASC"
+	if decrescente {
+		comparacao, direcao = "<", "DESC"
+	}
+
+	pagina := filtrado.Session(&gorm.Session{})
+	if consulta.Cursor != "" {
+		cursor, err := decodificarCursor(consulta.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if campo == "id" {
+			pagina = pagina.Where("id "+comparacao+" ?", cursor.ID)
+		} else {
+			dataEntrada := time.Unix(0, cursor.Valor)
+			pagina = pagina.Where("(data_entrada "+comparacao+" ? OR (data_entrada = ? AND id "+comparacao+" ?))",
+				dataEntrada, dataEntrada, cursor.ID)
+		}
+	}
+
+	if campo == "id" {
+		pagina = pagina.Order("id " + direcao)
+	} else {
+		pagina = pagina.Order("data_entrada " + direcao).Order("id " + direcao)
+	}
+
+	// Um registro a mais indica se existe próxima página
+	ordens := make([]models.OrdemServico, 0, consulta.TamanhoPagina+1)
+	if err := pagina.Limit(consulta.TamanhoPagina + 1).Find(&ordens).Error; err != nil {
+		return nil, err
+	}
+
+	resultado := &models.Pagina[models.OrdemServico]{
+		Itens:         ordens,
+		Total:         total,
+		TamanhoPagina: consulta.TamanhoPagina,
+	}
+	if len(ordens) > consulta.TamanhoPagina {
+		resultado.Itens = ordens[:consulta.TamanhoPagina]
+		ultima := resultado.Itens[len(resultado.Itens)-1]
+		resultado.ProximoCursor = codificarCursor(cursorLista{Valor: ultima.DataEntrada.UnixNano(), ID: ultima.ID})
+	}
+	return resultado, nil
+}
+
 func (r *OrdemServicoRepositoryImpl) FindByID(id uint) (*models.OrdemServico, error) {
 	var os models.OrdemServico
 	result := r.db.Preload("Veiculo").Preload("Cliente").Preload("Funcionario").
 		Preload("ItensUtilizados").Preload("ItensUtilizados.Item").
+		Preload("Servicos").Preload("Servicos.Servico").Preload("Servicos.Funcionario").
+		Preload("Pagamentos", func(db *gorm.DB) *gorm.DB { return db.Order("data_pagamento") }).
 		First(&os, id)
 	return &os, result.Error
 }
 
+// FindByIDForUpdate busca a OS (sem relacionamentos) bloqueando a linha até o fim da transação
+func (r *OrdemServicoRepositoryImpl) FindByIDForUpdate(id uint) (*models.OrdemServico, error) {
+	var os models.OrdemServico
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&os, id)
+	return &os, result.Error
+}
+
+// Create grava a OS sem criar relacionamentos enviados junto no JSON;
+// itens devem ser adicionados por AddItem para que o estoque seja baixado
 func (r *OrdemServicoRepositoryImpl) Create(os *models.OrdemServico) error {
-	return r.db.Create(os).Error
+	return r.db.Omit(clause.Associations).Create(os).Error
 }
 
+// Update grava apenas a própria OS; cliente, veículo, funcionário e itens
+// carregados via Preload não são regravados
 func (r *OrdemServicoRepositoryImpl) Update(os *models.OrdemServico) error {
-	return r.db.Save(os).Error
+	return r.db.Omit(clause.Associations).Save(os).Error
 }
 
 func (r *OrdemServicoRepositoryImpl) Delete(id uint) error {
@@ -90,10 +226,25 @@ func (r *OrdemServicoRepositoryImpl) FindByNumeroOS(numeroOS string) (*models.Or
 	var os models.OrdemServico
 	result := r.db.Preload("Veiculo").Preload("Cliente").Preload("Funcionario").
 		Preload("ItensUtilizados").Preload("ItensUtilizados.Item").
+		Preload("Servicos").Preload("Servicos.Servico").Preload("Servicos.Funcionario").
+		Preload("Pagamentos", func(db *gorm.DB) *gorm.DB { return db.Order("data_pagamento") }).
 		Where("numero_os = ?", numeroOS).First(&os)
 	return &os, result.Error
 }
 
+// FindByFuncionarioID busca a fila de ordens de um funcionário, opcionalmente filtrando pelos status
+// A fila é ordenada pela previsão de entrega e depois pela data de entrada
+func (r *OrdemServicoRepositoryImpl) FindByFuncionarioID(funcionarioID uint, status []string) ([]models.OrdemServico, error) {
+	var ordens []models.OrdemServico
+	query := r.db.Preload("Veiculo").Preload("Cliente").Preload("Funcionario").
+		Where("funcionario_id = ?", funcionarioID)
+	if len(status) > 0 {
+		query = query.Where("status IN ?", status)
+	}
+	result := query.Order("data_previsao, data_entrada").Find(&ordens)
+	return ordens, result.Error
+}
+
 func (r *OrdemServicoRepositoryImpl) AddItem(item *models.ItemOrdemServico) error {
 	return r.db.Create(item).Error
 }
@@ -111,3 +262,55 @@ func (r *OrdemServicoRepositoryImpl) FindItens(osID uint) ([]models.ItemOrdemSer
 	result := r.db.Preload("Item").Where("ordem_servico_id = ?", osID).Find(&itens)
 	return itens, result.Error
 }
+
+// FindItem busca um item garantindo que ele pertence à OS informada
+func (r *OrdemServicoRepositoryImpl) FindItem(osID uint, itemID uint) (*models.ItemOrdemServico, error) {
+	var item models.ItemOrdemServico
+	result := r.db.Where("id = ? AND ordem_servico_id = ?", itemID, osID).First(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// AddServico grava uma nova linha de mão de obra da OS
+func (r *OrdemServicoRepositoryImpl) AddServico(linha *models.ServicoOrdemServico) error {
+	return r.db.Omit(clause.Associations).Create(linha).Error
+}
+
+// UpdateServico grava as alterações de uma linha de mão de obra
+func (r *OrdemServicoRepositoryImpl) UpdateServico(linha *models.ServicoOrdemServico) error {
+	return r.db.Omit(clause.Associations).Save(linha).Error
+}
+
+// RemoveServico exclui uma linha de mão de obra
+func (r *OrdemServicoRepositoryImpl) RemoveServico(linhaID uint) error {
+	return r.db.Delete(&models.ServicoOrdemServico{}, linhaID).Error
+}
+
+// FindServicos busca as linhas de mão de obra de uma OS com o serviço e o mecânico
+func (r *OrdemServicoRepositoryImpl) FindServicos(osID uint) ([]models.ServicoOrdemServico, error) {
+	var linhas []models.ServicoOrdemServico
+	result := r.db.Preload("Servico").Preload("Funcionario").Where("ordem_servico_id = ?", osID).Order("id").Find(&linhas)
+	return linhas, result.Error
+}
+
+// FindServico busca uma linha de mão de obra garantindo que ela pertence à OS informada
+func (r *OrdemServicoRepositoryImpl) FindServico(osID uint, linhaID uint) (*models.ServicoOrdemServico, error) {
+	var linha models.ServicoOrdemServico
+	result := r.db.Where("id = ? AND ordem_servico_id = ?", linhaID, osID).First(&linha)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &linha, nil
+}
+
+// SomarServicos retorna o valor total das linhas de mão de obra da OS
+func (r *OrdemServicoRepositoryImpl) SomarServicos(osID uint) (float64, error) {
+	var total float64
+	result := r.db.Model(&models.ServicoOrdemServico{}).
+		Where("ordem_servico_id = ?", osID).
+		Select("COALESCE(SUM(valor_total), 0)").
+		Scan(&total)
+	return total, result.Error
+}
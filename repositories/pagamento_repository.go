@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// PagamentoRepository define a interface para operações de repositório de pagamentos de OS
+type PagamentoRepository interface {
+	FindByOrdemServicoID(osID uint) ([]models.Pagamento, error)
+	FindByID(osID uint, id uint) (*models.Pagamento, error)
+	Create(pagamento *models.Pagamento) error
+	Update(pagamento *models.Pagamento) error
+	Delete(id uint) error
+	SomarPorOS(osID uint) (float64, error)
+	WithTx(tx *gorm.DB) PagamentoRepository
+}
+
+// PagamentoRepositoryImpl implementa a interface PagamentoRepository
+type PagamentoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPagamentoRepository cria uma nova instância de PagamentoRepository
+func NewPagamentoRepository(db *gorm.DB) PagamentoRepository {
+	return &PagamentoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *PagamentoRepositoryImpl) WithTx(tx *gorm.DB) PagamentoRepository {
+	return &PagamentoRepositoryImpl{db: tx}
+}
+
+// FindByOrdemServicoID busca os pagamentos de uma OS em ordem cronológica
+func (r *PagamentoRepositoryImpl) FindByOrdemServicoID(osID uint) ([]models.Pagamento, error) {
+	var pagamentos []models.Pagamento
+	result := r.db.Where("ordem_servico_id = ?", osID).Order("data_pagamento, id").Find(&pagamentos)
+	return pagamentos, result.Error
+}
+
+// FindByID busca um pagamento garantindo que pertence à OS informada
+func (r *PagamentoRepositoryImpl) FindByID(osID uint, id uint) (*models.Pagamento, error) {
+	var pagamento models.Pagamento
+	result := r.db.Where("ordem_servico_id = ?", osID).First(&pagamento, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &pagamento, nil
+}
+
+// Create registra um novo pagamento
+func (r *PagamentoRepositoryImpl) Create(pagamento *models.Pagamento) error {
+	return r.db.Create(pagamento).Error
+}
+
+// Update atualiza um pagamento existente
+func (r *PagamentoRepositoryImpl) Update(pagamento *models.Pagamento) error {
+	return r.db.Save(pagamento).Error
+}
+
+// Delete estorna um pagamento pelo ID (soft delete)
+func (r *PagamentoRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Pagamento{}, id).Error
+}
+
+// SomarPorOS retorna o total recebido da OS, desconsiderando os pagamentos estornados
+func (r *PagamentoRepositoryImpl) SomarPorOS(osID uint) (float64, error) {
+	var total float64
+	result := r.db.Model(&models.Pagamento{}).
+		Where("ordem_servico_id = ?", osID).
+		Select("COALESCE(SUM(valor), 0)").
+		Scan(&total)
+	return total, result.Error
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PedidoCompraRepository define a interface para operações de repositório dos pedidos de compra
+type PedidoCompraRepository interface {
+	FindAll() ([]models.PedidoCompra, error)
+	FindByStatus(status string) ([]models.PedidoCompra, error)
+	FindByID(id uint) (*models.PedidoCompra, error)
+	FindByIDForUpdate(id uint) (*models.PedidoCompra, error)
+	Create(pedido *models.PedidoCompra) error
+	Update(pedido *models.PedidoCompra) error
+	Delete(id uint) error
+	FindItens(pedidoID uint) ([]models.ItemPedidoCompra, error)
+	AddItem(item *models.ItemPedidoCompra) error
+	UpdateItem(item *models.ItemPedidoCompra) error
+	RemoveItens(pedidoID uint) error
+	QuantidadesPendentes(status []string) (map[uint]int, error)
+	WithTx(tx *gorm.DB) PedidoCompraRepository
+}
+
+// PedidoCompraRepositoryImpl implementa a interface PedidoCompraRepository
+type PedidoCompraRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPedidoCompraRepository cria uma nova instância de PedidoCompraRepository
+func NewPedidoCompraRepository(db *gorm.DB) PedidoCompraRepository {
+	return &PedidoCompraRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *PedidoCompraRepositoryImpl) WithTx(tx *gorm.DB) PedidoCompraRepository {
+	return &PedidoCompraRepositoryImpl{db: tx}
+}
+
+// FindAll busca todos os pedidos, dos mais recentes para os mais antigos
+func (r *PedidoCompraRepositoryImpl) FindAll() ([]models.PedidoCompra, error) {
+	var pedidos []models.PedidoCompra
+	result := r.db.Preload("Fornecedor").Preload("Itens").Order("created_at DESC").Find(&pedidos)
+	return pedidos, result.Error
+}
+
+// FindByStatus busca os pedidos em um status
+func (r *PedidoCompraRepositoryImpl) FindByStatus(status string) ([]models.PedidoCompra, error) {
+	var pedidos []models.PedidoCompra
+	result := r.db.Preload("Fornecedor").Preload("Itens").Where("status = ?", status).Order("created_at DESC").Find(&pedidos)
+	return pedidos, result.Error
+}
+
+// FindByID busca um pedido com suas linhas e os itens de estoque correspondentes
+func (r *PedidoCompraRepositoryImpl) FindByID(id uint) (*models.PedidoCompra, error) {
+	var pedido models.PedidoCompra
+	result := r.db.Preload("Fornecedor").Preload("Itens").Preload("Itens.Item").First(&pedido, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &pedido, nil
+}
+
+// FindByIDForUpdate busca o pedido (sem relacionamentos) bloqueando a linha até o fim da transação
+func (r *PedidoCompraRepositoryImpl) FindByIDForUpdate(id uint) (*models.PedidoCompra, error) {
+	var pedido models.PedidoCompra
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&pedido, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &pedido, nil
+}
+
+// Create grava o pedido; as linhas são gravadas por AddItem
+func (r *PedidoCompraRepositoryImpl) Create(pedido *models.PedidoCompra) error {
+	return r.db.Omit(clause.Associations).Create(pedido).Error
+}
+
+// Update grava apenas o próprio pedido, sem regravar as linhas
+func (r *PedidoCompraRepositoryImpl) Update(pedido *models.PedidoCompra) error {
+	return r.db.Omit(clause.Associations).Save(pedido).Error
+}
+
+// Delete remove um pedido pelo ID (soft delete)
+func (r *PedidoCompraRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.PedidoCompra{}, id).Error
+}
+
+// FindItens busca as linhas de um pedido
+func (r *PedidoCompraRepositoryImpl) FindItens(pedidoID uint) ([]models.ItemPedidoCompra, error) {
+	var itens []models.ItemPedidoCompra
+	result := r.db.Where("pedido_compra_id = ?", pedidoID).Order("id").Find(&itens)
+	return itens, result.Error
+}
+
+// AddItem grava uma nova linha do pedido
+func (r *PedidoCompraRepositoryImpl) AddItem(item *models.ItemPedidoCompra) error {
+	return r.db.Omit(clause.Associations).Create(item).Error
+}
+
+// UpdateItem grava as alterações de uma linha do pedido
+func (r *PedidoCompraRepositoryImpl) UpdateItem(item *models.ItemPedidoCompra) error {
+	return r.db.Omit(clause.Associations).Save(item).Error
+}
+
+// RemoveItens apaga todas as linhas de um pedido
+func (r *PedidoCompraRepositoryImpl) RemoveItens(pedidoID uint) error {
+	return r.db.Where("pedido_compra_id = ?", pedidoID).Delete(&models.ItemPedidoCompra{}).Error
+}
+
+// QuantidadesPendentes soma, por item de estoque, o que ainda não foi recebido
+// nos pedidos que estão nos status informados
+func (r *PedidoCompraRepositoryImpl) QuantidadesPendentes(status []string) (map[uint]int, error) {
+	var linhas []struct {
+		EstoqueID uint
+		Pendente  int
+	}
+	result := r.db.Model(&models.ItemPedidoCompra{}).
+		Select("itens_pedido_compra.estoque_id, SUM(itens_pedido_compra.quantidade - itens_pedido_compra.quantidade_recebida) AS pendente").
+		Joins("JOIN pedidos_compra ON pedidos_compra.id = itens_pedido_compra.pedido_compra_id AND pedidos_compra.deleted_at IS NULL").
+		Where("pedidos_compra.status IN ?", status).
+		Group("itens_pedido_compra.estoque_id").
+		Scan(&linhas)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	pendentes := make(map[uint]int, len(linhas))
+	for _, linha := range linhas {
+		pendentes[linha.EstoqueID] = linha.Pendente
+	}
+	return pendentes, nil
+}
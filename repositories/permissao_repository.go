@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// PermissaoRepository define a interface para operações de permissões e seus vínculos com cargos
+type PermissaoRepository interface {
+	FindAll() ([]models.Permissao, error)
+	FindByCodigo(codigo string) (*models.Permissao, error)
+	Create(permissao *models.Permissao) error
+	FindAllCargoPermissoes() ([]models.CargoPermissao, error)
+	FindByCargo(cargo string) ([]models.CargoPermissao, error)
+	CreateCargoPermissao(cargoPermissao *models.CargoPermissao) error
+	ReplaceCargo(cargo string, codigos []string) error
+	WithTx(tx *gorm.DB) PermissaoRepository
+}
+
+// PermissaoRepositoryImpl implementa a interface PermissaoRepository
+type PermissaoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPermissaoRepository cria uma nova instância de PermissaoRepository
+func NewPermissaoRepository(db *gorm.DB) PermissaoRepository {
+	return &PermissaoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *PermissaoRepositoryImpl) WithTx(tx *gorm.DB) PermissaoRepository {
+	return &PermissaoRepositoryImpl{db: tx}
+}
+
+// FindAll busca todas as permissões cadastradas
+func (r *PermissaoRepositoryImpl) FindAll() ([]models.Permissao, error) {
+	var permissoes []models.Permissao
+	result := r.db.Order("codigo").Find(&permissoes)
+	return permissoes, result.Error
+}
+
+// FindByCodigo busca uma permissão pelo código
+func (r *PermissaoRepositoryImpl) FindByCodigo(codigo string) (*models.Permissao, error) {
+	var permissao models.Permissao
+	result := r.db.Where("codigo = ?", codigo).First(&permissao)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &permissao, nil
+}
+
+// Create cadastra uma nova permissão
+func (r *PermissaoRepositoryImpl) Create(permissao *models.Permissao) error {
+	return r.db.Create(permissao).Error
+}
+
+// FindAllCargoPermissoes busca todos os vínculos entre cargos e permissões
+func (r *PermissaoRepositoryImpl) FindAllCargoPermissoes() ([]models.CargoPermissao, error) {
+	var vinculos []models.CargoPermissao
+	result := r.db.Order("cargo, permissao_codigo").Find(&vinculos)
+	return vinculos, result.Error
+}
+
+// FindByCargo busca as permissões concedidas a um cargo
+func (r *PermissaoRepositoryImpl) FindByCargo(cargo string) ([]models.CargoPermissao, error) {
+	var vinculos []models.CargoPermissao
+	result := r.db.Where("cargo = ?", cargo).Order("permissao_codigo").Find(&vinculos)
+	return vinculos, result.Error
+}
+
+// CreateCargoPermissao concede uma permissão a um cargo
+func (r *PermissaoRepositoryImpl) CreateCargoPermissao(cargoPermissao *models.CargoPermissao) error {
+	return r.db.Create(cargoPermissao).Error
+}
+
+// ReplaceCargo substitui todas as permissões de um cargo em uma única transação
+func (r *PermissaoRepositoryImpl) ReplaceCargo(cargo string, codigos []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cargo = ?", cargo).Delete(&models.CargoPermissao{}).Error; err != nil {
+			return err
+		}
+		for _, codigo := range codigos {
+			vinculo := models.CargoPermissao{Cargo: cargo, PermissaoCodigo: codigo}
+			if err := tx.Create(&vinculo).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"time"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RedefinicaoSenhaRepository define a interface para os pedidos de redefinição de senha
+type RedefinicaoSenhaRepository interface {
+	Create(redefinicao *models.RedefinicaoSenha) error
+	FindByHashForUpdate(hash string) (*models.RedefinicaoSenha, error)
+	Update(redefinicao *models.RedefinicaoSenha) error
+	ContarDesde(usuarioID uint, desde time.Time) (int64, error)
+	InvalidarPendentes(usuarioID uint) error
+	WithTx(tx *gorm.DB) RedefinicaoSenhaRepository
+}
+
+// RedefinicaoSenhaRepositoryImpl implementa a interface RedefinicaoSenhaRepository
+type RedefinicaoSenhaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRedefinicaoSenhaRepository cria uma nova instância de RedefinicaoSenhaRepository
+func NewRedefinicaoSenhaRepository(db *gorm.DB) RedefinicaoSenhaRepository {
+	return &RedefinicaoSenhaRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *RedefinicaoSenhaRepositoryImpl) WithTx(tx *gorm.DB) RedefinicaoSenhaRepository {
+	return &RedefinicaoSenhaRepositoryImpl{db: tx}
+}
+
+// Create registra um pedido de redefinição
+func (r *RedefinicaoSenhaRepositoryImpl) Create(redefinicao *models.RedefinicaoSenha) error {
+	return r.db.Create(redefinicao).Error
+}
+
+// FindByHashForUpdate busca o pedido pelo hash do token bloqueando a linha até o fim da transação,
+// para que o mesmo link não seja usado duas vezes em requisições simultâneas
+func (r *RedefinicaoSenhaRepositoryImpl) FindByHashForUpdate(hash string) (*models.RedefinicaoSenha, error) {
+	var redefinicao models.RedefinicaoSenha
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", hash).First(&redefinicao)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &redefinicao, nil
+}
+
+// Update atualiza um pedido de redefinição existente
+func (r *RedefinicaoSenhaRepositoryImpl) Update(redefinicao *models.RedefinicaoSenha) error {
+	return r.db.Save(redefinicao).Error
+}
+
+// ContarDesde conta os pedidos do usuário feitos a partir do momento informado
+func (r *RedefinicaoSenhaRepositoryImpl) ContarDesde(usuarioID uint, desde time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.RedefinicaoSenha{}).
+		Where("usuario_id = ? AND created_at >= ?", usuarioID, desde).Count(&total).Error
+	return total, err
+}
+
+// InvalidarPendentes marca como usados os links do usuário que ainda não foram usados
+func (r *RedefinicaoSenhaRepositoryImpl) InvalidarPendentes(usuarioID uint) error {
+	return r.db.Model(&models.RedefinicaoSenha{}).
+		Where("usuario_id = ? AND usado_em IS NULL", usuarioID).
+		Update("usado_em", time.Now()).Error
+}
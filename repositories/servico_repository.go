@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+)
+
+// ServicoRepository define a interface para operações de repositório do catálogo de serviços
+type ServicoRepository interface {
+	FindAll() ([]models.Servico, error)
+	FindByCategoria(categoria string) ([]models.Servico, error)
+	FindByID(id uint) (*models.Servico, error)
+	FindByNome(nome string) (*models.Servico, error)
+	Create(servico *models.Servico) error
+	Update(servico *models.Servico) error
+	Delete(id uint) error
+	WithTx(tx *gorm.DB) ServicoRepository
+}
+
+// ServicoRepositoryImpl implementa a interface ServicoRepository
+type ServicoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewServicoRepository cria uma nova instância de ServicoRepository
+func NewServicoRepository(db *gorm.DB) ServicoRepository {
+	return &ServicoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *ServicoRepositoryImpl) WithTx(tx *gorm.DB) ServicoRepository {
+	return &ServicoRepositoryImpl{db: tx}
+}
+
+// FindAll busca todos os serviços do catálogo
+func (r *ServicoRepositoryImpl) FindAll() ([]models.Servico, error) {
+	var servicos []models.Servico
+	result := r.db.Order("categoria, nome").Find(&servicos)
+	return servicos, result.Error
+}
+
+// FindByCategoria busca os serviços de uma categoria
+func (r *ServicoRepositoryImpl) FindByCategoria(categoria string) ([]models.Servico, error) {
+	var servicos []models.Servico
+	result := r.db.Where("categoria = ?", categoria).Order("nome").Find(&servicos)
+	return servicos, result.Error
+}
+
+// FindByID busca um serviço pelo ID
+func (r *ServicoRepositoryImpl) FindByID(id uint) (*models.Servico, error) {
+	var servico models.Servico
+	result := r.db.First(&servico, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &servico, nil
+}
+
+// FindByNome busca um serviço pelo nome, incluindo os excluídos (o nome é único)
+func (r *ServicoRepositoryImpl) FindByNome(nome string) (*models.Servico, error) {
+	var servico models.Servico
+	result := r.db.Unscoped().Where("nome = ?", nome).First(&servico)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &servico, nil
+}
+
+// Create cria um novo serviço no catálogo
+func (r *ServicoRepositoryImpl) Create(servico *models.Servico) error {
+	return r.db.Create(servico).Error
+}
+
+// Update atualiza um serviço existente
+func (r *ServicoRepositoryImpl) Update(servico *models.Servico) error {
+	return r.db.Save(servico).Error
+}
+
+// Delete remove um serviço pelo ID (soft delete); as linhas de OS mantêm a referência
+func (r *ServicoRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.Servico{}, id).Error
+}
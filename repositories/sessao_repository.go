@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"time"
+
+	"OficinaMecanica/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SessaoRepository define a interface para operações de repositório das sessões de login
+type SessaoRepository interface {
+	Create(sessao *models.Sessao) error
+	FindByChave(chave string) (*models.Sessao, error)
+	FindByIDForUpdate(id uint) (*models.Sessao, error)
+	FindAtivasPorUsuario(usuarioID uint) ([]models.Sessao, error)
+	Update(sessao *models.Sessao) error
+	RevogarPorUsuario(usuarioID uint, motivo string) error
+	CreateToken(token *models.TokenRenovacao) error
+	FindTokenByHashForUpdate(hash string) (*models.TokenRenovacao, error)
+	UpdateToken(token *models.TokenRenovacao) error
+	WithTx(tx *gorm.DB) SessaoRepository
+}
+
+// SessaoRepositoryImpl implementa a interface SessaoRepository
+type SessaoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSessaoRepository cria uma nova instância de SessaoRepository
+func NewSessaoRepository(db *gorm.DB) SessaoRepository {
+	return &SessaoRepositoryImpl{db: db}
+}
+
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *SessaoRepositoryImpl) WithTx(tx *gorm.DB) SessaoRepository {
+	return &SessaoRepositoryImpl{db: tx}
+}
+
+// Create registra uma nova sessão
+func (r *SessaoRepositoryImpl) Create(sessao *models.Sessao) error {
+	return r.db.Create(sessao).Error
+}
+
+// FindByChave busca a sessão pela chave gravada no token de acesso
+func (r *SessaoRepositoryImpl) FindByChave(chave string) (*models.Sessao, error) {
+	var sessao models.Sessao
+	result := r.db.Where("chave = ?", chave).First(&sessao)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sessao, nil
+}
+
+// FindByIDForUpdate busca a sessão bloqueando a linha até o fim da transação
+func (r *SessaoRepositoryImpl) FindByIDForUpdate(id uint) (*models.Sessao, error) {
+	var sessao models.Sessao
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sessao, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sessao, nil
+}
+
+// FindAtivasPorUsuario busca as sessões não encerradas e não expiradas do usuário, das mais recentes para as mais antigas
+func (r *SessaoRepositoryImpl) FindAtivasPorUsuario(usuarioID uint) ([]models.Sessao, error) {
+	var sessoes []models.Sessao
+	result := r.db.Where("usuario_id = ? AND revogada_em IS NULL AND expira_em > ?", usuarioID, time.Now()).
+		Order("created_at DESC").Find(&sessoes)
+	return sessoes, result.Error
+}
+
+// Update atualiza uma sessão existente
+func (r *SessaoRepositoryImpl) Update(sessao *models.Sessao) error {
+	return r.db.Save(sessao).Error
+}
+
+// RevogarPorUsuario encerra de uma vez todas as sessões abertas do usuário
+func (r *SessaoRepositoryImpl) RevogarPorUsuario(usuarioID uint, motivo string) error {
+	return r.db.Model(&models.Sessao{}).
+		Where("usuario_id = ? AND revogada_em IS NULL", usuarioID).
+		Updates(map[string]interface{}{"revogada_em": time.Now(), "motivo_revogacao": motivo}).Error
+}
+
+// CreateToken registra um token de renovação
+func (r *SessaoRepositoryImpl) CreateToken(token *models.TokenRenovacao) error {
+	return r.db.Create(token).Error
+}
+
+// FindTokenByHashForUpdate busca o token de renovação pelo hash bloqueando a linha até o fim da transação,
+// para que duas renovações simultâneas com o mesmo token não emitam dois sucessores
+func (r *SessaoRepositoryImpl) FindTokenByHashForUpdate(hash string) (*models.TokenRenovacao, error) {
+	var token models.TokenRenovacao
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", hash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// UpdateToken atualiza um token de renovação existente
+func (r *SessaoRepositoryImpl) UpdateToken(token *models.TokenRenovacao) error {
+	return r.db.Save(token).Error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+// UnitOfWork executa operações de vários repositórios em uma única transação.
+// Dentro da função, os repositórios devem ser obtidos com WithTx(tx) para participar da transação;
+// se a função retornar erro, todas as alterações são desfeitas.
+type UnitOfWork interface {
+	Executar(fn func(tx *gorm.DB) error) error
+}
+
+// UnitOfWorkImpl implementa a interface UnitOfWork usando transações do GORM
+type UnitOfWorkImpl struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork cria uma nova instância de UnitOfWork
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &UnitOfWorkImpl{db: db}
+}
+
+// Executar abre uma transação, executa a função e faz commit ou rollback conforme o resultado
+func (u *UnitOfWorkImpl) Executar(fn func(tx *gorm.DB) error) error {
+	return u.db.Transaction(fn)
+}
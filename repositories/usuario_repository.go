@@ -9,11 +9,13 @@ import (
 // UsuarioRepository define a interface para operações de repositório do usuário
 type UsuarioRepository interface {
 	FindAll() ([]models.Usuario, error)
+	FindPaginado(consulta Consulta) (*models.Pagina[models.Usuario], error)
 	FindByID(id uint) (*models.Usuario, error)
 	FindByEmail(email string) (*models.Usuario, error)
 	Create(usuario *models.Usuario) error
 	Update(usuario *models.Usuario) error
 	Delete(id uint) error
+	WithTx(tx *gorm.DB) UsuarioRepository
 }
 
 // UsuarioRepositoryImpl implementa a interface UsuarioRepository
@@ -26,6 +28,11 @@ func NewUsuarioRepository(db *gorm.DB) UsuarioRepository {
 	return &UsuarioRepositoryImpl{db: db}
 }
 
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *UsuarioRepositoryImpl) WithTx(tx *gorm.DB) UsuarioRepository {
+	return &UsuarioRepositoryImpl{db: tx}
+}
+
 // FindAll busca todos os usuários
 func (r *UsuarioRepositoryImpl) FindAll() ([]models.Usuario, error) {
 	var usuarios []models.Usuario
@@ -33,6 +40,30 @@ func (r *UsuarioRepositoryImpl) FindAll() ([]models.Usuario, error) {
 	return usuarios, result.Error
 }
 
+// camposConsultaUsuario define a ordenação e os filtros aceitos na listagem de usuários
+var camposConsultaUsuario = CamposConsulta{
+	Ordenacao: map[string]string{
+		"id":          "id",
+		"nome":        "nome",
+		"email":       "email",
+		"cargo":       "cargo",
+		"ultimoLogin": "ultimo_login",
+		"criadoEm":    "created_at",
+	},
+	Filtros: map[string]CampoFiltro{
+		"nome":  {Coluna: "nome", Operador: FiltroContem},
+		"email": {Coluna: "email", Operador: FiltroContem},
+		"cargo": {Coluna: "cargo", Operador: FiltroIgual},
+		"ativo": {Coluna: "ativo", Operador: FiltroBooleano},
+	},
+	OrdenacaoPadrao: "nome",
+}
+
+// FindPaginado busca uma página de usuários conforme a ordenação e os filtros da consulta
+func (r *UsuarioRepositoryImpl) FindPaginado(consulta Consulta) (*models.Pagina[models.Usuario], error) {
+	return paginar[models.Usuario](r.db, consulta, camposConsultaUsuario)
+}
+
 // FindByID busca um usuário pelo ID
 func (r *UsuarioRepositoryImpl) FindByID(id uint) (*models.Usuario, error) {
 	var usuario models.Usuario
@@ -8,12 +8,15 @@ import (
 
 type VeiculoRepository interface {
 	FindAll() ([]models.Veiculo, error)
+	FindPaginado(consulta Consulta) (*models.Pagina[models.Veiculo], error)
 	FindByID(id uint) (*models.Veiculo, error)
 	Create(veiculo *models.Veiculo) error
 	Update(veiculo *models.Veiculo) error
 	Delete(id uint) error
 	FindByPlaca(placa string) (*models.Veiculo, error)
+	FindByChassi(chassi string) (*models.Veiculo, error)
 	FindByClienteID(clienteID uint) ([]models.Veiculo, error)
+	WithTx(tx *gorm.DB) VeiculoRepository
 }
 
 type VeiculoRepositoryImpl struct {
@@ -24,12 +27,44 @@ func NewVeiculoRepository(db *gorm.DB) VeiculoRepository {
 	return &VeiculoRepositoryImpl{db: db}
 }
 
+// WithTx retorna uma cópia do repositório que executa as operações na transação informada
+func (r *VeiculoRepositoryImpl) WithTx(tx *gorm.DB) VeiculoRepository {
+	return &VeiculoRepositoryImpl{db: tx}
+}
+
 func (r *VeiculoRepositoryImpl) FindAll() ([]models.Veiculo, error) {
 	var veiculos []models.Veiculo
 	result := r.db.Find(&veiculos)
 	return veiculos, result.Error
 }
 
+// camposConsultaVeiculo define a ordenação e os filtros aceitos na listagem de veículos
+var camposConsultaVeiculo = CamposConsulta{
+	Ordenacao: map[string]string{
+		"id":        "id",
+		"placa":     "placa",
+		"marca":     "marca",
+		"modelo":    "modelo",
+		"anoModelo": "ano_modelo",
+		"kmAtual":   "km_atual",
+		"criadoEm":  "created_at",
+	},
+	Filtros: map[string]CampoFiltro{
+		"placa":     {Coluna: "placa", Operador: FiltroContem},
+		"marca":     {Coluna: "marca", Operador: FiltroContem},
+		"modelo":    {Coluna: "modelo", Operador: FiltroContem},
+		"anoModelo": {Coluna: "ano_modelo", Operador: FiltroIgual},
+		"chassi":    {Coluna: "chassi", Operador: FiltroIgual},
+		"clienteId": {Coluna: "cliente_id", Operador: FiltroIgual},
+	},
+	OrdenacaoPadrao: "placa",
+}
+
+// FindPaginado busca uma página de veículos conforme a ordenação e os filtros da consulta
+func (r *VeiculoRepositoryImpl) FindPaginado(consulta Consulta) (*models.Pagina[models.Veiculo], error) {
+	return paginar[models.Veiculo](r.db, consulta, camposConsultaVeiculo)
+}
+
 func (r *VeiculoRepositoryImpl) FindByID(id uint) (*models.Veiculo, error) {
 	var veiculo models.Veiculo
 	result := r.db.First(&veiculo, id)
@@ -60,6 +95,16 @@ func (r *VeiculoRepositoryImpl) FindByPlaca(placa string) (*models.Veiculo, erro
 	return &veiculo, nil
 }
 
+// FindByChassi busca um veículo pelo chassi (VIN) normalizado
+func (r *VeiculoRepositoryImpl) FindByChassi(chassi string) (*models.Veiculo, error) {
+	var veiculo models.Veiculo
+	result := r.db.Where("chassi = ?", chassi).First(&veiculo)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &veiculo, nil
+}
+
 func (r *VeiculoRepositoryImpl) FindByClienteID(clienteID uint) ([]models.Veiculo, error) {
 	var veiculos []models.Veiculo
 	result := r.db.Where("cliente_id = ?", clienteID).Find(&veiculos)
@@ -1,19 +1,28 @@
 package routes
 
 import (
+	"OficinaMecanica/configs"
 	"OficinaMecanica/controllers"
-	"OficinaMecanica/database"
 	"OficinaMecanica/middlewares"
+	"OficinaMecanica/models"
 	"OficinaMecanica/repositories"
 	"OficinaMecanica/services"
+	"OficinaMecanica/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine) {
-	// Obtendo conexão com banco de dados
-	db := getDBConnection()
+// SetupRoutes registra as rotas da API usando a conexão já aberta (e migrada) por main.
+// Com o SQLite em memória cada conexão nova seria um banco vazio, então as rotas não abrem a sua
+func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+	// Sem arquivo .env, a validade dos tokens e a política de senhas usam os valores padrão
+	config, _ := configs.LoadConfig()
+	models.CustoSenha = config.CustoBcrypt()
+	politicaSenha, err := utils.NovaPoliticaSenha(config)
+	if err != nil {
+		panic("Falha ao carregar a política de senhas: " + err.Error())
+	}
 
 	// Repositórios
 	usuarioRepo := repositories.NewUsuarioRepository(db)
@@ -21,21 +30,83 @@ func SetupRoutes(r *gin.Engine) {
 	veiculoRepo := repositories.NewVeiculoRepository(db)
 	estoqueRepo := repositories.NewEstoqueRepository(db)
 	ordemServicoRepo := repositories.NewOrdemServicoRepository(db)
+	permissaoRepo := repositories.NewPermissaoRepository(db)
+	funcionarioRepo := repositories.NewFuncionarioRepository(db)
+	movimentacaoEstoqueRepo := repositories.NewMovimentacaoEstoqueRepository(db)
+	pedidoCompraRepo := repositories.NewPedidoCompraRepository(db)
+	fornecedorRepo := repositories.NewFornecedorRepository(db)
+	orcamentoRepo := repositories.NewOrcamentoRepository(db)
+	servicoRepo := repositories.NewServicoRepository(db)
+	pagamentoRepo := repositories.NewPagamentoRepository(db)
+	caixaRepo := repositories.NewCaixaRepository(db)
+	boxRepo := repositories.NewBoxRepository(db)
+	feriadoRepo := repositories.NewFeriadoRepository(db)
+	agendamentoRepo := repositories.NewAgendamentoRepository(db)
+	manutencaoRepo := repositories.NewManutencaoRepository(db)
+	buscaRepo := repositories.NewBuscaRepository(db)
+	sessaoRepo := repositories.NewSessaoRepository(db)
+	acessoRepo := repositories.NewAcessoRepository(db)
+	redefinicaoSenhaRepo := repositories.NewRedefinicaoSenhaRepository(db)
+	historicoSenhaRepo := repositories.NewHistoricoSenhaRepository(db)
+	unitOfWork := repositories.NewUnitOfWork(db)
 
 	// Serviços
-	usuarioService := services.NewUsuarioService(usuarioRepo)
+	usuarioService := services.NewUsuarioService(usuarioRepo, sessaoRepo, historicoSenhaRepo, unitOfWork, politicaSenha)
+	sessaoService := services.NewSessaoService(sessaoRepo, usuarioRepo, unitOfWork, config.ValidadeTokenAcesso(), config.ValidadeSessao())
+	protecaoLoginService := services.NewProtecaoLoginService(acessoRepo, usuarioRepo, unitOfWork, config.LimiteFalhasConta(), config.LimiteFalhasIP(), config.DuracaoBloqueioLogin())
+	redefinicaoSenhaService := services.NewRedefinicaoSenhaService(redefinicaoSenhaRepo, usuarioRepo, sessaoRepo, acessoRepo, historicoSenhaRepo, unitOfWork, politicaSenha, utils.NovoMailer(config), config.AppURL, config.ValidadeRedefinicaoSenha())
 	clienteService := services.NewClienteService(clienteRepo)
-	veiculoService := services.NewVeiculoService(veiculoRepo)
-	estoqueService := services.NewEstoqueService(estoqueRepo)
-	ordemServicoService := services.NewOrdemServicoService(ordemServicoRepo, veiculoRepo, clienteRepo, estoqueRepo)
+	veiculoService := services.NewVeiculoService(veiculoRepo, clienteRepo)
+	movimentacaoEstoqueService := services.NewMovimentacaoEstoqueService(movimentacaoEstoqueRepo, estoqueRepo, unitOfWork)
+	estoqueService := services.NewEstoqueService(estoqueRepo, fornecedorRepo, movimentacaoEstoqueService, unitOfWork)
+	manutencaoService := services.NewManutencaoService(manutencaoRepo, veiculoRepo, clienteRepo, servicoRepo, unitOfWork)
+	permissaoService := services.NewPermissaoService(permissaoRepo, usuarioRepo)
+	ordemServicoService := services.NewOrdemServicoService(ordemServicoRepo, veiculoRepo, clienteRepo, estoqueRepo, funcionarioRepo, orcamentoRepo, servicoRepo, movimentacaoEstoqueService, manutencaoService, permissaoService, unitOfWork)
+	orcamentoService := services.NewOrcamentoService(orcamentoRepo, ordemServicoRepo, veiculoRepo, clienteRepo, estoqueRepo, servicoRepo, ordemServicoService, unitOfWork)
+	servicoService := services.NewServicoService(servicoRepo)
+	pagamentoService := services.NewPagamentoService(pagamentoRepo, ordemServicoRepo, caixaRepo, unitOfWork)
+	oficinaService := services.NewOficinaService()
+	documentoService := services.NewDocumentoService(ordemServicoRepo, oficinaService)
+	agendaService := services.NewAgendaService(boxRepo, feriadoRepo)
+	agendamentoService := services.NewAgendamentoService(agendamentoRepo, boxRepo, clienteRepo, veiculoRepo, funcionarioRepo, ordemServicoRepo, repositories.NewFeedAgendaRepository(db), agendaService, manutencaoService, unitOfWork)
+	pedidoCompraService := services.NewPedidoCompraService(pedidoCompraRepo, estoqueRepo, fornecedorRepo, movimentacaoEstoqueService, unitOfWork)
+	fornecedorService := services.NewFornecedorService(fornecedorRepo, estoqueRepo)
+	caixaService := services.NewCaixaService(caixaRepo, permissaoService, unitOfWork)
+	buscaService := services.NewBuscaService(buscaRepo, permissaoService)
+	funcionarioService := services.NewFuncionarioService(funcionarioRepo, usuarioRepo)
+
+	// Cadastrar permissões novas do catálogo com os valores padrão de cada cargo
+	if err := permissaoService.SincronizarCatalogo(); err != nil {
+		panic("Falha ao sincronizar permissões: " + err.Error())
+	}
 
 	// Controllers
-	authController := controllers.NewAuthController(usuarioService)
-	usuarioController := controllers.NewUsuarioController(usuarioService)
+	authController := controllers.NewAuthController(usuarioService, sessaoService, protecaoLoginService)
+	redefinicaoSenhaController := controllers.NewRedefinicaoSenhaController(redefinicaoSenhaService)
+	usuarioController := controllers.NewUsuarioController(usuarioService, permissaoService)
 	clienteController := controllers.NewClienteController(clienteService)
 	veiculoController := controllers.NewVeiculoController(veiculoService)
-	estoqueController := controllers.NewEstoqueController(estoqueService)
+	manutencaoController := controllers.NewManutencaoController(manutencaoService)
+	buscaController := controllers.NewBuscaController(buscaService)
+	estoqueController := controllers.NewEstoqueController(estoqueService, movimentacaoEstoqueService)
 	ordemServicoController := controllers.NewOrdemServicoController(ordemServicoService)
+	permissaoController := controllers.NewPermissaoController(permissaoService, usuarioService)
+	funcionarioController := controllers.NewFuncionarioController(funcionarioService)
+	pedidoCompraController := controllers.NewPedidoCompraController(pedidoCompraService)
+	fornecedorController := controllers.NewFornecedorController(fornecedorService)
+	orcamentoController := controllers.NewOrcamentoController(orcamentoService)
+	servicoController := controllers.NewServicoController(servicoService)
+	pagamentoController := controllers.NewPagamentoController(pagamentoService)
+	caixaController := controllers.NewCaixaController(caixaService)
+	agendaController := controllers.NewAgendaController(agendaService)
+	agendamentoController := controllers.NewAgendamentoController(agendamentoService)
+	oficinaController := controllers.NewOficinaController(oficinaService)
+	documentoController := controllers.NewDocumentoController(documentoService)
+
+	// perm cria o middleware que exige uma permissão específica do cargo do usuário
+	perm := func(permissao string) gin.HandlerFunc {
+		return middlewares.RequirePermission(permissaoService, permissao)
+	}
 
 	// Rotas públicas
 	public := r.Group("/api")
@@ -43,95 +114,260 @@ func SetupRoutes(r *gin.Engine) {
 		// Rotas de autenticação
 		public.POST("/login", authController.Login)
 		public.POST("/register", authController.Register)
-		public.GET("/validate-token", middlewares.AuthMiddleware(), func(c *gin.Context) {
+		public.POST("/token/renovar", authController.Renovar)
+		public.POST("/senha/esqueci", redefinicaoSenhaController.Solicitar)
+		public.POST("/senha/redefinir", redefinicaoSenhaController.Redefinir)
+		public.GET("/validate-token", middlewares.AuthMiddleware(sessaoService, usuarioService), func(c *gin.Context) {
 			c.JSON(200, gin.H{"valid": true})
 		})
+
+		// Feed iCalendar do mecânico, protegido pelo token do link (calendários não enviam Authorization)
+		public.GET("/agenda/feed/:id/agenda.ics", agendamentoController.FeedICS)
 	}
 
 	// Rotas protegidas por autenticação
 	authorized := r.Group("/api")
-	authorized.Use(middlewares.AuthMiddleware())
+	authorized.Use(middlewares.AuthMiddleware(sessaoService, usuarioService))
 	{
-		// Rotas de usuários
+		// Sessões do usuário autenticado
+		authorized.POST("/logout", authController.Logout)
+		authorized.POST("/logout/todas", authController.LogoutTodas)
+		authorized.GET("/sessoes", authController.MinhasSessoes)
+
+		// Busca textual do balcão; cada grupo do resultado respeita a permissão de leitura correspondente
+		authorized.GET("/busca", buscaController.Buscar)
+
+		// Rotas de usuários; as alterações na conta de um admin ficam restritas a outros admins (gestao)
 		usuarios := authorized.Group("/usuarios")
 		{
-			usuarios.GET("/", usuarioController.BuscarTodos)
-			usuarios.GET("/:id", usuarioController.BuscarPorID)
-			usuarios.POST("/", usuarioController.Criar)
-			usuarios.PUT("/:id", usuarioController.Atualizar)
-			usuarios.DELETE("/:id", usuarioController.Deletar)
-			usuarios.POST("/:id/avatar", usuarioController.UploadAvatar) // Rota para upload de avatar
+			gestao := middlewares.RequireGestaoUsuario(permissaoService, "id")
+			usuarios.GET("/", perm(models.PermUsuariosLer), usuarioController.BuscarTodos)
+			usuarios.GET("/:id", perm(models.PermUsuariosLer), usuarioController.BuscarPorID)
+			usuarios.POST("/", perm(models.PermUsuariosEscrever), usuarioController.Criar)
+			usuarios.PUT("/:id", perm(models.PermUsuariosEscrever), gestao, usuarioController.Atualizar)
+			usuarios.DELETE("/:id", perm(models.PermUsuariosDeletar), gestao, usuarioController.Deletar)
+			usuarios.POST("/:id/ativar", perm(models.PermUsuariosEscrever), gestao, usuarioController.Ativar)
+			usuarios.POST("/:id/desativar", perm(models.PermUsuariosEscrever), gestao, usuarioController.Desativar)
+			usuarios.POST("/:id/encerrar-sessoes", perm(models.PermUsuariosEscrever), gestao, authController.EncerrarSessoesDoUsuario)
+			usuarios.POST("/:id/desbloquear", perm(models.PermUsuariosEscrever), gestao, authController.Desbloquear)
+			usuarios.GET("/:id/acessos", perm(models.PermUsuariosLer), authController.Acessos)
+			usuarios.PUT("/:id/senha", middlewares.RequirePermissionOrSelf(permissaoService, models.PermUsuariosEscrever, "id"), gestao, usuarioController.AlterarSenha)   // Exige a senha atual
+			usuarios.POST("/:id/avatar", middlewares.RequirePermissionOrSelf(permissaoService, models.PermUsuariosEscrever, "id"), gestao, usuarioController.UploadAvatar) // Rota para upload de avatar
+		}
+
+		// Rotas de funcionários
+		funcionarios := authorized.Group("/funcionarios")
+		{
+			funcionarios.GET("", perm(models.PermFuncionariosLer), funcionarioController.BuscarTodos)
+			funcionarios.GET("/excluidos", perm(models.PermFuncionariosDeletar), funcionarioController.BuscarExcluidos)
+			funcionarios.GET("/cargo/:cargo", perm(models.PermFuncionariosLer), funcionarioController.BuscarPorCargo)
+			funcionarios.GET("/:id", perm(models.PermFuncionariosLer), funcionarioController.BuscarPorID)
+			funcionarios.POST("", perm(models.PermFuncionariosEscrever), funcionarioController.Criar)
+			funcionarios.PUT("/:id", perm(models.PermFuncionariosEscrever), funcionarioController.Atualizar)
+			funcionarios.DELETE("/:id", perm(models.PermFuncionariosDeletar), funcionarioController.Deletar)
+			funcionarios.POST("/:id/restaurar", perm(models.PermFuncionariosDeletar), funcionarioController.Restaurar)
+			funcionarios.PUT("/:id/usuario", perm(models.PermFuncionariosEscrever), funcionarioController.VincularUsuario)
+			funcionarios.DELETE("/:id/usuario", perm(models.PermFuncionariosEscrever), funcionarioController.DesvincularUsuario)
+			funcionarios.GET("/:id/ordens-servico", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarPorFuncionario)
+			funcionarios.POST("/:id/agenda/link", perm(models.PermAgendaLer), agendamentoController.LinkFeed) // Gera um novo link, invalidando o anterior
+			funcionarios.DELETE("/:id/agenda/link", perm(models.PermAgendaLer), agendamentoController.RevogarLinkFeed)
 		}
 
 		// Rotas de clientes
 		clientes := authorized.Group("/clientes")
 		{
-			clientes.GET("/", clienteController.BuscarTodos)
-			clientes.GET("/:id", clienteController.BuscarPorID)
-			clientes.POST("/", clienteController.Criar)
-			clientes.PUT("/:id", clienteController.Atualizar)
-			clientes.DELETE("/:id", clienteController.Deletar)
+			clientes.GET("/", perm(models.PermClientesLer), clienteController.BuscarTodos)
+			clientes.GET("/:id", perm(models.PermClientesLer), clienteController.BuscarPorID)
+			clientes.GET("/documento/:doc", perm(models.PermClientesLer), clienteController.BuscarPorDocumento)
+			clientes.POST("/", perm(models.PermClientesEscrever), clienteController.Criar)
+			clientes.PUT("/:id", perm(models.PermClientesEscrever), clienteController.Atualizar)
+			clientes.DELETE("/:id", perm(models.PermClientesDeletar), clienteController.Deletar)
 		}
 
 		// Rotas de veículos
 		veiculos := authorized.Group("/veiculos")
 		{
-			veiculos.GET("/", veiculoController.BuscarTodos)
-			veiculos.GET("/:id", veiculoController.BuscarPorID)
-			veiculos.POST("/", veiculoController.Criar)
-			veiculos.PUT("/:id", veiculoController.Atualizar)
-			veiculos.DELETE("/:id", veiculoController.Deletar)
-			veiculos.GET("/cliente/:clienteId", veiculoController.BuscarPorCliente)
+			veiculos.GET("/", perm(models.PermVeiculosLer), veiculoController.BuscarTodos)
+			veiculos.GET("/:id", perm(models.PermVeiculosLer), veiculoController.BuscarPorID)
+			veiculos.POST("/", perm(models.PermVeiculosEscrever), veiculoController.Criar)
+			veiculos.PUT("/:id", perm(models.PermVeiculosEscrever), veiculoController.Atualizar)
+			veiculos.DELETE("/:id", perm(models.PermVeiculosDeletar), veiculoController.Deletar)
+			veiculos.GET("/cliente/:clienteId", perm(models.PermVeiculosLer), veiculoController.BuscarPorCliente)
+
+			// Quilometragem e manutenção preventiva
+			veiculos.GET("/manutencoes-pendentes", perm(models.PermVeiculosLer), manutencaoController.BuscarPendentes)
+			veiculos.GET("/:id/leituras-km", perm(models.PermVeiculosLer), manutencaoController.BuscarLeituras)
+			veiculos.POST("/:id/leituras-km", perm(models.PermVeiculosEscrever), manutencaoController.RegistrarLeitura)
+			veiculos.GET("/:id/planos-manutencao", perm(models.PermVeiculosLer), manutencaoController.BuscarPlanos)
+			veiculos.POST("/:id/planos-manutencao", perm(models.PermVeiculosEscrever), manutencaoController.CriarPlano)
+			veiculos.PUT("/:id/planos-manutencao/:planoId", perm(models.PermVeiculosEscrever), manutencaoController.AtualizarPlano)
+			veiculos.DELETE("/:id/planos-manutencao/:planoId", perm(models.PermVeiculosEscrever), manutencaoController.DeletarPlano)
+			veiculos.POST("/:id/planos-manutencao/:planoId/realizar", perm(models.PermVeiculosEscrever), manutencaoController.RegistrarRealizacao)
 		}
 
 		// Rotas de estoque
 		estoque := authorized.Group("/estoque")
 		{
-			estoque.GET("", estoqueController.BuscarTodos)
-			estoque.GET("/:id", estoqueController.BuscarPorID)
-			estoque.POST("", estoqueController.Criar)
-			estoque.PUT("/:id", estoqueController.Atualizar)
-			estoque.DELETE("/:id", estoqueController.Deletar)
-			estoque.GET("/categoria/:categoria", estoqueController.BuscarPorCategoria)
-			estoque.GET("/baixo-estoque", estoqueController.BuscarBaixoEstoque)
-			estoque.GET("/controle-estoque", estoqueController.BuscarControleEstoque)
-			estoque.POST("/controle-estoque", estoqueController.SalvarControleEstoque)
+			estoque.GET("", perm(models.PermEstoqueLer), estoqueController.BuscarTodos)
+			estoque.GET("/:id", perm(models.PermEstoqueLer), estoqueController.BuscarPorID)
+			estoque.POST("", perm(models.PermEstoqueEscrever), estoqueController.Criar)
+			estoque.PUT("/:id", perm(models.PermEstoqueEscrever), estoqueController.Atualizar)
+			estoque.DELETE("/:id", perm(models.PermEstoqueDeletar), estoqueController.Deletar)
+			estoque.PATCH("/:id/quantidade", perm(models.PermEstoqueMovimentar), estoqueController.AtualizarQuantidade)
+			estoque.GET("/:id/movimentacoes", perm(models.PermEstoqueLer), estoqueController.BuscarMovimentacoes)
+			estoque.POST("/:id/movimentacoes", perm(models.PermEstoqueMovimentar), estoqueController.RegistrarMovimentacao)
+			estoque.GET("/:id/fornecedores", perm(models.PermFornecedoresLer), fornecedorController.BuscarFornecedoresDoItem)
+			estoque.GET("/categoria/:categoria", perm(models.PermEstoqueLer), estoqueController.BuscarPorCategoria)
+			estoque.GET("/baixo-estoque", perm(models.PermEstoqueLer), estoqueController.BuscarBaixoEstoque)
+			estoque.GET("/controle-estoque", perm(models.PermEstoqueLer), estoqueController.BuscarControleEstoque)
+			estoque.POST("/controle-estoque", perm(models.PermEstoqueConfigurar), estoqueController.SalvarControleEstoque)
 		}
 
 		// Rotas de ordens de serviço
 		os := authorized.Group("/ordens-servico")
 		{
-			os.GET("/", ordemServicoController.BuscarTodas)
-			os.GET("/:id", ordemServicoController.BuscarPorID)
-			os.GET("/numero/:numero", ordemServicoController.BuscarPorNumero)
-			os.GET("/cliente/:clienteId", ordemServicoController.BuscarPorCliente)
-			os.GET("/veiculo/:veiculoId", ordemServicoController.BuscarPorVeiculo)
-			os.GET("/status/:status", ordemServicoController.BuscarPorStatus)
-			os.POST("/", ordemServicoController.Criar)
-			os.PUT("/:id", ordemServicoController.Atualizar)
-			os.PATCH("/:id/status", ordemServicoController.AtualizarStatus)
-			os.DELETE("/:id", ordemServicoController.Deletar)
+			os.GET("/", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarTodas)
+			os.GET("/:id", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarPorID)
+			os.GET("/numero/:numero", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarPorNumero)
+			os.GET("/cliente/:clienteId", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarPorCliente)
+			os.GET("/veiculo/:veiculoId", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarPorVeiculo)
+			os.GET("/status/:status", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarPorStatus)
+			os.GET("/:id/pdf", perm(models.PermOrdensServicoLer), documentoController.GerarPDFOrdemServico)
+			os.POST("/", perm(models.PermOrdensServicoEscrever), ordemServicoController.Criar)
+			os.PUT("/:id", perm(models.PermOrdensServicoEscrever), ordemServicoController.Atualizar)
+			os.PATCH("/:id/status", perm(models.PermOrdensServicoEscrever), ordemServicoController.AtualizarStatus)
+			os.PUT("/:id/funcionario", perm(models.PermOrdensServicoEscrever), ordemServicoController.AtribuirFuncionario)
+			os.DELETE("/:id", perm(models.PermOrdensServicoDeletar), ordemServicoController.Deletar)
 
 			// Rotas para itens da OS
-			os.GET("/:id/itens", ordemServicoController.BuscarItens)
-			os.POST("/:id/itens", ordemServicoController.AdicionarItem)
-			os.PUT("/:id/itens/:itemId", ordemServicoController.AtualizarItem)
-			os.DELETE("/:id/itens/:itemId", ordemServicoController.RemoverItem)
+			os.GET("/:id/itens", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarItens)
+			os.POST("/:id/itens", perm(models.PermOrdensServicoEscrever), ordemServicoController.AdicionarItem)
+			os.PUT("/:id/itens/:itemId", perm(models.PermOrdensServicoEscrever), ordemServicoController.AtualizarItem)
+			os.DELETE("/:id/itens/:itemId", perm(models.PermOrdensServicoEscrever), ordemServicoController.RemoverItem)
+
+			// Rotas para a mão de obra da OS
+			os.GET("/:id/servicos", perm(models.PermOrdensServicoLer), ordemServicoController.BuscarServicos)
+			os.POST("/:id/servicos", perm(models.PermOrdensServicoEscrever), ordemServicoController.AdicionarServico)
+			os.PUT("/:id/servicos/:linhaId", perm(models.PermOrdensServicoEscrever), ordemServicoController.AtualizarServico)
+			os.DELETE("/:id/servicos/:linhaId", perm(models.PermOrdensServicoEscrever), ordemServicoController.RemoverServico)
+
+			// Rotas para os pagamentos da OS
+			os.GET("/:id/pagamentos", perm(models.PermFinanceiroLer), pagamentoController.BuscarPorOS)
+			os.POST("/:id/pagamentos", perm(models.PermFinanceiroReceber), pagamentoController.Registrar)
+			os.DELETE("/:id/pagamentos/:pagamentoId", perm(models.PermFinanceiroEstornar), pagamentoController.Estornar)
 
 			// Ações específicas
-			os.POST("/:id/concluir", ordemServicoController.ConcluirOS)
-			os.POST("/:id/cancelar", ordemServicoController.CancelarOS)
+			os.POST("/:id/concluir", perm(models.PermOrdensServicoEscrever), ordemServicoController.ConcluirOS)
+			os.POST("/:id/cancelar", perm(models.PermOrdensServicoEscrever), ordemServicoController.CancelarOS)
+			os.POST("/:id/entregar", perm(models.PermOrdensServicoEscrever), ordemServicoController.Entregar)
+			os.POST("/:id/entregar-sem-pagamento", perm(models.PermFinanceiroLiberarEntrega), ordemServicoController.EntregarSemPagamento)
 		}
-	}
-}
 
-// getDBConnection retorna uma conexão com o banco de dados usando GORM
-func getDBConnection() *gorm.DB {
-	db, err := database.ConnectDB()
-	if err != nil {
-		// Em produção, você deve lidar com este erro de forma mais robusta
-		// Considere usar um logger estruturado e talvez reiniciar o aplicativo
-		panic("Falha ao conectar ao banco de dados: " + err.Error())
+		// Rotas do catálogo de serviços
+		servicos := authorized.Group("/servicos")
+		{
+			servicos.GET("", perm(models.PermServicosLer), servicoController.BuscarTodos)
+			servicos.GET("/:id", perm(models.PermServicosLer), servicoController.BuscarPorID)
+			servicos.POST("", perm(models.PermServicosEscrever), servicoController.Criar)
+			servicos.PUT("/:id", perm(models.PermServicosEscrever), servicoController.Atualizar)
+			servicos.DELETE("/:id", perm(models.PermServicosDeletar), servicoController.Deletar)
+		}
+
+		// Rotas de orçamentos
+		orcamentos := authorized.Group("/orcamentos")
+		{
+			orcamentos.GET("", perm(models.PermOrcamentosLer), orcamentoController.BuscarTodos)
+			orcamentos.GET("/:id", perm(models.PermOrcamentosLer), orcamentoController.BuscarPorID)
+			orcamentos.GET("/:id/versoes", perm(models.PermOrcamentosLer), orcamentoController.BuscarVersoes)
+			orcamentos.POST("", perm(models.PermOrcamentosEscrever), orcamentoController.Criar)
+			orcamentos.POST("/:id/revisar", perm(models.PermOrcamentosEscrever), orcamentoController.Revisar)
+			orcamentos.POST("/:id/aprovar", perm(models.PermOrcamentosAprovar), orcamentoController.Aprovar)
+			orcamentos.POST("/:id/rejeitar", perm(models.PermOrcamentosAprovar), orcamentoController.Rejeitar)
+			orcamentos.POST("/:id/converter", perm(models.PermOrcamentosAprovar), orcamentoController.Converter)
+		}
+
+		// Rotas de fornecedores
+		fornecedores := authorized.Group("/fornecedores")
+		{
+			fornecedores.GET("", perm(models.PermFornecedoresLer), fornecedorController.BuscarTodos)
+			fornecedores.GET("/relatorio-itens", perm(models.PermFornecedoresLer), fornecedorController.Relatorio)
+			fornecedores.GET("/:id", perm(models.PermFornecedoresLer), fornecedorController.BuscarPorID)
+			fornecedores.POST("", perm(models.PermFornecedoresEscrever), fornecedorController.Criar)
+			fornecedores.PUT("/:id", perm(models.PermFornecedoresEscrever), fornecedorController.Atualizar)
+			fornecedores.DELETE("/:id", perm(models.PermFornecedoresDeletar), fornecedorController.Deletar)
+			fornecedores.GET("/:id/itens", perm(models.PermFornecedoresLer), fornecedorController.BuscarItens)
+			fornecedores.PUT("/:id/itens/:estoqueId", perm(models.PermFornecedoresEscrever), fornecedorController.SalvarItem)
+			fornecedores.DELETE("/:id/itens/:estoqueId", perm(models.PermFornecedoresEscrever), fornecedorController.RemoverItem)
+		}
+
+		// Rotas de pedidos de compra
+		compras := authorized.Group("/pedidos-compra")
+		{
+			compras.GET("", perm(models.PermComprasLer), pedidoCompraController.BuscarTodos)
+			compras.GET("/:id", perm(models.PermComprasLer), pedidoCompraController.BuscarPorID)
+			compras.POST("", perm(models.PermComprasEscrever), pedidoCompraController.Criar)
+			compras.POST("/gerar-baixo-estoque", perm(models.PermComprasEscrever), pedidoCompraController.GerarDeBaixoEstoque)
+			compras.PUT("/:id", perm(models.PermComprasEscrever), pedidoCompraController.Atualizar)
+			compras.DELETE("/:id", perm(models.PermComprasEscrever), pedidoCompraController.Deletar)
+			compras.POST("/:id/enviar", perm(models.PermComprasEscrever), pedidoCompraController.Enviar)
+			compras.POST("/:id/receber", perm(models.PermComprasReceber), pedidoCompraController.Receber)
+			compras.POST("/:id/cancelar", perm(models.PermComprasEscrever), pedidoCompraController.Cancelar)
+		}
+
+		// Rotas da configuração da agenda
+		agenda := authorized.Group("/agenda")
+		{
+			agenda.GET("/boxes", perm(models.PermAgendaLer), agendaController.BuscarBoxes)
+			agenda.POST("/boxes", perm(models.PermAgendaConfigurar), agendaController.CriarBox)
+			agenda.PUT("/boxes/:id", perm(models.PermAgendaConfigurar), agendaController.AtualizarBox)
+			agenda.DELETE("/boxes/:id", perm(models.PermAgendaConfigurar), agendaController.DeletarBox)
+			agenda.GET("/feriados", perm(models.PermAgendaLer), agendaController.BuscarFeriados)
+			agenda.POST("/feriados", perm(models.PermAgendaConfigurar), agendaController.CriarFeriado)
+			agenda.DELETE("/feriados/:id", perm(models.PermAgendaConfigurar), agendaController.DeletarFeriado)
+			agenda.GET("/expediente", perm(models.PermAgendaLer), agendaController.BuscarExpediente)
+			agenda.PUT("/expediente", perm(models.PermAgendaConfigurar), agendaController.SalvarExpediente)
+			agenda.GET("/horarios-disponiveis", perm(models.PermAgendaLer), agendamentoController.HorariosDisponiveis)
+		}
+
+		// Rotas de agendamentos
+		agendamentos := authorized.Group("/agendamentos")
+		{
+			agendamentos.GET("", perm(models.PermAgendaLer), agendamentoController.BuscarTodos)
+			agendamentos.GET("/:id", perm(models.PermAgendaLer), agendamentoController.BuscarPorID)
+			agendamentos.POST("", perm(models.PermAgendaEscrever), agendamentoController.Criar)
+			agendamentos.PUT("/:id", perm(models.PermAgendaEscrever), agendamentoController.Reagendar)
+			agendamentos.PATCH("/:id/status", perm(models.PermAgendaEscrever), agendamentoController.AtualizarStatus)
+			agendamentos.POST("/:id/checkin", perm(models.PermOrdensServicoEscrever), agendamentoController.CheckIn)
+		}
+
+		// Rotas do caixa da recepção
+		caixas := authorized.Group("/caixas")
+		{
+			caixas.GET("", perm(models.PermCaixaGerenciar), caixaController.BuscarTodos)
+			caixas.GET("/atual", perm(models.PermCaixaOperar), caixaController.BuscarAtual)
+			caixas.GET("/:id", perm(models.PermCaixaOperar), caixaController.BuscarRelatorio)
+			caixas.POST("/abrir", perm(models.PermCaixaOperar), caixaController.Abrir)
+			caixas.POST("/:id/sangria", perm(models.PermCaixaOperar), caixaController.RegistrarSangria)
+			caixas.POST("/:id/suprimento", perm(models.PermCaixaOperar), caixaController.RegistrarSuprimento)
+			caixas.POST("/:id/fechar", perm(models.PermCaixaOperar), caixaController.Fechar)
+		}
+
+		// Rotas dos dados da oficina (cabeçalho dos documentos impressos)
+		oficina := authorized.Group("/oficina")
+		{
+			oficina.GET("", oficinaController.BuscarDados)
+			oficina.PUT("", perm(models.PermOficinaConfigurar), oficinaController.SalvarDados)
+			oficina.POST("/logo", perm(models.PermOficinaConfigurar), oficinaController.UploadLogo)
+		}
+
+		// Rotas de permissões (administração da política de acesso por cargo)
+		permissoes := authorized.Group("/permissoes")
+		{
+			permissoes.GET("/minhas", permissaoController.MinhasPermissoes)
+			permissoes.GET("", perm(models.PermPermissoesGerenciar), permissaoController.BuscarCatalogo)
+			permissoes.GET("/cargos", perm(models.PermPermissoesGerenciar), permissaoController.BuscarMapeamentos)
+			permissoes.GET("/cargos/:cargo", perm(models.PermPermissoesGerenciar), permissaoController.BuscarPorCargo)
+			permissoes.PUT("/cargos/:cargo", perm(models.PermPermissoesGerenciar), permissaoController.AtualizarCargo)
+		}
 	}
-	return db
 }
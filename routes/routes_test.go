@@ -0,0 +1,202 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"OficinaMecanica/configs"
+	"OficinaMecanica/database"
+	"OficinaMecanica/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// novaAPI sobe a API inteira sobre um arquivo SQLite temporário, como no notebook de quem desenvolve:
+// o .env escolhe o driver, main conecta e migra, e as rotas usam a mesma conexão
+func novaAPI(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	env := fmt.Sprintf("DB_DRIVER=sqlite\nDB_NAME=%s\nJWT_SECRET=segredo-de-teste\nBCRYPT_CUSTO=4\nMAIL_OUTBOX_DIR=%s\n",
+		filepath.Join(dir, "oficina.db"), filepath.Join(dir, "outbox"))
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600); err != nil {
+		t.Fatalf("erro ao gravar o .env: %v", err)
+	}
+
+	// LoadConfig lê o .env do diretório atual
+	anterior, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("erro ao ler o diretório atual: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("erro ao trocar de diretório: %v", err)
+	}
+	viper.Reset()
+	t.Cleanup(func() {
+		os.Chdir(anterior)
+		viper.Reset()
+	})
+
+	config, err := configs.LoadConfig()
+	if err != nil || config.DBDriver != database.DriverSQLite {
+		t.Fatalf("configuração = %+v (err=%v), esperado o driver sqlite", config, err)
+	}
+	db, err := database.ConnectDB()
+	if err != nil {
+		t.Fatalf("erro ao conectar: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := database.SetupMigrations(db); err != nil {
+		t.Fatalf("erro ao migrar: %v", err)
+	}
+
+	r := gin.New()
+	SetupRoutes(r, db)
+	return r, db
+}
+
+// requisitar envia a requisição à API com o corpo em JSON e o token, quando informados
+func requisitar(t *testing.T, r *gin.Engine, metodo, caminho, token string, corpo interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var leitor io.Reader
+	if corpo != nil {
+		dados, err := json.Marshal(corpo)
+		if err != nil {
+			t.Fatalf("erro ao montar o corpo: %v", err)
+		}
+		leitor = bytes.NewReader(dados)
+	}
+	req := httptest.NewRequest(metodo, caminho, leitor)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// esperar confere o status da resposta e decodifica o corpo no destino, quando informado
+func esperar(t *testing.T, w *httptest.ResponseRecorder, status int, destino interface{}) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, esperado %d: %s", w.Code, status, w.Body.String())
+	}
+	if destino != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), destino); err != nil {
+			t.Fatalf("erro ao ler a resposta: %v", err)
+		}
+	}
+}
+
+func TestAPIDePontaAPontaNoSQLite(t *testing.T) {
+	r, db := novaAPI(t)
+
+	esperar(t, requisitar(t, r, http.MethodGet, "/api/clientes/", "", nil), http.StatusUnauthorized, nil)
+
+	// O cadastro cria um usuário sem permissões; o gerente é promovido direto no banco
+	credenciais := map[string]string{"nome": "Ana", "email": "ana@oficina.com", "senha": "Motor2024x"}
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/register", "", credenciais), http.StatusCreated, nil)
+	db.Model(&models.Usuario{}).Where("email = ?", credenciais["email"]).UpdateColumn("cargo", models.CargoGerente)
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/login", "", credenciais), http.StatusOK, &login)
+	token := login.Token
+
+	var cliente models.Cliente
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/clientes/", token,
+		map[string]interface{}{"nome": "João Prata", "tipoPessoa": "fisica"}), http.StatusCreated, &cliente)
+
+	var veiculo models.Veiculo
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/veiculos/", token, map[string]interface{}{
+		"placa": "GOL1A23", "marca": "Volkswagen", "modelo": "Gol", "cor": "Prata", "clienteId": cliente.ID, "ordemServico": "-",
+	}), http.StatusCreated, &veiculo)
+
+	var os models.OrdemServico
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/ordens-servico/", token, map[string]interface{}{
+		"clienteId": cliente.ID, "veiculoId": veiculo.ID, "descricao": "Barulho na suspensão", "kmEntrada": 42000,
+	}), http.StatusCreated, &os)
+	if os.NumeroOS == "" {
+		t.Error("a OS deveria receber um número")
+	}
+
+	// O km de entrada vira uma leitura do veículo
+	var leituras []models.LeituraKm
+	esperar(t, requisitar(t, r, http.MethodGet, fmt.Sprintf("/api/veiculos/%d/leituras-km", veiculo.ID), token, nil), http.StatusOK, &leituras)
+	if len(leituras) != 1 || leituras[0].Km != 42000 {
+		t.Errorf("leituras = %+v, esperado a de 42000 km vinda da OS", leituras)
+	}
+
+	// A busca do balcão usa o LIKE fora do MySQL
+	var busca models.ResultadoBusca
+	esperar(t, requisitar(t, r, http.MethodGet, "/api/busca?q="+url.QueryEscape("gol prata joão"), token, nil), http.StatusOK, &busca)
+	if len(busca.OrdensServico) != 1 || busca.OrdensServico[0].OrdemServico.ID != os.ID || len(busca.Veiculos) != 1 {
+		t.Errorf("a busca trouxe %d OS e %d veículos, esperado a OS e o Gol", len(busca.OrdensServico), len(busca.Veiculos))
+	}
+
+	var mecanico models.Funcionario
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/funcionarios", token, map[string]interface{}{
+		"nome": "Carlos", "telefone": "11987654321", "cpf": "529.982.247-25", "cargo": "Mecânico",
+	}), http.StatusCreated, &mecanico)
+	esperar(t, requisitar(t, r, http.MethodPut, fmt.Sprintf("/api/ordens-servico/%d/funcionario", os.ID), token,
+		map[string]uint{"funcionarioId": mecanico.ID}), http.StatusOK, nil)
+	var atribuida models.OrdemServico
+	esperar(t, requisitar(t, r, http.MethodGet, fmt.Sprintf("/api/ordens-servico/%d", os.ID), token, nil), http.StatusOK, &atribuida)
+	if atribuida.FuncionarioID == nil || *atribuida.FuncionarioID != mecanico.ID || atribuida.Veiculo.Placa != veiculo.Placa {
+		t.Errorf("OS com mecânico %v e placa %q, esperado o mecânico %d e o Gol", atribuida.FuncionarioID, atribuida.Veiculo.Placa, mecanico.ID)
+	}
+
+	pdf := requisitar(t, r, http.MethodGet, fmt.Sprintf("/api/ordens-servico/%d/pdf", os.ID), token, nil)
+	esperar(t, pdf, http.StatusOK, nil)
+	if !strings.HasPrefix(pdf.Body.String(), "%PDF") {
+		t.Error("o documento da OS deveria ser um PDF")
+	}
+
+	// Depois do logout o token deixa de valer
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/logout", token, nil), http.StatusNoContent, nil)
+	esperar(t, requisitar(t, r, http.MethodGet, "/api/clientes/", token, nil), http.StatusUnauthorized, nil)
+}
+
+func TestPermissoesNaAPIComSQLite(t *testing.T) {
+	r, db := novaAPI(t)
+
+	credenciais := map[string]string{"nome": "Beto", "email": "beto@oficina.com", "senha": "Motor2024x"}
+	var cadastro struct {
+		Token string `json:"token"`
+	}
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/register", "", credenciais), http.StatusCreated, &cadastro)
+
+	// Sem cargo com permissão, a rota protegida recusa o acesso
+	esperar(t, requisitar(t, r, http.MethodGet, "/api/clientes/", cadastro.Token, nil), http.StatusForbidden, nil)
+
+	// O mecânico lê os clientes, mas não os cadastra
+	db.Model(&models.Usuario{}).Where("email = ?", credenciais["email"]).UpdateColumn("cargo", models.CargoMecanico)
+	db.Create(&models.Cliente{Nome: "João Prata", TipoPessoa: models.TipoPessoaFisica})
+	var clientes models.Pagina[models.Cliente]
+	esperar(t, requisitar(t, r, http.MethodGet, "/api/clientes/", cadastro.Token, nil), http.StatusOK, &clientes)
+	if clientes.Total != 1 || len(clientes.Itens) != 1 {
+		t.Errorf("a listagem trouxe %d clientes, esperado 1", clientes.Total)
+	}
+	esperar(t, requisitar(t, r, http.MethodPost, "/api/clientes/", cadastro.Token,
+		map[string]interface{}{"nome": "Carla", "tipoPessoa": "fisica"}), http.StatusForbidden, nil)
+}
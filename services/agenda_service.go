@@ -0,0 +1,249 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// agendaConfigFile guarda o expediente da oficina usado pela agenda
+const agendaConfigFile = "agenda_config.json"
+
+// AgendaService define a interface para a configuração da agenda: boxes, feriados e expediente
+type AgendaService interface {
+	BuscarBoxes(apenasAtivos bool) ([]models.Box, error)
+	CriarBox(box *models.Box) (*models.Box, error)
+	AtualizarBox(box *models.Box) (*models.Box, error)
+	DeletarBox(id uint) error
+	BuscarFeriados(ano int) ([]models.Feriado, error)
+	CriarFeriado(feriado *models.Feriado) (*models.Feriado, error)
+	DeletarFeriado(id uint) error
+	BuscarConfig() (*models.AgendaConfig, error)
+	SalvarConfig(config *models.AgendaConfig) (*models.AgendaConfig, error)
+	Expediente(data time.Time) (inicio, fim time.Time, aberto bool, err error) // Horário de funcionamento do dia informado
+}
+
+// AgendaServiceImpl implementa a interface AgendaService; o expediente fica em arquivo local
+type AgendaServiceImpl struct {
+	boxRepo     repositories.BoxRepository
+	feriadoRepo repositories.FeriadoRepository
+	arquivo     string
+}
+
+// NewAgendaService cria uma nova instância de AgendaService
+func NewAgendaService(boxRepo repositories.BoxRepository, feriadoRepo repositories.FeriadoRepository) AgendaService {
+	return &AgendaServiceImpl{
+		boxRepo:     boxRepo,
+		feriadoRepo: feriadoRepo,
+		arquivo:     agendaConfigFile,
+	}
+}
+
+// BuscarBoxes retorna os boxes cadastrados, opcionalmente apenas os ativos
+func (s *AgendaServiceImpl) BuscarBoxes(apenasAtivos bool) ([]models.Box, error) {
+	if apenasAtivos {
+		return s.boxRepo.FindAtivos()
+	}
+	return s.boxRepo.FindAll()
+}
+
+// CriarBox cadastra um novo box garantindo nome único
+func (s *AgendaServiceImpl) CriarBox(box *models.Box) (*models.Box, error) {
+	if err := s.validarBox(box); err != nil {
+		return nil, err
+	}
+
+	box.Ativo = true
+	if err := s.boxRepo.Create(box); err != nil {
+		return nil, errors.New("erro ao criar box: " + err.Error())
+	}
+	return box, nil
+}
+
+// AtualizarBox altera nome, descrição e situação de um box
+func (s *AgendaServiceImpl) AtualizarBox(box *models.Box) (*models.Box, error) {
+	existente, err := s.boxRepo.FindByID(box.ID)
+	if err != nil {
+		return nil, errors.New("box não encontrado")
+	}
+
+	if err := s.validarBox(box); err != nil {
+		return nil, err
+	}
+
+	box.CreatedAt = existente.CreatedAt
+	if err := s.boxRepo.Update(box); err != nil {
+		return nil, errors.New("erro ao atualizar box: " + err.Error())
+	}
+	return box, nil
+}
+
+// DeletarBox remove um box (soft delete)
+func (s *AgendaServiceImpl) DeletarBox(id uint) error {
+	if _, err := s.boxRepo.FindByID(id); err != nil {
+		return errors.New("box não encontrado")
+	}
+
+	if err := s.boxRepo.Delete(id); err != nil {
+		return errors.New("erro ao excluir box")
+	}
+	return nil
+}
+
+// BuscarFeriados retorna os feriados cadastrados
+func (s *AgendaServiceImpl) BuscarFeriados(ano int) ([]models.Feriado, error) {
+	return s.feriadoRepo.FindAll(ano)
+}
+
+// CriarFeriado cadastra um dia sem expediente
+func (s *AgendaServiceImpl) CriarFeriado(feriado *models.Feriado) (*models.Feriado, error) {
+	feriado.Descricao = strings.TrimSpace(feriado.Descricao)
+	if feriado.Descricao == "" {
+		return nil, errors.New("descrição do feriado é obrigatória")
+	}
+
+	ano, mes, dia := feriado.Data.Date()
+	feriado.Data = time.Date(ano, mes, dia, 0, 0, 0, 0, time.Local)
+
+	if existe, err := s.feriadoRepo.ExisteNaData(feriado.Data); err == nil && existe {
+		return nil, fmt.Errorf("já existe um feriado em %s", feriado.Data.Format("02/01/2006"))
+	}
+
+	if err := s.feriadoRepo.Create(feriado); err != nil {
+		return nil, errors.New("erro ao cadastrar feriado: " + err.Error())
+	}
+	return feriado, nil
+}
+
+// DeletarFeriado remove um feriado
+func (s *AgendaServiceImpl) DeletarFeriado(id uint) error {
+	if _, err := s.feriadoRepo.FindByID(id); err != nil {
+		return errors.New("feriado não encontrado")
+	}
+
+	if err := s.feriadoRepo.Delete(id); err != nil {
+		return errors.New("erro ao excluir feriado")
+	}
+	return nil
+}
+
+// BuscarConfig lê o expediente da oficina; sem arquivo salvo, usa segunda a sexta das 8h às 18h
+// e sábado das 8h às 12h, com horários de uma hora
+func (s *AgendaServiceImpl) BuscarConfig() (*models.AgendaConfig, error) {
+	file, err := os.Open(s.arquivo)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return agendaConfigPadrao(), nil
+		}
+		return nil, errors.New("erro ao ler configuração da agenda: " + err.Error())
+	}
+	defer file.Close()
+
+	var config models.AgendaConfig
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return nil, errors.New("erro ao ler configuração da agenda: " + err.Error())
+	}
+	return &config, nil
+}
+
+// SalvarConfig valida e grava o expediente da oficina
+func (s *AgendaServiceImpl) SalvarConfig(config *models.AgendaConfig) (*models.AgendaConfig, error) {
+	if config.DuracaoSlotMinutos < 15 || config.DuracaoSlotMinutos > 480 {
+		return nil, errors.New("a duração dos horários deve estar entre 15 e 480 minutos")
+	}
+
+	dias := make(map[int]bool)
+	for _, horario := range config.Horarios {
+		if horario.DiaSemana < 0 || horario.DiaSemana > 6 {
+			return nil, errors.New("dia da semana inválido; use 0 (domingo) a 6 (sábado)")
+		}
+		if dias[horario.DiaSemana] {
+			return nil, fmt.Errorf("o dia da semana %d foi informado mais de uma vez", horario.DiaSemana)
+		}
+		dias[horario.DiaSemana] = true
+
+		abertura, errAbertura := time.Parse("15:04", horario.Abertura)
+		fechamento, errFechamento := time.Parse("15:04", horario.Fechamento)
+		if errAbertura != nil || errFechamento != nil {
+			return nil, errors.New("horários de abertura e fechamento devem estar no formato HH:MM")
+		}
+		if !fechamento.After(abertura) {
+			return nil, errors.New("o fechamento deve ser depois da abertura")
+		}
+	}
+
+	file, err := os.Create(s.arquivo)
+	if err != nil {
+		return nil, errors.New("erro ao salvar configuração da agenda: " + err.Error())
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(config); err != nil {
+		return nil, errors.New("erro ao salvar configuração da agenda: " + err.Error())
+	}
+	return config, nil
+}
+
+// Expediente retorna o início e o fim do expediente no dia; aberto é falso em feriados e dias sem expediente
+func (s *AgendaServiceImpl) Expediente(data time.Time) (time.Time, time.Time, bool, error) {
+	ano, mes, dia := data.In(time.Local).Date()
+	dataLocal := time.Date(ano, mes, dia, 0, 0, 0, 0, time.Local)
+
+	feriado, err := s.feriadoRepo.ExisteNaData(dataLocal)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, errors.New("erro ao consultar feriados: " + err.Error())
+	}
+	if feriado {
+		return time.Time{}, time.Time{}, false, nil
+	}
+
+	config, err := s.BuscarConfig()
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+
+	for _, horario := range config.Horarios {
+		if horario.DiaSemana != int(dataLocal.Weekday()) {
+			continue
+		}
+		abertura, errAbertura := time.Parse("15:04", horario.Abertura)
+		fechamento, errFechamento := time.Parse("15:04", horario.Fechamento)
+		if errAbertura != nil || errFechamento != nil {
+			return time.Time{}, time.Time{}, false, errors.New("configuração de expediente inválida")
+		}
+		inicio := dataLocal.Add(time.Duration(abertura.Hour())*time.Hour + time.Duration(abertura.Minute())*time.Minute)
+		fim := dataLocal.Add(time.Duration(fechamento.Hour())*time.Hour + time.Duration(fechamento.Minute())*time.Minute)
+		return inicio, fim, true, nil
+	}
+
+	return time.Time{}, time.Time{}, false, nil
+}
+
+// validarBox normaliza o nome e impede nomes repetidos
+func (s *AgendaServiceImpl) validarBox(box *models.Box) error {
+	box.Nome = strings.Join(strings.Fields(box.Nome), " ")
+	if box.Nome == "" {
+		return errors.New("nome do box é obrigatório")
+	}
+
+	if existente, err := s.boxRepo.FindByNome(box.Nome); err == nil && existente.ID != box.ID {
+		return fmt.Errorf("já existe um box com o nome %s", existente.Nome)
+	}
+	return nil
+}
+
+// agendaConfigPadrao retorna o expediente usado enquanto a oficina não configura o seu
+func agendaConfigPadrao() *models.AgendaConfig {
+	config := &models.AgendaConfig{DuracaoSlotMinutos: 60}
+	for dia := int(time.Monday); dia <= int(time.Friday); dia++ {
+		config.Horarios = append(config.Horarios, models.HorarioFuncionamento{DiaSemana: dia, Abertura: "08:00", Fechamento: "18:00"})
+	}
+	config.Horarios = append(config.Horarios, models.HorarioFuncionamento{DiaSemana: int(time.Saturday), Abertura: "08:00", Fechamento: "12:00"})
+	return config
+}
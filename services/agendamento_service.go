@@ -0,0 +1,634 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// AgendamentoService define a interface para a agenda de recebimento de veículos
+type AgendamentoService interface {
+	BuscarTodos(filtro repositories.FiltroAgendamento) ([]models.Agendamento, error)
+	BuscarPorID(id uint) (*models.Agendamento, error)
+	HorariosDisponiveis(data time.Time, duracaoMinutos int) ([]models.HorarioDisponivel, error) // Horários do dia com boxes e mecânicos livres
+	Criar(agendamento *models.Agendamento, usuarioID *uint) (*models.Agendamento, error)
+	Reagendar(agendamento *models.Agendamento) (*models.Agendamento, error)             // Altera horário, box, mecânico ou descrição
+	AtualizarStatus(id uint, status string, motivo string) (*models.Agendamento, error) // Confirma, cancela ou registra a falta
+	CheckIn(id uint, kmEntrada int, usuarioID *uint) (*models.OrdemServico, error)      // Recebe o veículo abrindo a OS
+	GerarICS(funcionarioID uint) ([]byte, error)                                        // Feed iCalendar da agenda do mecânico
+	ValidarTokenFeed(funcionarioID uint, token string) bool                             // Confere o token do link público do feed
+	GerarTokenFeed(funcionarioID uint) (string, error)                                  // Novo segredo do link do feed, invalidando o anterior
+	RevogarTokenFeed(funcionarioID uint) error                                          // Desativa o link do feed
+}
+
+// AgendamentoServiceImpl implementa a interface AgendamentoService
+type AgendamentoServiceImpl struct {
+	agendamentoRepo   repositories.AgendamentoRepository
+	boxRepo           repositories.BoxRepository
+	clienteRepo       repositories.ClienteRepositoryGorm
+	veiculoRepo       repositories.VeiculoRepository
+	funcionarioRepo   repositories.FuncionarioRepository
+	osRepo            repositories.OrdemServicoRepository
+	feedRepo          repositories.FeedAgendaRepository
+	agendaService     AgendaService
+	manutencaoService ManutencaoService
+	uow               repositories.UnitOfWork
+}
+
+// NewAgendamentoService cria uma nova instância do serviço de agendamentos
+func NewAgendamentoService(
+	agendamentoRepo repositories.AgendamentoRepository,
+	boxRepo repositories.BoxRepository,
+	clienteRepo repositories.ClienteRepositoryGorm,
+	veiculoRepo repositories.VeiculoRepository,
+	funcionarioRepo repositories.FuncionarioRepository,
+	osRepo repositories.OrdemServicoRepository,
+	feedRepo repositories.FeedAgendaRepository,
+	agendaService AgendaService,
+	manutencaoService ManutencaoService,
+	uow repositories.UnitOfWork,
+) AgendamentoService {
+	return &AgendamentoServiceImpl{
+		agendamentoRepo:   agendamentoRepo,
+		boxRepo:           boxRepo,
+		clienteRepo:       clienteRepo,
+		veiculoRepo:       veiculoRepo,
+		funcionarioRepo:   funcionarioRepo,
+		osRepo:            osRepo,
+		feedRepo:          feedRepo,
+		agendaService:     agendaService,
+		manutencaoService: manutencaoService,
+		uow:               uow,
+	}
+}
+
+// BuscarTodos lista os agendamentos conforme os filtros
+func (s *AgendamentoServiceImpl) BuscarTodos(filtro repositories.FiltroAgendamento) ([]models.Agendamento, error) {
+	if filtro.Status != "" && !isValidStatusAgendamento(filtro.Status) {
+		return nil, errors.New("status de agendamento inválido")
+	}
+	return s.agendamentoRepo.FindAll(filtro)
+}
+
+// BuscarPorID busca um agendamento pelo ID
+func (s *AgendamentoServiceImpl) BuscarPorID(id uint) (*models.Agendamento, error) {
+	agendamento, err := s.agendamentoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("agendamento não encontrado")
+	}
+	return agendamento, nil
+}
+
+// HorariosDisponiveis divide o expediente do dia em horários e informa, para cada um,
+// os boxes e mecânicos sem agendamento; horários sem box livre ou já passados não são listados
+func (s *AgendamentoServiceImpl) HorariosDisponiveis(data time.Time, duracaoMinutos int) ([]models.HorarioDisponivel, error) {
+	config, err := s.agendaService.BuscarConfig()
+	if err != nil {
+		return nil, err
+	}
+	if duracaoMinutos <= 0 {
+		duracaoMinutos = config.DuracaoSlotMinutos
+	}
+	duracao := time.Duration(duracaoMinutos) * time.Minute
+
+	abertura, fechamento, aberto, err := s.agendaService.Expediente(data)
+	if err != nil {
+		return nil, err
+	}
+
+	horarios := []models.HorarioDisponivel{}
+	if !aberto {
+		return horarios, nil
+	}
+
+	boxes, err := s.boxRepo.FindAtivos()
+	if err != nil {
+		return nil, errors.New("erro ao buscar boxes: " + err.Error())
+	}
+	mecanicos, err := s.mecanicosDisponiveis()
+	if err != nil {
+		return nil, err
+	}
+	ocupados, err := s.agendamentoRepo.FindAtivosNoPeriodo(abertura, fechamento)
+	if err != nil {
+		return nil, errors.New("erro ao buscar agendamentos: " + err.Error())
+	}
+
+	agora := time.Now()
+	passo := time.Duration(config.DuracaoSlotMinutos) * time.Minute
+	for inicio := abertura; !inicio.Add(duracao).After(fechamento); inicio = inicio.Add(passo) {
+		fim := inicio.Add(duracao)
+		if inicio.Before(agora) {
+			continue
+		}
+
+		boxesOcupados := make(map[uint]bool)
+		mecanicosOcupados := make(map[uint]bool)
+		for _, agendamento := range ocupados {
+			if agendamento.DataInicio.Before(fim) && agendamento.DataFim.After(inicio) {
+				boxesOcupados[agendamento.BoxID] = true
+				if agendamento.FuncionarioID != nil {
+					mecanicosOcupados[*agendamento.FuncionarioID] = true
+				}
+			}
+		}
+
+		horario := models.HorarioDisponivel{Inicio: inicio, Fim: fim, Boxes: []models.RecursoAgenda{}, Mecanicos: []models.RecursoAgenda{}}
+		for _, box := range boxes {
+			if !boxesOcupados[box.ID] {
+				horario.Boxes = append(horario.Boxes, models.RecursoAgenda{ID: box.ID, Nome: box.Nome})
+			}
+		}
+		for _, mecanico := range mecanicos {
+			if !mecanicosOcupados[mecanico.ID] {
+				horario.Mecanicos = append(horario.Mecanicos, models.RecursoAgenda{ID: mecanico.ID, Nome: mecanico.Nome})
+			}
+		}
+
+		if len(horario.Boxes) > 0 {
+			horarios = append(horarios, horario)
+		}
+	}
+
+	return horarios, nil
+}
+
+// Criar reserva o box (e o mecânico, se informado) para o cliente trazer o veículo
+func (s *AgendamentoServiceImpl) Criar(agendamento *models.Agendamento, usuarioID *uint) (*models.Agendamento, error) {
+	if err := s.validarVinculos(agendamento); err != nil {
+		return nil, err
+	}
+	if agendamento.DataInicio.Before(time.Now()) {
+		return nil, errors.New("não é possível agendar em um horário que já passou")
+	}
+	if err := s.validarHorario(agendamento.DataInicio, agendamento.DataFim); err != nil {
+		return nil, err
+	}
+
+	agendamento.ID = 0
+	agendamento.Status = models.StatusAgendamentoAgendado
+	agendamento.OrdemServicoID = nil
+	agendamento.MotivoCancelamento = ""
+	agendamento.CriadoPorID = usuarioID
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		if err := s.verificarConflitos(tx, agendamento); err != nil {
+			return err
+		}
+		if err := s.agendamentoRepo.WithTx(tx).Create(agendamento); err != nil {
+			return errors.New("erro ao criar agendamento: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.agendamentoRepo.FindByID(agendamento.ID)
+}
+
+// Reagendar altera horário, box, mecânico e descrição de um agendamento ainda ativo
+func (s *AgendamentoServiceImpl) Reagendar(agendamento *models.Agendamento) (*models.Agendamento, error) {
+	existente, err := s.agendamentoRepo.FindByID(agendamento.ID)
+	if err != nil {
+		return nil, errors.New("agendamento não encontrado")
+	}
+	if !existente.Ativo() {
+		return nil, errors.New("apenas agendamentos ativos podem ser alterados")
+	}
+
+	// Cliente e veículo não mudam; para outro veículo, cancele e agende novamente
+	agendamento.ClienteID = existente.ClienteID
+	agendamento.VeiculoID = existente.VeiculoID
+	if err := s.validarVinculos(agendamento); err != nil {
+		return nil, err
+	}
+	if agendamento.DataInicio.Before(time.Now()) {
+		return nil, errors.New("não é possível agendar em um horário que já passou")
+	}
+	if err := s.validarHorario(agendamento.DataInicio, agendamento.DataFim); err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Executar(func(tx *gorm.DB) error {
+		agendamentoRepo := s.agendamentoRepo.WithTx(tx)
+
+		atual, err := agendamentoRepo.FindByIDForUpdate(agendamento.ID)
+		if err != nil {
+			return errors.New("agendamento não encontrado")
+		}
+		if !atual.Ativo() {
+			return errors.New("apenas agendamentos ativos podem ser alterados")
+		}
+
+		if err := s.verificarConflitos(tx, agendamento); err != nil {
+			return err
+		}
+
+		atual.BoxID = agendamento.BoxID
+		atual.FuncionarioID = agendamento.FuncionarioID
+		atual.DataInicio = agendamento.DataInicio
+		atual.DataFim = agendamento.DataFim
+		atual.Descricao = agendamento.Descricao
+		atual.Observacoes = agendamento.Observacoes
+		if err := agendamentoRepo.Update(atual); err != nil {
+			return errors.New("erro ao atualizar agendamento: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.agendamentoRepo.FindByID(agendamento.ID)
+}
+
+// AtualizarStatus confirma, cancela ou registra a falta do cliente; o check-in tem rota própria
+func (s *AgendamentoServiceImpl) AtualizarStatus(id uint, status string, motivo string) (*models.Agendamento, error) {
+	if !isValidStatusAgendamento(status) {
+		return nil, errors.New("status de agendamento inválido")
+	}
+	if status == models.StatusAgendamentoAtendido {
+		return nil, errors.New("use o check-in para receber o veículo")
+	}
+
+	motivo = strings.TrimSpace(motivo)
+	if status == models.StatusAgendamentoCancelado && motivo == "" {
+		return nil, errors.New("informe o motivo do cancelamento")
+	}
+
+	agendamento, err := s.agendamentoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("agendamento não encontrado")
+	}
+
+	if !isValidStatusAgendamentoTransition(agendamento.Status, status) {
+		return nil, fmt.Errorf("transição de status inválida: de %s para %s", agendamento.Status, status)
+	}
+	if status == models.StatusAgendamentoNaoCompareceu && time.Now().Before(agendamento.DataInicio) {
+		return nil, errors.New("a falta só pode ser registrada depois do horário agendado")
+	}
+
+	agendamento.Status = status
+	if status == models.StatusAgendamentoCancelado {
+		agendamento.MotivoCancelamento = motivo
+	}
+	if err := s.agendamentoRepo.Update(agendamento); err != nil {
+		return nil, errors.New("erro ao atualizar agendamento: " + err.Error())
+	}
+
+	return agendamento, nil
+}
+
+// CheckIn recebe o veículo agendado abrindo a OS com o cliente, o veículo, o mecânico e o serviço pedido;
+// o km informado na recepção é gravado na OS e no histórico de quilometragem do veículo
+func (s *AgendamentoServiceImpl) CheckIn(id uint, kmEntrada int, usuarioID *uint) (*models.OrdemServico, error) {
+	if kmEntrada < 0 {
+		return nil, errors.New("a quilometragem de entrada não pode ser negativa")
+	}
+
+	var osID uint
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		agendamentoRepo := s.agendamentoRepo.WithTx(tx)
+
+		agendamento, err := agendamentoRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("agendamento não encontrado")
+		}
+		if !isValidStatusAgendamentoTransition(agendamento.Status, models.StatusAgendamentoAtendido) {
+			return errors.New("apenas agendamentos ativos podem receber check-in")
+		}
+
+		veiculo, err := s.veiculoRepo.WithTx(tx).FindByID(agendamento.VeiculoID)
+		if err != nil {
+			return errors.New("veículo não encontrado")
+		}
+
+		descricao := strings.TrimSpace(agendamento.Descricao)
+		if descricao == "" {
+			descricao = "Recebimento agendado do veículo " + veiculo.Placa
+		}
+
+		os := &models.OrdemServico{
+			ClienteID:     agendamento.ClienteID,
+			VeiculoID:     agendamento.VeiculoID,
+			FuncionarioID: agendamento.FuncionarioID,
+			Descricao:     descricao,
+			Observacoes:   agendamento.Observacoes,
+			DataEntrada:   time.Now(),
+			DataPrevisao:  agendamento.DataFim,
+			KmEntrada:     kmEntrada,
+			Status:        "aberta",
+		}
+		if err := s.osRepo.WithTx(tx).Create(os); err != nil {
+			return errors.New("erro ao criar ordem de serviço: " + err.Error())
+		}
+
+		if kmEntrada > 0 {
+			err := s.manutencaoService.RegistrarLeituraNaTransacao(tx, &models.LeituraKm{
+				VeiculoID:      os.VeiculoID,
+				Km:             kmEntrada,
+				DataLeitura:    os.DataEntrada,
+				Origem:         models.OrigemLeituraKmOrdemServico,
+				OrdemServicoID: &os.ID,
+				UsuarioID:      usuarioID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		agendamento.Status = models.StatusAgendamentoAtendido
+		agendamento.OrdemServicoID = &os.ID
+		if err := agendamentoRepo.Update(agendamento); err != nil {
+			return errors.New("erro ao atualizar agendamento: " + err.Error())
+		}
+
+		osID = os.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindByID(osID)
+}
+
+// GerarICS monta o calendário do mecânico com os agendamentos dos últimos 30 dias em diante
+func (s *AgendamentoServiceImpl) GerarICS(funcionarioID uint) ([]byte, error) {
+	funcionario, err := s.funcionarioRepo.FindByID(funcionarioID)
+	if err != nil {
+		return nil, errors.New("funcionário não encontrado")
+	}
+
+	agendamentos, err := s.agendamentoRepo.FindAll(repositories.FiltroAgendamento{
+		Inicio:        time.Now().AddDate(0, 0, -30),
+		FuncionarioID: &funcionarioID,
+	})
+	if err != nil {
+		return nil, errors.New("erro ao buscar agendamentos: " + err.Error())
+	}
+
+	return gerarCalendario("Agenda - "+funcionario.Nome, agendamentos), nil
+}
+
+// GerarTokenFeed sorteia um novo segredo para o link do feed do funcionário.
+// Só o hash é gravado, então o link completo é exibido apenas agora; o link anterior deixa de valer
+func (s *AgendamentoServiceImpl) GerarTokenFeed(funcionarioID uint) (string, error) {
+	if _, err := s.funcionarioRepo.FindByID(funcionarioID); err != nil {
+		return "", errors.New("funcionário não encontrado")
+	}
+
+	token, err := utils.GerarTokenAleatorio(32)
+	if err != nil {
+		return "", errors.New("erro ao gerar o token do feed: " + err.Error())
+	}
+	feed := &models.FeedAgenda{FuncionarioID: funcionarioID, TokenHash: utils.HashToken(token)}
+	if err := s.feedRepo.Salvar(feed); err != nil {
+		return "", errors.New("erro ao salvar o token do feed: " + err.Error())
+	}
+	return token, nil
+}
+
+// RevogarTokenFeed apaga o segredo do feed, fazendo o link atual parar de responder
+func (s *AgendamentoServiceImpl) RevogarTokenFeed(funcionarioID uint) error {
+	if err := s.feedRepo.DeleteByFuncionario(funcionarioID); err != nil {
+		return errors.New("erro ao revogar o token do feed: " + err.Error())
+	}
+	return nil
+}
+
+// ValidarTokenFeed compara o hash do token recebido com o segredo gravado para o funcionário.
+// Aplicativos de calendário não enviam o cabeçalho Authorization, então o link público carrega o token
+func (s *AgendamentoServiceImpl) ValidarTokenFeed(funcionarioID uint, token string) bool {
+	if token == "" {
+		return false
+	}
+	feed, err := s.feedRepo.FindByFuncionario(funcionarioID)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(feed.TokenHash), []byte(utils.HashToken(token))) == 1
+}
+
+// validarVinculos confere cliente, veículo, box e mecânico do agendamento
+func (s *AgendamentoServiceImpl) validarVinculos(agendamento *models.Agendamento) error {
+	if _, err := s.clienteRepo.FindByID(agendamento.ClienteID); err != nil {
+		return errors.New("cliente não encontrado")
+	}
+
+	veiculo, err := s.veiculoRepo.FindByID(agendamento.VeiculoID)
+	if err != nil {
+		return errors.New("veículo não encontrado")
+	}
+	if veiculo.ClienteID != agendamento.ClienteID {
+		return errors.New("o veículo não pertence ao cliente informado")
+	}
+
+	box, err := s.boxRepo.FindByID(agendamento.BoxID)
+	if err != nil {
+		return errors.New("box não encontrado")
+	}
+	if !box.Ativo {
+		return fmt.Errorf("o box %s está inativo", box.Nome)
+	}
+
+	if agendamento.FuncionarioID != nil {
+		funcionario, err := s.funcionarioRepo.FindByID(*agendamento.FuncionarioID)
+		if err != nil {
+			return errors.New("funcionário não encontrado")
+		}
+		if funcionario.Usuario != nil && funcionario.Usuario.Ferias {
+			return fmt.Errorf("o funcionário %s está de férias", funcionario.Nome)
+		}
+	}
+
+	agendamento.Cliente = nil
+	agendamento.Veiculo = nil
+	agendamento.Box = nil
+	agendamento.Funcionario = nil
+	return nil
+}
+
+// validarHorario garante que o período cabe no expediente de um único dia
+func (s *AgendamentoServiceImpl) validarHorario(inicio, fim time.Time) error {
+	if !fim.After(inicio) {
+		return errors.New("o fim do agendamento deve ser depois do início")
+	}
+
+	abertura, fechamento, aberto, err := s.agendaService.Expediente(inicio)
+	if err != nil {
+		return err
+	}
+	if !aberto {
+		return fmt.Errorf("a oficina não abre em %s", inicio.In(time.Local).Format("02/01/2006"))
+	}
+	if inicio.Before(abertura) || fim.After(fechamento) {
+		return fmt.Errorf("o agendamento deve ficar entre %s e %s", abertura.Format("15:04"), fechamento.Format("15:04"))
+	}
+	return nil
+}
+
+// verificarConflitos bloqueia o box e recusa o agendamento se o box ou o mecânico já estiverem ocupados
+func (s *AgendamentoServiceImpl) verificarConflitos(tx *gorm.DB, agendamento *models.Agendamento) error {
+	box, err := s.boxRepo.WithTx(tx).FindByIDForUpdate(agendamento.BoxID)
+	if err != nil {
+		return errors.New("box não encontrado")
+	}
+
+	conflitos, err := s.agendamentoRepo.WithTx(tx).FindConflitos(
+		agendamento.DataInicio, agendamento.DataFim, agendamento.BoxID, agendamento.FuncionarioID, agendamento.ID)
+	if err != nil {
+		return errors.New("erro ao verificar conflitos de agenda: " + err.Error())
+	}
+
+	for _, conflito := range conflitos {
+		periodo := fmt.Sprintf("%s às %s", conflito.DataInicio.In(time.Local).Format("02/01/2006 15:04"), conflito.DataFim.In(time.Local).Format("15:04"))
+		if conflito.BoxID == agendamento.BoxID {
+			return fmt.Errorf("o box %s já está reservado de %s (agendamento %d)", box.Nome, periodo, conflito.ID)
+		}
+		return fmt.Errorf("o mecânico já está reservado de %s (agendamento %d)", periodo, conflito.ID)
+	}
+	return nil
+}
+
+// mecanicosDisponiveis retorna os funcionários com cargo de mecânico que não estão de férias
+func (s *AgendamentoServiceImpl) mecanicosDisponiveis() ([]models.Funcionario, error) {
+	funcionarios, err := s.funcionarioRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("erro ao buscar funcionários: " + err.Error())
+	}
+
+	var mecanicos []models.Funcionario
+	for _, funcionario := range funcionarios {
+		if models.NormalizarCargo(funcionario.Cargo) != models.CargoMecanico {
+			continue
+		}
+		if funcionario.Usuario != nil && funcionario.Usuario.Ferias {
+			continue
+		}
+		mecanicos = append(mecanicos, funcionario)
+	}
+	return mecanicos, nil
+}
+
+// isValidStatusAgendamento verifica se o status de agendamento é válido
+func isValidStatusAgendamento(status string) bool {
+	switch status {
+	case models.StatusAgendamentoAgendado, models.StatusAgendamentoConfirmado, models.StatusAgendamentoAtendido,
+		models.StatusAgendamentoCancelado, models.StatusAgendamentoNaoCompareceu:
+		return true
+	}
+	return false
+}
+
+// isValidStatusAgendamentoTransition verifica se a transição de status do agendamento é permitida
+func isValidStatusAgendamentoTransition(atual, novo string) bool {
+	transicoes := map[string][]string{
+		models.StatusAgendamentoAgendado: {
+			models.StatusAgendamentoConfirmado, models.StatusAgendamentoAtendido,
+			models.StatusAgendamentoCancelado, models.StatusAgendamentoNaoCompareceu,
+		},
+		models.StatusAgendamentoConfirmado: {
+			models.StatusAgendamentoAtendido, models.StatusAgendamentoCancelado, models.StatusAgendamentoNaoCompareceu,
+		},
+		models.StatusAgendamentoAtendido:      {},
+		models.StatusAgendamentoCancelado:     {},
+		models.StatusAgendamentoNaoCompareceu: {},
+	}
+
+	for _, permitido := range transicoes[atual] {
+		if permitido == novo {
+			return true
+		}
+	}
+	return false
+}
+
+// gerarCalendario monta o arquivo iCalendar (RFC 5545) com um evento por agendamento
+func gerarCalendario(nome string, agendamentos []models.Agendamento) []byte {
+	var b strings.Builder
+	linha := func(conteudo string) {
+		b.WriteString(dobrarLinhaICS(conteudo))
+		b.WriteString("\r\n")
+	}
+
+	linha("BEGIN:VCALENDAR")
+	linha("VERSION:2.0")
+	linha("PRODID:-//OficinaMecanica//Agenda//PT-BR")
+	linha("CALSCALE:GREGORIAN")
+	linha("METHOD:PUBLISH")
+	linha("X-WR-CALNAME:" + escaparTextoICS(nome))
+
+	agora := time.Now().UTC().Format("20060102T150405Z")
+	for _, agendamento := range agendamentos {
+		resumo := "Agendamento"
+		if agendamento.Veiculo != nil {
+			resumo = strings.TrimSpace(fmt.Sprintf("%s %s %s", agendamento.Veiculo.Placa, agendamento.Veiculo.Marca, agendamento.Veiculo.Modelo))
+		}
+		if agendamento.Cliente != nil {
+			resumo += " - " + agendamento.Cliente.Nome
+		}
+
+		status := "CONFIRMED"
+		if agendamento.Status == models.StatusAgendamentoCancelado || agendamento.Status == models.StatusAgendamentoNaoCompareceu {
+			status = "CANCELLED"
+		} else if agendamento.Status == models.StatusAgendamentoAgendado {
+			status = "TENTATIVE"
+		}
+
+		linha("BEGIN:VEVENT")
+		linha(fmt.Sprintf("UID:agendamento-%d@oficinamecanica", agendamento.ID))
+		linha("DTSTAMP:" + agora)
+		linha("DTSTART:" + agendamento.DataInicio.UTC().Format("20060102T150405Z"))
+		linha("DTEND:" + agendamento.DataFim.UTC().Format("20060102T150405Z"))
+		linha("SUMMARY:" + escaparTextoICS(resumo))
+		if agendamento.Descricao != "" {
+			linha("DESCRIPTION:" + escaparTextoICS(agendamento.Descricao))
+		}
+		if agendamento.Box != nil {
+			linha("LOCATION:" + escaparTextoICS(agendamento.Box.Nome))
+		}
+		linha("STATUS:" + status)
+		linha("LAST-MODIFIED:" + agendamento.UpdatedAt.UTC().Format("20060102T150405Z"))
+		linha("END:VEVENT")
+	}
+
+	linha("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escaparTextoICS escapa os caracteres especiais dos valores de texto do iCalendar
+func escaparTextoICS(texto string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	).Replace(texto)
+}
+
+// dobrarLinhaICS quebra linhas com mais de 75 bytes, como exige o formato, sem partir caracteres UTF-8
+func dobrarLinhaICS(conteudo string) string {
+	var b strings.Builder
+	tamanho := 0
+	for _, r := range conteudo {
+		bytesRuna := len(string(r))
+		if tamanho+bytesRuna > 75 {
+			b.WriteString("\r\n ")
+			tamanho = 1
+		}
+		b.WriteRune(r)
+		tamanho += bytesRuna
+	}
+	return b.String()
+}
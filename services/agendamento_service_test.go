@@ -0,0 +1,245 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+)
+
+// proximaQuarta retorna a quarta-feira da semana que vem no horário informado, dentro do expediente padrão
+func proximaQuarta(hora, minuto int) time.Time {
+	dia := time.Now().AddDate(0, 0, 7)
+	for dia.Weekday() != time.Wednesday {
+		dia = dia.AddDate(0, 0, 1)
+	}
+	ano, mes, d := dia.Date()
+	return time.Date(ano, mes, d, hora, minuto, 0, 0, time.Local)
+}
+
+// novoBox cadastra um box ativo
+func (a *ambienteTeste) novoBox(t *testing.T) *models.Box {
+	t.Helper()
+	var total int64
+	a.db.Model(&models.Box{}).Count(&total)
+	box := &models.Box{Nome: fmt.Sprintf("Box %d", total+1), Ativo: true}
+	a.criar(t, box)
+	return box
+}
+
+// agendar reserva o box para um veículo novo no período informado
+func (a *ambienteTeste) agendar(t *testing.T, boxID uint, funcionarioID *uint, inicio time.Time, duracao time.Duration) (*models.Agendamento, error) {
+	t.Helper()
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	return a.agendamento.Criar(&models.Agendamento{
+		ClienteID:     cliente.ID,
+		VeiculoID:     veiculo.ID,
+		BoxID:         boxID,
+		FuncionarioID: funcionarioID,
+		DataInicio:    inicio,
+		DataFim:       inicio.Add(duracao),
+		Descricao:     "Troca de óleo",
+	}, nil)
+}
+
+func TestAgendamentoRecusaBoxOcupado(t *testing.T) {
+	a := novoAmbiente(t)
+	box := a.novoBox(t)
+
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(9, 0), 2*time.Hour); err != nil {
+		t.Fatalf("erro ao agendar: %v", err)
+	}
+
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(10, 0), time.Hour); err == nil {
+		t.Error("o box já reservado não deveria aceitar outro agendamento no mesmo período")
+	}
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(8, 30), time.Hour); err == nil {
+		t.Error("o agendamento que termina dentro da reserva deveria ser recusado")
+	}
+
+	// Períodos encostados não se sobrepõem
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(11, 0), time.Hour); err != nil {
+		t.Errorf("o horário logo após a reserva deveria ser aceito: %v", err)
+	}
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(8, 0), time.Hour); err != nil {
+		t.Errorf("o horário logo antes da reserva deveria ser aceito: %v", err)
+	}
+
+	// Outro box no mesmo horário está livre
+	if _, err := a.agendar(t, a.novoBox(t).ID, nil, proximaQuarta(9, 0), time.Hour); err != nil {
+		t.Errorf("outro box deveria aceitar o mesmo horário: %v", err)
+	}
+}
+
+func TestAgendamentoRecusaMecanicoOcupado(t *testing.T) {
+	a := novoAmbiente(t)
+	mecanico := a.novoMecanico(t)
+
+	if _, err := a.agendar(t, a.novoBox(t).ID, &mecanico.ID, proximaQuarta(14, 0), time.Hour); err != nil {
+		t.Fatalf("erro ao agendar: %v", err)
+	}
+	if _, err := a.agendar(t, a.novoBox(t).ID, &mecanico.ID, proximaQuarta(14, 30), time.Hour); err == nil {
+		t.Error("o mecânico não deveria ficar em dois boxes ao mesmo tempo")
+	}
+	if _, err := a.agendar(t, a.novoBox(t).ID, nil, proximaQuarta(14, 30), time.Hour); err != nil {
+		t.Errorf("sem mecânico definido o outro box deveria ser aceito: %v", err)
+	}
+}
+
+func TestAgendamentoRespeitaExpediente(t *testing.T) {
+	a := novoAmbiente(t)
+	box := a.novoBox(t)
+
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(7, 0), time.Hour); err == nil {
+		t.Error("o agendamento antes da abertura deveria ser recusado")
+	}
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(17, 30), time.Hour); err == nil {
+		t.Error("o agendamento após o fechamento deveria ser recusado")
+	}
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(10, 0), 0); err == nil {
+		t.Error("o agendamento sem duração deveria ser recusado")
+	}
+	if _, err := a.agendar(t, box.ID, nil, time.Now().Add(-time.Hour), time.Hour); err == nil {
+		t.Error("o agendamento no passado deveria ser recusado")
+	}
+
+	domingo := proximaQuarta(10, 0).AddDate(0, 0, 4)
+	if _, err := a.agendar(t, box.ID, nil, domingo, time.Hour); err == nil {
+		t.Error("o agendamento no domingo deveria ser recusado")
+	}
+
+	if _, err := a.agenda.CriarFeriado(&models.Feriado{Data: proximaQuarta(0, 0), Descricao: "Feriado municipal"}); err != nil {
+		t.Fatalf("erro ao cadastrar feriado: %v", err)
+	}
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(10, 0), time.Hour); err == nil {
+		t.Error("o agendamento no feriado deveria ser recusado")
+	}
+}
+
+func TestCancelarAgendamentoLiberaOHorario(t *testing.T) {
+	a := novoAmbiente(t)
+	box := a.novoBox(t)
+	agendamento, _ := a.agendar(t, box.ID, nil, proximaQuarta(9, 0), time.Hour)
+
+	if _, err := a.agendamento.AtualizarStatus(agendamento.ID, models.StatusAgendamentoCancelado, ""); err == nil {
+		t.Error("o cancelamento deveria exigir o motivo")
+	}
+	if _, err := a.agendamento.AtualizarStatus(agendamento.ID, models.StatusAgendamentoNaoCompareceu, ""); err == nil {
+		t.Error("a falta não deveria ser registrada antes do horário")
+	}
+	if _, err := a.agendamento.AtualizarStatus(agendamento.ID, models.StatusAgendamentoCancelado, "Cliente desmarcou"); err != nil {
+		t.Fatalf("erro ao cancelar: %v", err)
+	}
+
+	if _, err := a.agendar(t, box.ID, nil, proximaQuarta(9, 0), time.Hour); err != nil {
+		t.Errorf("o horário cancelado deveria ficar livre: %v", err)
+	}
+	if _, err := a.agendamento.AtualizarStatus(agendamento.ID, models.StatusAgendamentoConfirmado, ""); err == nil {
+		t.Error("um agendamento cancelado não deveria ser confirmado")
+	}
+}
+
+func TestReagendarNaoConflitaConsigoMesmo(t *testing.T) {
+	a := novoAmbiente(t)
+	box := a.novoBox(t)
+	agendamento, _ := a.agendar(t, box.ID, nil, proximaQuarta(9, 0), 2*time.Hour)
+	outro, _ := a.agendar(t, box.ID, nil, proximaQuarta(13, 0), time.Hour)
+
+	// Estender o próprio período sobrepõe apenas a reserva antiga dele
+	agendamento.DataFim = proximaQuarta(12, 0)
+	if _, err := a.agendamento.Reagendar(agendamento); err != nil {
+		t.Fatalf("erro ao reagendar: %v", err)
+	}
+
+	agendamento.DataFim = proximaQuarta(13, 30)
+	if _, err := a.agendamento.Reagendar(agendamento); err == nil {
+		t.Error("o reagendamento sobre outra reserva deveria ser recusado")
+	}
+
+	atual, _ := a.agendamento.BuscarPorID(agendamento.ID)
+	if !atual.DataFim.Equal(proximaQuarta(12, 0)) {
+		t.Errorf("fim = %v, o reagendamento recusado não deveria alterar o período", atual.DataFim)
+	}
+	if _, err := a.agendamento.BuscarPorID(outro.ID); err != nil {
+		t.Errorf("erro ao buscar o outro agendamento: %v", err)
+	}
+}
+
+func TestCheckInAbreOS(t *testing.T) {
+	a := novoAmbiente(t)
+	mecanico := a.novoMecanico(t)
+	agendamento, _ := a.agendar(t, a.novoBox(t).ID, &mecanico.ID, proximaQuarta(9, 0), time.Hour)
+
+	if _, err := a.agendamento.AtualizarStatus(agendamento.ID, models.StatusAgendamentoAtendido, ""); err == nil {
+		t.Error("o atendimento só deveria ser registrado pelo check-in")
+	}
+
+	os, err := a.agendamento.CheckIn(agendamento.ID, 42000, nil)
+	if err != nil {
+		t.Fatalf("erro no check-in: %v", err)
+	}
+	if os.Status != "aberta" || os.Descricao != "Troca de óleo" || os.KmEntrada != 42000 {
+		t.Errorf("OS %s (%q, km %d), esperado aberta com a descrição e o km do agendamento", os.Status, os.Descricao, os.KmEntrada)
+	}
+	if os.FuncionarioID == nil || *os.FuncionarioID != mecanico.ID {
+		t.Error("a OS deveria ficar com o mecânico do agendamento")
+	}
+
+	atendido, _ := a.agendamento.BuscarPorID(agendamento.ID)
+	if atendido.Status != models.StatusAgendamentoAtendido || atendido.OrdemServicoID == nil || *atendido.OrdemServicoID != os.ID {
+		t.Error("o agendamento deveria ficar atendido e vinculado à OS")
+	}
+	if _, err := a.agendamento.CheckIn(agendamento.ID, 42000, nil); err == nil {
+		t.Error("o check-in não deveria abrir uma segunda OS")
+	}
+}
+
+func TestTokenDoFeedPodeSerRegeneradoERevogado(t *testing.T) {
+	a := novoAmbiente(t)
+	mecanico := a.novoMecanico(t)
+	outro := a.novoMecanico(t)
+
+	if a.agendamento.ValidarTokenFeed(mecanico.ID, "") {
+		t.Fatal("sem link gerado, nenhum token deveria ser aceito")
+	}
+
+	primeiro, err := a.agendamento.GerarTokenFeed(mecanico.ID)
+	if err != nil {
+		t.Fatalf("erro ao gerar o token: %v", err)
+	}
+	if !a.agendamento.ValidarTokenFeed(mecanico.ID, primeiro) {
+		t.Error("o token recém-gerado deveria ser aceito")
+	}
+	if a.agendamento.ValidarTokenFeed(outro.ID, primeiro) {
+		t.Error("o token de um funcionário não deveria abrir o feed de outro")
+	}
+
+	var feed models.FeedAgenda
+	a.db.Where("funcionario_id = ?", mecanico.ID).First(&feed)
+	if feed.TokenHash == primeiro {
+		t.Error("o token deveria ser gravado apenas como hash")
+	}
+
+	segundo, err := a.agendamento.GerarTokenFeed(mecanico.ID)
+	if err != nil {
+		t.Fatalf("erro ao regenerar o token: %v", err)
+	}
+	if segundo == primeiro || a.agendamento.ValidarTokenFeed(mecanico.ID, primeiro) {
+		t.Error("regenerar o link deveria invalidar o token anterior")
+	}
+	if !a.agendamento.ValidarTokenFeed(mecanico.ID, segundo) {
+		t.Error("o token regenerado deveria ser aceito")
+	}
+
+	if err := a.agendamento.RevogarTokenFeed(mecanico.ID); err != nil {
+		t.Fatalf("erro ao revogar o token: %v", err)
+	}
+	if a.agendamento.ValidarTokenFeed(mecanico.ID, segundo) {
+		t.Error("o token revogado não deveria ser aceito")
+	}
+
+	if _, err := a.agendamento.GerarTokenFeed(9999); err == nil {
+		t.Error("não deveria gerar link para funcionário inexistente")
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"OficinaMecanica/migrations"
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// TestMain silencia o log das migrações, aplicadas de novo a cada teste, e troca a assinatura do JWT,
+// que depende do .env, por um token previsível
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	utils.GerarTokenFn = func(usuario models.Usuario, chaveSessao string, validade time.Duration) (string, error) {
+		return fmt.Sprintf("acesso:%d:%s", usuario.ID, chaveSessao), nil
+	}
+	os.Exit(m.Run())
+}
+
+// ambienteTeste reúne os serviços ligados a um banco SQLite em memória com todas as migrações aplicadas,
+// montados da mesma forma que em routes.SetupRoutes
+type ambienteTeste struct {
+	db *gorm.DB
+
+	usuario      UsuarioService
+	sessao       SessaoService
+	protecao     ProtecaoLoginService
+	redefinicao  RedefinicaoSenhaService
+	mailer       *mailerFalso
+	permissao    PermissaoService
+	funcionario  FuncionarioService
+	estoque      EstoqueService
+	movimentacao MovimentacaoEstoqueService
+	compra       PedidoCompraService
+	fornecedor   FornecedorService
+	os           OrdemServicoService
+	servico      ServicoService
+	manutencao   ManutencaoService
+	orcamento    OrcamentoService
+	pagamento    PagamentoService
+	caixa        CaixaService
+	agenda       AgendaService
+	agendamento  AgendamentoService
+	busca        BuscaService
+}
+
+// mailerFalso guarda os e-mails em vez de enviá-los
+type mailerFalso struct {
+	enviados []utils.Mensagem
+}
+
+func (m *mailerFalso) Enviar(mensagem utils.Mensagem) error {
+	m.enviados = append(m.enviados, mensagem)
+	return nil
+}
+
+// novoAmbiente cria um banco vazio e isolado para o teste
+func novoAmbiente(t *testing.T) *ambienteTeste {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:?_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("erro ao abrir o banco: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("erro ao obter a conexão: %v", err)
+	}
+	// Cada conexão com ":memory:" abre um banco novo; uma única conexão mantém todos no mesmo banco
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := migrations.NewMigrador(db, migrations.Todas()).Migrar(0); err != nil {
+		t.Fatalf("erro ao migrar o banco: %v", err)
+	}
+
+	usuarioRepo := repositories.NewUsuarioRepository(db)
+	clienteRepo := repositories.NewClienteRepositoryGorm(db)
+	veiculoRepo := repositories.NewVeiculoRepository(db)
+	estoqueRepo := repositories.NewEstoqueRepository(db)
+	ordemServicoRepo := repositories.NewOrdemServicoRepository(db)
+	funcionarioRepo := repositories.NewFuncionarioRepository(db)
+	orcamentoRepo := repositories.NewOrcamentoRepository(db)
+	servicoRepo := repositories.NewServicoRepository(db)
+	caixaRepo := repositories.NewCaixaRepository(db)
+	boxRepo := repositories.NewBoxRepository(db)
+	sessaoRepo := repositories.NewSessaoRepository(db)
+	unitOfWork := repositories.NewUnitOfWork(db)
+
+	a := &ambienteTeste{db: db}
+	historicoSenhaRepo := repositories.NewHistoricoSenhaRepository(db)
+	politicaSenha := &utils.PoliticaSenha{TamanhoMinimo: 8, ClassesMinimas: 2, Historico: 3}
+	politicaSenha.Bloquear("senha1234")
+	a.usuario = NewUsuarioService(usuarioRepo, sessaoRepo, historicoSenhaRepo, unitOfWork, politicaSenha)
+	a.sessao = NewSessaoService(sessaoRepo, usuarioRepo, unitOfWork, 15*time.Minute, 7*24*time.Hour)
+	acessoRepo := repositories.NewAcessoRepository(db)
+	a.protecao = NewProtecaoLoginService(acessoRepo, usuarioRepo, unitOfWork, 3, 5, 15*time.Minute)
+	a.mailer = &mailerFalso{}
+	a.redefinicao = NewRedefinicaoSenhaService(repositories.NewRedefinicaoSenhaRepository(db), usuarioRepo, sessaoRepo, acessoRepo, historicoSenhaRepo, unitOfWork, politicaSenha, a.mailer, "https://oficina.test/", 30*time.Minute)
+	a.permissao = NewPermissaoService(repositories.NewPermissaoRepository(db), usuarioRepo)
+	a.funcionario = NewFuncionarioService(funcionarioRepo, usuarioRepo)
+	a.movimentacao = NewMovimentacaoEstoqueService(repositories.NewMovimentacaoEstoqueRepository(db), estoqueRepo, unitOfWork)
+	fornecedorRepo := repositories.NewFornecedorRepository(db)
+	a.estoque = NewEstoqueService(estoqueRepo, fornecedorRepo, a.movimentacao, unitOfWork)
+	a.fornecedor = NewFornecedorService(fornecedorRepo, estoqueRepo)
+	a.compra = NewPedidoCompraService(repositories.NewPedidoCompraRepository(db), estoqueRepo, fornecedorRepo, a.movimentacao, unitOfWork)
+	a.servico = NewServicoService(servicoRepo)
+	a.manutencao = NewManutencaoService(repositories.NewManutencaoRepository(db), veiculoRepo, clienteRepo, servicoRepo, unitOfWork)
+	a.os = NewOrdemServicoService(ordemServicoRepo, veiculoRepo, clienteRepo, estoqueRepo, funcionarioRepo, orcamentoRepo, servicoRepo, a.movimentacao, a.manutencao, a.permissao, unitOfWork)
+	a.orcamento = NewOrcamentoService(orcamentoRepo, ordemServicoRepo, veiculoRepo, clienteRepo, estoqueRepo, servicoRepo, a.os, unitOfWork)
+	a.pagamento = NewPagamentoService(repositories.NewPagamentoRepository(db), ordemServicoRepo, caixaRepo, unitOfWork)
+	a.caixa = NewCaixaService(caixaRepo, a.permissao, unitOfWork)
+	a.agenda = NewAgendaService(boxRepo, repositories.NewFeriadoRepository(db))
+	a.agendamento = NewAgendamentoService(repositories.NewAgendamentoRepository(db), boxRepo, clienteRepo, veiculoRepo, funcionarioRepo, ordemServicoRepo, repositories.NewFeedAgendaRepository(db), a.agenda, a.manutencao, unitOfWork)
+	a.busca = NewBuscaService(repositories.NewBuscaRepository(db), a.permissao)
+
+	if err := a.permissao.SincronizarCatalogo(); err != nil {
+		t.Fatalf("erro ao sincronizar permissões: %v", err)
+	}
+	return a
+}
+
+// criar grava o registro diretamente no banco, sem passar pelas regras dos serviços
+func (a *ambienteTeste) criar(t *testing.T, registro interface{}) {
+	t.Helper()
+	if err := a.db.Create(registro).Error; err != nil {
+		t.Fatalf("erro ao criar %T: %v", registro, err)
+	}
+}
+
+// novoUsuario cria um usuário com o cargo informado
+func (a *ambienteTeste) novoUsuario(t *testing.T, cargo string) *models.Usuario {
+	t.Helper()
+	var total int64
+	a.db.Model(&models.Usuario{}).Count(&total)
+	usuario := &models.Usuario{
+		Nome:  "Usuário " + cargo,
+		Email: fmt.Sprintf("usuario%d@oficina.com", total+1),
+		Cargo: cargo,
+		Ativo: true,
+	}
+	if err := usuario.DefinirSenha("senha123"); err != nil {
+		t.Fatalf("erro ao criptografar a senha: %v", err)
+	}
+	a.criar(t, usuario)
+	return usuario
+}
+
+// novoClienteComVeiculo cria um cliente e um veículo dele
+func (a *ambienteTeste) novoClienteComVeiculo(t *testing.T) (*models.Cliente, *models.Veiculo) {
+	t.Helper()
+	var total int64
+	a.db.Model(&models.Veiculo{}).Count(&total)
+	cliente := &models.Cliente{Nome: "Cliente", TipoPessoa: models.TipoPessoaFisica}
+	a.criar(t, cliente)
+	veiculo := &models.Veiculo{
+		Marca:        "Fiat",
+		Modelo:       "Uno",
+		Placa:        fmt.Sprintf("ABC%04d", total+1),
+		ClienteID:    cliente.ID,
+		OrdemServico: "-",
+	}
+	a.criar(t, veiculo)
+	return cliente, veiculo
+}
+
+// novoMecanico cria um funcionário sem usuário vinculado
+func (a *ambienteTeste) novoMecanico(t *testing.T) *models.Funcionario {
+	t.Helper()
+	var total int64
+	a.db.Model(&models.Funcionario{}).Count(&total)
+	funcionario := &models.Funcionario{
+		Nome:     "Mecânico",
+		Telefone: "11999999999",
+		CPF:      fmt.Sprintf("000.000.000-%02d", total+1),
+		Cargo:    "Mecânico",
+	}
+	a.criar(t, funcionario)
+	return funcionario
+}
+
+// novaPeca cadastra uma peça com o saldo inicial informado, gerando a movimentação de entrada
+func (a *ambienteTeste) novaPeca(t *testing.T, quantidade int, usuarioID *uint) *models.Estoque {
+	t.Helper()
+	var total int64
+	a.db.Model(&models.Estoque{}).Count(&total)
+	peca, err := a.estoque.Criar(&models.Estoque{
+		Nome:          "Filtro de óleo",
+		Codigo:        fmt.Sprintf("FO-%d", total+1),
+		Quantidade:    quantidade,
+		PrecoUnitario: 20,
+		PrecoVenda:    35,
+	}, usuarioID)
+	if err != nil {
+		t.Fatalf("erro ao cadastrar peça: %v", err)
+	}
+	return peca
+}
+
+// novaOS abre uma ordem de serviço para um veículo novo
+func (a *ambienteTeste) novaOS(t *testing.T) *models.OrdemServico {
+	t.Helper()
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	os, err := a.os.Criar(&models.OrdemServico{
+		ClienteID:   cliente.ID,
+		VeiculoID:   veiculo.ID,
+		Descricao:   "Revisão",
+		DataEntrada: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("erro ao abrir OS: %v", err)
+	}
+	return os
+}
+
+// saldoPeca relê a peça do banco
+func (a *ambienteTeste) saldoPeca(t *testing.T, id uint) *models.Estoque {
+	t.Helper()
+	var peca models.Estoque
+	if err := a.db.First(&peca, id).Error; err != nil {
+		t.Fatalf("erro ao buscar peça: %v", err)
+	}
+	return &peca
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// Limites da busca textual
+const (
+	limiteBuscaPadrao = 10
+	limiteBuscaMaximo = 50
+	maximoTermosBusca = 8
+)
+
+// BuscaService define a interface para a busca textual do balcão
+type BuscaService interface {
+	Buscar(consulta string, limite int, usuarioID uint) (*models.ResultadoBusca, error)
+}
+
+// BuscaServiceImpl implementa a interface BuscaService
+type BuscaServiceImpl struct {
+	buscaRepo        repositories.BuscaRepository
+	permissaoService PermissaoService
+}
+
+// NewBuscaService cria uma nova instância do serviço de busca
+func NewBuscaService(buscaRepo repositories.BuscaRepository, permissaoService PermissaoService) BuscaService {
+	return &BuscaServiceImpl{
+		buscaRepo:        buscaRepo,
+		permissaoService: permissaoService,
+	}
+}
+
+// Buscar procura os termos digitados em clientes, veículos e ordens de serviço ao mesmo tempo.
+// Cada grupo só é pesquisado se o usuário puder visualizar aquele tipo de registro
+func (s *BuscaServiceImpl) Buscar(consulta string, limite int, usuarioID uint) (*models.ResultadoBusca, error) {
+	consulta = strings.Join(strings.Fields(consulta), " ")
+	if len([]rune(consulta)) < 2 {
+		return nil, errors.New("digite ao menos 2 caracteres para buscar")
+	}
+
+	termos := strings.Fields(consulta)
+	if len(termos) > maximoTermosBusca {
+		termos = termos[:maximoTermosBusca]
+	}
+
+	if limite <= 0 {
+		limite = limiteBuscaPadrao
+	}
+	if limite > limiteBuscaMaximo {
+		limite = limiteBuscaMaximo
+	}
+
+	resultado := &models.ResultadoBusca{
+		Consulta:      consulta,
+		Clientes:      []models.ClienteEncontrado{},
+		Veiculos:      []models.VeiculoEncontrado{},
+		OrdensServico: []models.OrdemServicoEncontrada{},
+	}
+
+	var err error
+	if s.podeVer(usuarioID, models.PermClientesLer) {
+		if resultado.Clientes, err = s.buscaRepo.BuscarClientes(termos, limite); err != nil {
+			return nil, errors.New("erro ao buscar clientes: " + err.Error())
+		}
+	}
+	if s.podeVer(usuarioID, models.PermVeiculosLer) {
+		if resultado.Veiculos, err = s.buscaRepo.BuscarVeiculos(termos, limite); err != nil {
+			return nil, errors.New("erro ao buscar veículos: " + err.Error())
+		}
+	}
+	if s.podeVer(usuarioID, models.PermOrdensServicoLer) {
+		if resultado.OrdensServico, err = s.buscaRepo.BuscarOrdensServico(termos, limite); err != nil {
+			return nil, errors.New("erro ao buscar ordens de serviço: " + err.Error())
+		}
+	}
+
+	return resultado, nil
+}
+
+// podeVer indica se o usuário tem a permissão de leitura do grupo
+func (s *BuscaServiceImpl) podeVer(usuarioID uint, permissao string) bool {
+	permitido, err := s.permissaoService.UsuarioTemPermissao(usuarioID, permissao)
+	return err == nil && permitido
+}
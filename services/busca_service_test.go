@@ -0,0 +1,164 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+)
+
+// baseDaBusca cadastra dois donos de Gol, com uma OS para cada carro.
+// Fora do MySQL a relevância é um ponto por termo encontrado em alguma coluna
+type baseDaBusca struct {
+	joao, mariaSilva, mariaSouza *models.Cliente
+	golPrata, golBranco          *models.Veiculo
+	osPrata, osBranco            *models.OrdemServico
+}
+
+func (a *ambienteTeste) novaBaseDaBusca(t *testing.T) *baseDaBusca {
+	t.Helper()
+	email := "maria.silva@email.com"
+	b := &baseDaBusca{
+		joao:       &models.Cliente{Nome: "João Prata", TipoPessoa: models.TipoPessoaFisica},
+		mariaSilva: &models.Cliente{Nome: "Maria Silva", Email: &email, TipoPessoa: models.TipoPessoaFisica},
+		mariaSouza: &models.Cliente{Nome: "Maria Souza", TipoPessoa: models.TipoPessoaFisica},
+	}
+	a.criar(t, b.joao)
+	a.criar(t, b.mariaSilva)
+	a.criar(t, b.mariaSouza)
+
+	b.golPrata = &models.Veiculo{Placa: "GOL1A23", Marca: "Volkswagen", Modelo: "Gol", Cor: "Prata", ClienteID: b.joao.ID, OrdemServico: "-"}
+	b.golBranco = &models.Veiculo{Placa: "GOL4B56", Marca: "Volkswagen", Modelo: "Gol", Cor: "Branco", ClienteID: b.mariaSilva.ID, OrdemServico: "-"}
+	a.criar(t, b.golPrata)
+	a.criar(t, b.golBranco)
+
+	var err error
+	b.osBranco, err = a.os.Criar(&models.OrdemServico{ClienteID: b.mariaSilva.ID, VeiculoID: b.golBranco.ID, Descricao: "Troca de óleo", DataEntrada: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("erro ao abrir a OS: %v", err)
+	}
+	b.osPrata, err = a.os.Criar(&models.OrdemServico{ClienteID: b.joao.ID, VeiculoID: b.golPrata.ID, Descricao: "Barulho na suspensão", DataEntrada: time.Now()})
+	if err != nil {
+		t.Fatalf("erro ao abrir a OS: %v", err)
+	}
+	return b
+}
+
+func TestBuscaOrdenaPorRelevancia(t *testing.T) {
+	a := novoAmbiente(t)
+	b := a.novaBaseDaBusca(t)
+	atendente := a.novoUsuario(t, models.CargoAtendente)
+
+	resultado, err := a.busca.Buscar("  maria   silva ", 0, atendente.ID)
+	if err != nil {
+		t.Fatalf("erro ao buscar: %v", err)
+	}
+	if resultado.Consulta != "maria silva" {
+		t.Errorf("consulta = %q, esperado os espaços normalizados", resultado.Consulta)
+	}
+	if len(resultado.Clientes) != 2 || resultado.Clientes[0].Cliente.ID != b.mariaSilva.ID || resultado.Clientes[1].Cliente.ID != b.mariaSouza.ID {
+		t.Fatalf("clientes = %+v, esperado Maria Silva antes de Maria Souza", resultado.Clientes)
+	}
+	if resultado.Clientes[0].Relevancia != 2 || resultado.Clientes[1].Relevancia != 1 {
+		t.Errorf("relevâncias = %.0f e %.0f, esperado 2 e 1", resultado.Clientes[0].Relevancia, resultado.Clientes[1].Relevancia)
+	}
+
+	// O dono soma na relevância do veículo: o Gol prata do João vem antes do Gol da Maria
+	resultado, _ = a.busca.Buscar("gol prata", 0, atendente.ID)
+	if len(resultado.Veiculos) != 2 || resultado.Veiculos[0].Veiculo.ID != b.golPrata.ID {
+		t.Fatalf("veículos = %+v, esperado o Gol prata primeiro", resultado.Veiculos)
+	}
+	if resultado.Veiculos[0].Relevancia != 3 || resultado.Veiculos[1].Relevancia != 1 {
+		t.Errorf("relevâncias = %.0f e %.0f, esperado 3 (gol, prata e o dono) e 1", resultado.Veiculos[0].Relevancia, resultado.Veiculos[1].Relevancia)
+	}
+	if resultado.Veiculos[0].Cliente == nil || resultado.Veiculos[0].Cliente.ID != b.joao.ID {
+		t.Error("o veículo encontrado deveria trazer o dono")
+	}
+	if len(resultado.Clientes) != 1 || resultado.Clientes[0].Cliente.ID != b.joao.ID {
+		t.Errorf("clientes = %d, esperado só o João Prata", len(resultado.Clientes))
+	}
+
+	// A OS casa pela descrição, pelo veículo e pelo cliente ao mesmo tempo
+	resultado, _ = a.busca.Buscar("gol prata joão", 0, atendente.ID)
+	if len(resultado.OrdensServico) != 2 || resultado.OrdensServico[0].OrdemServico.ID != b.osPrata.ID {
+		t.Fatalf("OS = %+v, esperado a do Gol prata primeiro", resultado.OrdensServico)
+	}
+	if resultado.OrdensServico[0].Relevancia != 4 || resultado.OrdensServico[0].OrdemServico.Veiculo.ID != b.golPrata.ID {
+		t.Errorf("relevância = %.0f, esperado 4 com o veículo carregado", resultado.OrdensServico[0].Relevancia)
+	}
+
+	// O número é dividido no hífen: a OS do mesmo dia casa só pela data
+	resultado, _ = a.busca.Buscar(b.osBranco.NumeroOS, 0, atendente.ID)
+	if len(resultado.OrdensServico) == 0 || resultado.OrdensServico[0].OrdemServico.ID != b.osBranco.ID || resultado.OrdensServico[0].Relevancia != 2 {
+		t.Errorf("a busca pelo número %s deveria trazer a própria OS primeiro", b.osBranco.NumeroOS)
+	}
+
+	// Entre OS igualmente relevantes, a mais recente vem primeiro
+	resultado, _ = a.busca.Buscar("volkswagen", 0, atendente.ID)
+	if len(resultado.OrdensServico) != 2 || resultado.OrdensServico[0].OrdemServico.ID != b.osPrata.ID {
+		t.Error("no empate, a OS mais recente deveria vir primeiro")
+	}
+}
+
+func TestBuscaComLikeForaDoMySQL(t *testing.T) {
+	a := novoAmbiente(t)
+	b := a.novaBaseDaBusca(t)
+	atendente := a.novoUsuario(t, models.CargoAtendente)
+
+	// O LIKE ignora maiúsculas e encontra trechos no meio das colunas
+	resultado, err := a.busca.Buscar("SILVA@EMAIL", 0, atendente.ID)
+	if err != nil {
+		t.Fatalf("erro ao buscar: %v", err)
+	}
+	if len(resultado.Clientes) != 1 || resultado.Clientes[0].Cliente.ID != b.mariaSilva.ID {
+		t.Errorf("clientes = %d, esperado a Maria Silva pelo e-mail", len(resultado.Clientes))
+	}
+
+	// Os operadores e curingas digitados são descartados em vez de casar com tudo
+	resultado, _ = a.busca.Buscar("+gol -branco", 0, atendente.ID)
+	if len(resultado.Veiculos) != 2 || resultado.Veiculos[1].Veiculo.ID != b.golPrata.ID {
+		t.Errorf("veículos = %d, esperado os dois Gol com o branco primeiro", len(resultado.Veiculos))
+	}
+	resultado, err = a.busca.Buscar("%% __", 0, atendente.ID)
+	if err != nil || len(resultado.Clientes)+len(resultado.Veiculos)+len(resultado.OrdensServico) != 0 {
+		t.Errorf("só curingas deveria não encontrar nada (err=%v)", err)
+	}
+
+	// Registros excluídos não aparecem
+	a.db.Delete(b.mariaSouza)
+	resultado, _ = a.busca.Buscar("maria", 0, atendente.ID)
+	if len(resultado.Clientes) != 1 {
+		t.Errorf("clientes = %d, esperado só a Maria Silva depois da exclusão", len(resultado.Clientes))
+	}
+}
+
+func TestBuscaValidaConsultaLimiteEPermissoes(t *testing.T) {
+	a := novoAmbiente(t)
+	a.novaBaseDaBusca(t)
+	atendente := a.novoUsuario(t, models.CargoAtendente)
+
+	for _, consulta := range []string{"", " m ", "é"} {
+		if _, err := a.busca.Buscar(consulta, 0, atendente.ID); err == nil {
+			t.Errorf("a consulta %q deveria ser recusada", consulta)
+		}
+	}
+
+	resultado, _ := a.busca.Buscar("gol", 1, atendente.ID)
+	if len(resultado.Veiculos) != 1 || len(resultado.OrdensServico) != 1 {
+		t.Errorf("com limite 1 vieram %d veículos e %d OS", len(resultado.Veiculos), len(resultado.OrdensServico))
+	}
+
+	// Cada grupo só é pesquisado com a permissão de leitura correspondente
+	if _, err := a.permissao.AtualizarCargo(models.CargoMecanico, []string{models.PermVeiculosLer}); err != nil {
+		t.Fatalf("erro ao alterar as permissões do mecânico: %v", err)
+	}
+	mecanico := a.novoUsuario(t, models.CargoMecanico)
+	resultado, err := a.busca.Buscar("maria gol", 0, mecanico.ID)
+	if err != nil {
+		t.Fatalf("erro ao buscar: %v", err)
+	}
+	if len(resultado.Clientes) != 0 || len(resultado.OrdensServico) != 0 || len(resultado.Veiculos) != 2 {
+		t.Errorf("o mecânico viu %d clientes, %d veículos e %d OS; esperado só os veículos",
+			len(resultado.Clientes), len(resultado.Veiculos), len(resultado.OrdensServico))
+	}
+}
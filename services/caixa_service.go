@@ -0,0 +1,391 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// ContagemCaixa é o valor contado pelo operador para uma forma de pagamento no fechamento
+type ContagemCaixa struct {
+	Forma        string  `json:"forma" binding:"required"`
+	ValorContado float64 `json:"valorContado" binding:"min=0"`
+}
+
+// CaixaService define a interface para a abertura, movimentação e fechamento do caixa da recepção
+type CaixaService interface {
+	BuscarTodos(status string) ([]models.Caixa, error)                                                                // Lista os caixas, opcionalmente por status
+	BuscarAtual(usuarioID *uint) (*models.RelatorioCaixa, error)                                                      // Caixa aberto do operador com o resumo parcial
+	BuscarRelatorio(id uint, usuarioID *uint) (*models.RelatorioCaixa, error)                                         // Relatório de conferência de um caixa
+	Abrir(valorInicial float64, observacoes string, usuarioID *uint) (*models.Caixa, error)                           // Abre o caixa do operador com o troco inicial
+	RegistrarSangria(id uint, valor float64, descricao string, usuarioID *uint) (*models.MovimentacaoCaixa, error)    // Retira dinheiro da gaveta
+	RegistrarSuprimento(id uint, valor float64, descricao string, usuarioID *uint) (*models.MovimentacaoCaixa, error) // Reforça o troco da gaveta
+	Fechar(id uint, contagens []ContagemCaixa, observacoes string, usuarioID *uint) (*models.RelatorioCaixa, error)   // Fecha o caixa conferindo os valores contados
+}
+
+// CaixaServiceImpl implementa a interface CaixaService
+type CaixaServiceImpl struct {
+	caixaRepo        repositories.CaixaRepository
+	permissaoService PermissaoService
+	uow              repositories.UnitOfWork
+}
+
+// NewCaixaService cria uma nova instância do serviço de caixa
+func NewCaixaService(
+	caixaRepo repositories.CaixaRepository,
+	permissaoService PermissaoService,
+	uow repositories.UnitOfWork,
+) CaixaService {
+	return &CaixaServiceImpl{
+		caixaRepo:        caixaRepo,
+		permissaoService: permissaoService,
+		uow:              uow,
+	}
+}
+
+// BuscarTodos lista os caixas abertos e fechados
+func (s *CaixaServiceImpl) BuscarTodos(status string) ([]models.Caixa, error) {
+	if status != "" && status != models.StatusCaixaAberto && status != models.StatusCaixaFechado {
+		return nil, errors.New("status de caixa inválido")
+	}
+	return s.caixaRepo.FindAll(status)
+}
+
+// BuscarAtual retorna o caixa aberto do operador autenticado
+func (s *CaixaServiceImpl) BuscarAtual(usuarioID *uint) (*models.RelatorioCaixa, error) {
+	if usuarioID == nil {
+		return nil, errors.New("usuário não identificado")
+	}
+
+	caixa, err := s.caixaRepo.FindAbertoPorUsuario(*usuarioID)
+	if err != nil {
+		return nil, errors.New("nenhum caixa aberto para o usuário")
+	}
+	return s.BuscarRelatorio(caixa.ID, usuarioID)
+}
+
+// BuscarRelatorio monta o relatório do caixa: valores esperados por forma de pagamento e,
+// depois do fechamento, a contagem e as diferenças apuradas
+func (s *CaixaServiceImpl) BuscarRelatorio(id uint, usuarioID *uint) (*models.RelatorioCaixa, error) {
+	caixa, err := s.caixaRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("caixa não encontrado")
+	}
+
+	if err := s.verificarOperador(caixa, usuarioID); err != nil {
+		return nil, err
+	}
+
+	return montarRelatorioCaixa(caixa), nil
+}
+
+// Abrir inicia um turno de caixa para o operador; cada operador tem no máximo um caixa aberto
+func (s *CaixaServiceImpl) Abrir(valorInicial float64, observacoes string, usuarioID *uint) (*models.Caixa, error) {
+	if usuarioID == nil {
+		return nil, errors.New("usuário não identificado")
+	}
+
+	valorInicial = arredondarCentavos(valorInicial)
+	if valorInicial < 0 {
+		return nil, errors.New("o troco inicial não pode ser negativo")
+	}
+
+	caixa := &models.Caixa{
+		UsuarioID:           *usuarioID,
+		Status:              models.StatusCaixaAberto,
+		ValorInicial:        valorInicial,
+		ObservacoesAbertura: strings.TrimSpace(observacoes),
+	}
+
+	// A verificação e a abertura ocorrem na mesma transação, com o operador bloqueado,
+	// para que duas requisições simultâneas não abram dois caixas
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		caixaRepo := s.caixaRepo.WithTx(tx)
+
+		if err := caixaRepo.TravarOperador(*usuarioID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("usuário não encontrado")
+			}
+			return errors.New("erro ao verificar o operador: " + err.Error())
+		}
+
+		aberto, err := caixaRepo.FindAbertoPorUsuarioForUpdate(*usuarioID)
+		if err == nil {
+			return fmt.Errorf("o usuário já possui o caixa %d aberto", aberto.ID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("erro ao verificar caixa aberto: " + err.Error())
+		}
+
+		if err := caixaRepo.Create(caixa); err != nil {
+			return errors.New("erro ao abrir caixa: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return caixa, nil
+}
+
+// RegistrarSangria retira dinheiro da gaveta, limitado ao saldo em dinheiro do caixa
+func (s *CaixaServiceImpl) RegistrarSangria(id uint, valor float64, descricao string, usuarioID *uint) (*models.MovimentacaoCaixa, error) {
+	return s.movimentarDinheiro(id, models.TipoMovimentacaoCaixaSangria, valor, descricao, usuarioID)
+}
+
+// RegistrarSuprimento adiciona dinheiro à gaveta para troco
+func (s *CaixaServiceImpl) RegistrarSuprimento(id uint, valor float64, descricao string, usuarioID *uint) (*models.MovimentacaoCaixa, error) {
+	return s.movimentarDinheiro(id, models.TipoMovimentacaoCaixaSuprimento, valor, descricao, usuarioID)
+}
+
+// Fechar encerra o caixa gravando, para cada forma de pagamento, o valor esperado, o contado e a diferença.
+// Formas movimentadas e não informadas na contagem são consideradas com valor contado zero
+func (s *CaixaServiceImpl) Fechar(id uint, contagens []ContagemCaixa, observacoes string, usuarioID *uint) (*models.RelatorioCaixa, error) {
+	contado := make(map[string]float64)
+	for _, contagem := range contagens {
+		if !isValidFormaPagamento(contagem.Forma) {
+			return nil, fmt.Errorf("forma de pagamento inválida: %s", contagem.Forma)
+		}
+		if contagem.ValorContado < 0 {
+			return nil, errors.New("o valor contado não pode ser negativo")
+		}
+		if _, repetida := contado[contagem.Forma]; repetida {
+			return nil, fmt.Errorf("forma de pagamento informada mais de uma vez: %s", contagem.Forma)
+		}
+		contado[contagem.Forma] = arredondarCentavos(contagem.ValorContado)
+	}
+
+	if err := s.verificarOperadorDoCaixa(id, usuarioID); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		caixaRepo := s.caixaRepo.WithTx(tx)
+
+		caixa, err := caixaRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("caixa não encontrado")
+		}
+		if !caixa.Aberto() {
+			return errors.New("o caixa já está fechado")
+		}
+
+		caixa.Movimentacoes, err = caixaRepo.FindMovimentacoes(caixa.ID)
+		if err != nil {
+			return errors.New("erro ao buscar movimentações do caixa: " + err.Error())
+		}
+
+		esperado := saldosPorForma(caixa)
+		for forma := range contado {
+			if _, ok := esperado[forma]; !ok {
+				esperado[forma] = &models.ResumoFormaCaixa{Forma: forma}
+			}
+		}
+
+		caixa.TotalEsperado, caixa.TotalContado = 0, 0
+		for _, forma := range formasOrdenadas(esperado) {
+			resumo := esperado[forma]
+			conferencia := &models.ConferenciaCaixa{
+				CaixaID:       caixa.ID,
+				Forma:         forma,
+				ValorEsperado: resumo.Esperado,
+				ValorContado:  contado[forma],
+				Diferenca:     arredondarCentavos(contado[forma] - resumo.Esperado),
+			}
+			if err := caixaRepo.AddConferencia(conferencia); err != nil {
+				return errors.New("erro ao registrar conferência do caixa: " + err.Error())
+			}
+			caixa.TotalEsperado += conferencia.ValorEsperado
+			caixa.TotalContado += conferencia.ValorContado
+		}
+
+		agora := time.Now()
+		caixa.Status = models.StatusCaixaFechado
+		caixa.DataFechamento = &agora
+		caixa.FechadoPorID = usuarioID
+		caixa.ObservacoesFechamento = strings.TrimSpace(observacoes)
+		caixa.TotalEsperado = arredondarCentavos(caixa.TotalEsperado)
+		caixa.TotalContado = arredondarCentavos(caixa.TotalContado)
+		caixa.Diferenca = arredondarCentavos(caixa.TotalContado - caixa.TotalEsperado)
+		if err := caixaRepo.Update(caixa); err != nil {
+			return errors.New("erro ao fechar caixa: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.BuscarRelatorio(id, usuarioID)
+}
+
+// movimentarDinheiro registra sangria ou suprimento com o caixa bloqueado
+func (s *CaixaServiceImpl) movimentarDinheiro(id uint, tipo string, valor float64, descricao string, usuarioID *uint) (*models.MovimentacaoCaixa, error) {
+	valor = arredondarCentavos(valor)
+	if valor <= 0 {
+		return nil, errors.New("o valor deve ser maior que zero")
+	}
+
+	movimentacao := &models.MovimentacaoCaixa{
+		CaixaID:   id,
+		Tipo:      tipo,
+		Forma:     models.FormaPagamentoDinheiro,
+		Valor:     valor,
+		UsuarioID: usuarioID,
+		Descricao: strings.TrimSpace(descricao),
+	}
+
+	if err := s.verificarOperadorDoCaixa(id, usuarioID); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		caixaRepo := s.caixaRepo.WithTx(tx)
+
+		caixa, err := caixaRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("caixa não encontrado")
+		}
+		if !caixa.Aberto() {
+			return errors.New("o caixa está fechado")
+		}
+
+		if tipo == models.TipoMovimentacaoCaixaSangria {
+			caixa.Movimentacoes, err = caixaRepo.FindMovimentacoes(caixa.ID)
+			if err != nil {
+				return errors.New("erro ao buscar movimentações do caixa: " + err.Error())
+			}
+			disponivel := 0.0
+			if dinheiro, ok := saldosPorForma(caixa)[models.FormaPagamentoDinheiro]; ok {
+				disponivel = dinheiro.Esperado
+			}
+			if valor > disponivel {
+				return fmt.Errorf("a sangria excede o dinheiro disponível no caixa (%s)", formatarMoeda(disponivel))
+			}
+		}
+
+		if err := caixaRepo.AddMovimentacao(movimentacao); err != nil {
+			return errors.New("erro ao registrar movimentação do caixa: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movimentacao, nil
+}
+
+// verificarOperadorDoCaixa busca o caixa e confere o operador antes de abrir a transação.
+// O dono do caixa não muda, e a consulta de permissões usa outra conexão: feita dentro da transação,
+// ela esperaria para sempre no SQLite em memória, que tem uma única conexão
+func (s *CaixaServiceImpl) verificarOperadorDoCaixa(id uint, usuarioID *uint) error {
+	caixa, err := s.caixaRepo.FindByID(id)
+	if err != nil {
+		return errors.New("caixa não encontrado")
+	}
+	return s.verificarOperador(caixa, usuarioID)
+}
+
+// verificarOperador permite operar o caixa apenas ao próprio operador ou a quem gerencia os caixas
+func (s *CaixaServiceImpl) verificarOperador(caixa *models.Caixa, usuarioID *uint) error {
+	if usuarioID == nil {
+		return errors.New("usuário não identificado")
+	}
+	if caixa.UsuarioID == *usuarioID {
+		return nil
+	}
+
+	permitido, err := s.permissaoService.UsuarioTemPermissao(*usuarioID, models.PermCaixaGerenciar)
+	if err != nil || !permitido {
+		return errors.New("o caixa pertence a outro operador")
+	}
+	return nil
+}
+
+// montarRelatorioCaixa consolida as movimentações e, se houver, a conferência do fechamento
+func montarRelatorioCaixa(caixa *models.Caixa) *models.RelatorioCaixa {
+	resumos := saldosPorForma(caixa)
+	for _, conferencia := range caixa.Conferencias {
+		if _, ok := resumos[conferencia.Forma]; !ok {
+			resumos[conferencia.Forma] = &models.ResumoFormaCaixa{Forma: conferencia.Forma}
+		}
+		contado, diferenca := conferencia.ValorContado, conferencia.Diferenca
+		resumos[conferencia.Forma].Contado = &contado
+		resumos[conferencia.Forma].Diferenca = &diferenca
+	}
+
+	relatorio := &models.RelatorioCaixa{Caixa: *caixa}
+	for _, forma := range formasOrdenadas(resumos) {
+		relatorio.Formas = append(relatorio.Formas, *resumos[forma])
+		relatorio.TotalEsperado += resumos[forma].Esperado
+	}
+	relatorio.TotalEsperado = arredondarCentavos(relatorio.TotalEsperado)
+
+	if !caixa.Aberto() {
+		totalContado, diferenca := caixa.TotalContado, caixa.Diferenca
+		relatorio.TotalContado = &totalContado
+		relatorio.DiferencaTotal = &diferenca
+	}
+	return relatorio
+}
+
+// saldosPorForma calcula o valor esperado de cada forma de pagamento; o troco inicial entra no dinheiro
+func saldosPorForma(caixa *models.Caixa) map[string]*models.ResumoFormaCaixa {
+	resumos := map[string]*models.ResumoFormaCaixa{
+		models.FormaPagamentoDinheiro: {Forma: models.FormaPagamentoDinheiro, Esperado: caixa.ValorInicial},
+	}
+
+	for _, movimentacao := range caixa.Movimentacoes {
+		resumo, ok := resumos[movimentacao.Forma]
+		if !ok {
+			resumo = &models.ResumoFormaCaixa{Forma: movimentacao.Forma}
+			resumos[movimentacao.Forma] = resumo
+		}
+
+		switch movimentacao.Tipo {
+		case models.TipoMovimentacaoCaixaRecebimento:
+			resumo.Recebimentos += movimentacao.Valor
+		case models.TipoMovimentacaoCaixaEstorno:
+			resumo.Estornos += movimentacao.Valor
+		case models.TipoMovimentacaoCaixaSuprimento:
+			resumo.Suprimentos += movimentacao.Valor
+		case models.TipoMovimentacaoCaixaSangria:
+			resumo.Sangrias += movimentacao.Valor
+		}
+		resumo.Esperado += movimentacao.Variacao()
+	}
+
+	for _, resumo := range resumos {
+		resumo.Recebimentos = arredondarCentavos(resumo.Recebimentos)
+		resumo.Estornos = arredondarCentavos(resumo.Estornos)
+		resumo.Suprimentos = arredondarCentavos(resumo.Suprimentos)
+		resumo.Sangrias = arredondarCentavos(resumo.Sangrias)
+		resumo.Esperado = arredondarCentavos(resumo.Esperado)
+	}
+	return resumos
+}
+
+// formasOrdenadas retorna as formas de pagamento do resumo em ordem alfabética, para um relatório estável
+func formasOrdenadas(resumos map[string]*models.ResumoFormaCaixa) []string {
+	formas := make([]string, 0, len(resumos))
+	for forma := range resumos {
+		formas = append(formas, forma)
+	}
+	sort.Strings(formas)
+	return formas
+}
+
+// arredondarCentavos arredonda o valor para duas casas decimais
+func arredondarCentavos(valor float64) float64 {
+	return math.Round(valor*100) / 100
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+// resumoDaForma localiza a linha da forma de pagamento no relatório
+func resumoDaForma(t *testing.T, relatorio *models.RelatorioCaixa, forma string) models.ResumoFormaCaixa {
+	t.Helper()
+	for _, resumo := range relatorio.Formas {
+		if resumo.Forma == forma {
+			return resumo
+		}
+	}
+	t.Fatalf("forma %s ausente no relatório", forma)
+	return models.ResumoFormaCaixa{}
+}
+
+func TestAbrirCaixaUmPorOperador(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+
+	if _, err := a.caixa.Abrir(-1, "", &operador.ID); err == nil {
+		t.Error("o troco inicial negativo deveria ser rejeitado")
+	}
+	semCadastro := uint(9999)
+	if _, err := a.caixa.Abrir(0, "", &semCadastro); err == nil {
+		t.Error("um usuário inexistente não deveria abrir caixa")
+	}
+
+	caixa, err := a.caixa.Abrir(100, " Turno da manhã ", &operador.ID)
+	if err != nil {
+		t.Fatalf("erro ao abrir caixa: %v", err)
+	}
+	if caixa.Status != models.StatusCaixaAberto || caixa.ObservacoesAbertura != "Turno da manhã" {
+		t.Errorf("caixa = %q (%q)", caixa.Status, caixa.ObservacoesAbertura)
+	}
+
+	if _, err := a.caixa.Abrir(50, "", &operador.ID); err == nil {
+		t.Error("o operador não deveria ter dois caixas abertos")
+	}
+
+	// Outro operador abre o seu normalmente
+	outro := a.novoUsuario(t, models.CargoAtendente)
+	if _, err := a.caixa.Abrir(0, "", &outro.ID); err != nil {
+		t.Errorf("erro ao abrir o caixa de outro operador: %v", err)
+	}
+}
+
+func TestFecharCaixaConfereCadaForma(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	caixa, _ := a.caixa.Abrir(100, "", &operador.ID)
+
+	os := a.osComPecas(t)
+	if _, err := a.receber(t, os.ID, 30, operador); err != nil {
+		t.Fatalf("erro ao receber em dinheiro: %v", err)
+	}
+	if _, err := a.pagamento.Registrar(os.ID, &models.Pagamento{Valor: 40, Forma: models.FormaPagamentoPix}, &operador.ID); err != nil {
+		t.Fatalf("erro ao receber no pix: %v", err)
+	}
+	if _, err := a.caixa.RegistrarSuprimento(caixa.ID, 50, "Troco", &operador.ID); err != nil {
+		t.Fatalf("erro no suprimento: %v", err)
+	}
+	if _, err := a.caixa.RegistrarSangria(caixa.ID, 180.01, "Depósito", &operador.ID); err == nil {
+		t.Error("a sangria acima do dinheiro na gaveta deveria ser rejeitada")
+	}
+	if _, err := a.caixa.RegistrarSangria(caixa.ID, 20, "Depósito", &operador.ID); err != nil {
+		t.Fatalf("erro na sangria: %v", err)
+	}
+
+	// Dinheiro esperado: 100 de troco + 30 recebidos + 50 de suprimento - 20 de sangria
+	relatorio, err := a.caixa.Fechar(caixa.ID, []ContagemCaixa{
+		{Forma: models.FormaPagamentoDinheiro, ValorContado: 155},
+		{Forma: models.FormaPagamentoPix, ValorContado: 40},
+		{Forma: models.FormaPagamentoBoleto, ValorContado: 10},
+	}, "", &operador.ID)
+	if err != nil {
+		t.Fatalf("erro ao fechar: %v", err)
+	}
+
+	esperados := []struct {
+		forma                        string
+		esperado, contado, diferenca float64
+	}{
+		{models.FormaPagamentoDinheiro, 160, 155, -5},
+		{models.FormaPagamentoPix, 40, 40, 0},
+		{models.FormaPagamentoBoleto, 0, 10, 10},
+	}
+	for _, e := range esperados {
+		resumo := resumoDaForma(t, relatorio, e.forma)
+		if resumo.Esperado != e.esperado || resumo.Contado == nil || *resumo.Contado != e.contado || *resumo.Diferenca != e.diferenca {
+			t.Errorf("%s: esperado %v, contado %v, diferença %v; queria %v, %v, %v",
+				e.forma, resumo.Esperado, resumo.Contado, resumo.Diferenca, e.esperado, e.contado, e.diferenca)
+		}
+	}
+
+	if relatorio.Caixa.Status != models.StatusCaixaFechado {
+		t.Errorf("status = %q, esperado fechado", relatorio.Caixa.Status)
+	}
+	if relatorio.TotalEsperado != 200 || *relatorio.TotalContado != 205 || *relatorio.DiferencaTotal != 5 {
+		t.Errorf("totais = esperado %v, contado %v, diferença %v; queria 200, 205, 5",
+			relatorio.TotalEsperado, *relatorio.TotalContado, *relatorio.DiferencaTotal)
+	}
+
+	if _, err := a.caixa.Fechar(caixa.ID, nil, "", &operador.ID); err == nil {
+		t.Error("um caixa fechado não deveria ser fechado de novo")
+	}
+	if _, err := a.caixa.Abrir(0, "", &operador.ID); err != nil {
+		t.Errorf("após o fechamento o operador deveria abrir um novo caixa: %v", err)
+	}
+}
+
+func TestFecharCaixaRejeitaContagemInvalida(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	caixa, _ := a.caixa.Abrir(0, "", &operador.ID)
+
+	invalidas := map[string][]ContagemCaixa{
+		"forma desconhecida": {{Forma: "cheque", ValorContado: 1}},
+		"valor negativo":     {{Forma: models.FormaPagamentoDinheiro, ValorContado: -1}},
+		"forma repetida":     {{Forma: models.FormaPagamentoPix}, {Forma: models.FormaPagamentoPix}},
+	}
+	for nome, contagens := range invalidas {
+		if _, err := a.caixa.Fechar(caixa.ID, contagens, "", &operador.ID); err == nil {
+			t.Errorf("%s: a contagem deveria ser rejeitada", nome)
+		}
+	}
+}
+
+func TestCaixaDeOutroOperadorExigeGerencia(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	colega := a.novoUsuario(t, models.CargoAtendente)
+	gerente := a.novoUsuario(t, models.CargoGerente)
+	caixa, _ := a.caixa.Abrir(0, "", &operador.ID)
+
+	if _, err := a.caixa.BuscarRelatorio(caixa.ID, &colega.ID); err == nil {
+		t.Error("o colega não deveria ver o caixa de outro operador")
+	}
+	if _, err := a.caixa.Fechar(caixa.ID, nil, "", &colega.ID); err == nil {
+		t.Error("o colega não deveria fechar o caixa de outro operador")
+	}
+
+	relatorio, err := a.caixa.Fechar(caixa.ID, nil, "Fechado pela gerência", &gerente.ID)
+	if err != nil {
+		t.Fatalf("o gerente deveria fechar o caixa: %v", err)
+	}
+	if relatorio.Caixa.FechadoPorID == nil || *relatorio.Caixa.FechadoPorID != gerente.ID {
+		t.Error("o fechamento deveria registrar o gerente")
+	}
+}
@@ -2,18 +2,22 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"OficinaMecanica/models"
 	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
 )
 
 type ClienteService interface {
-	BuscarTodos() ([]models.Cliente, error)
+	BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Cliente], error)
 	BuscarPorID(id uint) (*models.Cliente, error)
 	Criar(cliente *models.Cliente) (*models.Cliente, error)
 	Atualizar(cliente *models.Cliente) (*models.Cliente, error)
 	Deletar(id uint) error
 	BuscarClienteComVeiculos(id uint) (*models.ClienteVeiculosDTO, error)
+	BuscarPorDocumento(documento string) (*models.Cliente, error)
 }
 
 type ClienteServiceImpl struct {
@@ -26,8 +30,8 @@ func NewClienteService(clienteRepo repositories.ClienteRepositoryGorm) ClienteSe
 	}
 }
 
-func (s *ClienteServiceImpl) BuscarTodos() ([]models.Cliente, error) {
-	return s.clienteRepo.FindAll()
+func (s *ClienteServiceImpl) BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Cliente], error) {
+	return s.clienteRepo.FindPaginado(consulta)
 }
 
 func (s *ClienteServiceImpl) BuscarPorID(id uint) (*models.Cliente, error) {
@@ -39,6 +43,10 @@ func (s *ClienteServiceImpl) BuscarPorID(id uint) (*models.Cliente, error) {
 }
 
 func (s *ClienteServiceImpl) Criar(cliente *models.Cliente) (*models.Cliente, error) {
+	if err := s.validarDados(cliente); err != nil {
+		return nil, err
+	}
+
 	err := s.clienteRepo.Create(cliente)
 	if err != nil {
 		return nil, errors.New("erro ao criar cliente")
@@ -47,7 +55,17 @@ func (s *ClienteServiceImpl) Criar(cliente *models.Cliente) (*models.Cliente, er
 }
 
 func (s *ClienteServiceImpl) Atualizar(cliente *models.Cliente) (*models.Cliente, error) {
-	err := s.clienteRepo.Update(cliente)
+	existente, err := s.clienteRepo.FindByID(cliente.ID)
+	if err != nil {
+		return nil, errors.New("cliente não encontrado")
+	}
+	cliente.CreatedAt = existente.CreatedAt
+
+	if err := s.validarDados(cliente); err != nil {
+		return nil, err
+	}
+
+	err = s.clienteRepo.Update(cliente)
 	if err != nil {
 		return nil, errors.New("erro ao atualizar cliente")
 	}
@@ -78,3 +96,98 @@ func (s *ClienteServiceImpl) BuscarClienteComVeiculos(id uint) (*models.ClienteV
 	return dto, nil
 }
 
+// BuscarPorDocumento busca o cliente pelo CPF ou CNPJ, com ou sem pontuação
+func (s *ClienteServiceImpl) BuscarPorDocumento(documento string) (*models.Cliente, error) {
+	formatado, _, err := formatarDocumento(documento)
+	if err != nil {
+		return nil, err
+	}
+
+	cliente, err := s.clienteRepo.FindByDocumento(formatado)
+	if err != nil || cliente.DeletedAt.Valid {
+		return nil, errors.New("cliente não encontrado")
+	}
+	return cliente, nil
+}
+
+// validarDados normaliza o tipo de pessoa e o documento do cliente e garante que o documento não pertence a outro cadastro.
+// Sem tipo informado, o tipo é deduzido do documento; os dados de empresa só são mantidos para pessoa jurídica
+func (s *ClienteServiceImpl) validarDados(cliente *models.Cliente) error {
+	cliente.Nome = strings.TrimSpace(cliente.Nome)
+	if cliente.Nome == "" {
+		return errors.New("nome do cliente é obrigatório")
+	}
+
+	cliente.TipoPessoa = strings.ToLower(strings.TrimSpace(cliente.TipoPessoa))
+	if cliente.TipoPessoa != "" && cliente.TipoPessoa != models.TipoPessoaFisica && cliente.TipoPessoa != models.TipoPessoaJuridica {
+		return errors.New("tipo de pessoa inválido: use fisica ou juridica")
+	}
+
+	if cliente.Documento != nil && strings.TrimSpace(*cliente.Documento) == "" {
+		cliente.Documento = nil
+	}
+
+	if cliente.Documento != nil {
+		documento, tipo, err := formatarDocumento(*cliente.Documento)
+		if err != nil {
+			return err
+		}
+		if cliente.TipoPessoa != "" && cliente.TipoPessoa != tipo {
+			if tipo == models.TipoPessoaFisica {
+				return errors.New("pessoa jurídica deve ser identificada por CNPJ")
+			}
+			return errors.New("pessoa física deve ser identificada por CPF")
+		}
+		cliente.TipoPessoa = tipo
+		cliente.Documento = &documento
+
+		existente, err := s.clienteRepo.FindByDocumento(documento)
+		if err == nil && existente.ID != cliente.ID {
+			if existente.DeletedAt.Valid {
+				return fmt.Errorf("existe um cliente excluído com este documento (ID %d)", existente.ID)
+			}
+			return fmt.Errorf("documento já cadastrado para o cliente %s (ID %d)", existente.Nome, existente.ID)
+		}
+	}
+
+	if cliente.TipoPessoa == "" {
+		cliente.TipoPessoa = models.TipoPessoaFisica
+	}
+
+	if cliente.TipoPessoa == models.TipoPessoaFisica {
+		cliente.RazaoSocial = ""
+		cliente.InscricaoEstadual = ""
+		return nil
+	}
+
+	cliente.RazaoSocial = strings.Join(strings.Fields(cliente.RazaoSocial), " ")
+	if cliente.RazaoSocial == "" {
+		cliente.RazaoSocial = cliente.Nome
+	}
+
+	inscricao := strings.ToUpper(strings.TrimSpace(cliente.InscricaoEstadual))
+	if inscricao != "" && inscricao != "ISENTO" {
+		inscricao = utils.ApenasDigitos(inscricao)
+		if inscricao == "" || len(inscricao) > 14 {
+			return errors.New("inscrição estadual inválida")
+		}
+	}
+	cliente.InscricaoEstadual = inscricao
+
+	return nil
+}
+
+// formatarDocumento identifica pelo número de dígitos se o documento é CPF ou CNPJ,
+// valida os dígitos verificadores e retorna-o formatado com o tipo de pessoa correspondente
+func formatarDocumento(documento string) (string, string, error) {
+	switch len(utils.ApenasDigitos(documento)) {
+	case 11:
+		cpf, err := utils.ValidarCPF(documento)
+		return cpf, models.TipoPessoaFisica, err
+	case 14:
+		cnpj, err := utils.ValidarCNPJ(documento)
+		return cnpj, models.TipoPessoaJuridica, err
+	default:
+		return "", "", errors.New("documento deve ser um CPF (11 dígitos) ou um CNPJ (14 dígitos)")
+	}
+}
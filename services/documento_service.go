@@ -0,0 +1,433 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// Variantes do documento impresso a partir de uma OS
+const (
+	TipoDocumentoOrcamento = "orcamento"
+	TipoDocumentoOrdem     = "ordem"
+	TipoDocumentoRecibo    = "recibo"
+)
+
+// DocumentoService define a interface para a geração de documentos impressos
+type DocumentoService interface {
+	GerarPDFOrdemServico(osID uint, tipo string) ([]byte, string, error)
+}
+
+// DocumentoServiceImpl implementa a interface DocumentoService usando fpdf (Go puro, sem binários externos)
+type DocumentoServiceImpl struct {
+	osRepo         repositories.OrdemServicoRepository
+	oficinaService OficinaService
+}
+
+// NewDocumentoService cria uma nova instância de DocumentoService
+func NewDocumentoService(osRepo repositories.OrdemServicoRepository, oficinaService OficinaService) DocumentoService {
+	return &DocumentoServiceImpl{
+		osRepo:         osRepo,
+		oficinaService: oficinaService,
+	}
+}
+
+// GerarPDFOrdemServico renderiza a OS no formato pedido e retorna o PDF com o nome sugerido do arquivo
+func (s *DocumentoServiceImpl) GerarPDFOrdemServico(osID uint, tipo string) ([]byte, string, error) {
+	if tipo == "" {
+		tipo = TipoDocumentoOrdem
+	}
+	if tipo != TipoDocumentoOrcamento && tipo != TipoDocumentoOrdem && tipo != TipoDocumentoRecibo {
+		return nil, "", errors.New("tipo de documento inválido; use orcamento, ordem ou recibo")
+	}
+
+	os, err := s.osRepo.FindByID(osID)
+	if err != nil {
+		return nil, "", errors.New("ordem de serviço não encontrada")
+	}
+
+	if tipo == TipoDocumentoRecibo && os.ValorPago <= 0 {
+		return nil, "", errors.New("o recibo só pode ser emitido para uma OS com pagamentos registrados")
+	}
+
+	oficina, err := s.oficinaService.BuscarDados()
+	if err != nil {
+		return nil, "", err
+	}
+
+	conteudo, err := renderizarOrdemServico(models.NovoOrdemServicoDTO(os), oficina, tipo)
+	if err != nil {
+		return nil, "", errors.New("erro ao gerar PDF: " + err.Error())
+	}
+
+	nome := fmt.Sprintf("%s-%s.pdf", tipo, os.NumeroOS)
+	return conteudo, nome, nil
+}
+
+// documentoPDF agrupa o fpdf e o tradutor de caracteres das fontes padrão (cp1252)
+type documentoPDF struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// Larguras úteis da página A4 com margens de 15 mm
+const (
+	larguraPagina = 180.0
+	alturaLinha   = 6.0
+)
+
+// renderizarOrdemServico monta o PDF: cabeçalho da oficina, cliente, veículo, peças,
+// serviços, totais e área de assinatura conforme a variante
+func renderizarOrdemServico(dto models.OrdemServicoDTO, oficina *models.DadosOficina, tipo string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AliasNbPages("")
+
+	d := &documentoPDF{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, d.tr(fmt.Sprintf("Página %d/{nb}", pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	os := dto.OrdemServico
+	d.cabecalho(oficina, tituloDocumento(tipo), os.NumeroOS, dataDocumento(os, tipo))
+
+	if tipo == TipoDocumentoRecibo {
+		d.recibo(dto)
+		return d.saida()
+	}
+
+	d.secao("Cliente")
+	d.campo("Nome", dto.Cliente.Nome)
+	if dto.Cliente.Telefone != nil && *dto.Cliente.Telefone != "" {
+		d.campo("Telefone", *dto.Cliente.Telefone)
+	}
+	if dto.Cliente.Email != nil && *dto.Cliente.Email != "" {
+		d.campo("E-mail", *dto.Cliente.Email)
+	}
+	if dto.Cliente.Endereco != "" {
+		d.campo("Endereço", dto.Cliente.Endereco)
+	}
+
+	d.secao("Veículo")
+	d.campo("Veículo", strings.TrimSpace(fmt.Sprintf("%s %s %s", dto.Veiculo.Marca, dto.Veiculo.Modelo, dto.Veiculo.AnoModelo)))
+	d.campo("Placa", dto.Veiculo.Placa)
+	if dto.Veiculo.Cor != "" {
+		d.campo("Cor", dto.Veiculo.Cor)
+	}
+
+	d.secao("Serviço solicitado")
+	d.texto(os.Descricao)
+	if os.Diagnostico != "" {
+		d.campo("Diagnóstico", os.Diagnostico)
+	}
+	if tipo == TipoDocumentoOrdem {
+		d.campo("Entrada", os.DataEntrada.Format("02/01/2006 15:04"))
+		if !os.DataPrevisao.IsZero() {
+			d.campo("Previsão", os.DataPrevisao.Format("02/01/2006"))
+		}
+		if dto.Funcionario.Nome != "" {
+			d.campo("Responsável", dto.Funcionario.Nome)
+		}
+	}
+
+	if len(dto.ItensUtilizados) > 0 {
+		d.secao("Peças")
+		larguras := []float64{100, 20, 30, 30}
+		d.linhaTabela(larguras, []string{"Descrição", "Qtd.", "Valor unit.", "Total"}, true)
+		for _, item := range dto.ItensUtilizados {
+			d.linhaTabela(larguras, []string{
+				item.Item.Nome,
+				fmt.Sprintf("%d", item.Quantidade),
+				formatarMoeda(item.ValorUnitario),
+				formatarMoeda(item.ValorTotal),
+			}, false)
+		}
+	}
+
+	if len(dto.Servicos) > 0 {
+		d.secao("Serviços")
+		larguras := []float64{100, 20, 30, 30}
+		d.linhaTabela(larguras, []string{"Descrição", "Horas", "Valor hora", "Total"}, true)
+		for _, linha := range dto.Servicos {
+			d.linhaTabela(larguras, []string{
+				linha.Descricao,
+				formatarNumero(linha.Horas),
+				formatarMoeda(linha.ValorHora),
+				formatarMoeda(linha.ValorTotal),
+			}, false)
+		}
+	}
+
+	d.totais(os)
+
+	if os.Observacoes != "" {
+		d.secao("Observações")
+		d.texto(os.Observacoes)
+	}
+
+	switch tipo {
+	case TipoDocumentoOrcamento:
+		d.texto(fmt.Sprintf("Orçamento válido por %d dias a partir da data de emissão.", models.ValidadePadraoOrcamentoDias))
+		d.assinaturas("Aprovação do cliente", "")
+	default:
+		d.assinaturas("Cliente", oficina.Nome)
+	}
+
+	return d.saida()
+}
+
+// cabecalho imprime logo e dados da oficina à esquerda e o título do documento à direita
+func (d *documentoPDF) cabecalho(oficina *models.DadosOficina, titulo, numero string, data time.Time) {
+	pdf := d.pdf
+	xTexto := 15.0
+	if oficina.Logo != "" {
+		if _, err := os.Stat(oficina.Logo); err == nil {
+			pdf.ImageOptions(oficina.Logo, 15, 15, 25, 0, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+			xTexto = 45
+		}
+	}
+
+	pdf.SetXY(xTexto, 15)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(90, 7, d.tr(oficina.Nome), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, linha := range []string{
+		prefixar("CNPJ: ", oficina.CNPJ),
+		oficina.Endereco,
+		strings.Trim(oficina.Telefone+" | "+oficina.Email, " |"),
+	} {
+		if linha != "" {
+			pdf.CellFormat(90, 4.5, d.tr(linha), "", 2, "L", false, 0, "")
+		}
+	}
+
+	pdf.SetXY(135, 15)
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(60, 7, d.tr(titulo), "", 2, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(60, 5, d.tr("Nº "+numero), "", 2, "R", false, 0, "")
+	pdf.CellFormat(60, 5, data.Format("02/01/2006"), "", 2, "R", false, 0, "")
+
+	pdf.SetY(math.Max(pdf.GetY(), 42))
+	pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
+	pdf.Ln(3)
+}
+
+// secao imprime o título de um bloco do documento
+func (d *documentoPDF) secao(titulo string) {
+	d.pdf.Ln(2)
+	d.pdf.SetFont("Helvetica", "B", 11)
+	d.pdf.SetFillColor(230, 230, 230)
+	d.pdf.CellFormat(larguraPagina, 7, d.tr(titulo), "", 1, "L", true, 0, "")
+	d.pdf.Ln(1)
+}
+
+// campo imprime um par rótulo/valor
+func (d *documentoPDF) campo(rotulo, valor string) {
+	d.pdf.SetFont("Helvetica", "B", 10)
+	d.pdf.CellFormat(30, alturaLinha, d.tr(rotulo+":"), "", 0, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(larguraPagina-30, alturaLinha, d.tr(valor), "", "L", false)
+}
+
+// texto imprime um parágrafo livre
+func (d *documentoPDF) texto(conteudo string) {
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(larguraPagina, alturaLinha, d.tr(conteudo), "", "L", false)
+}
+
+// linhaTabela imprime uma linha de tabela; a primeira coluna é texto e as demais são numéricas
+func (d *documentoPDF) linhaTabela(larguras []float64, colunas []string, titulo bool) {
+	estilo := ""
+	if titulo {
+		estilo = "B"
+	}
+	d.pdf.SetFont("Helvetica", estilo, 9)
+	for i, coluna := range colunas {
+		alinhamento := "R"
+		if i == 0 {
+			alinhamento = "L"
+		}
+		d.pdf.CellFormat(larguras[i], alturaLinha, d.tr(coluna), "B", 0, alinhamento, false, 0, "")
+	}
+	d.pdf.Ln(-1)
+}
+
+// totais imprime o resumo de valores da OS
+func (d *documentoPDF) totais(os models.OrdemServico) {
+	d.pdf.Ln(3)
+	linhas := [][2]string{
+		{"Peças", formatarMoeda(os.ValorPecas)},
+		{"Serviços", formatarMoeda(os.ValorServico)},
+	}
+	if os.ValorDesconto > 0 {
+		linhas = append(linhas, [2]string{"Desconto", "- " + formatarMoeda(os.ValorDesconto)})
+	}
+
+	d.pdf.SetFont("Helvetica", "", 10)
+	for _, linha := range linhas {
+		d.pdf.CellFormat(150, alturaLinha, d.tr(linha[0]), "", 0, "R", false, 0, "")
+		d.pdf.CellFormat(30, alturaLinha, d.tr(linha[1]), "", 1, "R", false, 0, "")
+	}
+	d.pdf.SetFont("Helvetica", "B", 11)
+	d.pdf.CellFormat(150, alturaLinha+1, "Total", "", 0, "R", false, 0, "")
+	d.pdf.CellFormat(30, alturaLinha+1, d.tr(formatarMoeda(os.ValorTotal)), "T", 1, "R", false, 0, "")
+}
+
+// recibo imprime a declaração de quitação da OS
+func (d *documentoPDF) recibo(dto models.OrdemServicoDTO) {
+	os := dto.OrdemServico
+	d.pdf.Ln(6)
+	d.texto(fmt.Sprintf(
+		"Recebemos de %s a importância de %s referente aos serviços e peças da ordem de serviço %s, "+
+			"executados no veículo %s %s, placa %s.",
+		dto.Cliente.Nome, formatarMoeda(os.ValorPago), os.NumeroOS,
+		dto.Veiculo.Marca, dto.Veiculo.Modelo, dto.Veiculo.Placa,
+	))
+
+	if len(dto.Pagamentos) > 0 {
+		d.secao("Pagamentos")
+		larguras := []float64{30, 60, 60, 30}
+		d.linhaTabela(larguras, []string{"Data", "Forma", "Detalhes", "Valor"}, true)
+		for _, pagamento := range dto.Pagamentos {
+			detalhes := strings.TrimSpace(pagamento.BandeiraCartao + " " + prefixar("NSU ", pagamento.NSU))
+			if pagamento.Parcelas > 1 {
+				detalhes = strings.TrimSpace(fmt.Sprintf("%s %dx", detalhes, pagamento.Parcelas))
+			}
+			d.linhaTabela(larguras, []string{
+				pagamento.DataPagamento.Format("02/01/2006"),
+				descricaoFormaPagamento(pagamento.Forma),
+				detalhes,
+				formatarMoeda(pagamento.Valor),
+			}, false)
+		}
+	}
+
+	d.totais(os)
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.CellFormat(150, alturaLinha, "Pago", "", 0, "R", false, 0, "")
+	d.pdf.CellFormat(30, alturaLinha, d.tr(formatarMoeda(os.ValorPago)), "", 1, "R", false, 0, "")
+	if saldo := os.CalcularSaldoDevedor(); saldo > 0 {
+		d.pdf.CellFormat(150, alturaLinha, "Saldo em aberto", "", 0, "R", false, 0, "")
+		d.pdf.CellFormat(30, alturaLinha, d.tr(formatarMoeda(saldo)), "", 1, "R", false, 0, "")
+	}
+	d.assinaturas("", "Recebido por")
+}
+
+// assinaturas imprime até duas linhas de assinatura lado a lado no fim do documento
+func (d *documentoPDF) assinaturas(esquerda, direita string) {
+	pdf := d.pdf
+	if pdf.GetY() > 240 {
+		pdf.AddPage()
+	}
+	pdf.SetY(math.Max(pdf.GetY()+20, 250))
+	y := pdf.GetY()
+
+	pdf.SetFont("Helvetica", "", 9)
+	if esquerda != "" {
+		pdf.Line(20, y, 95, y)
+		pdf.SetXY(20, y+1)
+		pdf.CellFormat(75, 5, d.tr(esquerda), "", 0, "C", false, 0, "")
+	}
+	if direita != "" {
+		pdf.Line(115, y, 190, y)
+		pdf.SetXY(115, y+1)
+		pdf.CellFormat(75, 5, d.tr(direita), "", 0, "C", false, 0, "")
+	}
+}
+
+// saida finaliza o documento e retorna os bytes do PDF
+func (d *documentoPDF) saida() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tituloDocumento retorna o título impresso para cada variante
+func tituloDocumento(tipo string) string {
+	switch tipo {
+	case TipoDocumentoOrcamento:
+		return "ORÇAMENTO"
+	case TipoDocumentoRecibo:
+		return "RECIBO"
+	default:
+		return "ORDEM DE SERVIÇO"
+	}
+}
+
+// dataDocumento escolhe a data impressa: conclusão no recibo, emissão no orçamento e entrada na OS
+func dataDocumento(os models.OrdemServico, tipo string) time.Time {
+	switch {
+	case tipo == TipoDocumentoRecibo && os.DataConclusao != nil:
+		return *os.DataConclusao
+	case tipo == TipoDocumentoOrcamento:
+		return time.Now()
+	default:
+		return os.DataEntrada
+	}
+}
+
+// descricaoFormaPagamento retorna o nome impresso de cada forma de pagamento
+func descricaoFormaPagamento(forma string) string {
+	switch forma {
+	case models.FormaPagamentoDinheiro:
+		return "Dinheiro"
+	case models.FormaPagamentoPix:
+		return "PIX"
+	case models.FormaPagamentoCartaoCredito:
+		return "Cartão de crédito"
+	case models.FormaPagamentoCartaoDebito:
+		return "Cartão de débito"
+	case models.FormaPagamentoBoleto:
+		return "Boleto"
+	case models.FormaPagamentoTransferencia:
+		return "Transferência"
+	default:
+		return forma
+	}
+}
+
+// formatarMoeda formata o valor no padrão brasileiro (R$ 1.234,56)
+func formatarMoeda(valor float64) string {
+	sinal := ""
+	if valor < 0 {
+		sinal = "-"
+		valor = -valor
+	}
+
+	centavos := int64(math.Round(valor * 100))
+	inteiro := fmt.Sprintf("%d", centavos/100)
+	for i := len(inteiro) - 3; i > 0; i -= 3 {
+		inteiro = inteiro[:i] + "." + inteiro[i:]
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sinal, inteiro, centavos%100)
+}
+
+// formatarNumero formata um decimal com vírgula e sem zeros à direita (ex.: 1,5)
+func formatarNumero(valor float64) string {
+	texto := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", valor), "0"), ".")
+	return strings.Replace(texto, ".", ",", 1)
+}
+
+// prefixar adiciona o prefixo apenas quando o valor não está vazio
+func prefixar(prefixo, valor string) string {
+	if valor == "" {
+		return ""
+	}
+	return prefixo + valor
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// oficinaFixa devolve sempre os mesmos dados, sem ler o arquivo de configuração
+type oficinaFixa struct {
+	OficinaService
+	dados models.DadosOficina
+}
+
+func (o *oficinaFixa) BuscarDados() (*models.DadosOficina, error) {
+	dados := o.dados
+	return &dados, nil
+}
+
+// novoDocumento monta o serviço de documentos com os dados de oficina informados
+func (a *ambienteTeste) novoDocumento(dados models.DadosOficina) DocumentoService {
+	return NewDocumentoService(repositories.NewOrdemServicoRepository(a.db), &oficinaFixa{dados: dados})
+}
+
+// textoDoPDF descompacta os fluxos do PDF e devolve o conteúdo das páginas, para conferir o que foi impresso
+func textoDoPDF(t *testing.T, conteudo []byte) string {
+	t.Helper()
+	if !bytes.HasPrefix(conteudo, []byte("%PDF-")) || !bytes.Contains(conteudo, []byte("%%EOF")) {
+		t.Fatalf("o conteúdo gerado não é um PDF completo")
+	}
+
+	var texto strings.Builder
+	resto := conteudo
+	for {
+		inicio := bytes.Index(resto, []byte("stream\n"))
+		if inicio < 0 {
+			break
+		}
+		resto = resto[inicio+len("stream\n"):]
+		fim := bytes.Index(resto, []byte("endstream"))
+		if fim < 0 {
+			break
+		}
+		if leitor, err := zlib.NewReader(bytes.NewReader(resto[:fim])); err == nil {
+			dados, _ := io.ReadAll(leitor)
+			texto.Write(dados)
+		}
+		resto = resto[fim+len("endstream"):]
+	}
+	return texto.String()
+}
+
+// conferirTrechos falha para cada trecho esperado que não foi impresso
+func conferirTrechos(t *testing.T, texto string, trechos ...string) {
+	t.Helper()
+	for _, trecho := range trechos {
+		if !strings.Contains(texto, trecho) {
+			t.Errorf("o PDF deveria conter %q", trecho)
+		}
+	}
+}
+
+func TestPDFDaOrdemDeServicoPorVariante(t *testing.T) {
+	a := novoAmbiente(t)
+	documento := a.novoDocumento(models.DadosOficina{Nome: "Auto Center Silva", CNPJ: "11.222.333/0001-81", Telefone: "1133334444"})
+	os := a.osComPecas(t)
+	alinhamento := a.novoServico(t, "Alinhamento", 1.5, 80)
+	if _, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{ServicoID: &alinhamento.ID}); err != nil {
+		t.Fatalf("erro ao lançar o serviço: %v", err)
+	}
+	os, _ = a.os.BuscarPorID(os.ID)
+
+	conteudo, nome, err := documento.GerarPDFOrdemServico(os.ID, "")
+	if err != nil {
+		t.Fatalf("erro ao gerar a OS: %v", err)
+	}
+	if nome != "ordem-"+os.NumeroOS+".pdf" {
+		t.Errorf("nome = %q, esperado a variante ordem por padrão", nome)
+	}
+	conferirTrechos(t, textoDoPDF(t, conteudo),
+		"Auto Center Silva", "CNPJ: 11.222.333/0001-81", os.NumeroOS, os.Veiculo.Placa,
+		"Filtro de", "Alinhamento", "1,5", "R$ 70,00", "R$ 120,00", "R$ 190,00", "Cliente")
+
+	conteudo, nome, err = documento.GerarPDFOrdemServico(os.ID, TipoDocumentoOrcamento)
+	if err != nil {
+		t.Fatalf("erro ao gerar o orçamento: %v", err)
+	}
+	if nome != "orcamento-"+os.NumeroOS+".pdf" {
+		t.Errorf("nome = %q", nome)
+	}
+	conferirTrechos(t, textoDoPDF(t, conteudo), "dias a partir da data de emiss", "R$ 190,00")
+
+	if _, _, err := documento.GerarPDFOrdemServico(os.ID, TipoDocumentoRecibo); err == nil {
+		t.Error("o recibo não deveria ser emitido sem pagamento")
+	}
+	if _, _, err := documento.GerarPDFOrdemServico(os.ID, "nota"); err == nil {
+		t.Error("um tipo de documento desconhecido deveria ser recusado")
+	}
+	if _, _, err := documento.GerarPDFOrdemServico(9999, TipoDocumentoOrdem); err == nil {
+		t.Error("uma OS inexistente deveria ser recusada")
+	}
+
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	if _, err := a.caixa.Abrir(0, "", &operador.ID); err != nil {
+		t.Fatalf("erro ao abrir o caixa: %v", err)
+	}
+	if _, err := a.receber(t, os.ID, 100, operador); err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+	conteudo, _, err = documento.GerarPDFOrdemServico(os.ID, TipoDocumentoRecibo)
+	if err != nil {
+		t.Fatalf("erro ao gerar o recibo: %v", err)
+	}
+	conferirTrechos(t, textoDoPDF(t, conteudo), "RECIBO", "Recebemos de", "R$ 100,00", "Dinheiro", "Saldo em aberto", "R$ 90,00")
+}
+
+func TestPDFComLogoEVariasPaginas(t *testing.T) {
+	a := novoAmbiente(t)
+	ordem := a.novaOS(t)
+	for i := 0; i < 45; i++ {
+		if _, err := a.os.AdicionarServico(ordem.ID, &models.ServicoOrdemServico{Descricao: "Ajuste", Horas: 1, ValorHora: 10}); err != nil {
+			t.Fatalf("erro ao lançar o serviço: %v", err)
+		}
+	}
+
+	logo := filepath.Join(t.TempDir(), "logo.png")
+	imagem := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	imagem.Set(1, 1, color.RGBA{R: 200, A: 255})
+	arquivo, err := os.Create(logo)
+	if err != nil {
+		t.Fatalf("erro ao criar o logo: %v", err)
+	}
+	if err := png.Encode(arquivo, imagem); err != nil {
+		t.Fatalf("erro ao gravar o logo: %v", err)
+	}
+	arquivo.Close()
+
+	conteudo, _, err := a.novoDocumento(models.DadosOficina{Nome: "Oficina", Logo: logo}).GerarPDFOrdemServico(ordem.ID, TipoDocumentoOrdem)
+	if err != nil {
+		t.Fatalf("erro ao gerar com logo: %v", err)
+	}
+	if !bytes.Contains(conteudo, []byte("/Subtype /Image")) {
+		t.Error("o logo da oficina deveria ser incluído no PDF")
+	}
+	texto := textoDoPDF(t, conteudo)
+	conferirTrechos(t, texto, "gina 1/2", "gina 2/2", "R$ 450,00")
+
+	// Um logo que não existe mais é ignorado em vez de impedir a impressão
+	conteudo, _, err = a.novoDocumento(models.DadosOficina{Nome: "Oficina", Logo: logo + ".apagado"}).GerarPDFOrdemServico(ordem.ID, TipoDocumentoOrdem)
+	if err != nil {
+		t.Fatalf("erro ao gerar sem o logo: %v", err)
+	}
+	if bytes.Contains(conteudo, []byte("/Subtype /Image")) {
+		t.Error("sem o arquivo do logo, nenhuma imagem deveria ser incluída")
+	}
+}
@@ -3,14 +3,16 @@ package services
 import (
 	"errors"
 
+	"gorm.io/gorm"
+
 	"OficinaMecanica/models"
 	"OficinaMecanica/repositories"
 )
 
 type EstoqueService interface {
-	BuscarTodos() ([]models.Estoque, error)
+	BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Estoque], error)
 	BuscarPorID(id uint) (*models.Estoque, error)
-	Criar(estoque *models.Estoque) (*models.Estoque, error)
+	Criar(estoque *models.Estoque, usuarioID *uint) (*models.Estoque, error)
 	Atualizar(estoque *models.Estoque) (*models.Estoque, error)
 	Deletar(id uint) error
 	BuscarPorCategoria(categoria string) ([]models.Estoque, error)
@@ -18,17 +20,28 @@ type EstoqueService interface {
 }
 
 type EstoqueServiceImpl struct {
-	estoqueRepo repositories.EstoqueRepository
+	estoqueRepo         repositories.EstoqueRepository
+	fornecedorRepo      repositories.FornecedorRepository
+	movimentacaoService MovimentacaoEstoqueService
+	uow                 repositories.UnitOfWork
 }
 
-func NewEstoqueService(estoqueRepo repositories.EstoqueRepository) EstoqueService {
+func NewEstoqueService(
+	estoqueRepo repositories.EstoqueRepository,
+	fornecedorRepo repositories.FornecedorRepository,
+	movimentacaoService MovimentacaoEstoqueService,
+	uow repositories.UnitOfWork,
+) EstoqueService {
 	return &EstoqueServiceImpl{
-		estoqueRepo: estoqueRepo,
+		estoqueRepo:         estoqueRepo,
+		fornecedorRepo:      fornecedorRepo,
+		movimentacaoService: movimentacaoService,
+		uow:                 uow,
 	}
 }
 
-func (s *EstoqueServiceImpl) BuscarTodos() ([]models.Estoque, error) {
-	return s.estoqueRepo.FindAll()
+func (s *EstoqueServiceImpl) BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Estoque], error) {
+	return s.estoqueRepo.FindPaginado(consulta)
 }
 
 func (s *EstoqueServiceImpl) BuscarPorID(id uint) (*models.Estoque, error) {
@@ -39,7 +52,8 @@ func (s *EstoqueServiceImpl) BuscarPorID(id uint) (*models.Estoque, error) {
 	return item, nil
 }
 
-func (s *EstoqueServiceImpl) Criar(estoque *models.Estoque) (*models.Estoque, error) {
+// Criar cadastra o item com saldo zero e registra a quantidade informada como entrada inicial
+func (s *EstoqueServiceImpl) Criar(estoque *models.Estoque, usuarioID *uint) (*models.Estoque, error) {
 	// Validações antes de criar o item
 	if estoque.Nome == "" {
 		return nil, errors.New("nome do item é obrigatório")
@@ -49,20 +63,58 @@ func (s *EstoqueServiceImpl) Criar(estoque *models.Estoque) (*models.Estoque, er
 		return nil, errors.New("preço de venda não pode ser menor que o preço de custo")
 	}
 
-	err := s.estoqueRepo.Create(estoque)
+	if estoque.Quantidade < 0 {
+		return nil, errors.New("a quantidade não pode ser negativa")
+	}
+
+	if err := s.validarFornecedor(estoque); err != nil {
+		return nil, err
+	}
+
+	quantidadeInicial := estoque.Quantidade
+	estoque.Quantidade = 0
+	estoque.QuantidadeReservada = 0
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		if err := s.estoqueRepo.WithTx(tx).Create(estoque); err != nil {
+			return errors.New("erro ao criar item no estoque")
+		}
+
+		if quantidadeInicial == 0 {
+			return nil
+		}
+
+		_, err := s.movimentacaoService.RegistrarNaTransacao(tx, &models.MovimentacaoEstoque{
+			EstoqueID:  estoque.ID,
+			Tipo:       models.TipoMovimentacaoEntrada,
+			Quantidade: quantidadeInicial,
+			UsuarioID:  usuarioID,
+			Motivo:     "Saldo inicial",
+		})
+		return err
+	})
 	if err != nil {
-		return nil, errors.New("erro ao criar item no estoque")
+		return nil, err
 	}
 
+	estoque.Quantidade = quantidadeInicial
 	return estoque, nil
 }
 
+// Atualizar altera os dados cadastrais do item; a quantidade só muda por movimentações
 func (s *EstoqueServiceImpl) Atualizar(estoque *models.Estoque) (*models.Estoque, error) {
 	// Verificar se o item existe
-	_, err := s.estoqueRepo.FindByID(estoque.ID)
+	existente, err := s.estoqueRepo.FindByID(estoque.ID)
 	if err != nil {
 		return nil, errors.New("item não encontrado")
 	}
+	estoque.Quantidade = existente.Quantidade
+	estoque.QuantidadeReservada = existente.QuantidadeReservada
+	estoque.CriadoEm = existente.CriadoEm
+
+	if err := s.validarFornecedor(estoque); err != nil {
+		return nil, err
+	}
 
 	// Aplicar validações
 	if estoque.Nome == "" {
@@ -117,3 +169,15 @@ func (s *EstoqueServiceImpl) BuscarBaixoEstoque() ([]models.Estoque, error) {
 
 	return itens, nil
 }
+
+// validarFornecedor verifica se o fornecedor principal informado existe
+func (s *EstoqueServiceImpl) validarFornecedor(estoque *models.Estoque) error {
+	estoque.Fornecedor = nil
+	if estoque.FornecedorID == nil {
+		return nil
+	}
+	if _, err := s.fornecedorRepo.FindByID(*estoque.FornecedorID); err != nil {
+		return errors.New("fornecedor não encontrado")
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// FornecedorService define a interface para operações relacionadas a fornecedores
+type FornecedorService interface {
+	BuscarTodos() ([]models.Fornecedor, error)                                // Retorna todos os fornecedores
+	BuscarPorID(id uint) (*models.Fornecedor, error)                          // Busca um fornecedor pelo ID
+	Criar(fornecedor *models.Fornecedor) (*models.Fornecedor, error)          // Cadastra um novo fornecedor
+	Atualizar(fornecedor *models.Fornecedor) (*models.Fornecedor, error)      // Atualiza os dados de um fornecedor
+	Deletar(id uint) error                                                    // Remove um fornecedor (soft delete)
+	BuscarItens(fornecedorID uint) ([]models.FornecedorItem, error)           // Lista as peças com código e preço do fornecedor
+	BuscarFornecedoresDoItem(estoqueID uint) ([]models.FornecedorItem, error) // Lista os fornecedores de uma peça
+	SalvarItem(item *models.FornecedorItem) (*models.FornecedorItem, error)   // Cadastra ou altera o código e o preço de uma peça
+	RemoverItem(fornecedorID uint, estoqueID uint) error                      // Remove a peça do catálogo do fornecedor
+	Relatorio() ([]models.RelatorioFornecedor, error)                         // Resume os itens de estoque por fornecedor
+}
+
+// FornecedorServiceImpl implementa a interface FornecedorService
+type FornecedorServiceImpl struct {
+	fornecedorRepo repositories.FornecedorRepository
+	estoqueRepo    repositories.EstoqueRepository
+}
+
+// NewFornecedorService cria uma nova instância do serviço de fornecedores
+func NewFornecedorService(fornecedorRepo repositories.FornecedorRepository, estoqueRepo repositories.EstoqueRepository) FornecedorService {
+	return &FornecedorServiceImpl{
+		fornecedorRepo: fornecedorRepo,
+		estoqueRepo:    estoqueRepo,
+	}
+}
+
+// BuscarTodos retorna todos os fornecedores não excluídos
+func (s *FornecedorServiceImpl) BuscarTodos() ([]models.Fornecedor, error) {
+	return s.fornecedorRepo.FindAll()
+}
+
+// BuscarPorID busca um fornecedor pelo seu ID
+func (s *FornecedorServiceImpl) BuscarPorID(id uint) (*models.Fornecedor, error) {
+	fornecedor, err := s.fornecedorRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("fornecedor não encontrado")
+	}
+	return fornecedor, nil
+}
+
+// Criar cadastra um novo fornecedor garantindo nome e CNPJ únicos
+func (s *FornecedorServiceImpl) Criar(fornecedor *models.Fornecedor) (*models.Fornecedor, error) {
+	if err := s.validarDados(fornecedor); err != nil {
+		return nil, err
+	}
+
+	if err := s.validarUnicidade(fornecedor); err != nil {
+		return nil, err
+	}
+
+	fornecedor.Ativo = true
+	if err := s.fornecedorRepo.Create(fornecedor); err != nil {
+		return nil, errors.New("erro ao criar fornecedor: " + err.Error())
+	}
+
+	return fornecedor, nil
+}
+
+// Atualizar altera os dados de um fornecedor existente
+func (s *FornecedorServiceImpl) Atualizar(fornecedor *models.Fornecedor) (*models.Fornecedor, error) {
+	existente, err := s.fornecedorRepo.FindByID(fornecedor.ID)
+	if err != nil {
+		return nil, errors.New("fornecedor não encontrado")
+	}
+
+	if err := s.validarDados(fornecedor); err != nil {
+		return nil, err
+	}
+
+	if err := s.validarUnicidade(fornecedor); err != nil {
+		return nil, err
+	}
+
+	fornecedor.CreatedAt = existente.CreatedAt
+	if err := s.fornecedorRepo.Update(fornecedor); err != nil {
+		return nil, errors.New("erro ao atualizar fornecedor: " + err.Error())
+	}
+
+	return fornecedor, nil
+}
+
+// Deletar remove um fornecedor (soft delete); os itens de estoque mantêm o vínculo histórico
+func (s *FornecedorServiceImpl) Deletar(id uint) error {
+	if _, err := s.fornecedorRepo.FindByID(id); err != nil {
+		return errors.New("fornecedor não encontrado")
+	}
+
+	if err := s.fornecedorRepo.Delete(id); err != nil {
+		return errors.New("erro ao excluir fornecedor")
+	}
+	return nil
+}
+
+// BuscarItens lista as peças do fornecedor com seus códigos e preços
+func (s *FornecedorServiceImpl) BuscarItens(fornecedorID uint) ([]models.FornecedorItem, error) {
+	if _, err := s.fornecedorRepo.FindByID(fornecedorID); err != nil {
+		return nil, errors.New("fornecedor não encontrado")
+	}
+	return s.fornecedorRepo.FindItens(fornecedorID)
+}
+
+// BuscarFornecedoresDoItem lista os fornecedores de uma peça, do menor para o maior preço
+func (s *FornecedorServiceImpl) BuscarFornecedoresDoItem(estoqueID uint) ([]models.FornecedorItem, error) {
+	if _, err := s.estoqueRepo.FindByID(estoqueID); err != nil {
+		return nil, errors.New("item não encontrado")
+	}
+	return s.fornecedorRepo.FindItensByEstoqueID(estoqueID)
+}
+
+// SalvarItem cadastra ou altera o código e o preço de uma peça no fornecedor
+func (s *FornecedorServiceImpl) SalvarItem(item *models.FornecedorItem) (*models.FornecedorItem, error) {
+	if _, err := s.fornecedorRepo.FindByID(item.FornecedorID); err != nil {
+		return nil, errors.New("fornecedor não encontrado")
+	}
+
+	if _, err := s.estoqueRepo.FindByID(item.EstoqueID); err != nil {
+		return nil, errors.New("item de estoque não encontrado")
+	}
+
+	if item.Preco < 0 {
+		return nil, errors.New("o preço não pode ser negativo")
+	}
+
+	item.CodigoFornecedor = strings.TrimSpace(item.CodigoFornecedor)
+
+	// Reaproveitar o registro existente para manter o índice único (fornecedor, peça)
+	if existente, err := s.fornecedorRepo.FindItem(item.FornecedorID, item.EstoqueID); err == nil {
+		item.ID = existente.ID
+		item.CreatedAt = existente.CreatedAt
+	} else {
+		item.ID = 0
+	}
+
+	if err := s.fornecedorRepo.SaveItem(item); err != nil {
+		return nil, errors.New("erro ao salvar item do fornecedor: " + err.Error())
+	}
+
+	return item, nil
+}
+
+// RemoverItem retira a peça do catálogo do fornecedor
+func (s *FornecedorServiceImpl) RemoverItem(fornecedorID uint, estoqueID uint) error {
+	if err := s.fornecedorRepo.RemoveItem(fornecedorID, estoqueID); err != nil {
+		return errors.New("item não encontrado no catálogo do fornecedor")
+	}
+	return nil
+}
+
+// Relatorio resume os itens de estoque de cada fornecedor
+func (s *FornecedorServiceImpl) Relatorio() ([]models.RelatorioFornecedor, error) {
+	relatorio, err := s.fornecedorRepo.Relatorio()
+	if err != nil {
+		return nil, errors.New("erro ao gerar relatório de fornecedores")
+	}
+	return relatorio, nil
+}
+
+// validarDados aplica as validações comuns a criação e atualização e normaliza o CNPJ
+func (s *FornecedorServiceImpl) validarDados(fornecedor *models.Fornecedor) error {
+	fornecedor.Nome = strings.Join(strings.Fields(fornecedor.Nome), " ")
+	if fornecedor.Nome == "" {
+		return errors.New("nome do fornecedor é obrigatório")
+	}
+
+	if fornecedor.PrazoEntregaDias < 0 {
+		return errors.New("o prazo de entrega não pode ser negativo")
+	}
+
+	if fornecedor.CNPJ != nil {
+		if strings.TrimSpace(*fornecedor.CNPJ) == "" {
+			fornecedor.CNPJ = nil
+			return nil
+		}
+		cnpj, err := utils.ValidarCNPJ(*fornecedor.CNPJ)
+		if err != nil {
+			return err
+		}
+		fornecedor.CNPJ = &cnpj
+	}
+
+	return nil
+}
+
+// validarUnicidade garante que nome e CNPJ não pertencem a outro fornecedor
+func (s *FornecedorServiceImpl) validarUnicidade(fornecedor *models.Fornecedor) error {
+	if existente, err := s.fornecedorRepo.FindByNome(fornecedor.Nome); err == nil && existente.ID != fornecedor.ID {
+		return fmt.Errorf("já existe um fornecedor com o nome %s", existente.Nome)
+	}
+
+	if fornecedor.CNPJ != nil {
+		if existente, err := s.fornecedorRepo.FindByCNPJ(*fornecedor.CNPJ); err == nil && existente.ID != fornecedor.ID {
+			return errors.New("CNPJ já cadastrado para outro fornecedor")
+		}
+	}
+
+	return nil
+}
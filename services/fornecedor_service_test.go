@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+func TestFornecedorRecusaNomeOuCNPJRepetido(t *testing.T) {
+	a := novoAmbiente(t)
+	cnpj := "11222333000181"
+	bosch, err := a.fornecedor.Criar(&models.Fornecedor{Nome: "  Bosch   Ltda ", CNPJ: &cnpj})
+	if err != nil {
+		t.Fatalf("erro ao cadastrar: %v", err)
+	}
+	if bosch.Nome != "Bosch Ltda" || bosch.CNPJ == nil || *bosch.CNPJ != "11.222.333/0001-81" {
+		t.Errorf("nome %q e CNPJ %v, esperado os espaços e o CNPJ normalizados", bosch.Nome, bosch.CNPJ)
+	}
+
+	formatado := "11.222.333/0001-81"
+	invalido := "11.222.333/0001-82"
+	vazio := " "
+	casos := []struct {
+		nome       string
+		fornecedor models.Fornecedor
+		aceito     bool
+	}{
+		{"nome repetido", models.Fornecedor{Nome: "Bosch Ltda"}, false},
+		{"CNPJ repetido em outro formato", models.Fornecedor{Nome: "Bosch Filial", CNPJ: &formatado}, false},
+		{"CNPJ inválido", models.Fornecedor{Nome: "Marelli", CNPJ: &invalido}, false},
+		{"prazo negativo", models.Fornecedor{Nome: "Marelli", PrazoEntregaDias: -1}, false},
+		{"CNPJ em branco vira vazio", models.Fornecedor{Nome: "Marelli", CNPJ: &vazio}, true},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			fornecedor := caso.fornecedor
+			criado, err := a.fornecedor.Criar(&fornecedor)
+			if (err == nil) != caso.aceito {
+				t.Fatalf("erro = %v, aceito esperado = %v", err, caso.aceito)
+			}
+			if err == nil && criado.CNPJ != nil {
+				t.Errorf("CNPJ = %q, esperado nulo", *criado.CNPJ)
+			}
+		})
+	}
+}
+
+func TestPrecosPorFornecedorERelatorio(t *testing.T) {
+	a := novoAmbiente(t)
+	bosch := a.novoFornecedor(t, "Bosch")
+	marelli := a.novoFornecedor(t, "Marelli")
+	baixa := a.pecaComMinimo(t, 1, 5, &bosch.ID)
+	a.pecaComMinimo(t, 10, 5, &bosch.ID)
+
+	if _, err := a.fornecedor.SalvarItem(&models.FornecedorItem{FornecedorID: bosch.ID, EstoqueID: baixa.ID, CodigoFornecedor: "0 986 AB", Preco: 14}); err != nil {
+		t.Fatalf("erro ao salvar o preço: %v", err)
+	}
+	// Salvar de novo a mesma peça altera o registro em vez de duplicá-lo
+	if _, err := a.fornecedor.SalvarItem(&models.FornecedorItem{FornecedorID: bosch.ID, EstoqueID: baixa.ID, CodigoFornecedor: " 0 986 AC ", Preco: 16}); err != nil {
+		t.Fatalf("erro ao alterar o preço: %v", err)
+	}
+	if _, err := a.fornecedor.SalvarItem(&models.FornecedorItem{FornecedorID: marelli.ID, EstoqueID: baixa.ID, Preco: 12}); err != nil {
+		t.Fatalf("erro ao salvar o preço: %v", err)
+	}
+	if _, err := a.fornecedor.SalvarItem(&models.FornecedorItem{FornecedorID: marelli.ID, EstoqueID: baixa.ID, Preco: -1}); err == nil {
+		t.Error("o preço negativo deveria ser recusado")
+	}
+
+	precos, err := a.fornecedor.BuscarFornecedoresDoItem(baixa.ID)
+	if err != nil {
+		t.Fatalf("erro ao buscar os fornecedores da peça: %v", err)
+	}
+	if len(precos) != 2 || precos[0].FornecedorID != marelli.ID || precos[1].Preco != 16 || precos[1].CodigoFornecedor != "0 986 AC" {
+		t.Errorf("preços = %+v, esperado Marelli (12) antes de Bosch (16, código 0 986 AC)", precos)
+	}
+
+	relatorio, err := a.fornecedor.Relatorio()
+	if err != nil {
+		t.Fatalf("erro ao gerar o relatório: %v", err)
+	}
+	if len(relatorio) != 2 {
+		t.Fatalf("%d linhas no relatório, esperado uma por fornecedor", len(relatorio))
+	}
+	linhaBosch, linhaMarelli := relatorio[0], relatorio[1]
+	if linhaBosch.QuantidadeItens != 2 || linhaBosch.QuantidadeEstoque != 11 || linhaBosch.ValorEstoque != 165 || linhaBosch.ItensBaixoEstoque != 1 {
+		t.Errorf("Bosch = %+v, esperado 2 itens, 11 unidades, 165,00 e 1 abaixo do mínimo", linhaBosch)
+	}
+	if linhaMarelli.QuantidadeItens != 0 || linhaMarelli.ValorEstoque != 0 {
+		t.Errorf("Marelli = %+v, esperado sem itens (os preços não tornam o fornecedor principal)", linhaMarelli)
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// FuncionarioService define a interface para operações relacionadas a funcionários
+type FuncionarioService interface {
+	BuscarTodos() ([]models.Funcionario, error)                             // Retorna todos os funcionários ativos
+	BuscarPorID(id uint) (*models.Funcionario, error)                       // Busca um funcionário pelo ID
+	BuscarPorCargo(cargo string) ([]models.Funcionario, error)              // Lista os funcionários de um cargo
+	BuscarExcluidos() ([]models.Funcionario, error)                         // Lista os funcionários excluídos
+	Criar(funcionario *models.Funcionario) (*models.Funcionario, error)     // Cadastra um novo funcionário
+	Atualizar(funcionario *models.Funcionario) (*models.Funcionario, error) // Atualiza os dados de um funcionário
+	Deletar(id uint) error                                                  // Remove um funcionário (soft delete)
+	Restaurar(id uint) (*models.Funcionario, error)                         // Desfaz a exclusão de um funcionário
+	VincularUsuario(id uint, usuarioID uint) (*models.Funcionario, error)   // Vincula um usuário existente ao funcionário
+	DesvincularUsuario(id uint) (*models.Funcionario, error)                // Remove o vínculo com o usuário
+}
+
+// FuncionarioServiceImpl implementa a interface FuncionarioService
+type FuncionarioServiceImpl struct {
+	funcionarioRepo repositories.FuncionarioRepository
+	usuarioRepo     repositories.UsuarioRepository
+}
+
+// NewFuncionarioService cria uma nova instância do serviço de funcionários
+func NewFuncionarioService(funcionarioRepo repositories.FuncionarioRepository, usuarioRepo repositories.UsuarioRepository) FuncionarioService {
+	return &FuncionarioServiceImpl{
+		funcionarioRepo: funcionarioRepo,
+		usuarioRepo:     usuarioRepo,
+	}
+}
+
+// BuscarTodos retorna todos os funcionários não excluídos
+func (s *FuncionarioServiceImpl) BuscarTodos() ([]models.Funcionario, error) {
+	return s.funcionarioRepo.FindAll()
+}
+
+// BuscarPorID busca um funcionário pelo seu ID
+func (s *FuncionarioServiceImpl) BuscarPorID(id uint) (*models.Funcionario, error) {
+	funcionario, err := s.funcionarioRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("funcionário não encontrado")
+	}
+	return funcionario, nil
+}
+
+// BuscarPorCargo lista os funcionários de um cargo (ex.: mecânico)
+func (s *FuncionarioServiceImpl) BuscarPorCargo(cargo string) ([]models.Funcionario, error) {
+	cargo = models.NormalizarCargo(cargo)
+	if cargo == "" {
+		return nil, errors.New("cargo não pode ser vazio")
+	}
+
+	funcionarios, err := s.funcionarioRepo.FindByCargo(cargo)
+	if err != nil {
+		return nil, errors.New("erro ao buscar funcionários por cargo")
+	}
+	return funcionarios, nil
+}
+
+// BuscarExcluidos lista os funcionários removidos que podem ser restaurados
+func (s *FuncionarioServiceImpl) BuscarExcluidos() ([]models.Funcionario, error) {
+	funcionarios, err := s.funcionarioRepo.FindDeleted()
+	if err != nil {
+		return nil, errors.New("erro ao buscar funcionários excluídos")
+	}
+	return funcionarios, nil
+}
+
+// Criar cadastra um novo funcionário
+// Valida o CPF e, se informado, o usuário a ser vinculado
+func (s *FuncionarioServiceImpl) Criar(funcionario *models.Funcionario) (*models.Funcionario, error) {
+	if err := s.validarDados(funcionario); err != nil {
+		return nil, err
+	}
+
+	// Verifica unicidade do CPF, inclusive entre os excluídos
+	existente, err := s.funcionarioRepo.FindByCPF(funcionario.CPF)
+	if err == nil && existente != nil {
+		if existente.DeletedAt.Valid {
+			return nil, fmt.Errorf("existe um funcionário excluído com este CPF (ID %d); restaure-o em vez de cadastrar novamente", existente.ID)
+		}
+		return nil, errors.New("já existe um funcionário com este CPF")
+	}
+
+	if funcionario.UsuarioID != nil {
+		if err := s.validarUsuarioDisponivel(*funcionario.UsuarioID, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	funcionario.ID = 0
+	funcionario.Usuario = nil
+	err = s.funcionarioRepo.Create(funcionario)
+	if err != nil {
+		return nil, errors.New("erro ao criar funcionário")
+	}
+
+	return s.funcionarioRepo.FindByID(funcionario.ID)
+}
+
+// Atualizar modifica os dados cadastrais de um funcionário
+// O vínculo com usuário é mantido; use VincularUsuario/DesvincularUsuario para alterá-lo
+func (s *FuncionarioServiceImpl) Atualizar(funcionario *models.Funcionario) (*models.Funcionario, error) {
+	atual, err := s.funcionarioRepo.FindByID(funcionario.ID)
+	if err != nil {
+		return nil, errors.New("funcionário não encontrado")
+	}
+
+	if err := s.validarDados(funcionario); err != nil {
+		return nil, err
+	}
+
+	// Verifica se existe outro funcionário com o mesmo CPF
+	existente, err := s.funcionarioRepo.FindByCPF(funcionario.CPF)
+	if err == nil && existente != nil && existente.ID != funcionario.ID {
+		return nil, errors.New("já existe outro funcionário com este CPF")
+	}
+
+	funcionario.UsuarioID = atual.UsuarioID
+	funcionario.Usuario = nil
+	funcionario.CreatedAt = atual.CreatedAt
+
+	err = s.funcionarioRepo.Update(funcionario)
+	if err != nil {
+		return nil, errors.New("erro ao atualizar funcionário")
+	}
+
+	return s.funcionarioRepo.FindByID(funcionario.ID)
+}
+
+// Deletar remove um funcionário (soft delete), preservando o histórico das ordens de serviço
+func (s *FuncionarioServiceImpl) Deletar(id uint) error {
+	_, err := s.funcionarioRepo.FindByID(id)
+	if err != nil {
+		return errors.New("funcionário não encontrado")
+	}
+
+	err = s.funcionarioRepo.Delete(id)
+	if err != nil {
+		return errors.New("erro ao deletar funcionário")
+	}
+
+	return nil
+}
+
+// Restaurar desfaz a exclusão de um funcionário
+func (s *FuncionarioServiceImpl) Restaurar(id uint) (*models.Funcionario, error) {
+	err := s.funcionarioRepo.Restore(id)
+	if err != nil {
+		return nil, errors.New("funcionário excluído não encontrado")
+	}
+
+	return s.funcionarioRepo.FindByID(id)
+}
+
+// VincularUsuario associa um usuário do sistema ao funcionário
+// Cada usuário pode estar vinculado a apenas um funcionário (índice único em usuario_id)
+func (s *FuncionarioServiceImpl) VincularUsuario(id uint, usuarioID uint) (*models.Funcionario, error) {
+	funcionario, err := s.funcionarioRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("funcionário não encontrado")
+	}
+
+	if err := s.validarUsuarioDisponivel(usuarioID, id); err != nil {
+		return nil, err
+	}
+
+	funcionario.UsuarioID = &usuarioID
+	funcionario.Usuario = nil
+	err = s.funcionarioRepo.Update(funcionario)
+	if err != nil {
+		return nil, errors.New("erro ao vincular usuário")
+	}
+
+	return s.funcionarioRepo.FindByID(id)
+}
+
+// DesvincularUsuario remove o vínculo entre o funcionário e seu usuário
+func (s *FuncionarioServiceImpl) DesvincularUsuario(id uint) (*models.Funcionario, error) {
+	funcionario, err := s.funcionarioRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("funcionário não encontrado")
+	}
+
+	if funcionario.UsuarioID == nil {
+		return nil, errors.New("funcionário não possui usuário vinculado")
+	}
+
+	funcionario.UsuarioID = nil
+	funcionario.Usuario = nil
+	err = s.funcionarioRepo.Update(funcionario)
+	if err != nil {
+		return nil, errors.New("erro ao desvincular usuário")
+	}
+
+	return s.funcionarioRepo.FindByID(id)
+}
+
+// validarDados aplica as validações comuns a criação e atualização
+// e normaliza o CPF e o cargo antes de persistir
+func (s *FuncionarioServiceImpl) validarDados(funcionario *models.Funcionario) error {
+	if strings.TrimSpace(funcionario.Nome) == "" {
+		return errors.New("nome do funcionário é obrigatório")
+	}
+
+	if strings.TrimSpace(funcionario.Telefone) == "" {
+		return errors.New("telefone do funcionário é obrigatório")
+	}
+
+	cpf, err := utils.ValidarCPF(funcionario.CPF)
+	if err != nil {
+		return err
+	}
+	funcionario.CPF = cpf
+	funcionario.Cargo = models.NormalizarCargo(funcionario.Cargo)
+
+	return nil
+}
+
+// validarUsuarioDisponivel garante que o usuário existe e não está vinculado a outro funcionário
+func (s *FuncionarioServiceImpl) validarUsuarioDisponivel(usuarioID uint, funcionarioID uint) error {
+	if _, err := s.usuarioRepo.FindByID(usuarioID); err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	vinculado, err := s.funcionarioRepo.FindByUsuarioID(usuarioID)
+	if err == nil && vinculado != nil && vinculado.ID != funcionarioID {
+		if vinculado.DeletedAt.Valid {
+			return fmt.Errorf("usuário já está vinculado ao funcionário excluído %d", vinculado.ID)
+		}
+		return fmt.Errorf("usuário já está vinculado ao funcionário %d", vinculado.ID)
+	}
+
+	return nil
+}
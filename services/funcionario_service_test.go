@@ -0,0 +1,135 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+// novoFuncionario monta um funcionário válido para ser cadastrado pelo serviço
+func novoFuncionario(nome, cpf, cargo string) *models.Funcionario {
+	return &models.Funcionario{Nome: nome, Telefone: "11988887777", CPF: cpf, Cargo: cargo}
+}
+
+func TestCriarFuncionarioValidaENormalizaOCPF(t *testing.T) {
+	a := novoAmbiente(t)
+
+	for _, cpf := range []string{"", "529.982.247-24", "111.111.111-11", "5299822472"} {
+		if _, err := a.funcionario.Criar(novoFuncionario("Carlos", cpf, "Mecânico")); err == nil {
+			t.Errorf("o CPF %q deveria ser recusado", cpf)
+		}
+	}
+	if _, err := a.funcionario.Criar(novoFuncionario(" ", "52998224725", "Mecânico")); err == nil {
+		t.Error("o funcionário sem nome deveria ser recusado")
+	}
+
+	funcionario, err := a.funcionario.Criar(novoFuncionario("Carlos", "52998224725", " Mecânico "))
+	if err != nil {
+		t.Fatalf("erro ao cadastrar: %v", err)
+	}
+	if funcionario.CPF != "529.982.247-25" {
+		t.Errorf("CPF = %q, esperado formatado como 529.982.247-25", funcionario.CPF)
+	}
+	if funcionario.Cargo != models.CargoMecanico {
+		t.Errorf("cargo = %q, esperado %q", funcionario.Cargo, models.CargoMecanico)
+	}
+
+	if _, err := a.funcionario.Criar(novoFuncionario("Outro", "529.982.247-25", "atendente")); err == nil {
+		t.Error("o mesmo CPF não deveria ser cadastrado duas vezes")
+	}
+
+	outro, err := a.funcionario.Criar(novoFuncionario("Beatriz", "111.444.777-35", "Atendente"))
+	if err != nil {
+		t.Fatalf("erro ao cadastrar: %v", err)
+	}
+	outro.CPF = "52998224725"
+	if _, err := a.funcionario.Atualizar(outro); err == nil {
+		t.Error("a atualização não deveria repetir o CPF de outro funcionário")
+	}
+
+	mecanicos, err := a.funcionario.BuscarPorCargo("MECÂNICO")
+	if err != nil || len(mecanicos) != 1 || mecanicos[0].ID != funcionario.ID {
+		t.Errorf("a busca por cargo deveria ignorar acentos e maiúsculas (encontrados %d, err=%v)", len(mecanicos), err)
+	}
+}
+
+func TestExcluirERestaurarFuncionario(t *testing.T) {
+	a := novoAmbiente(t)
+	funcionario, err := a.funcionario.Criar(novoFuncionario("Carlos", "529.982.247-25", "mecanico"))
+	if err != nil {
+		t.Fatalf("erro ao cadastrar: %v", err)
+	}
+
+	if err := a.funcionario.Deletar(funcionario.ID); err != nil {
+		t.Fatalf("erro ao excluir: %v", err)
+	}
+	if _, err := a.funcionario.BuscarPorID(funcionario.ID); err == nil {
+		t.Error("o funcionário excluído não deveria ser encontrado")
+	}
+	if ativos, _ := a.funcionario.BuscarTodos(); len(ativos) != 0 {
+		t.Errorf("%d funcionários ativos, esperado nenhum", len(ativos))
+	}
+	if excluidos, _ := a.funcionario.BuscarExcluidos(); len(excluidos) != 1 {
+		t.Errorf("%d funcionários excluídos, esperado 1", len(excluidos))
+	}
+
+	_, err = a.funcionario.Criar(novoFuncionario("Carlos", "529.982.247-25", "mecanico"))
+	if err == nil || !strings.Contains(err.Error(), "restaure") {
+		t.Errorf("recadastrar o CPF excluído deveria orientar a restauração, erro = %v", err)
+	}
+
+	restaurado, err := a.funcionario.Restaurar(funcionario.ID)
+	if err != nil {
+		t.Fatalf("erro ao restaurar: %v", err)
+	}
+	if restaurado.DeletedAt.Valid {
+		t.Error("o funcionário restaurado não deveria continuar marcado como excluído")
+	}
+	if _, err := a.funcionario.Restaurar(funcionario.ID); err == nil {
+		t.Error("restaurar um funcionário ativo deveria falhar")
+	}
+}
+
+func TestVincularUsuarioAUmFuncionarioPorVez(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoMecanico)
+	carlos, _ := a.funcionario.Criar(novoFuncionario("Carlos", "529.982.247-25", "mecanico"))
+	beatriz, _ := a.funcionario.Criar(novoFuncionario("Beatriz", "111.444.777-35", "mecanico"))
+
+	if _, err := a.funcionario.VincularUsuario(carlos.ID, 9999); err == nil {
+		t.Error("não deveria vincular um usuário inexistente")
+	}
+
+	vinculado, err := a.funcionario.VincularUsuario(carlos.ID, usuario.ID)
+	if err != nil {
+		t.Fatalf("erro ao vincular: %v", err)
+	}
+	if vinculado.UsuarioID == nil || *vinculado.UsuarioID != usuario.ID {
+		t.Fatalf("UsuarioID = %v, esperado %d", vinculado.UsuarioID, usuario.ID)
+	}
+	if _, err := a.funcionario.VincularUsuario(beatriz.ID, usuario.ID); err == nil {
+		t.Error("o usuário já vinculado não deveria ser vinculado a outro funcionário")
+	}
+
+	// A atualização cadastral não mexe no vínculo, mesmo sem o usuário no corpo
+	vinculado.UsuarioID = nil
+	vinculado.Telefone = "11911112222"
+	atualizado, err := a.funcionario.Atualizar(vinculado)
+	if err != nil {
+		t.Fatalf("erro ao atualizar: %v", err)
+	}
+	if atualizado.UsuarioID == nil {
+		t.Error("Atualizar não deveria desfazer o vínculo com o usuário")
+	}
+
+	if _, err := a.funcionario.DesvincularUsuario(carlos.ID); err != nil {
+		t.Fatalf("erro ao desvincular: %v", err)
+	}
+	if _, err := a.funcionario.DesvincularUsuario(carlos.ID); err == nil {
+		t.Error("desvincular um funcionário sem usuário deveria falhar")
+	}
+	if _, err := a.funcionario.VincularUsuario(beatriz.ID, usuario.ID); err != nil {
+		t.Errorf("o usuário liberado deveria poder ser vinculado a outro funcionário: %v", err)
+	}
+}
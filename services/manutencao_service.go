@@ -0,0 +1,460 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// janelaMediaKm limita as leituras usadas no cálculo do uso médio ao último ano,
+// para que mudanças de hábito do cliente reflitam logo na previsão
+const janelaMediaKm = 365 * 24 * time.Hour
+
+// ManutencaoService define a interface para a quilometragem e a manutenção preventiva dos veículos
+type ManutencaoService interface {
+	BuscarLeituras(veiculoID uint) ([]models.LeituraKm, error)
+	RegistrarLeitura(veiculoID uint, km int, data time.Time, usuarioID *uint) (*models.LeituraKm, error)
+	RegistrarLeituraNaTransacao(tx *gorm.DB, leitura *models.LeituraKm) error // Usado na abertura da OS
+	BuscarPlanos(veiculoID uint) ([]models.SituacaoManutencao, error)
+	CriarPlano(plano *models.PlanoManutencao) (*models.PlanoManutencao, error)
+	AtualizarPlano(plano *models.PlanoManutencao) (*models.PlanoManutencao, error)
+	DeletarPlano(veiculoID uint, planoID uint) error
+	RegistrarRealizacao(veiculoID uint, planoID uint, km int, data time.Time) (*models.PlanoManutencao, error)
+	RegistrarRealizacoesNaTransacao(tx *gorm.DB, os *models.OrdemServico) error // Renova os planos atendidos por uma OS concluída
+	BuscarPendentes(dias int) ([]models.SituacaoManutencao, error)              // Planos vencidos ou que vencem nos próximos dias
+}
+
+// ManutencaoServiceImpl implementa a interface ManutencaoService
+type ManutencaoServiceImpl struct {
+	manutencaoRepo repositories.ManutencaoRepository
+	veiculoRepo    repositories.VeiculoRepository
+	clienteRepo    repositories.ClienteRepositoryGorm
+	servicoRepo    repositories.ServicoRepository
+	uow            repositories.UnitOfWork
+}
+
+// NewManutencaoService cria uma nova instância do serviço de manutenção preventiva
+func NewManutencaoService(
+	manutencaoRepo repositories.ManutencaoRepository,
+	veiculoRepo repositories.VeiculoRepository,
+	clienteRepo repositories.ClienteRepositoryGorm,
+	servicoRepo repositories.ServicoRepository,
+	uow repositories.UnitOfWork,
+) ManutencaoService {
+	return &ManutencaoServiceImpl{
+		manutencaoRepo: manutencaoRepo,
+		veiculoRepo:    veiculoRepo,
+		clienteRepo:    clienteRepo,
+		servicoRepo:    servicoRepo,
+		uow:            uow,
+	}
+}
+
+// BuscarLeituras retorna o histórico de quilometragem do veículo
+func (s *ManutencaoServiceImpl) BuscarLeituras(veiculoID uint) ([]models.LeituraKm, error) {
+	if _, err := s.veiculoRepo.FindByID(veiculoID); err != nil {
+		return nil, errors.New("veículo não encontrado")
+	}
+	return s.manutencaoRepo.FindLeituras(veiculoID)
+}
+
+// RegistrarLeitura registra uma leitura de km informada fora de uma OS
+func (s *ManutencaoServiceImpl) RegistrarLeitura(veiculoID uint, km int, data time.Time, usuarioID *uint) (*models.LeituraKm, error) {
+	if data.IsZero() {
+		data = time.Now()
+	}
+	if data.After(time.Now()) {
+		return nil, errors.New("a data da leitura não pode estar no futuro")
+	}
+
+	leitura := &models.LeituraKm{
+		VeiculoID:   veiculoID,
+		Km:          km,
+		DataLeitura: data,
+		Origem:      models.OrigemLeituraKmManual,
+		UsuarioID:   usuarioID,
+	}
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		return s.RegistrarLeituraNaTransacao(tx, leitura)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leitura, nil
+}
+
+// RegistrarLeituraNaTransacao grava a leitura e atualiza o km atual do veículo.
+// O hodômetro não volta: a leitura não pode ser menor que a anterior nem maior que a seguinte
+func (s *ManutencaoServiceImpl) RegistrarLeituraNaTransacao(tx *gorm.DB, leitura *models.LeituraKm) error {
+	manutencaoRepo := s.manutencaoRepo.WithTx(tx)
+
+	if leitura.Km <= 0 {
+		return errors.New("a quilometragem deve ser maior que zero")
+	}
+
+	veiculo, err := s.veiculoRepo.WithTx(tx).FindByID(leitura.VeiculoID)
+	if err != nil {
+		return errors.New("veículo não encontrado")
+	}
+
+	if leitura.DataLeitura.IsZero() {
+		leitura.DataLeitura = time.Now()
+	}
+
+	leituras, err := manutencaoRepo.FindLeituras(veiculo.ID)
+	if err != nil {
+		return errors.New("erro ao buscar leituras de km: " + err.Error())
+	}
+	for _, anterior := range leituras {
+		if !anterior.DataLeitura.After(leitura.DataLeitura) && leitura.Km < anterior.Km {
+			return fmt.Errorf("a quilometragem informada (%d km) é menor que a leitura de %s (%d km)",
+				leitura.Km, anterior.DataLeitura.Format("02/01/2006"), anterior.Km)
+		}
+		if anterior.DataLeitura.After(leitura.DataLeitura) && leitura.Km > anterior.Km {
+			return fmt.Errorf("a quilometragem informada (%d km) é maior que a leitura posterior de %s (%d km)",
+				leitura.Km, anterior.DataLeitura.Format("02/01/2006"), anterior.Km)
+		}
+	}
+
+	leitura.ID = 0
+	if err := manutencaoRepo.CreateLeitura(leitura); err != nil {
+		return errors.New("erro ao registrar leitura de km: " + err.Error())
+	}
+
+	if leitura.Km > veiculo.KmAtual {
+		if err := manutencaoRepo.AtualizarKmVeiculo(veiculo.ID, leitura.Km); err != nil {
+			return errors.New("erro ao atualizar km do veículo: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// BuscarPlanos retorna os planos do veículo com o vencimento de cada um
+func (s *ManutencaoServiceImpl) BuscarPlanos(veiculoID uint) ([]models.SituacaoManutencao, error) {
+	veiculo, err := s.veiculoRepo.FindByID(veiculoID)
+	if err != nil {
+		return nil, errors.New("veículo não encontrado")
+	}
+
+	planos, err := s.manutencaoRepo.FindPlanos(veiculoID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar planos de manutenção: " + err.Error())
+	}
+
+	leituras, err := s.manutencaoRepo.FindLeituras(veiculoID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar leituras de km: " + err.Error())
+	}
+
+	agora := time.Now()
+	situacoes := make([]models.SituacaoManutencao, 0, len(planos))
+	for _, plano := range planos {
+		situacoes = append(situacoes, calcularSituacaoManutencao(plano, veiculo, leituras, agora))
+	}
+	return situacoes, nil
+}
+
+// CriarPlano cadastra um plano; sem última realização informada, o plano começa a contar do km e da data atuais
+func (s *ManutencaoServiceImpl) CriarPlano(plano *models.PlanoManutencao) (*models.PlanoManutencao, error) {
+	veiculo, err := s.veiculoRepo.FindByID(plano.VeiculoID)
+	if err != nil {
+		return nil, errors.New("veículo não encontrado")
+	}
+
+	if err := s.validarPlano(plano); err != nil {
+		return nil, err
+	}
+
+	if plano.UltimaRealizacaoData == nil {
+		agora := time.Now()
+		plano.UltimaRealizacaoData = &agora
+		if plano.UltimaRealizacaoKm == 0 {
+			plano.UltimaRealizacaoKm = veiculo.KmAtual
+		}
+	}
+
+	plano.ID = 0
+	plano.Ativo = true
+	plano.Veiculo = nil
+	if err := s.manutencaoRepo.CreatePlano(plano); err != nil {
+		return nil, errors.New("erro ao criar plano de manutenção: " + err.Error())
+	}
+	return plano, nil
+}
+
+// AtualizarPlano altera descrição, intervalos, serviço vinculado e situação do plano
+func (s *ManutencaoServiceImpl) AtualizarPlano(plano *models.PlanoManutencao) (*models.PlanoManutencao, error) {
+	existente, err := s.manutencaoRepo.FindPlano(plano.VeiculoID, plano.ID)
+	if err != nil {
+		return nil, errors.New("plano de manutenção não encontrado")
+	}
+
+	if err := s.validarPlano(plano); err != nil {
+		return nil, err
+	}
+
+	existente.Descricao = plano.Descricao
+	existente.ServicoID = plano.ServicoID
+	existente.IntervaloKm = plano.IntervaloKm
+	existente.IntervaloMeses = plano.IntervaloMeses
+	existente.Ativo = plano.Ativo
+	existente.Observacoes = plano.Observacoes
+	if plano.UltimaRealizacaoData != nil {
+		existente.UltimaRealizacaoData = plano.UltimaRealizacaoData
+		existente.UltimaRealizacaoKm = plano.UltimaRealizacaoKm
+	}
+
+	if err := s.manutencaoRepo.UpdatePlano(existente); err != nil {
+		return nil, errors.New("erro ao atualizar plano de manutenção: " + err.Error())
+	}
+	return existente, nil
+}
+
+// DeletarPlano remove um plano de manutenção do veículo
+func (s *ManutencaoServiceImpl) DeletarPlano(veiculoID uint, planoID uint) error {
+	if _, err := s.manutencaoRepo.FindPlano(veiculoID, planoID); err != nil {
+		return errors.New("plano de manutenção não encontrado")
+	}
+
+	if err := s.manutencaoRepo.DeletePlano(planoID); err != nil {
+		return errors.New("erro ao excluir plano de manutenção")
+	}
+	return nil
+}
+
+// RegistrarRealizacao renova o plano a partir de uma manutenção feita fora do sistema
+func (s *ManutencaoServiceImpl) RegistrarRealizacao(veiculoID uint, planoID uint, km int, data time.Time) (*models.PlanoManutencao, error) {
+	plano, err := s.manutencaoRepo.FindPlano(veiculoID, planoID)
+	if err != nil {
+		return nil, errors.New("plano de manutenção não encontrado")
+	}
+
+	if km < 0 {
+		return nil, errors.New("a quilometragem não pode ser negativa")
+	}
+	if data.IsZero() {
+		data = time.Now()
+	}
+	if data.After(time.Now()) {
+		return nil, errors.New("a data da realização não pode estar no futuro")
+	}
+
+	plano.UltimaRealizacaoKm = km
+	plano.UltimaRealizacaoData = &data
+	if err := s.manutencaoRepo.UpdatePlano(plano); err != nil {
+		return nil, errors.New("erro ao atualizar plano de manutenção: " + err.Error())
+	}
+	return plano, nil
+}
+
+// RegistrarRealizacoesNaTransacao renova os planos ativos do veículo cujo serviço do catálogo foi executado na OS
+func (s *ManutencaoServiceImpl) RegistrarRealizacoesNaTransacao(tx *gorm.DB, os *models.OrdemServico) error {
+	executados := make(map[uint]bool)
+	for _, linha := range os.Servicos {
+		if linha.ServicoID != nil {
+			executados[*linha.ServicoID] = true
+		}
+	}
+	if len(executados) == 0 {
+		return nil
+	}
+
+	manutencaoRepo := s.manutencaoRepo.WithTx(tx)
+	planos, err := manutencaoRepo.FindPlanos(os.VeiculoID)
+	if err != nil {
+		return errors.New("erro ao buscar planos de manutenção: " + err.Error())
+	}
+
+	data := time.Now()
+	if os.DataConclusao != nil {
+		data = *os.DataConclusao
+	}
+
+	for i := range planos {
+		plano := &planos[i]
+		if !plano.Ativo || plano.ServicoID == nil || !executados[*plano.ServicoID] {
+			continue
+		}
+
+		plano.UltimaRealizacaoData = &data
+		if os.KmEntrada > 0 {
+			plano.UltimaRealizacaoKm = os.KmEntrada
+		}
+		if err := manutencaoRepo.UpdatePlano(plano); err != nil {
+			return errors.New("erro ao atualizar plano de manutenção: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// BuscarPendentes lista os planos vencidos ou com vencimento nos próximos dias, do mais urgente ao menos urgente,
+// com os dados de contato do cliente para as ligações de lembrete
+func (s *ManutencaoServiceImpl) BuscarPendentes(dias int) ([]models.SituacaoManutencao, error) {
+	if dias < 0 {
+		return nil, errors.New("o número de dias não pode ser negativo")
+	}
+
+	planos, err := s.manutencaoRepo.FindPlanosAtivos()
+	if err != nil {
+		return nil, errors.New("erro ao buscar planos de manutenção: " + err.Error())
+	}
+
+	agora := time.Now()
+	limite := agora.AddDate(0, 0, dias)
+	leiturasPorVeiculo := make(map[uint][]models.LeituraKm)
+	clientes := make(map[uint]*models.Cliente)
+
+	pendentes := []models.SituacaoManutencao{}
+	for _, plano := range planos {
+		veiculo := plano.Veiculo
+		if veiculo == nil || veiculo.ID == 0 {
+			continue // Veículo excluído
+		}
+
+		leituras, ok := leiturasPorVeiculo[veiculo.ID]
+		if !ok {
+			leituras, err = s.manutencaoRepo.FindLeituras(veiculo.ID)
+			if err != nil {
+				return nil, errors.New("erro ao buscar leituras de km: " + err.Error())
+			}
+			leiturasPorVeiculo[veiculo.ID] = leituras
+		}
+
+		situacao := calcularSituacaoManutencao(plano, veiculo, leituras, agora)
+		if situacao.Vencimento == nil || situacao.Vencimento.After(limite) {
+			continue
+		}
+
+		cliente, ok := clientes[veiculo.ClienteID]
+		if !ok {
+			cliente, _ = s.clienteRepo.FindByID(veiculo.ClienteID)
+			clientes[veiculo.ClienteID] = cliente
+		}
+		situacao.Cliente = cliente
+		pendentes = append(pendentes, situacao)
+	}
+
+	sort.SliceStable(pendentes, func(i, j int) bool {
+		return pendentes[i].Vencimento.Before(*pendentes[j].Vencimento)
+	})
+	return pendentes, nil
+}
+
+// validarPlano exige a descrição e ao menos um intervalo, e confere o serviço do catálogo
+func (s *ManutencaoServiceImpl) validarPlano(plano *models.PlanoManutencao) error {
+	plano.Descricao = strings.Join(strings.Fields(plano.Descricao), " ")
+	if plano.Descricao == "" {
+		return errors.New("descrição do plano é obrigatória")
+	}
+
+	if plano.IntervaloKm < 0 || plano.IntervaloMeses < 0 {
+		return errors.New("os intervalos não podem ser negativos")
+	}
+	if plano.IntervaloKm == 0 && plano.IntervaloMeses == 0 {
+		return errors.New("informe o intervalo em km, em meses ou ambos")
+	}
+
+	if plano.UltimaRealizacaoKm < 0 {
+		return errors.New("a quilometragem da última realização não pode ser negativa")
+	}
+
+	if plano.ServicoID != nil {
+		if _, err := s.servicoRepo.FindByID(*plano.ServicoID); err != nil {
+			return errors.New("serviço não encontrado")
+		}
+	}
+	return nil
+}
+
+// calcularSituacaoManutencao projeta o vencimento do plano: por km, usando a média diária das leituras,
+// e por tempo, somando o intervalo em meses à última realização
+func calcularSituacaoManutencao(plano models.PlanoManutencao, veiculo *models.Veiculo, leituras []models.LeituraKm, agora time.Time) models.SituacaoManutencao {
+	situacao := models.SituacaoManutencao{
+		Plano:      plano,
+		Veiculo:    veiculo,
+		KmAtual:    veiculo.KmAtual,
+		MediaKmDia: mediaKmDia(leituras),
+	}
+	situacao.Plano.Veiculo = nil
+
+	var vencimentos []time.Time
+
+	if plano.IntervaloKm > 0 {
+		proximaKm := plano.UltimaRealizacaoKm + plano.IntervaloKm
+		situacao.ProximaKm = &proximaKm
+
+		var previsao time.Time
+		switch {
+		case veiculo.KmAtual >= proximaKm:
+			situacao.Vencida = true
+			previsao = agora
+		case situacao.MediaKmDia != nil && *situacao.MediaKmDia > 0:
+			base := agora
+			if len(leituras) > 0 {
+				base = leituras[len(leituras)-1].DataLeitura
+			}
+			diasAteKm := float64(proximaKm-veiculo.KmAtual) / *situacao.MediaKmDia
+			previsao = base.Add(time.Duration(diasAteKm * float64(24*time.Hour)))
+		}
+		if !previsao.IsZero() {
+			situacao.DataPrevistaKm = &previsao
+			vencimentos = append(vencimentos, previsao)
+		}
+	}
+
+	if plano.IntervaloMeses > 0 && plano.UltimaRealizacaoData != nil {
+		proximaData := plano.UltimaRealizacaoData.AddDate(0, plano.IntervaloMeses, 0)
+		situacao.ProximaData = &proximaData
+		vencimentos = append(vencimentos, proximaData)
+	}
+
+	for _, vencimento := range vencimentos {
+		if situacao.Vencimento == nil || vencimento.Before(*situacao.Vencimento) {
+			v := vencimento
+			situacao.Vencimento = &v
+		}
+	}
+
+	if situacao.Vencimento != nil {
+		if !situacao.Vencimento.After(agora) {
+			situacao.Vencida = true
+		}
+		dias := int(math.Floor(situacao.Vencimento.Sub(agora).Hours() / 24))
+		situacao.DiasRestantes = &dias
+	}
+
+	return situacao
+}
+
+// mediaKmDia calcula o uso médio diário a partir das leituras do último ano;
+// retorna nil sem ao menos duas leituras com um dia de diferença
+func mediaKmDia(leituras []models.LeituraKm) *float64 {
+	if len(leituras) < 2 {
+		return nil
+	}
+
+	ultima := leituras[len(leituras)-1]
+	primeira := leituras[0]
+	for _, leitura := range leituras {
+		if ultima.DataLeitura.Sub(leitura.DataLeitura) <= janelaMediaKm {
+			primeira = leitura
+			break
+		}
+	}
+
+	dias := ultima.DataLeitura.Sub(primeira.DataLeitura).Hours() / 24
+	if dias < 1 {
+		return nil
+	}
+
+	media := math.Round(float64(ultima.Km-primeira.Km)/dias*10) / 10
+	return &media
+}
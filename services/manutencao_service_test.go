@@ -0,0 +1,296 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+)
+
+// novoPlano cadastra um plano de manutenção pelo serviço
+func (a *ambienteTeste) novoPlano(t *testing.T, plano models.PlanoManutencao) *models.PlanoManutencao {
+	t.Helper()
+	criado, err := a.manutencao.CriarPlano(&plano)
+	if err != nil {
+		t.Fatalf("erro ao cadastrar o plano %s: %v", plano.Descricao, err)
+	}
+	return criado
+}
+
+// registrarLeitura grava uma leitura de km manual e falha o teste se ela for recusada
+func (a *ambienteTeste) registrarLeitura(t *testing.T, veiculoID uint, km int, data time.Time) {
+	t.Helper()
+	if _, err := a.manutencao.RegistrarLeitura(veiculoID, km, data, nil); err != nil {
+		t.Fatalf("erro ao registrar a leitura de %d km: %v", km, err)
+	}
+}
+
+// kmAtual relê o hodômetro do veículo no banco
+func (a *ambienteTeste) kmAtual(t *testing.T, veiculoID uint) int {
+	t.Helper()
+	var veiculo models.Veiculo
+	if err := a.db.First(&veiculo, veiculoID).Error; err != nil {
+		t.Fatalf("erro ao buscar o veículo: %v", err)
+	}
+	return veiculo.KmAtual
+}
+
+func TestCalcularSituacaoManutencao(t *testing.T) {
+	agora := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	veiculo := &models.Veiculo{ID: 1, KmAtual: 13000}
+	// 3.000 km em 30 dias: 100 km por dia
+	leituras := []models.LeituraKm{
+		{Km: 10000, DataLeitura: agora.AddDate(0, 0, -30)},
+		{Km: 13000, DataLeitura: agora},
+	}
+	data := func(t time.Time) *time.Time { return &t }
+
+	casos := []struct {
+		nome          string
+		plano         models.PlanoManutencao
+		leituras      []models.LeituraKm
+		vencimento    *time.Time
+		diasRestantes int
+		vencida       bool
+	}{
+		{
+			nome:          "km projetado pela média de uso",
+			plano:         models.PlanoManutencao{IntervaloKm: 5000, UltimaRealizacaoKm: 10000},
+			leituras:      leituras,
+			vencimento:    data(agora.AddDate(0, 0, 20)),
+			diasRestantes: 20,
+		},
+		{
+			nome:          "o prazo em meses vence antes do km",
+			plano:         models.PlanoManutencao{IntervaloKm: 5000, IntervaloMeses: 6, UltimaRealizacaoKm: 10000, UltimaRealizacaoData: data(agora.AddDate(0, -6, 10))},
+			leituras:      leituras,
+			vencimento:    data(agora.AddDate(0, -6, 10).AddDate(0, 6, 0)),
+			diasRestantes: 10,
+		},
+		{
+			nome:       "km atual já passou do previsto",
+			plano:      models.PlanoManutencao{IntervaloKm: 5000, UltimaRealizacaoKm: 8000},
+			leituras:   leituras,
+			vencimento: data(agora),
+			vencida:    true,
+		},
+		{
+			nome:          "prazo em meses vencido",
+			plano:         models.PlanoManutencao{IntervaloMeses: 6, UltimaRealizacaoData: data(agora.AddDate(0, -7, 0))},
+			leituras:      leituras,
+			vencimento:    data(agora.AddDate(0, -7, 0).AddDate(0, 6, 0)),
+			diasRestantes: -30,
+			vencida:       true,
+		},
+		{
+			nome:     "sem média de uso não há previsão por km",
+			plano:    models.PlanoManutencao{IntervaloKm: 5000, UltimaRealizacaoKm: 10000},
+			leituras: leituras[1:],
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			situacao := calcularSituacaoManutencao(caso.plano, veiculo, caso.leituras, agora)
+			if situacao.Vencida != caso.vencida {
+				t.Errorf("vencida = %v, esperado %v", situacao.Vencida, caso.vencida)
+			}
+			if caso.vencimento == nil {
+				if situacao.Vencimento != nil || situacao.DiasRestantes != nil {
+					t.Errorf("vencimento = %v, esperado nenhum", situacao.Vencimento)
+				}
+				return
+			}
+			if situacao.Vencimento == nil || !situacao.Vencimento.Equal(*caso.vencimento) {
+				t.Fatalf("vencimento = %v, esperado %v", situacao.Vencimento, *caso.vencimento)
+			}
+			if *situacao.DiasRestantes != caso.diasRestantes {
+				t.Errorf("dias restantes = %d, esperado %d", *situacao.DiasRestantes, caso.diasRestantes)
+			}
+		})
+	}
+}
+
+func TestMediaKmDiaConsideraOUltimoAno(t *testing.T) {
+	agora := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// A leitura de dois anos atrás fica fora da janela: 3.000 km em 300 dias
+	media := mediaKmDia([]models.LeituraKm{
+		{Km: 1000, DataLeitura: agora.AddDate(0, 0, -800)},
+		{Km: 5000, DataLeitura: agora.AddDate(0, 0, -300)},
+		{Km: 8000, DataLeitura: agora},
+	})
+	if media == nil || *media != 10 {
+		t.Errorf("média = %v, esperado 10 km/dia", media)
+	}
+
+	if media := mediaKmDia([]models.LeituraKm{{Km: 1000, DataLeitura: agora}}); media != nil {
+		t.Errorf("com uma leitura a média deveria ser nula, veio %v", *media)
+	}
+	mesmoDia := []models.LeituraKm{
+		{Km: 1000, DataLeitura: agora.Add(-6 * time.Hour)},
+		{Km: 1100, DataLeitura: agora},
+	}
+	if media := mediaKmDia(mesmoDia); media != nil {
+		t.Errorf("com leituras do mesmo dia a média deveria ser nula, veio %v", *media)
+	}
+}
+
+func TestLeiturasDeKmNaoVoltamOHodometro(t *testing.T) {
+	a := novoAmbiente(t)
+	_, veiculo := a.novoClienteComVeiculo(t)
+	agora := time.Now()
+
+	a.registrarLeitura(t, veiculo.ID, 10000, agora.AddDate(0, 0, -30))
+	a.registrarLeitura(t, veiculo.ID, 13000, agora)
+	if km := a.kmAtual(t, veiculo.ID); km != 13000 {
+		t.Errorf("km atual = %d, esperado 13000", km)
+	}
+
+	invalidas := map[string]struct {
+		km   int
+		data time.Time
+	}{
+		"sem quilometragem":            {0, agora},
+		"no futuro":                    {14000, agora.Add(time.Hour)},
+		"menor que a anterior":         {12000, agora},
+		"maior que a leitura seguinte": {14000, agora.AddDate(0, 0, -15)},
+	}
+	for nome, leitura := range invalidas {
+		if _, err := a.manutencao.RegistrarLeitura(veiculo.ID, leitura.km, leitura.data, nil); err == nil {
+			t.Errorf("%s: a leitura deveria ser recusada", nome)
+		}
+	}
+
+	// Uma leitura retroativa entre as duas é aceita e não rebaixa o km atual
+	a.registrarLeitura(t, veiculo.ID, 11500, agora.AddDate(0, 0, -15))
+	if km := a.kmAtual(t, veiculo.ID); km != 13000 {
+		t.Errorf("km atual = %d depois da leitura retroativa, esperado 13000", km)
+	}
+	leituras, err := a.manutencao.BuscarLeituras(veiculo.ID)
+	if err != nil || len(leituras) != 3 || leituras[1].Km != 11500 {
+		t.Errorf("leituras = %+v (err=%v), esperado 3 em ordem de data", leituras, err)
+	}
+}
+
+func TestCriarPlanoValidaOsIntervalos(t *testing.T) {
+	a := novoAmbiente(t)
+	_, veiculo := a.novoClienteComVeiculo(t)
+	a.registrarLeitura(t, veiculo.ID, 42000, time.Now())
+	servicoInexistente := uint(999)
+
+	invalidos := map[string]models.PlanoManutencao{
+		"sem descrição":       {Descricao: " ", IntervaloKm: 5000},
+		"sem intervalo":       {Descricao: "Troca de óleo"},
+		"intervalo negativo":  {Descricao: "Troca de óleo", IntervaloKm: -5000, IntervaloMeses: 6},
+		"serviço inexistente": {Descricao: "Troca de óleo", IntervaloKm: 5000, ServicoID: &servicoInexistente},
+		"última km negativa":  {Descricao: "Troca de óleo", IntervaloKm: 5000, UltimaRealizacaoKm: -1},
+	}
+	for nome, plano := range invalidos {
+		plano.VeiculoID = veiculo.ID
+		if _, err := a.manutencao.CriarPlano(&plano); err == nil {
+			t.Errorf("%s: o plano deveria ser recusado", nome)
+		}
+	}
+
+	// Sem a última realização, o plano começa a contar do km e da data atuais
+	plano := a.novoPlano(t, models.PlanoManutencao{VeiculoID: veiculo.ID, Descricao: "  Troca  de óleo ", IntervaloKm: 5000})
+	if plano.Descricao != "Troca de óleo" || plano.UltimaRealizacaoKm != 42000 || plano.UltimaRealizacaoData == nil {
+		t.Errorf("plano = %q, %d km, %v; esperado a partir de 42000 km e de hoje", plano.Descricao, plano.UltimaRealizacaoKm, plano.UltimaRealizacaoData)
+	}
+}
+
+func TestPendentesListamOsPlanosPorUrgencia(t *testing.T) {
+	a := novoAmbiente(t)
+	agora := time.Now()
+	cliente, carro := a.novoClienteComVeiculo(t)
+	_, outro := a.novoClienteComVeiculo(t)
+
+	// 100 km por dia: a troca de óleo vence em cerca de 20 dias
+	a.registrarLeitura(t, carro.ID, 10000, agora.AddDate(0, 0, -30))
+	a.registrarLeitura(t, carro.ID, 13000, agora)
+	haUmMes := agora.AddDate(0, 0, -30)
+	oleo := a.novoPlano(t, models.PlanoManutencao{VeiculoID: carro.ID, Descricao: "Troca de óleo", IntervaloKm: 5000, UltimaRealizacaoKm: 10000, UltimaRealizacaoData: &haUmMes})
+
+	haSeteMeses := agora.AddDate(0, -7, 0)
+	freios := a.novoPlano(t, models.PlanoManutencao{VeiculoID: outro.ID, Descricao: "Revisão dos freios", IntervaloMeses: 6, UltimaRealizacaoData: &haSeteMeses})
+	a.novoPlano(t, models.PlanoManutencao{VeiculoID: outro.ID, Descricao: "Correia dentada", IntervaloMeses: 12, UltimaRealizacaoData: &haUmMes})
+	inativo := a.novoPlano(t, models.PlanoManutencao{VeiculoID: outro.ID, Descricao: "Arrefecimento", IntervaloMeses: 6, UltimaRealizacaoData: &haSeteMeses})
+	a.db.Model(inativo).UpdateColumn("ativo", false)
+
+	pendentes, err := a.manutencao.BuscarPendentes(30)
+	if err != nil {
+		t.Fatalf("erro ao buscar pendentes: %v", err)
+	}
+	if len(pendentes) != 2 || pendentes[0].Plano.ID != freios.ID || pendentes[1].Plano.ID != oleo.ID {
+		t.Fatalf("pendentes = %d, esperado os freios vencidos e depois a troca de óleo", len(pendentes))
+	}
+	if !pendentes[0].Vencida || pendentes[1].Vencida {
+		t.Error("só os freios deveriam estar vencidos")
+	}
+	if pendentes[1].Cliente == nil || pendentes[1].Cliente.ID != cliente.ID {
+		t.Error("o pendente deveria trazer o cliente para o lembrete")
+	}
+	if pendentes[1].DiasRestantes == nil || *pendentes[1].DiasRestantes < 19 || *pendentes[1].DiasRestantes > 20 {
+		t.Errorf("dias restantes da troca de óleo = %v, esperado cerca de 20", pendentes[1].DiasRestantes)
+	}
+
+	if proximos, _ := a.manutencao.BuscarPendentes(7); len(proximos) != 1 || proximos[0].Plano.ID != freios.ID {
+		t.Errorf("na próxima semana = %d pendentes, esperado só os freios", len(proximos))
+	}
+	if _, err := a.manutencao.BuscarPendentes(-1); err == nil {
+		t.Error("um número de dias negativo deveria ser recusado")
+	}
+
+	// A realização renova o plano e o tira da lista
+	if _, err := a.manutencao.RegistrarRealizacao(carro.ID, oleo.ID, 13000, agora.Add(time.Hour)); err == nil {
+		t.Error("uma realização no futuro deveria ser recusada")
+	}
+	if _, err := a.manutencao.RegistrarRealizacao(carro.ID, oleo.ID, 13000, agora); err != nil {
+		t.Fatalf("erro ao registrar a realização: %v", err)
+	}
+	if pendentes, _ := a.manutencao.BuscarPendentes(30); len(pendentes) != 1 {
+		t.Errorf("pendentes = %d depois da troca de óleo, esperado 1", len(pendentes))
+	}
+}
+
+func TestConcluirOSRenovaOPlanoDoServico(t *testing.T) {
+	a := novoAmbiente(t)
+	oleo := a.novoServico(t, "Troca de óleo", 0.5, 60)
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	haUmAno := time.Now().AddDate(-1, 0, 0)
+	renovado := a.novoPlano(t, models.PlanoManutencao{VeiculoID: veiculo.ID, Descricao: "Troca de óleo", ServicoID: &oleo.ID, IntervaloKm: 10000, UltimaRealizacaoKm: 1000, UltimaRealizacaoData: &haUmAno})
+	mantido := a.novoPlano(t, models.PlanoManutencao{VeiculoID: veiculo.ID, Descricao: "Revisão geral", IntervaloMeses: 12, UltimaRealizacaoKm: 1000, UltimaRealizacaoData: &haUmAno})
+
+	// O km de entrada da OS vira uma leitura do veículo
+	os, err := a.os.Criar(&models.OrdemServico{ClienteID: cliente.ID, VeiculoID: veiculo.ID, Descricao: "Troca de óleo", DataEntrada: time.Now(), KmEntrada: 20000})
+	if err != nil {
+		t.Fatalf("erro ao abrir a OS: %v", err)
+	}
+	leituras, _ := a.manutencao.BuscarLeituras(veiculo.ID)
+	if len(leituras) != 1 || leituras[0].Origem != models.OrigemLeituraKmOrdemServico || leituras[0].OrdemServicoID == nil || *leituras[0].OrdemServicoID != os.ID {
+		t.Fatalf("leituras = %+v, esperado uma leitura vinda da OS", leituras)
+	}
+	if km := a.kmAtual(t, veiculo.ID); km != 20000 {
+		t.Errorf("km atual = %d, esperado 20000", km)
+	}
+
+	if _, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{ServicoID: &oleo.ID}); err != nil {
+		t.Fatalf("erro ao lançar o serviço: %v", err)
+	}
+	a.forcarStatus(t, os.ID, "emandamento")
+	if _, err := a.os.ConcluirOS(os.ID); err != nil {
+		t.Fatalf("erro ao concluir a OS: %v", err)
+	}
+
+	var plano models.PlanoManutencao
+	a.db.First(&plano, renovado.ID)
+	if plano.UltimaRealizacaoKm != 20000 || plano.UltimaRealizacaoData == nil || time.Since(*plano.UltimaRealizacaoData) > time.Minute {
+		t.Errorf("plano renovado em %d km, %v; esperado 20000 km e agora", plano.UltimaRealizacaoKm, plano.UltimaRealizacaoData)
+	}
+	var outro models.PlanoManutencao
+	a.db.First(&outro, mantido.ID)
+	if outro.UltimaRealizacaoKm != 1000 || outro.UltimaRealizacaoData.After(haUmAno.Add(time.Minute)) {
+		t.Error("um plano sem o serviço executado não deveria ser renovado")
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// MovimentacaoEstoqueService é o único caminho autorizado a alterar a quantidade de um item do estoque.
+// Toda alteração gera uma movimentação com o saldo anterior e o posterior.
+type MovimentacaoEstoqueService interface {
+	Registrar(movimentacao *models.MovimentacaoEstoque) (*models.MovimentacaoEstoque, error)
+	RegistrarNaTransacao(tx *gorm.DB, movimentacao *models.MovimentacaoEstoque) (*models.MovimentacaoEstoque, error)
+	AjustarSaldo(estoqueID uint, novoSaldo int, usuarioID *uint, motivo string) (*models.MovimentacaoEstoque, error)
+	BuscarPorEstoque(estoqueID uint, inicio, fim *time.Time) ([]models.MovimentacaoEstoque, error)
+}
+
+// MovimentacaoEstoqueServiceImpl implementa a interface MovimentacaoEstoqueService
+type MovimentacaoEstoqueServiceImpl struct {
+	movimentacaoRepo repositories.MovimentacaoEstoqueRepository
+	estoqueRepo      repositories.EstoqueRepository
+	uow              repositories.UnitOfWork
+}
+
+// NewMovimentacaoEstoqueService cria uma nova instância de MovimentacaoEstoqueService
+func NewMovimentacaoEstoqueService(
+	movimentacaoRepo repositories.MovimentacaoEstoqueRepository,
+	estoqueRepo repositories.EstoqueRepository,
+	uow repositories.UnitOfWork,
+) MovimentacaoEstoqueService {
+	return &MovimentacaoEstoqueServiceImpl{
+		movimentacaoRepo: movimentacaoRepo,
+		estoqueRepo:      estoqueRepo,
+		uow:              uow,
+	}
+}
+
+// Registrar aplica a movimentação em uma transação própria
+func (s *MovimentacaoEstoqueServiceImpl) Registrar(movimentacao *models.MovimentacaoEstoque) (*models.MovimentacaoEstoque, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		_, err := s.RegistrarNaTransacao(tx, movimentacao)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movimentacao, nil
+}
+
+// RegistrarNaTransacao aplica a movimentação dentro de uma transação já aberta pelo chamador,
+// bloqueando a linha do item para que o saldo lido seja o mesmo gravado
+func (s *MovimentacaoEstoqueServiceImpl) RegistrarNaTransacao(tx *gorm.DB, movimentacao *models.MovimentacaoEstoque) (*models.MovimentacaoEstoque, error) {
+	if err := validarMovimentacao(movimentacao); err != nil {
+		return nil, err
+	}
+
+	estoqueRepo := s.estoqueRepo.WithTx(tx)
+	movimentacaoRepo := s.movimentacaoRepo.WithTx(tx)
+
+	// Bloquear o item até o fim da transação
+	item, err := estoqueRepo.FindByIDForUpdate(movimentacao.EstoqueID)
+	if err != nil {
+		return nil, errors.New("item de estoque não encontrado")
+	}
+
+	novoSaldo := item.Quantidade + movimentacao.Variacao()
+	if novoSaldo < 0 {
+		return nil, errors.New("quantidade insuficiente em estoque")
+	}
+
+	// Saídas não podem consumir unidades reservadas por orçamentos; ajustes de inventário podem
+	if movimentacao.Tipo == models.TipoMovimentacaoSaida && novoSaldo < item.QuantidadeReservada {
+		return nil, errors.New("quantidade insuficiente em estoque: há unidades reservadas em orçamentos")
+	}
+
+	// Sem custo informado, usa o custo atual do item
+	if movimentacao.CustoUnitario <= 0 {
+		movimentacao.CustoUnitario = item.PrecoUnitario
+	}
+	movimentacao.SaldoAnterior = item.Quantidade
+	movimentacao.SaldoPosterior = novoSaldo
+
+	if err := estoqueRepo.UpdateQuantidade(item.ID, novoSaldo); err != nil {
+		return nil, errors.New("erro ao atualizar estoque: " + err.Error())
+	}
+
+	if err := movimentacaoRepo.Create(movimentacao); err != nil {
+		return nil, errors.New("erro ao registrar movimentação: " + err.Error())
+	}
+
+	return movimentacao, nil
+}
+
+// AjustarSaldo registra um ajuste de inventário que leva o item ao saldo informado
+func (s *MovimentacaoEstoqueServiceImpl) AjustarSaldo(estoqueID uint, novoSaldo int, usuarioID *uint, motivo string) (*models.MovimentacaoEstoque, error) {
+	if novoSaldo < 0 {
+		return nil, errors.New("a quantidade não pode ser negativa")
+	}
+
+	var movimentacao *models.MovimentacaoEstoque
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		// Ler o saldo atual já bloqueado para calcular a diferença
+		item, err := s.estoqueRepo.WithTx(tx).FindByIDForUpdate(estoqueID)
+		if err != nil {
+			return errors.New("item de estoque não encontrado")
+		}
+
+		if motivo == "" {
+			motivo = "Ajuste de inventário"
+		}
+
+		movimentacao, err = s.RegistrarNaTransacao(tx, &models.MovimentacaoEstoque{
+			EstoqueID:  estoqueID,
+			Tipo:       models.TipoMovimentacaoAjuste,
+			Quantidade: novoSaldo - item.Quantidade,
+			UsuarioID:  usuarioID,
+			Motivo:     motivo,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movimentacao, nil
+}
+
+// BuscarPorEstoque retorna o histórico de movimentações de um item
+func (s *MovimentacaoEstoqueServiceImpl) BuscarPorEstoque(estoqueID uint, inicio, fim *time.Time) ([]models.MovimentacaoEstoque, error) {
+	if _, err := s.estoqueRepo.FindByID(estoqueID); err != nil {
+		return nil, errors.New("item não encontrado")
+	}
+
+	if inicio != nil && fim != nil && fim.Before(*inicio) {
+		return nil, errors.New("a data final deve ser posterior à data inicial")
+	}
+
+	movimentacoes, err := s.movimentacaoRepo.FindByEstoqueID(estoqueID, inicio, fim)
+	if err != nil {
+		return nil, errors.New("erro ao buscar movimentações")
+	}
+
+	return movimentacoes, nil
+}
+
+// validarMovimentacao verifica tipo e quantidade de uma movimentação
+func validarMovimentacao(movimentacao *models.MovimentacaoEstoque) error {
+	if movimentacao.EstoqueID == 0 {
+		return errors.New("item de estoque é obrigatório")
+	}
+
+	switch movimentacao.Tipo {
+	case models.TipoMovimentacaoEntrada, models.TipoMovimentacaoSaida:
+		if movimentacao.Quantidade <= 0 {
+			return errors.New("a quantidade deve ser maior que zero")
+		}
+	case models.TipoMovimentacaoAjuste:
+		if movimentacao.Quantidade == 0 {
+			return errors.New("o ajuste não altera o saldo do item")
+		}
+		if movimentacao.Motivo == "" {
+			return errors.New("o motivo é obrigatório para ajustes")
+		}
+	default:
+		return errors.New("tipo de movimentação inválido")
+	}
+
+	return nil
+}
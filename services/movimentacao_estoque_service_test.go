@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+func TestRegistrarMovimentacaoGravaSaldos(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 10, nil)
+
+	entrada, err := a.movimentacao.Registrar(&models.MovimentacaoEstoque{
+		EstoqueID: peca.ID, Tipo: models.TipoMovimentacaoEntrada, Quantidade: 5,
+	})
+	if err != nil {
+		t.Fatalf("erro na entrada: %v", err)
+	}
+	if entrada.SaldoAnterior != 10 || entrada.SaldoPosterior != 15 {
+		t.Errorf("saldos da entrada = %d -> %d, esperado 10 -> 15", entrada.SaldoAnterior, entrada.SaldoPosterior)
+	}
+	if entrada.CustoUnitario != peca.PrecoUnitario {
+		t.Errorf("custo = %v, esperado o custo atual do item %v", entrada.CustoUnitario, peca.PrecoUnitario)
+	}
+
+	saida, err := a.movimentacao.Registrar(&models.MovimentacaoEstoque{
+		EstoqueID: peca.ID, Tipo: models.TipoMovimentacaoSaida, Quantidade: 4,
+	})
+	if err != nil {
+		t.Fatalf("erro na saída: %v", err)
+	}
+	if saida.SaldoAnterior != 15 || saida.SaldoPosterior != 11 {
+		t.Errorf("saldos da saída = %d -> %d, esperado 15 -> 11", saida.SaldoAnterior, saida.SaldoPosterior)
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 11 {
+		t.Errorf("saldo do item = %d, esperado 11", saldo)
+	}
+}
+
+func TestRegistrarMovimentacaoRejeitaSaldoNegativo(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 2, nil)
+
+	_, err := a.movimentacao.Registrar(&models.MovimentacaoEstoque{
+		EstoqueID: peca.ID, Tipo: models.TipoMovimentacaoSaida, Quantidade: 3,
+	})
+	if err == nil {
+		t.Fatal("a saída acima do saldo deveria falhar")
+	}
+
+	// A transação desfeita não deixa movimentação nem altera o saldo
+	movimentacoes, _ := a.movimentacao.BuscarPorEstoque(peca.ID, nil, nil)
+	if len(movimentacoes) != 1 {
+		t.Errorf("movimentações = %d, esperado apenas o saldo inicial", len(movimentacoes))
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 2 {
+		t.Errorf("saldo do item = %d, esperado 2", saldo)
+	}
+}
+
+func TestValidarMovimentacao(t *testing.T) {
+	casos := []struct {
+		nome         string
+		movimentacao models.MovimentacaoEstoque
+		valida       bool
+	}{
+		{"entrada", models.MovimentacaoEstoque{EstoqueID: 1, Tipo: models.TipoMovimentacaoEntrada, Quantidade: 1}, true},
+		{"entrada sem quantidade", models.MovimentacaoEstoque{EstoqueID: 1, Tipo: models.TipoMovimentacaoEntrada}, false},
+		{"saída negativa", models.MovimentacaoEstoque{EstoqueID: 1, Tipo: models.TipoMovimentacaoSaida, Quantidade: -1}, false},
+		{"ajuste negativo com motivo", models.MovimentacaoEstoque{EstoqueID: 1, Tipo: models.TipoMovimentacaoAjuste, Quantidade: -2, Motivo: "Perda"}, true},
+		{"ajuste sem motivo", models.MovimentacaoEstoque{EstoqueID: 1, Tipo: models.TipoMovimentacaoAjuste, Quantidade: 2}, false},
+		{"sem item", models.MovimentacaoEstoque{Tipo: models.TipoMovimentacaoEntrada, Quantidade: 1}, false},
+		{"tipo desconhecido", models.MovimentacaoEstoque{EstoqueID: 1, Tipo: "transferencia", Quantidade: 1}, false},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := validarMovimentacao(&caso.movimentacao)
+			if (err == nil) != caso.valida {
+				t.Errorf("erro = %v, válida esperada = %v", err, caso.valida)
+			}
+		})
+	}
+}
+
+func TestAjustarSaldoLevaAoValorContado(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 10, nil)
+
+	ajuste, err := a.movimentacao.AjustarSaldo(peca.ID, 7, nil, "")
+	if err != nil {
+		t.Fatalf("erro no ajuste: %v", err)
+	}
+	if ajuste.Quantidade != -3 || ajuste.Motivo != "Ajuste de inventário" {
+		t.Errorf("ajuste = %+d (%q), esperado -3 com o motivo padrão", ajuste.Quantidade, ajuste.Motivo)
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 7 {
+		t.Errorf("saldo do item = %d, esperado 7", saldo)
+	}
+
+	if _, err := a.movimentacao.AjustarSaldo(peca.ID, 7, nil, ""); err == nil {
+		t.Error("um ajuste sem diferença deveria ser rejeitado")
+	}
+	if _, err := a.movimentacao.AjustarSaldo(peca.ID, -1, nil, ""); err == nil {
+		t.Error("um saldo negativo deveria ser rejeitado")
+	}
+}
+
+func TestAtualizarEstoqueNaoAlteraQuantidade(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 10, nil)
+
+	peca.Quantidade = 99
+	peca.Nome = "Filtro de ar"
+	atualizada, err := a.estoque.Atualizar(peca)
+	if err != nil {
+		t.Fatalf("erro ao atualizar: %v", err)
+	}
+	if atualizada.Quantidade != 10 || a.saldoPeca(t, peca.ID).Quantidade != 10 {
+		t.Error("a quantidade só deveria mudar por movimentações")
+	}
+}
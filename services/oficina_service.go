@@ -0,0 +1,113 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/utils"
+)
+
+// oficinaConfigFile guarda os dados da oficina usados nos documentos impressos
+const oficinaConfigFile = "oficina_config.json"
+
+// OficinaService define a interface para os dados cadastrais da própria oficina
+type OficinaService interface {
+	BuscarDados() (*models.DadosOficina, error)
+	SalvarDados(dados *models.DadosOficina) (*models.DadosOficina, error)
+	AtualizarLogo(caminho string) (*models.DadosOficina, error)
+}
+
+// OficinaServiceImpl implementa a interface OficinaService gravando os dados em arquivo local
+type OficinaServiceImpl struct {
+	arquivo string
+}
+
+// NewOficinaService cria uma nova instância de OficinaService
+func NewOficinaService() OficinaService {
+	return &OficinaServiceImpl{arquivo: oficinaConfigFile}
+}
+
+// BuscarDados lê os dados da oficina; sem arquivo salvo, retorna um cadastro vazio
+func (s *OficinaServiceImpl) BuscarDados() (*models.DadosOficina, error) {
+	var dados models.DadosOficina
+	file, err := os.Open(s.arquivo)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &models.DadosOficina{Nome: "Oficina Mecânica"}, nil
+		}
+		return nil, errors.New("erro ao ler dados da oficina: " + err.Error())
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(&dados); err != nil {
+		return nil, errors.New("erro ao ler dados da oficina: " + err.Error())
+	}
+	return &dados, nil
+}
+
+// SalvarDados valida e grava os dados da oficina, mantendo o logo já enviado
+func (s *OficinaServiceImpl) SalvarDados(dados *models.DadosOficina) (*models.DadosOficina, error) {
+	dados.Nome = strings.TrimSpace(dados.Nome)
+	if dados.Nome == "" {
+		return nil, errors.New("nome da oficina é obrigatório")
+	}
+
+	if strings.TrimSpace(dados.CNPJ) != "" {
+		cnpj, err := utils.ValidarCNPJ(dados.CNPJ)
+		if err != nil {
+			return nil, err
+		}
+		dados.CNPJ = cnpj
+	} else {
+		dados.CNPJ = ""
+	}
+
+	// O logo só muda pelo envio da imagem
+	atual, err := s.BuscarDados()
+	if err != nil {
+		return nil, err
+	}
+	dados.Logo = atual.Logo
+
+	if err := s.gravar(dados); err != nil {
+		return nil, err
+	}
+	return dados, nil
+}
+
+// AtualizarLogo registra o caminho da nova imagem do logo e remove a anterior
+func (s *OficinaServiceImpl) AtualizarLogo(caminho string) (*models.DadosOficina, error) {
+	dados, err := s.BuscarDados()
+	if err != nil {
+		return nil, err
+	}
+
+	if dados.Logo != "" && dados.Logo != caminho {
+		if _, statErr := os.Stat(dados.Logo); statErr == nil {
+			_ = os.Remove(dados.Logo)
+		}
+	}
+
+	dados.Logo = caminho
+	if err := s.gravar(dados); err != nil {
+		return nil, err
+	}
+	return dados, nil
+}
+
+// gravar salva os dados no arquivo de configuração
+func (s *OficinaServiceImpl) gravar(dados *models.DadosOficina) error {
+	file, err := os.Create(s.arquivo)
+	if err != nil {
+		return errors.New("erro ao salvar dados da oficina: " + err.Error())
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(dados); err != nil {
+		return errors.New("erro ao salvar dados da oficina: " + err.Error())
+	}
+	return nil
+}
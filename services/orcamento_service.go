@@ -0,0 +1,679 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// OrcamentoService define a interface para os serviços de orçamentos
+type OrcamentoService interface {
+	BuscarTodos(status string) ([]models.Orcamento, error)
+	BuscarPorID(id uint) (*models.Orcamento, error)
+	BuscarVersoes(id uint) ([]models.Orcamento, error)
+	Criar(orcamento *models.Orcamento, usuarioID *uint) (*models.Orcamento, error)
+	Revisar(id uint, revisao *models.Orcamento, usuarioID *uint) (*models.Orcamento, error)
+	Aprovar(id uint, aprovadoPor string, usuarioID *uint) (*models.Orcamento, error)
+	Rejeitar(id uint, responsavel string, motivo string, usuarioID *uint) (*models.Orcamento, error)
+	ConverterEmOS(id uint, osID *uint, usuarioID *uint) (*models.OrdemServico, error)
+	ExpirarVencidos() (int, error)
+}
+
+// OrcamentoServiceImpl implementa a interface OrcamentoService
+type OrcamentoServiceImpl struct {
+	orcamentoRepo repositories.OrcamentoRepository
+	osRepo        repositories.OrdemServicoRepository
+	veiculoRepo   repositories.VeiculoRepository
+	clienteRepo   repositories.ClienteRepositoryGorm
+	estoqueRepo   repositories.EstoqueRepository
+	servicoRepo   repositories.ServicoRepository
+	osService     OrdemServicoService
+	uow           repositories.UnitOfWork
+}
+
+// NewOrcamentoService cria uma nova instância de OrcamentoService
+func NewOrcamentoService(
+	orcamentoRepo repositories.OrcamentoRepository,
+	osRepo repositories.OrdemServicoRepository,
+	veiculoRepo repositories.VeiculoRepository,
+	clienteRepo repositories.ClienteRepositoryGorm,
+	estoqueRepo repositories.EstoqueRepository,
+	servicoRepo repositories.ServicoRepository,
+	osService OrdemServicoService,
+	uow repositories.UnitOfWork,
+) OrcamentoService {
+	return &OrcamentoServiceImpl{
+		orcamentoRepo: orcamentoRepo,
+		osRepo:        osRepo,
+		veiculoRepo:   veiculoRepo,
+		clienteRepo:   clienteRepo,
+		estoqueRepo:   estoqueRepo,
+		servicoRepo:   servicoRepo,
+		osService:     osService,
+		uow:           uow,
+	}
+}
+
+// BuscarTodos retorna os orçamentos, opcionalmente filtrados por status.
+// Antes da consulta, os pendentes vencidos passam para "expirado"
+func (s *OrcamentoServiceImpl) BuscarTodos(status string) ([]models.Orcamento, error) {
+	if _, err := s.ExpirarVencidos(); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		return s.orcamentoRepo.FindAll()
+	}
+
+	if !isValidStatusOrcamento(status) {
+		return nil, errors.New("status inválido")
+	}
+	return s.orcamentoRepo.FindByStatus(status)
+}
+
+// BuscarPorID retorna um orçamento com suas linhas
+func (s *OrcamentoServiceImpl) BuscarPorID(id uint) (*models.Orcamento, error) {
+	orcamento, err := s.orcamentoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("orçamento não encontrado")
+	}
+	return orcamento, nil
+}
+
+// BuscarVersoes retorna todas as versões do orçamento informado, da primeira para a última
+func (s *OrcamentoServiceImpl) BuscarVersoes(id uint) ([]models.Orcamento, error) {
+	orcamento, err := s.orcamentoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("orçamento não encontrado")
+	}
+	return s.orcamentoRepo.FindVersoes(orcamento.Numero)
+}
+
+// Criar cadastra a primeira versão de um orçamento e reserva as peças no estoque
+func (s *OrcamentoServiceImpl) Criar(orcamento *models.Orcamento, usuarioID *uint) (*models.Orcamento, error) {
+	itens := orcamento.Itens
+	orcamento.ID = 0
+	orcamento.Numero = ""
+	orcamento.Versao = 1
+	orcamento.Status = models.StatusOrcamentoPendente
+	orcamento.UsuarioID = usuarioID
+	orcamento.AprovadoPor = ""
+	orcamento.UsuarioDecisaoID = nil
+	orcamento.DataDecisao = nil
+	orcamento.MotivoRejeicao = ""
+	orcamento.Cliente = nil
+	orcamento.Veiculo = nil
+	orcamento.Itens = nil
+
+	if err := s.validarVinculos(orcamento); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		return s.gravarVersao(tx, orcamento, itens)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orcamentoRepo.FindByID(orcamento.ID)
+}
+
+// Revisar gera uma nova versão do orçamento com o mesmo número. A versão anterior fica como "revisado"
+// e suas reservas são devolvidas; sem linhas informadas, a nova versão repete as linhas da anterior
+func (s *OrcamentoServiceImpl) Revisar(id uint, revisao *models.Orcamento, usuarioID *uint) (*models.Orcamento, error) {
+	var nova *models.Orcamento
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		orcamentoRepo := s.orcamentoRepo.WithTx(tx)
+
+		anterior, err := orcamentoRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("orçamento não encontrado")
+		}
+
+		if !isValidStatusOrcamentoTransition(anterior.Status, models.StatusOrcamentoRevisado) {
+			return fmt.Errorf("não é possível revisar um orçamento com status %s", anterior.Status)
+		}
+
+		itensAnteriores, err := orcamentoRepo.FindItens(anterior.ID)
+		if err != nil {
+			return errors.New("erro ao buscar itens do orçamento")
+		}
+
+		// Apenas orçamentos pendentes ainda seguram peças no estoque
+		if anterior.Status == models.StatusOrcamentoPendente {
+			if err := s.liberarReservas(tx, itensAnteriores); err != nil {
+				return err
+			}
+		}
+
+		anterior.Status = models.StatusOrcamentoRevisado
+		if err := orcamentoRepo.Update(anterior); err != nil {
+			return errors.New("erro ao atualizar orçamento: " + err.Error())
+		}
+
+		itens := revisao.Itens
+		if len(itens) == 0 {
+			for _, item := range itensAnteriores {
+				item.ID = 0
+				item.OrcamentoID = 0
+				itens = append(itens, item)
+			}
+		}
+
+		nova = &models.Orcamento{
+			Numero:         anterior.Numero,
+			Versao:         anterior.Versao + 1,
+			ClienteID:      anterior.ClienteID,
+			VeiculoID:      anterior.VeiculoID,
+			OrdemServicoID: anterior.OrdemServicoID,
+			Status:         models.StatusOrcamentoPendente,
+			Descricao:      revisao.Descricao,
+			ValidadeAte:    revisao.ValidadeAte,
+			ValorDesconto:  revisao.ValorDesconto,
+			UsuarioID:      usuarioID,
+			Observacoes:    revisao.Observacoes,
+		}
+		if nova.Descricao == "" {
+			nova.Descricao = anterior.Descricao
+		}
+
+		return s.gravarVersao(tx, nova, itens)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orcamentoRepo.FindByID(nova.ID)
+}
+
+// Aprovar registra a aprovação do cliente. Um orçamento vencido não pode ser aprovado:
+// ele passa para "expirado" e precisa ser revisado
+func (s *OrcamentoServiceImpl) Aprovar(id uint, aprovadoPor string, usuarioID *uint) (*models.Orcamento, error) {
+	if aprovadoPor == "" {
+		return nil, errors.New("informe quem aprovou o orçamento")
+	}
+
+	vencido := false
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		orcamentoRepo := s.orcamentoRepo.WithTx(tx)
+
+		orcamento, err := orcamentoRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("orçamento não encontrado")
+		}
+
+		if !isValidStatusOrcamentoTransition(orcamento.Status, models.StatusOrcamentoAprovado) {
+			return fmt.Errorf("transição de status inválida: de %s para %s", orcamento.Status, models.StatusOrcamentoAprovado)
+		}
+
+		agora := time.Now()
+		if orcamento.Vencido(agora) {
+			// A expiração precisa ser gravada, então a transação termina sem erro
+			vencido = true
+			return s.expirar(tx, orcamento)
+		}
+
+		orcamento.Status = models.StatusOrcamentoAprovado
+		orcamento.AprovadoPor = aprovadoPor
+		orcamento.UsuarioDecisaoID = usuarioID
+		orcamento.DataDecisao = &agora
+		if err := orcamentoRepo.Update(orcamento); err != nil {
+			return errors.New("erro ao aprovar orçamento: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if vencido {
+		return nil, errors.New("orçamento vencido; gere uma revisão com nova validade")
+	}
+
+	return s.orcamentoRepo.FindByID(id)
+}
+
+// Rejeitar registra a recusa do cliente e devolve as peças reservadas ao estoque
+func (s *OrcamentoServiceImpl) Rejeitar(id uint, responsavel string, motivo string, usuarioID *uint) (*models.Orcamento, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		orcamentoRepo := s.orcamentoRepo.WithTx(tx)
+
+		orcamento, err := orcamentoRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("orçamento não encontrado")
+		}
+
+		if !isValidStatusOrcamentoTransition(orcamento.Status, models.StatusOrcamentoRejeitado) {
+			return fmt.Errorf("transição de status inválida: de %s para %s", orcamento.Status, models.StatusOrcamentoRejeitado)
+		}
+
+		itens, err := orcamentoRepo.FindItens(orcamento.ID)
+		if err != nil {
+			return errors.New("erro ao buscar itens do orçamento")
+		}
+		if err := s.liberarReservas(tx, itens); err != nil {
+			return err
+		}
+
+		agora := time.Now()
+		orcamento.Status = models.StatusOrcamentoRejeitado
+		orcamento.AprovadoPor = responsavel
+		orcamento.MotivoRejeicao = motivo
+		orcamento.UsuarioDecisaoID = usuarioID
+		orcamento.DataDecisao = &agora
+		if err := orcamentoRepo.Update(orcamento); err != nil {
+			return errors.New("erro ao rejeitar orçamento: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orcamentoRepo.FindByID(id)
+}
+
+// ConverterEmOS transforma um orçamento aprovado em ordem de serviço. Usa a OS informada,
+// a OS já vinculada ao orçamento ou abre uma nova; as reservas viram saídas de estoque
+// por meio das peças lançadas na OS e os serviços viram linhas de mão de obra da OS
+func (s *OrcamentoServiceImpl) ConverterEmOS(id uint, osID *uint, usuarioID *uint) (*models.OrdemServico, error) {
+	var destinoID uint
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		orcamentoRepo := s.orcamentoRepo.WithTx(tx)
+		osRepo := s.osRepo.WithTx(tx)
+
+		orcamento, err := orcamentoRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("orçamento não encontrado")
+		}
+
+		if !isValidStatusOrcamentoTransition(orcamento.Status, models.StatusOrcamentoConvertido) {
+			return errors.New("apenas orçamentos aprovados podem ser convertidos em ordem de serviço")
+		}
+
+		if osID == nil {
+			osID = orcamento.OrdemServicoID
+		}
+
+		var os *models.OrdemServico
+		if osID != nil {
+			os, err = osRepo.FindByIDForUpdate(*osID)
+			if err != nil {
+				return errors.New("ordem de serviço não encontrada")
+			}
+			if os.ClienteID != orcamento.ClienteID || os.VeiculoID != orcamento.VeiculoID {
+				return errors.New("a ordem de serviço pertence a outro cliente ou veículo")
+			}
+			if os.Finalizada() {
+				return errors.New("não é possível converter o orçamento em uma OS concluída ou cancelada")
+			}
+		} else {
+			descricao := orcamento.Descricao
+			if descricao == "" {
+				descricao = "Serviços do orçamento " + orcamento.Numero
+			}
+			os = &models.OrdemServico{
+				ClienteID:   orcamento.ClienteID,
+				VeiculoID:   orcamento.VeiculoID,
+				Descricao:   descricao,
+				Observacoes: orcamento.Observacoes,
+				DataEntrada: time.Now(),
+				Status:      "aberta",
+			}
+			if err := osRepo.Create(os); err != nil {
+				return errors.New("erro ao criar ordem de serviço: " + err.Error())
+			}
+		}
+
+		itens, err := orcamentoRepo.FindItens(orcamento.ID)
+		if err != nil {
+			return errors.New("erro ao buscar itens do orçamento")
+		}
+
+		// Devolver as reservas antes de lançar as peças, que então dão baixa no saldo
+		if err := s.liberarReservas(tx, itens); err != nil {
+			return err
+		}
+
+		for _, item := range itens {
+			if item.Tipo == models.TipoItemOrcamentoServico {
+				linha, err := s.linhaServico(tx, item)
+				if err != nil {
+					return err
+				}
+				if err := s.osService.AdicionarServicoNaTransacao(tx, os.ID, linha); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if item.EstoqueID == nil {
+				continue
+			}
+			itemOS := &models.ItemOrdemServico{
+				EstoqueID:     *item.EstoqueID,
+				Quantidade:    item.Quantidade,
+				ValorUnitario: item.ValorUnitario,
+			}
+			if err := s.osService.AdicionarItemNaTransacao(tx, os.ID, itemOS, usuarioID); err != nil {
+				return err
+			}
+		}
+
+		// Recarregar a OS com os valores de peças e serviços atualizados pelas linhas lançadas
+		os, err = osRepo.FindByIDForUpdate(os.ID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+		os.ValorDesconto += orcamento.ValorDesconto
+		if err := osRepo.Update(os); err != nil {
+			return errors.New("erro ao atualizar valores da OS: " + err.Error())
+		}
+
+		orcamento.Status = models.StatusOrcamentoConvertido
+		orcamento.OrdemServicoID = &os.ID
+		if err := orcamentoRepo.Update(orcamento); err != nil {
+			return errors.New("erro ao atualizar orçamento: " + err.Error())
+		}
+
+		destinoID = os.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindByID(destinoID)
+}
+
+// ExpirarVencidos passa para "expirado" os orçamentos pendentes fora da validade,
+// devolvendo as peças reservadas. Retorna quantos orçamentos foram expirados
+func (s *OrcamentoServiceImpl) ExpirarVencidos() (int, error) {
+	total := 0
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		vencidos, err := s.orcamentoRepo.WithTx(tx).FindVencidos(time.Now())
+		if err != nil {
+			return errors.New("erro ao buscar orçamentos vencidos")
+		}
+
+		for i := range vencidos {
+			if err := s.expirar(tx, &vencidos[i]); err != nil {
+				return err
+			}
+		}
+		total = len(vencidos)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// Funções auxiliares
+
+// validarVinculos confere cliente, veículo e a OS de origem do orçamento
+func (s *OrcamentoServiceImpl) validarVinculos(orcamento *models.Orcamento) error {
+	if orcamento.ClienteID == 0 {
+		return errors.New("cliente é obrigatório")
+	}
+	if orcamento.VeiculoID == 0 {
+		return errors.New("veículo é obrigatório")
+	}
+
+	if _, err := s.clienteRepo.FindByID(orcamento.ClienteID); err != nil {
+		return errors.New("cliente não encontrado")
+	}
+
+	veiculo, err := s.veiculoRepo.FindByID(orcamento.VeiculoID)
+	if err != nil {
+		return errors.New("veículo não encontrado")
+	}
+	if veiculo.ClienteID != orcamento.ClienteID {
+		return errors.New("o veículo não pertence ao cliente informado")
+	}
+
+	if orcamento.OrdemServicoID != nil {
+		os, err := s.osRepo.FindByID(*orcamento.OrdemServicoID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+		if os.ClienteID != orcamento.ClienteID || os.VeiculoID != orcamento.VeiculoID {
+			return errors.New("a ordem de serviço pertence a outro cliente ou veículo")
+		}
+		if os.Finalizada() {
+			return errors.New("não é possível orçar uma OS concluída ou cancelada")
+		}
+	}
+
+	return nil
+}
+
+// gravarVersao valida as linhas, calcula os valores, grava o orçamento e reserva as peças
+func (s *OrcamentoServiceImpl) gravarVersao(tx *gorm.DB, orcamento *models.Orcamento, itens []models.ItemOrcamento) error {
+	if len(itens) == 0 {
+		return errors.New("o orçamento deve possuir ao menos um item")
+	}
+
+	if !orcamento.ValidadeAte.IsZero() && orcamento.ValidadeAte.Before(time.Now()) {
+		return errors.New("a validade do orçamento deve ser uma data futura")
+	}
+
+	if orcamento.ValorDesconto < 0 {
+		return errors.New("o desconto não pode ser negativo")
+	}
+
+	orcamento.ValorPecas = 0
+	orcamento.ValorServicos = 0
+	for i := range itens {
+		if err := s.prepararItem(tx, &itens[i]); err != nil {
+			return err
+		}
+		valor := float64(itens[i].Quantidade) * itens[i].ValorUnitario
+		if itens[i].Tipo == models.TipoItemOrcamentoPeca {
+			orcamento.ValorPecas += valor
+		} else {
+			orcamento.ValorServicos += valor
+		}
+	}
+
+	if orcamento.ValorDesconto > orcamento.ValorPecas+orcamento.ValorServicos {
+		return errors.New("o desconto não pode ser maior que o valor do orçamento")
+	}
+
+	orcamentoRepo := s.orcamentoRepo.WithTx(tx)
+	if err := orcamentoRepo.Create(orcamento); err != nil {
+		return errors.New("erro ao criar orçamento: " + err.Error())
+	}
+
+	for i := range itens {
+		itens[i].OrcamentoID = orcamento.ID
+		if err := orcamentoRepo.AddItem(&itens[i]); err != nil {
+			return errors.New("erro ao adicionar item ao orçamento: " + err.Error())
+		}
+	}
+
+	return s.reservar(tx, itens)
+}
+
+// prepararItem valida uma linha do orçamento e completa descrição e preço das peças
+func (s *OrcamentoServiceImpl) prepararItem(tx *gorm.DB, item *models.ItemOrcamento) error {
+	item.ID = 0
+	item.Item = nil
+
+	if item.Quantidade <= 0 {
+		return errors.New("a quantidade deve ser maior que zero")
+	}
+	if item.ValorUnitario < 0 {
+		return errors.New("o valor unitário não pode ser negativo")
+	}
+
+	switch item.Tipo {
+	case models.TipoItemOrcamentoPeca:
+		item.ServicoID = nil
+		if item.EstoqueID == nil {
+			return errors.New("item de estoque é obrigatório para peças")
+		}
+		estoque, err := s.estoqueRepo.WithTx(tx).FindByID(*item.EstoqueID)
+		if err != nil {
+			return errors.New("item de estoque não encontrado")
+		}
+		if item.Descricao == "" {
+			item.Descricao = estoque.Nome
+		}
+		if item.ValorUnitario == 0 {
+			item.ValorUnitario = estoque.PrecoVenda
+		}
+	case models.TipoItemOrcamentoServico:
+		item.EstoqueID = nil
+		item.Servico = nil
+		if item.ServicoID != nil {
+			servico, err := s.servicoRepo.WithTx(tx).FindByID(*item.ServicoID)
+			if err != nil {
+				return errors.New("serviço não encontrado no catálogo")
+			}
+			if !servico.Ativo {
+				return fmt.Errorf("o serviço %s está inativo", servico.Nome)
+			}
+			if item.Descricao == "" {
+				item.Descricao = servico.Nome
+			}
+			if item.ValorUnitario == 0 {
+				item.ValorUnitario = servico.ValorPadrao()
+			}
+		}
+		if item.Descricao == "" {
+			return errors.New("descrição é obrigatória para serviços")
+		}
+	default:
+		return errors.New("tipo de item inválido")
+	}
+
+	return nil
+}
+
+// linhaServico converte uma linha de serviço do orçamento em mão de obra da OS.
+// Serviços do catálogo são lançados com as horas padrão por unidade; o valor da hora
+// é derivado do preço orçado para que o total da OS seja o mesmo aprovado pelo cliente
+func (s *OrcamentoServiceImpl) linhaServico(tx *gorm.DB, item models.ItemOrcamento) (*models.ServicoOrdemServico, error) {
+	horasPorUnidade := 1.0
+	if item.ServicoID != nil {
+		servico, err := s.servicoRepo.WithTx(tx).FindByID(*item.ServicoID)
+		if err != nil {
+			return nil, errors.New("serviço não encontrado no catálogo")
+		}
+		if servico.HorasPadrao > 0 {
+			horasPorUnidade = servico.HorasPadrao
+		}
+	}
+
+	return &models.ServicoOrdemServico{
+		ServicoID: item.ServicoID,
+		Descricao: item.Descricao,
+		Horas:     float64(item.Quantidade) * horasPorUnidade,
+		ValorHora: item.ValorUnitario / horasPorUnidade,
+	}, nil
+}
+
+// reservar separa no estoque as peças do orçamento, sem alterar o saldo físico
+func (s *OrcamentoServiceImpl) reservar(tx *gorm.DB, itens []models.ItemOrcamento) error {
+	estoqueRepo := s.estoqueRepo.WithTx(tx)
+	for _, item := range itens {
+		if item.Tipo != models.TipoItemOrcamentoPeca || item.EstoqueID == nil {
+			continue
+		}
+
+		estoque, err := estoqueRepo.FindByIDForUpdate(*item.EstoqueID)
+		if err != nil {
+			return errors.New("item de estoque não encontrado")
+		}
+
+		if estoque.QuantidadeDisponivel() < item.Quantidade {
+			return fmt.Errorf("quantidade insuficiente em estoque para %s", estoque.Nome)
+		}
+
+		if err := estoqueRepo.UpdateQuantidadeReservada(estoque.ID, estoque.QuantidadeReservada+item.Quantidade); err != nil {
+			return errors.New("erro ao reservar estoque: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// liberarReservas devolve ao saldo disponível as peças reservadas pelo orçamento
+func (s *OrcamentoServiceImpl) liberarReservas(tx *gorm.DB, itens []models.ItemOrcamento) error {
+	estoqueRepo := s.estoqueRepo.WithTx(tx)
+	for _, item := range itens {
+		if item.Tipo != models.TipoItemOrcamentoPeca || item.EstoqueID == nil {
+			continue
+		}
+
+		estoque, err := estoqueRepo.FindByIDForUpdate(*item.EstoqueID)
+		if err != nil {
+			return errors.New("item de estoque não encontrado")
+		}
+
+		// O saldo reservado pode ter sido zerado por um ajuste manual no cadastro do item
+		reservada := estoque.QuantidadeReservada - item.Quantidade
+		if reservada < 0 {
+			reservada = 0
+		}
+
+		if err := estoqueRepo.UpdateQuantidadeReservada(estoque.ID, reservada); err != nil {
+			return errors.New("erro ao liberar reserva de estoque: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// expirar marca o orçamento pendente como expirado e devolve suas reservas
+func (s *OrcamentoServiceImpl) expirar(tx *gorm.DB, orcamento *models.Orcamento) error {
+	orcamentoRepo := s.orcamentoRepo.WithTx(tx)
+
+	itens, err := orcamentoRepo.FindItens(orcamento.ID)
+	if err != nil {
+		return errors.New("erro ao buscar itens do orçamento")
+	}
+	if err := s.liberarReservas(tx, itens); err != nil {
+		return err
+	}
+
+	orcamento.Status = models.StatusOrcamentoExpirado
+	if err := orcamentoRepo.Update(orcamento); err != nil {
+		return errors.New("erro ao expirar orçamento: " + err.Error())
+	}
+	return nil
+}
+
+// isValidStatusOrcamento verifica se o status informado existe
+func isValidStatusOrcamento(status string) bool {
+	switch status {
+	case models.StatusOrcamentoPendente, models.StatusOrcamentoAprovado, models.StatusOrcamentoRejeitado,
+		models.StatusOrcamentoExpirado, models.StatusOrcamentoRevisado, models.StatusOrcamentoConvertido:
+		return true
+	}
+	return false
+}
+
+// isValidStatusOrcamentoTransition verifica se a transição de status do orçamento é permitida
+func isValidStatusOrcamentoTransition(atual, novo string) bool {
+	transicoes := map[string][]string{
+		models.StatusOrcamentoPendente:  {models.StatusOrcamentoAprovado, models.StatusOrcamentoRejeitado, models.StatusOrcamentoExpirado, models.StatusOrcamentoRevisado},
+		models.StatusOrcamentoAprovado:  {models.StatusOrcamentoConvertido, models.StatusOrcamentoRejeitado},
+		models.StatusOrcamentoRejeitado: {models.StatusOrcamentoRevisado},
+		models.StatusOrcamentoExpirado:  {models.StatusOrcamentoRevisado},
+	}
+
+	for _, permitido := range transicoes[atual] {
+		if permitido == novo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+// novoOrcamento cria um orçamento pendente com uma peça do estoque
+func (a *ambienteTeste) novoOrcamento(t *testing.T, peca *models.Estoque, quantidade int) *models.Orcamento {
+	t.Helper()
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	orcamento, err := a.orcamento.Criar(&models.Orcamento{
+		ClienteID: cliente.ID,
+		VeiculoID: veiculo.ID,
+		Descricao: "Troca do filtro",
+		Itens: []models.ItemOrcamento{
+			{Tipo: models.TipoItemOrcamentoPeca, EstoqueID: &peca.ID, Quantidade: quantidade},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("erro ao criar orçamento: %v", err)
+	}
+	return orcamento
+}
+
+// conferirSaldos compara o saldo físico e o reservado da peça
+func (a *ambienteTeste) conferirSaldos(t *testing.T, pecaID uint, quantidade, reservada int) {
+	t.Helper()
+	peca := a.saldoPeca(t, pecaID)
+	if peca.Quantidade != quantidade || peca.QuantidadeReservada != reservada {
+		t.Errorf("saldo = %d (reservado %d), esperado %d (reservado %d)",
+			peca.Quantidade, peca.QuantidadeReservada, quantidade, reservada)
+	}
+}
+
+func TestCriarEstoqueIgnoraReservaInformada(t *testing.T) {
+	a := novoAmbiente(t)
+	peca, err := a.estoque.Criar(&models.Estoque{
+		Nome: "Pastilha", Quantidade: 4, QuantidadeReservada: 3, PrecoUnitario: 10, PrecoVenda: 15,
+	}, nil)
+	if err != nil {
+		t.Fatalf("erro ao cadastrar peça: %v", err)
+	}
+	a.conferirSaldos(t, peca.ID, 4, 0)
+}
+
+func TestOrcamentoReservaSemBaixarOSaldo(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 5, nil)
+
+	orcamento := a.novoOrcamento(t, peca, 3)
+	if orcamento.ValorPecas != 3*peca.PrecoVenda {
+		t.Errorf("valor de peças = %v, esperado %v", orcamento.ValorPecas, 3*peca.PrecoVenda)
+	}
+	a.conferirSaldos(t, peca.ID, 5, 3)
+
+	// Restam 2 unidades livres: outro orçamento com 3 não cabe
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	_, err := a.orcamento.Criar(&models.Orcamento{
+		ClienteID: cliente.ID,
+		VeiculoID: veiculo.ID,
+		Itens:     []models.ItemOrcamento{{Tipo: models.TipoItemOrcamentoPeca, EstoqueID: &peca.ID, Quantidade: 3}},
+	}, nil)
+	if err == nil {
+		t.Error("o orçamento não deveria reservar acima do saldo disponível")
+	}
+	a.conferirSaldos(t, peca.ID, 5, 3)
+}
+
+func TestSaidaNaoConsomeUnidadesReservadas(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 5, nil)
+	a.novoOrcamento(t, peca, 4)
+
+	_, err := a.movimentacao.Registrar(&models.MovimentacaoEstoque{
+		EstoqueID: peca.ID, Tipo: models.TipoMovimentacaoSaida, Quantidade: 2,
+	})
+	if err == nil {
+		t.Error("a saída não deveria usar unidades reservadas")
+	}
+
+	os := a.novaOS(t)
+	if _, err := a.os.AdicionarItem(os.ID, &models.ItemOrdemServico{EstoqueID: peca.ID, Quantidade: 2}, nil); err == nil {
+		t.Error("a OS não deveria usar unidades reservadas por outro orçamento")
+	}
+	a.conferirSaldos(t, peca.ID, 5, 4)
+}
+
+func TestRejeitarOrcamentoLiberaReserva(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 5, nil)
+	orcamento := a.novoOrcamento(t, peca, 3)
+
+	rejeitado, err := a.orcamento.Rejeitar(orcamento.ID, "Cliente", "Achou caro", nil)
+	if err != nil {
+		t.Fatalf("erro ao rejeitar: %v", err)
+	}
+	if rejeitado.Status != models.StatusOrcamentoRejeitado {
+		t.Errorf("status = %q, esperado rejeitado", rejeitado.Status)
+	}
+	a.conferirSaldos(t, peca.ID, 5, 0)
+
+	if _, err := a.orcamento.Aprovar(orcamento.ID, "Cliente", nil); err == nil {
+		t.Error("um orçamento rejeitado não deveria ser aprovado")
+	}
+}
+
+func TestRevisarOrcamentoTrocaAReserva(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 5, nil)
+	orcamento := a.novoOrcamento(t, peca, 3)
+
+	revisao, err := a.orcamento.Revisar(orcamento.ID, &models.Orcamento{
+		Itens: []models.ItemOrcamento{{Tipo: models.TipoItemOrcamentoPeca, EstoqueID: &peca.ID, Quantidade: 5}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("erro ao revisar: %v", err)
+	}
+	if revisao.Versao != 2 || revisao.Numero != orcamento.Numero {
+		t.Errorf("revisão = %s v%d, esperado %s v2", revisao.Numero, revisao.Versao, orcamento.Numero)
+	}
+	a.conferirSaldos(t, peca.ID, 5, 5)
+
+	anterior, _ := a.orcamento.BuscarPorID(orcamento.ID)
+	if anterior.Status != models.StatusOrcamentoRevisado {
+		t.Errorf("status da versão anterior = %q, esperado revisado", anterior.Status)
+	}
+}
+
+func TestConverterOrcamentoEmOSBaixaAsPecas(t *testing.T) {
+	a := novoAmbiente(t)
+	peca := a.novaPeca(t, 5, nil)
+	orcamento := a.novoOrcamento(t, peca, 3)
+
+	if _, err := a.orcamento.ConverterEmOS(orcamento.ID, nil, nil); err == nil {
+		t.Fatal("um orçamento pendente não deveria ser convertido")
+	}
+
+	if _, err := a.orcamento.Aprovar(orcamento.ID, "Cliente", nil); err != nil {
+		t.Fatalf("erro ao aprovar: %v", err)
+	}
+	// A aprovação mantém a reserva até a conversão
+	a.conferirSaldos(t, peca.ID, 5, 3)
+
+	os, err := a.orcamento.ConverterEmOS(orcamento.ID, nil, nil)
+	if err != nil {
+		t.Fatalf("erro ao converter: %v", err)
+	}
+	if os.Status != "aberta" || os.ValorPecas != orcamento.ValorPecas {
+		t.Errorf("OS %s com peças %v, esperado aberta com %v", os.Status, os.ValorPecas, orcamento.ValorPecas)
+	}
+	a.conferirSaldos(t, peca.ID, 2, 0)
+
+	if _, err := a.orcamento.ConverterEmOS(orcamento.ID, nil, nil); err == nil {
+		t.Error("um orçamento já convertido não deveria ser convertido de novo")
+	}
+	a.conferirSaldos(t, peca.ID, 2, 0)
+}
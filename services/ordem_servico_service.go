@@ -3,17 +3,20 @@ package services
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"gorm.io/gorm"
+
 	"OficinaMecanica/models"
 	"OficinaMecanica/repositories"
 )
 
 type OrdemServicoService interface {
-	BuscarTodas() ([]models.OrdemServico, error)
+	BuscarTodas(consulta repositories.Consulta) (*models.Pagina[models.OrdemServico], error)
 	BuscarPorID(id uint) (*models.OrdemServico, error)
 	Criar(os *models.OrdemServico) (*models.OrdemServico, error)
-	Atualizar(os *models.OrdemServico) (*models.OrdemServico, error)
+	Atualizar(os *models.OrdemServico, usuarioID *uint) (*models.OrdemServico, error)
 	AtualizarStatus(id uint, novoStatus string) (*models.OrdemServico, error)
 	Deletar(id uint) error
 	BuscarPorCliente(clienteID uint) ([]models.OrdemServico, error)
@@ -21,19 +24,35 @@ type OrdemServicoService interface {
 	BuscarPorStatus(status string) ([]models.OrdemServico, error)
 	BuscarPorPeriodo(inicio, fim time.Time) ([]models.OrdemServico, error)
 	BuscarPorNumeroOS(numeroOS string) (*models.OrdemServico, error)
-	AdicionarItem(osID uint, item *models.ItemOrdemServico) (*models.ItemOrdemServico, error)
-	RemoverItem(osID uint, itemID uint) error
-	AtualizarItem(osID uint, item *models.ItemOrdemServico) (*models.ItemOrdemServico, error)
+	BuscarPorFuncionario(funcionarioID uint, incluirFinalizadas bool) ([]models.OrdemServico, error)
+	AtribuirFuncionario(id uint, funcionarioID uint) (*models.OrdemServico, error)
+	AdicionarItem(osID uint, item *models.ItemOrdemServico, usuarioID *uint) (*models.ItemOrdemServico, error)
+	AdicionarItemNaTransacao(tx *gorm.DB, osID uint, item *models.ItemOrdemServico, usuarioID *uint) error
+	RemoverItem(osID uint, itemID uint, usuarioID *uint) error
+	AtualizarItem(osID uint, item *models.ItemOrdemServico, usuarioID *uint) (*models.ItemOrdemServico, error)
 	BuscarItens(osID uint) ([]models.ItemOrdemServico, error)
+	AdicionarServico(osID uint, linha *models.ServicoOrdemServico) (*models.ServicoOrdemServico, error)
+	AdicionarServicoNaTransacao(tx *gorm.DB, osID uint, linha *models.ServicoOrdemServico) error
+	AtualizarServico(osID uint, linha *models.ServicoOrdemServico) (*models.ServicoOrdemServico, error)
+	RemoverServico(osID uint, linhaID uint) error
+	BuscarServicos(osID uint) ([]models.ServicoOrdemServico, error)
 	ConcluirOS(id uint) (*models.OrdemServico, error)
-	CancelarOS(id uint) (*models.OrdemServico, error)
+	Entregar(id uint, liberarSemPagamento bool, motivo string, usuarioID *uint) (*models.OrdemServico, error)
+	CancelarOS(id uint, usuarioID *uint) (*models.OrdemServico, error)
 }
 
 type OrdemServicoServiceImpl struct {
-	osRepo          repositories.OrdemServicoRepository
-	veiculoRepo     repositories.VeiculoRepository
-	clienteRepo     repositories.ClienteRepositoryGorm
-	estoqueRepo     repositories.EstoqueRepository
+	osRepo              repositories.OrdemServicoRepository
+	veiculoRepo         repositories.VeiculoRepository
+	clienteRepo         repositories.ClienteRepositoryGorm
+	estoqueRepo         repositories.EstoqueRepository
+	funcionarioRepo     repositories.FuncionarioRepository
+	orcamentoRepo       repositories.OrcamentoRepository
+	servicoRepo         repositories.ServicoRepository
+	movimentacaoService MovimentacaoEstoqueService
+	manutencaoService   ManutencaoService
+	permissaoService    PermissaoService
+	uow                 repositories.UnitOfWork
 }
 
 func NewOrdemServicoService(
@@ -41,17 +60,31 @@ func NewOrdemServicoService(
 	veiculoRepo repositories.VeiculoRepository,
 	clienteRepo repositories.ClienteRepositoryGorm,
 	estoqueRepo repositories.EstoqueRepository,
+	funcionarioRepo repositories.FuncionarioRepository,
+	orcamentoRepo repositories.OrcamentoRepository,
+	servicoRepo repositories.ServicoRepository,
+	movimentacaoService MovimentacaoEstoqueService,
+	manutencaoService ManutencaoService,
+	permissaoService PermissaoService,
+	uow repositories.UnitOfWork,
 ) OrdemServicoService {
 	return &OrdemServicoServiceImpl{
-		osRepo:          osRepo,
-		veiculoRepo:     veiculoRepo,
-		clienteRepo:     clienteRepo,
-		estoqueRepo:     estoqueRepo,
+		osRepo:              osRepo,
+		veiculoRepo:         veiculoRepo,
+		clienteRepo:         clienteRepo,
+		estoqueRepo:         estoqueRepo,
+		funcionarioRepo:     funcionarioRepo,
+		orcamentoRepo:       orcamentoRepo,
+		servicoRepo:         servicoRepo,
+		movimentacaoService: movimentacaoService,
+		manutencaoService:   manutencaoService,
+		permissaoService:    permissaoService,
+		uow:                 uow,
 	}
 }
 
-func (s *OrdemServicoServiceImpl) BuscarTodas() ([]models.OrdemServico, error) {
-	return s.osRepo.FindAll()
+func (s *OrdemServicoServiceImpl) BuscarTodas(consulta repositories.Consulta) (*models.Pagina[models.OrdemServico], error) {
+	return s.osRepo.FindPaginado(consulta)
 }
 
 func (s *OrdemServicoServiceImpl) BuscarPorID(id uint) (*models.OrdemServico, error) {
@@ -73,6 +106,9 @@ func (s *OrdemServicoServiceImpl) Criar(os *models.OrdemServico) (*models.OrdemS
 	if os.Descricao == "" {
 		return nil, errors.New("descrição do serviço é obrigatória")
 	}
+	if os.KmEntrada < 0 {
+		return nil, errors.New("a quilometragem de entrada não pode ser negativa")
+	}
 
 	// Verificar se o veículo existe
 	veiculo, err := s.veiculoRepo.FindByID(os.VeiculoID)
@@ -91,88 +127,159 @@ func (s *OrdemServicoServiceImpl) Criar(os *models.OrdemServico) (*models.OrdemS
 		return nil, errors.New("o veículo não pertence ao cliente informado")
 	}
 
+	// Validar o mecânico responsável, se informado
+	os.Funcionario = nil
+	if os.FuncionarioID != nil {
+		funcionario, err := s.validarFuncionario(s.funcionarioRepo, *os.FuncionarioID)
+		if err != nil {
+			return nil, err
+		}
+		os.Funcionario = funcionario
+	}
+
+	// Os valores são calculados a partir das peças, da mão de obra e dos pagamentos lançados depois
+	os.ValorPecas = 0
+	os.ValorServico = 0
+	os.ValorDesconto = 0
+	os.ValorPago = 0
+	os.StatusFinanceiro = models.StatusFinanceiroPendente
+	os.Pagamentos = nil
+
+	// Conclusão e entrega só são registradas pelas transições de status
+	os.DataConclusao = nil
+	os.DataEntrega = nil
+	os.EntregaLiberadaPor = nil
+	os.MotivoLiberacao = ""
+
+	// Peças e mão de obra entram pelos endpoints próprios, que baixam o estoque e recalculam os valores
+	os.ItensUtilizados = nil
+	os.Servicos = nil
+
+	// Toda OS nasce aberta; os demais status dependem de orçamento aprovado, mecânico e pagamento
+	os.Status = "aberta"
+
 	// Definir valores padrão
 	if os.DataEntrada.IsZero() {
 		os.DataEntrada = time.Now()
 	}
-	if os.Status == "" {
-		os.Status = "aberta"
-	}
 
-	// Persistir a ordem de serviço
-	err = s.osRepo.Create(os)
+	// Persistir a ordem de serviço junto com a leitura do hodômetro
+	err = s.uow.Executar(func(tx *gorm.DB) error {
+		if err := s.osRepo.WithTx(tx).Create(os); err != nil {
+			return errors.New("erro ao criar ordem de serviço: " + err.Error())
+		}
+		return s.registrarKmEntrada(tx, os, nil)
+	})
 	if err != nil {
-		return nil, errors.New("erro ao criar ordem de serviço: " + err.Error())
+		return nil, err
 	}
 
 	return os, nil
 }
 
-func (s *OrdemServicoServiceImpl) Atualizar(os *models.OrdemServico) (*models.OrdemServico, error) {
-	// Verificar se a OS existe
-	osExistente, err := s.osRepo.FindByID(os.ID)
-	if err != nil {
-		return nil, errors.New("ordem de serviço não encontrada")
+// Atualizar altera os dados editáveis da OS. A OS é lida com bloqueio na mesma transação da gravação,
+// para não sobrescrever os valores gravados ao mesmo tempo por pagamentos, peças e serviços
+func (s *OrdemServicoServiceImpl) Atualizar(os *models.OrdemServico, usuarioID *uint) (*models.OrdemServico, error) {
+	// A permissão de desconto é consultada antes da transação, pois não depende da OS
+	podeDescontar := false
+	if usuarioID != nil {
+		podeDescontar, _ = s.permissaoService.UsuarioTemPermissao(*usuarioID, models.PermOrdensServicoDesconto)
 	}
 
-	// Não permitir alterar OS concluídas ou canceladas
-	if osExistente.Status == "concluida" || osExistente.Status == "cancelada" {
-		return nil, errors.New("não é possível alterar uma ordem de serviço concluída ou cancelada")
-	}
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
 
-	// Validações básicas
-	if os.Descricao == "" {
-		return nil, errors.New("descrição do serviço é obrigatória")
-	}
+		// Verificar se a OS existe
+		osExistente, err := osRepo.FindByIDForUpdate(os.ID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
 
-	// Atualizar apenas campos permitidos
-	osExistente.DataPrevisao = os.DataPrevisao
-	osExistente.Descricao = os.Descricao
-	osExistente.Diagnostico = os.Diagnostico
-	osExistente.ValorServico = os.ValorServico
-	osExistente.ValorDesconto = os.ValorDesconto
-	osExistente.FormaPagamento = os.FormaPagamento
-	osExistente.Observacoes = os.Observacoes
-	osExistente.ServicosRealizados = os.ServicosRealizados
+		// Não permitir alterar OS concluídas, entregues ou canceladas
+		if osExistente.Finalizada() {
+			return errors.New("não é possível alterar uma ordem de serviço concluída, entregue ou cancelada")
+		}
 
-	// Se mudar o status, verificar se é uma transição válida
-	if os.Status != "" && os.Status != osExistente.Status {
-		if !isValidStatusTransition(osExistente.Status, os.Status) {
-			return nil, fmt.Errorf("transição de status inválida: de %s para %s", osExistente.Status, os.Status)
+		// Validações básicas
+		if os.Descricao == "" {
+			return errors.New("descrição do serviço é obrigatória")
 		}
-		osExistente.Status = os.Status
 
-		// Se estiver concluindo a OS, registrar a data de conclusão
-		if os.Status == "concluida" && osExistente.DataConclusao == nil {
-			now := time.Now()
-			osExistente.DataConclusao = &now
+		if os.ValorDesconto != osExistente.ValorDesconto {
+			if err := validarDesconto(osExistente, os.ValorDesconto, podeDescontar); err != nil {
+				return err
+			}
 		}
-	}
 
-	// Persistir as alterações
-	err = s.osRepo.Update(osExistente)
+		// Atualizar apenas campos permitidos
+		osExistente.DataPrevisao = os.DataPrevisao
+		osExistente.Descricao = os.Descricao
+		osExistente.Diagnostico = os.Diagnostico
+		osExistente.ValorDesconto = os.ValorDesconto
+		osExistente.FormaPagamento = os.FormaPagamento
+		osExistente.Observacoes = os.Observacoes
+		osExistente.ServicosRealizados = os.ServicosRealizados
+
+		// Atribuir ou trocar o mecânico responsável, se informado
+		if os.FuncionarioID != nil && (osExistente.FuncionarioID == nil || *os.FuncionarioID != *osExistente.FuncionarioID) {
+			if _, err := s.validarFuncionario(s.funcionarioRepo.WithTx(tx), *os.FuncionarioID); err != nil {
+				return err
+			}
+			osExistente.FuncionarioID = os.FuncionarioID
+		}
+
+		// Se mudar o status, verificar se é uma transição válida
+		if os.Status != "" && os.Status != osExistente.Status {
+			return s.mudarStatusNaTransacao(tx, osExistente, os.Status)
+		}
+
+		if err := osRepo.Update(osExistente); err != nil {
+			return errors.New("erro ao atualizar ordem de serviço: " + err.Error())
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.New("erro ao atualizar ordem de serviço: " + err.Error())
+		return nil, err
 	}
 
-	return osExistente, nil
+	return s.osRepo.FindByID(os.ID)
 }
 
+// AtualizarStatus muda o status da OS, registrando as datas de conclusão e entrega
 func (s *OrdemServicoServiceImpl) AtualizarStatus(id uint, novoStatus string) (*models.OrdemServico, error) {
-	// Buscar a OS
-	os, err := s.osRepo.FindByID(id)
-	if err != nil {
-		return nil, errors.New("ordem de serviço não encontrada")
-	}
-
 	// Validar o status
 	if !isValidStatus(novoStatus) {
 		return nil, errors.New("status inválido")
 	}
 
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		// Buscar a OS, bloqueando a linha até gravar o novo status
+		os, err := s.osRepo.WithTx(tx).FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+		return s.mudarStatusNaTransacao(tx, os, novoStatus)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindByID(id)
+}
+
+// mudarStatusNaTransacao valida a transição e os requisitos do novo status e grava a OS já bloqueada.
+// Ao concluir, renova os planos de manutenção atendidos pelos serviços da OS
+func (s *OrdemServicoServiceImpl) mudarStatusNaTransacao(tx *gorm.DB, os *models.OrdemServico, novoStatus string) error {
+	osRepo := s.osRepo.WithTx(tx)
+
 	// Verificar transição válida
 	if !isValidStatusTransition(os.Status, novoStatus) {
-		return nil, fmt.Errorf("transição de status inválida: de %s para %s", os.Status, novoStatus)
+		return fmt.Errorf("transição de status inválida: de %s para %s", os.Status, novoStatus)
+	}
+
+	// Verificar requisitos do novo status (ex.: mecânico atribuído)
+	if err := s.validarRequisitosStatus(s.orcamentoRepo.WithTx(tx), os, novoStatus); err != nil {
+		return err
 	}
 
 	// Atualizar status
@@ -184,13 +291,27 @@ func (s *OrdemServicoServiceImpl) AtualizarStatus(id uint, novoStatus string) (*
 		os.DataConclusao = &now
 	}
 
+	// Se entregando o veículo, registrar data de entrega
+	if novoStatus == "entregue" && os.DataEntrega == nil {
+		now := time.Now()
+		os.DataEntrega = &now
+	}
+
 	// Persistir as alterações
-	err = s.osRepo.Update(os)
-	if err != nil {
-		return nil, errors.New("erro ao atualizar status: " + err.Error())
+	if err := osRepo.Update(os); err != nil {
+		return errors.New("erro ao atualizar status: " + err.Error())
+	}
+	if novoStatus != "concluida" {
+		return nil
 	}
 
-	return os, nil
+	// Os planos de manutenção são renovados pelos serviços do catálogo executados na OS
+	servicos, err := osRepo.FindServicos(os.ID)
+	if err != nil {
+		return errors.New("erro ao buscar serviços da OS")
+	}
+	os.Servicos = servicos
+	return s.manutencaoService.RegistrarRealizacoesNaTransacao(tx, os)
 }
 
 func (s *OrdemServicoServiceImpl) Deletar(id uint) error {
@@ -200,11 +321,16 @@ func (s *OrdemServicoServiceImpl) Deletar(id uint) error {
 		return errors.New("ordem de serviço não encontrada")
 	}
 
-	// Não permitir excluir OS concluídas ou em andamento
-	if os.Status == "concluida" || os.Status == "emandamento" {
+	// Não permitir excluir OS concluídas, entregues ou em andamento
+	if os.Status == "concluida" || os.Status == "entregue" || os.Status == "emandamento" {
 		return errors.New("não é possível excluir uma ordem de serviço concluída ou em andamento")
 	}
 
+	// Pagamentos recebidos precisam ser estornados antes
+	if os.ValorPago > 0 {
+		return errors.New("não é possível excluir uma ordem de serviço com pagamentos registrados")
+	}
+
 	// Excluir a OS
 	return s.osRepo.Delete(id)
 }
@@ -255,27 +381,94 @@ func (s *OrdemServicoServiceImpl) BuscarPorNumeroOS(numeroOS string) (*models.Or
 	return s.osRepo.FindByNumeroOS(numeroOS)
 }
 
-func (s *OrdemServicoServiceImpl) AdicionarItem(osID uint, item *models.ItemOrdemServico) (*models.ItemOrdemServico, error) {
+func (s *OrdemServicoServiceImpl) BuscarPorFuncionario(funcionarioID uint, incluirFinalizadas bool) ([]models.OrdemServico, error) {
+	// Verificar se o funcionário existe
+	_, err := s.funcionarioRepo.FindByID(funcionarioID)
+	if err != nil {
+		return nil, errors.New("funcionário não encontrado")
+	}
+
+	// Por padrão, a fila mostra apenas as ordens ainda em aberto
+	var status []string
+	if !incluirFinalizadas {
+		status = []string{"aberta", "emandamento"}
+	}
+
+	return s.osRepo.FindByFuncionarioID(funcionarioID, status)
+}
+
+// AtribuirFuncionario define ou troca o mecânico responsável pela OS
+func (s *OrdemServicoServiceImpl) AtribuirFuncionario(id uint, funcionarioID uint) (*models.OrdemServico, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+
+		os, err := osRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		// Não permitir alterar o responsável de OS concluídas, entregues ou canceladas
+		if os.Finalizada() {
+			return errors.New("não é possível alterar o responsável de uma OS concluída, entregue ou cancelada")
+		}
+
+		funcionario, err := s.validarFuncionario(s.funcionarioRepo.WithTx(tx), funcionarioID)
+		if err != nil {
+			return err
+		}
+
+		os.FuncionarioID = &funcionario.ID
+		if err := osRepo.Update(os); err != nil {
+			return errors.New("erro ao atribuir funcionário: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindByID(id)
+}
+
+// AdicionarItem inclui uma peça do estoque na OS, baixando o estoque e somando ao valor de peças.
+// Tudo acontece em uma transação, com a linha do estoque bloqueada para evitar venda acima do saldo
+func (s *OrdemServicoServiceImpl) AdicionarItem(osID uint, item *models.ItemOrdemServico, usuarioID *uint) (*models.ItemOrdemServico, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		return s.AdicionarItemNaTransacao(tx, osID, item, usuarioID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// AdicionarItemNaTransacao inclui a peça na OS dentro de uma transação já aberta pelo chamador
+// (ex.: conversão de orçamento em OS)
+func (s *OrdemServicoServiceImpl) AdicionarItemNaTransacao(tx *gorm.DB, osID uint, item *models.ItemOrdemServico, usuarioID *uint) error {
+	osRepo := s.osRepo.WithTx(tx)
+	estoqueRepo := s.estoqueRepo.WithTx(tx)
+
 	// Verificar se a OS existe
-	os, err := s.osRepo.FindByID(osID)
+	os, err := osRepo.FindByIDForUpdate(osID)
 	if err != nil {
-		return nil, errors.New("ordem de serviço não encontrada")
+		return errors.New("ordem de serviço não encontrada")
 	}
 
 	// Não permitir adicionar itens a OS concluídas ou canceladas
-	if os.Status == "concluida" || os.Status == "cancelada" {
-		return nil, errors.New("não é possível adicionar itens a uma OS concluída ou cancelada")
+	if os.Finalizada() {
+		return errors.New("não é possível adicionar itens a uma OS concluída ou cancelada")
 	}
 
-	// Verificar se o item existe no estoque
-	estoqueItem, err := s.estoqueRepo.FindByID(item.EstoqueID)
+	// Verificar se o item existe no estoque, bloqueando a linha
+	estoqueItem, err := estoqueRepo.FindByIDForUpdate(item.EstoqueID)
 	if err != nil {
-		return nil, errors.New("item de estoque não encontrado")
+		return errors.New("item de estoque não encontrado")
 	}
 
-	// Verificar se há quantidade suficiente
-	if estoqueItem.Quantidade < item.Quantidade {
-		return nil, errors.New("quantidade insuficiente em estoque")
+	// Verificar se há quantidade suficiente fora das reservas de orçamentos
+	if estoqueItem.QuantidadeDisponivel() < item.Quantidade {
+		return errors.New("quantidade insuficiente em estoque")
 	}
 
 	// Definir valores do item
@@ -286,210 +479,558 @@ func (s *OrdemServicoServiceImpl) AdicionarItem(osID uint, item *models.ItemOrde
 	item.ValorTotal = float64(item.Quantidade) * item.ValorUnitario
 
 	// Adicionar o item
-	err = s.osRepo.AddItem(item)
-	if err != nil {
-		return nil, errors.New("erro ao adicionar item: " + err.Error())
+	if err := osRepo.AddItem(item); err != nil {
+		return errors.New("erro ao adicionar item: " + err.Error())
 	}
 
-	// Atualizar estoque
-	estoqueItem.Quantidade -= item.Quantidade
-	err = s.estoqueRepo.Update(estoqueItem)
-	if err != nil {
-		return nil, errors.New("erro ao atualizar estoque: " + err.Error())
+	// Registrar a saída do estoque
+	if err := s.movimentarEstoque(tx, os, item, models.TipoMovimentacaoSaida, item.Quantidade, usuarioID); err != nil {
+		return err
 	}
 
 	// Atualizar valor de peças da OS
 	os.ValorPecas += item.ValorTotal
-	err = s.osRepo.Update(os)
+	if err := osRepo.Update(os); err != nil {
+		return errors.New("erro ao atualizar valor total da OS: " + err.Error())
+	}
+
+	return nil
+}
+
+// RemoverItem retira uma peça da OS, devolvendo a quantidade ao estoque em uma única transação
+func (s *OrdemServicoServiceImpl) RemoverItem(osID uint, itemID uint, usuarioID *uint) error {
+	return s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+
+		// Verificar se a OS existe
+		os, err := osRepo.FindByIDForUpdate(osID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		// Não permitir remover itens de OS concluídas ou canceladas
+		if os.Finalizada() {
+			return errors.New("não é possível remover itens de uma OS concluída ou cancelada")
+		}
+
+		// Verificar se o item pertence à OS
+		itemParaRemover, err := osRepo.FindItem(osID, itemID)
+		if err != nil {
+			return errors.New("item não encontrado na ordem de serviço")
+		}
+
+		// Devolver ao estoque
+		if err := s.movimentarEstoque(tx, os, itemParaRemover, models.TipoMovimentacaoEntrada, itemParaRemover.Quantidade, usuarioID); err != nil {
+			return err
+		}
+
+		// Atualizar valor da OS
+		os.ValorPecas -= itemParaRemover.ValorTotal
+		if os.ValorPecas < 0 {
+			os.ValorPecas = 0
+		}
+		if err := conferirValorPago(os); err != nil {
+			return err
+		}
+		if err := osRepo.Update(os); err != nil {
+			return errors.New("erro ao atualizar valor da OS: " + err.Error())
+		}
+
+		// Remover o item
+		if err := osRepo.RemoveItem(itemID); err != nil {
+			return errors.New("erro ao remover item: " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// AtualizarItem altera a quantidade/valor de uma peça da OS, ajustando estoque e valor de peças atomicamente
+func (s *OrdemServicoServiceImpl) AtualizarItem(osID uint, item *models.ItemOrdemServico, usuarioID *uint) (*models.ItemOrdemServico, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+
+		// Verificar se a OS existe
+		os, err := osRepo.FindByIDForUpdate(osID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		// Não permitir atualizar itens de OS concluídas ou canceladas
+		if os.Finalizada() {
+			return errors.New("não é possível atualizar itens de uma OS concluída ou cancelada")
+		}
+
+		// Buscar o item atual
+		itemAtual, err := osRepo.FindItem(osID, item.ID)
+		if err != nil {
+			return errors.New("item não encontrado na ordem de serviço")
+		}
+
+		// Calcular diferença de quantidade e movimentar o estoque;
+		// o serviço de movimentações verifica se há saldo suficiente
+		diferencaQuantidade := item.Quantidade - itemAtual.Quantidade
+		if diferencaQuantidade > 0 {
+			if err := s.movimentarEstoque(tx, os, itemAtual, models.TipoMovimentacaoSaida, diferencaQuantidade, usuarioID); err != nil {
+				return err
+			}
+		} else if diferencaQuantidade < 0 {
+			if err := s.movimentarEstoque(tx, os, itemAtual, models.TipoMovimentacaoEntrada, -diferencaQuantidade, usuarioID); err != nil {
+				return err
+			}
+		}
+
+		// O item continua apontando para a mesma OS e a mesma peça do estoque
+		item.OrdemServicoID = osID
+		item.EstoqueID = itemAtual.EstoqueID
+		item.CreatedAt = itemAtual.CreatedAt
+
+		// Como em AdicionarItem, sem valor positivo vale o preço de venda da peça
+		if item.ValorUnitario <= 0 {
+			estoqueItem, err := s.estoqueRepo.WithTx(tx).FindByID(item.EstoqueID)
+			if err != nil {
+				return errors.New("item de estoque não encontrado")
+			}
+			item.ValorUnitario = estoqueItem.PrecoVenda
+		}
+
+		// Calcular novo valor total do item
+		valorTotalAnterior := itemAtual.ValorTotal
+		item.ValorTotal = float64(item.Quantidade) * item.ValorUnitario
+
+		// Atualizar o item
+		if err := osRepo.UpdateItem(item); err != nil {
+			return errors.New("erro ao atualizar item: " + err.Error())
+		}
+
+		// Atualizar valor da OS
+		os.ValorPecas = os.ValorPecas - valorTotalAnterior + item.ValorTotal
+		if err := conferirValorPago(os); err != nil {
+			return err
+		}
+		if err := osRepo.Update(os); err != nil {
+			return errors.New("erro ao atualizar valor da OS: " + err.Error())
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, errors.New("erro ao atualizar valor total da OS: " + err.Error())
+		return nil, err
 	}
 
 	return item, nil
 }
 
-func (s *OrdemServicoServiceImpl) RemoverItem(osID uint, itemID uint) error {
+func (s *OrdemServicoServiceImpl) BuscarItens(osID uint) ([]models.ItemOrdemServico, error) {
 	// Verificar se a OS existe
-	os, err := s.osRepo.FindByID(osID)
+	_, err := s.osRepo.FindByID(osID)
 	if err != nil {
-		return errors.New("ordem de serviço não encontrada")
+		return nil, errors.New("ordem de serviço não encontrada")
 	}
 
-	// Não permitir remover itens de OS concluídas ou canceladas
-	if os.Status == "concluida" || os.Status == "cancelada" {
-		return errors.New("não é possível remover itens de uma OS concluída ou cancelada")
-	}
+	return s.osRepo.FindItens(osID)
+}
 
-	// Buscar itens da OS
-	itens, err := s.osRepo.FindItens(osID)
+// AdicionarServico lança uma linha de mão de obra na OS e recalcula o valor de serviços
+func (s *OrdemServicoServiceImpl) AdicionarServico(osID uint, linha *models.ServicoOrdemServico) (*models.ServicoOrdemServico, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		return s.AdicionarServicoNaTransacao(tx, osID, linha)
+	})
 	if err != nil {
-		return errors.New("erro ao buscar itens da OS")
+		return nil, err
 	}
 
-	// Verificar se o item pertence à OS
-	var itemParaRemover *models.ItemOrdemServico
-	for _, i := range itens {
-		if i.ID == itemID {
-			itemParaRemover = &i
-			break
-		}
-	}
+	return s.osRepo.FindServico(osID, linha.ID)
+}
 
-	if itemParaRemover == nil {
-		return errors.New("item não encontrado na ordem de serviço")
-	}
+// AdicionarServicoNaTransacao lança a linha de mão de obra dentro de uma transação já aberta pelo chamador
+// (ex.: conversão de orçamento em OS)
+func (s *OrdemServicoServiceImpl) AdicionarServicoNaTransacao(tx *gorm.DB, osID uint, linha *models.ServicoOrdemServico) error {
+	osRepo := s.osRepo.WithTx(tx)
 
-	// Devolver ao estoque
-	estoqueItem, err := s.estoqueRepo.FindByID(itemParaRemover.EstoqueID)
+	os, err := osRepo.FindByIDForUpdate(osID)
 	if err != nil {
-		return errors.New("item de estoque não encontrado")
+		return errors.New("ordem de serviço não encontrada")
 	}
 
-	// Atualizar estoque
-	estoqueItem.Quantidade += itemParaRemover.Quantidade
-	err = s.estoqueRepo.Update(estoqueItem)
-	if err != nil {
-		return errors.New("erro ao atualizar estoque: " + err.Error())
+	// Não permitir lançar serviços em OS concluídas ou canceladas
+	if os.Finalizada() {
+		return errors.New("não é possível adicionar serviços a uma OS concluída ou cancelada")
 	}
 
-	// Atualizar valor da OS
-	os.ValorPecas -= itemParaRemover.ValorTotal
-	if os.ValorPecas < 0 {
-		os.ValorPecas = 0
+	linha.ID = 0
+	linha.OrdemServicoID = osID
+	if err := s.prepararLinhaServico(tx, os, linha); err != nil {
+		return err
 	}
-	err = s.osRepo.Update(os)
-	if err != nil {
-		return errors.New("erro ao atualizar valor da OS: " + err.Error())
+
+	if err := osRepo.AddServico(linha); err != nil {
+		return errors.New("erro ao adicionar serviço: " + err.Error())
 	}
 
-	// Remover o item
-	return s.osRepo.RemoveItem(itemID)
+	return s.recalcularValorServico(osRepo, os)
 }
 
-func (s *OrdemServicoServiceImpl) AtualizarItem(osID uint, item *models.ItemOrdemServico) (*models.ItemOrdemServico, error) {
-	// Verificar se a OS existe
-	os, err := s.osRepo.FindByID(osID)
+// AtualizarServico altera horas, valor, descrição ou mecânico de uma linha de mão de obra
+func (s *OrdemServicoServiceImpl) AtualizarServico(osID uint, linha *models.ServicoOrdemServico) (*models.ServicoOrdemServico, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+
+		os, err := osRepo.FindByIDForUpdate(osID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		if os.Finalizada() {
+			return errors.New("não é possível atualizar serviços de uma OS concluída ou cancelada")
+		}
+
+		linhaAtual, err := osRepo.FindServico(osID, linha.ID)
+		if err != nil {
+			return errors.New("serviço não encontrado na ordem de serviço")
+		}
+
+		// A linha continua apontando para o mesmo serviço do catálogo
+		linha.OrdemServicoID = osID
+		linha.ServicoID = linhaAtual.ServicoID
+		linha.CreatedAt = linhaAtual.CreatedAt
+		if err := s.prepararLinhaServico(tx, os, linha); err != nil {
+			return err
+		}
+
+		if err := osRepo.UpdateServico(linha); err != nil {
+			return errors.New("erro ao atualizar serviço: " + err.Error())
+		}
+
+		return s.recalcularValorServico(osRepo, os)
+	})
 	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindServico(osID, linha.ID)
+}
+
+// RemoverServico exclui uma linha de mão de obra e recalcula o valor de serviços
+func (s *OrdemServicoServiceImpl) RemoverServico(osID uint, linhaID uint) error {
+	return s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+
+		os, err := osRepo.FindByIDForUpdate(osID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		if os.Finalizada() {
+			return errors.New("não é possível remover serviços de uma OS concluída ou cancelada")
+		}
+
+		if _, err := osRepo.FindServico(osID, linhaID); err != nil {
+			return errors.New("serviço não encontrado na ordem de serviço")
+		}
+
+		if err := osRepo.RemoveServico(linhaID); err != nil {
+			return errors.New("erro ao remover serviço: " + err.Error())
+		}
+
+		return s.recalcularValorServico(osRepo, os)
+	})
+}
+
+// BuscarServicos retorna as linhas de mão de obra de uma OS
+func (s *OrdemServicoServiceImpl) BuscarServicos(osID uint) ([]models.ServicoOrdemServico, error) {
+	if _, err := s.osRepo.FindByID(osID); err != nil {
 		return nil, errors.New("ordem de serviço não encontrada")
 	}
 
-	// Não permitir atualizar itens de OS concluídas ou canceladas
-	if os.Status == "concluida" || os.Status == "cancelada" {
-		return nil, errors.New("não é possível atualizar itens de uma OS concluída ou cancelada")
+	return s.osRepo.FindServicos(osID)
+}
+
+func (s *OrdemServicoServiceImpl) ConcluirOS(id uint) (*models.OrdemServico, error) {
+	return s.AtualizarStatus(id, "concluida")
+}
+
+// Entregar registra a retirada do veículo pelo cliente. Com saldo em aberto a entrega é bloqueada,
+// a menos que seja liberada por quem tem permissão, informando o motivo
+func (s *OrdemServicoServiceImpl) Entregar(id uint, liberarSemPagamento bool, motivo string, usuarioID *uint) (*models.OrdemServico, error) {
+	if liberarSemPagamento && motivo == "" {
+		return nil, errors.New("informe o motivo da entrega sem pagamento")
 	}
 
-	// Buscar o item atual
-	itens, err := s.osRepo.FindItens(osID)
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		os, err := s.osRepo.WithTx(tx).FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		// A entrega acontece uma única vez: entregar de novo sobrescreveria a data e quem liberou
+		if os.Status != "concluida" {
+			return fmt.Errorf("só é possível entregar uma OS concluída (status atual: %s)", os.Status)
+		}
+		if !liberarSemPagamento {
+			return s.mudarStatusNaTransacao(tx, os, "entregue")
+		}
+
+		agora := time.Now()
+		os.Status = "entregue"
+		os.DataEntrega = &agora
+		os.EntregaLiberadaPor = usuarioID
+		os.MotivoLiberacao = motivo
+		if err := s.osRepo.WithTx(tx).Update(os); err != nil {
+			return errors.New("erro ao registrar entrega: " + err.Error())
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, errors.New("erro ao buscar itens da OS")
+		return nil, err
 	}
 
-	var itemAtual *models.ItemOrdemServico
-	for _, i := range itens {
-		if i.ID == item.ID {
-			itemAtual = &i
-			break
+	return s.osRepo.FindByID(id)
+}
+
+// CancelarOS cancela a OS devolvendo todos os itens ao estoque.
+// A devolução e a mudança de status são gravadas na mesma transação
+func (s *OrdemServicoServiceImpl) CancelarOS(id uint, usuarioID *uint) (*models.OrdemServico, error) {
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+
+		// Buscar a OS
+		os, err := osRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
 		}
-	}
 
-	if itemAtual == nil {
-		return nil, errors.New("item não encontrado na ordem de serviço")
-	}
+		// Não permitir cancelar OS concluídas, entregues ou já canceladas (as peças voltariam duas vezes ao estoque)
+		if os.Finalizada() {
+			return errors.New("não é possível cancelar uma OS concluída, entregue ou já cancelada")
+		}
+
+		// Pagamentos recebidos precisam ser estornados antes do cancelamento
+		if os.ValorPago > 0 {
+			return errors.New("estorne os pagamentos da OS antes de cancelá-la")
+		}
+
+		// Verificar transição válida
+		if !isValidStatusTransition(os.Status, "cancelada") {
+			return fmt.Errorf("transição de status inválida: de %s para %s", os.Status, "cancelada")
+		}
+
+		// Devolver itens ao estoque
+		itens, err := osRepo.FindItens(id)
+		if err != nil {
+			return errors.New("erro ao buscar itens da OS")
+		}
+
+		// Para cada item, devolver ao estoque
+		for i := range itens {
+			if err := s.movimentarEstoque(tx, os, &itens[i], models.TipoMovimentacaoEntrada, itens[i].Quantidade, usuarioID); err != nil {
+				return err
+			}
+		}
 
-	// Verificar o estoque
-	estoqueItem, err := s.estoqueRepo.FindByID(itemAtual.EstoqueID)
+		// Atualizar status da OS
+		os.Status = "cancelada"
+		if err := osRepo.Update(os); err != nil {
+			return errors.New("erro ao atualizar status: " + err.Error())
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, errors.New("item de estoque não encontrado")
+		return nil, err
 	}
 
-	// Calcular diferença de quantidade
-	diferencaQuantidade := item.Quantidade - itemAtual.Quantidade
+	return s.osRepo.FindByID(id)
+}
+
+// movimentarEstoque registra, na transação da OS, a entrada ou saída de estoque causada por um item
+func (s *OrdemServicoServiceImpl) movimentarEstoque(tx *gorm.DB, os *models.OrdemServico, item *models.ItemOrdemServico, tipo string, quantidade int, usuarioID *uint) error {
+	motivo := "Peça utilizada na OS " + os.NumeroOS
+	if tipo == models.TipoMovimentacaoEntrada {
+		motivo = "Peça devolvida da OS " + os.NumeroOS
+	}
+
+	osID := os.ID
+	itemID := item.ID
+	_, err := s.movimentacaoService.RegistrarNaTransacao(tx, &models.MovimentacaoEstoque{
+		EstoqueID:          item.EstoqueID,
+		Tipo:               tipo,
+		Quantidade:         quantidade,
+		UsuarioID:          usuarioID,
+		Motivo:             motivo,
+		OrdemServicoID:     &osID,
+		ItemOrdemServicoID: &itemID,
+	})
+	return err
+}
+
+// prepararLinhaServico completa a linha com os valores do catálogo e valida horas, valor e mecânico.
+// Sem mecânico informado, a linha fica com o responsável pela OS
+func (s *OrdemServicoServiceImpl) prepararLinhaServico(tx *gorm.DB, os *models.OrdemServico, linha *models.ServicoOrdemServico) error {
+	linha.Servico = nil
+	linha.Funcionario = nil
+
+	if linha.ServicoID != nil {
+		servico, err := s.servicoRepo.WithTx(tx).FindByID(*linha.ServicoID)
+		if err != nil {
+			return errors.New("serviço não encontrado no catálogo")
+		}
+		if linha.ID == 0 && !servico.Ativo {
+			return fmt.Errorf("o serviço %s está inativo", servico.Nome)
+		}
+		if linha.Descricao == "" {
+			linha.Descricao = servico.Nome
+		}
+		if linha.Horas == 0 {
+			linha.Horas = servico.HorasPadrao
+		}
+		if linha.ValorHora == 0 {
+			linha.ValorHora = servico.ValorHora
+		}
+	} else if linha.Descricao == "" {
+		return errors.New("informe o serviço do catálogo ou a descrição do serviço")
+	}
 
-	// Verificar se há estoque suficiente
-	if diferencaQuantidade > 0 && estoqueItem.Quantidade < diferencaQuantidade {
-		return nil, errors.New("quantidade insuficiente em estoque")
+	if linha.Horas <= 0 {
+		return errors.New("as horas devem ser maiores que zero")
+	}
+	if linha.ValorHora < 0 {
+		return errors.New("o valor da hora não pode ser negativo")
 	}
 
-	// Atualizar estoque
-	estoqueItem.Quantidade -= diferencaQuantidade
-	err = s.estoqueRepo.Update(estoqueItem)
-	if err != nil {
-		return nil, errors.New("erro ao atualizar estoque: " + err.Error())
+	if linha.FuncionarioID == nil {
+		linha.FuncionarioID = os.FuncionarioID
+	} else if _, err := s.validarFuncionario(s.funcionarioRepo.WithTx(tx), *linha.FuncionarioID); err != nil {
+		return err
 	}
 
-	// Calcular novo valor total do item
-	valorTotalAnterior := itemAtual.ValorTotal
-	item.ValorTotal = float64(item.Quantidade) * item.ValorUnitario
+	return nil
+}
+
+// registrarKmEntrada grava o hodômetro informado na abertura da OS como leitura de km do veículo.
+// OS abertas sem quilometragem não geram leitura
+func (s *OrdemServicoServiceImpl) registrarKmEntrada(tx *gorm.DB, os *models.OrdemServico, usuarioID *uint) error {
+	if os.KmEntrada <= 0 {
+		return nil
+	}
+	osID := os.ID
+	return s.manutencaoService.RegistrarLeituraNaTransacao(tx, &models.LeituraKm{
+		VeiculoID:      os.VeiculoID,
+		Km:             os.KmEntrada,
+		DataLeitura:    os.DataEntrada,
+		Origem:         models.OrigemLeituraKmOrdemServico,
+		OrdemServicoID: &osID,
+		UsuarioID:      usuarioID,
+	})
+}
 
-	// Atualizar o item
-	err = s.osRepo.UpdateItem(item)
+// recalcularValorServico atualiza o valor de serviços da OS com a soma das linhas de mão de obra
+func (s *OrdemServicoServiceImpl) recalcularValorServico(osRepo repositories.OrdemServicoRepository, os *models.OrdemServico) error {
+	total, err := osRepo.SomarServicos(os.ID)
 	if err != nil {
-		return nil, errors.New("erro ao atualizar item: " + err.Error())
+		return errors.New("erro ao calcular valor de serviços da OS")
 	}
 
-	// Atualizar valor da OS
-	os.ValorPecas = os.ValorPecas - valorTotalAnterior + item.ValorTotal
-	err = s.osRepo.Update(os)
-	if err != nil {
-		return nil, errors.New("erro ao atualizar valor da OS: " + err.Error())
+	os.ValorServico = total
+	if err := conferirValorPago(os); err != nil {
+		return err
+	}
+	if err := osRepo.Update(os); err != nil {
+		return errors.New("erro ao atualizar valor da OS: " + err.Error())
 	}
+	return nil
+}
 
-	return item, nil
+// conferirValorPago impede que peças ou serviços retirados deixem o total da OS abaixo do que o cliente já pagou.
+// A diferença teria de ser devolvida, então o pagamento precisa ser estornado antes
+func conferirValorPago(os *models.OrdemServico) error {
+	total := math.Round((os.ValorPecas+os.ValorServico-os.ValorDesconto)*100) / 100
+	if total < os.ValorPago {
+		return fmt.Errorf("a alteração deixaria o total da OS (%s) abaixo do valor já pago (%s); estorne o pagamento antes",
+			formatarMoeda(total), formatarMoeda(os.ValorPago))
+	}
+	return nil
 }
 
-func (s *OrdemServicoServiceImpl) BuscarItens(osID uint) ([]models.ItemOrdemServico, error) {
-	// Verificar se a OS existe
-	_, err := s.osRepo.FindByID(osID)
+// validarFuncionario verifica se o funcionário existe e pode receber ordens de serviço.
+// Recebe o repositório para que a consulta use a transação do chamador, quando houver
+func (s *OrdemServicoServiceImpl) validarFuncionario(funcionarioRepo repositories.FuncionarioRepository, funcionarioID uint) (*models.Funcionario, error) {
+	funcionario, err := funcionarioRepo.FindByID(funcionarioID)
 	if err != nil {
-		return nil, errors.New("ordem de serviço não encontrada")
+		return nil, errors.New("funcionário não encontrado")
 	}
 
-	return s.osRepo.FindItens(osID)
-}
+	// As férias são controladas no usuário vinculado ao funcionário
+	if funcionario.Usuario != nil && funcionario.Usuario.Ferias {
+		return nil, fmt.Errorf("o funcionário %s está de férias", funcionario.Nome)
+	}
 
-func (s *OrdemServicoServiceImpl) ConcluirOS(id uint) (*models.OrdemServico, error) {
-	return s.AtualizarStatus(id, "concluida")
+	return funcionario, nil
 }
 
-func (s *OrdemServicoServiceImpl) CancelarOS(id uint) (*models.OrdemServico, error) {
-	// Buscar a OS
-	os, err := s.osRepo.FindByID(id)
-	if err != nil {
-		return nil, errors.New("ordem de serviço não encontrada")
+// validarDesconto verifica se o usuário pode conceder o desconto e se ele cabe no valor da OS.
+// O total com desconto também não pode ficar abaixo do que o cliente já pagou
+func validarDesconto(os *models.OrdemServico, desconto float64, permitido bool) error {
+	if !permitido {
+		return errors.New("alterar o desconto exige a permissão " + models.PermOrdensServicoDesconto)
 	}
 
-	// Não permitir cancelar OS concluídas
-	if os.Status == "concluida" {
-		return nil, errors.New("não é possível cancelar uma OS concluída")
+	if desconto < 0 {
+		return errors.New("o desconto não pode ser negativo")
+	}
+	bruto := os.ValorPecas + os.ValorServico
+	if desconto > bruto {
+		return fmt.Errorf("o desconto não pode ser maior que o valor de peças e serviços (%s)", formatarMoeda(bruto))
+	}
+	if bruto-desconto < os.ValorPago {
+		return fmt.Errorf("o desconto deixaria o total abaixo do valor já pago (%s)", formatarMoeda(os.ValorPago))
 	}
+	return nil
+}
 
-	// Devolver itens ao estoque
-	itens, err := s.osRepo.FindItens(id)
-	if err != nil {
-		return nil, errors.New("erro ao buscar itens da OS")
+// Funções auxiliares
+
+// validarRequisitosStatus verifica as condições necessárias para a OS assumir o novo status
+func (s *OrdemServicoServiceImpl) validarRequisitosStatus(orcamentoRepo repositories.OrcamentoRepository, os *models.OrdemServico, novoStatus string) error {
+	// O cancelamento devolve as peças ao estoque e confere os pagamentos; só pode ser feito por CancelarOS
+	if novoStatus == "cancelada" {
+		return errors.New("use o cancelamento da OS para cancelá-la")
 	}
 
-	// Para cada item, devolver ao estoque
-	for _, item := range itens {
-		estoqueItem, err := s.estoqueRepo.FindByID(item.EstoqueID)
-		if err != nil {
-			return nil, errors.New("item de estoque não encontrado: " + err.Error())
+	// O veículo só sai da oficina com a OS quitada; a liberação sem pagamento é feita em Entregar
+	if novoStatus == "entregue" {
+		if saldo := os.CalcularSaldoDevedor(); saldo > 0 {
+			return fmt.Errorf("a OS possui saldo em aberto de %s; registre o pagamento ou libere a entrega", formatarMoeda(saldo))
 		}
+		return nil
+	}
+
+	if novoStatus != "emandamento" {
+		return nil
+	}
 
-		estoqueItem.Quantidade += item.Quantidade
-		err = s.estoqueRepo.Update(estoqueItem)
+	if os.FuncionarioID == nil {
+		return errors.New("é necessário atribuir um mecânico antes de iniciar o serviço")
+	}
+
+	// O serviço só começa depois que o cliente aprova o orçamento
+	aprovado := false
+	if os.ID != 0 {
+		var err error
+		aprovado, err = orcamentoRepo.ExisteAprovadoParaOS(os.ID)
 		if err != nil {
-			return nil, errors.New("erro ao atualizar estoque: " + err.Error())
+			return errors.New("erro ao verificar orçamento da OS")
 		}
 	}
+	if !aprovado {
+		return errors.New("é necessário um orçamento aprovado pelo cliente antes de iniciar o serviço")
+	}
 
-	// Atualizar status da OS
-	return s.AtualizarStatus(id, "cancelada")
+	return nil
 }
 
-// Funções auxiliares
 func isValidStatus(status string) bool {
-	validStatus := []string{"aberta", "emandamento", "concluida", "cancelada"}
+	validStatus := []string{"aberta", "emandamento", "concluida", "entregue", "cancelada"}
 	for _, s := range validStatus {
 		if s == status {
 			return true
@@ -503,8 +1044,9 @@ func isValidStatusTransition(atual, novo string) bool {
 	transicoes := map[string][]string{
 		"aberta":      {"emandamento", "cancelada"},
 		"emandamento": {"concluida", "cancelada"},
-		"concluida":   {}, // Não permite transição após concluída
-		"cancelada":   {}, // Não permite transição após cancelada
+		"concluida":   {"entregue"}, // Após concluída, apenas a entrega ao cliente
+		"entregue":    {},           // Não permite transição após entregue
+		"cancelada":   {},           // Não permite transição após cancelada
 	}
 
 	// Verifica se a transição é válida
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// forcarStatus grava o status direto no banco, para montar cenários sem passar pelos requisitos de cada etapa
+func (a *ambienteTeste) forcarStatus(t *testing.T, osID uint, status string) {
+	t.Helper()
+	if err := a.db.Model(&models.OrdemServico{}).Where("id = ?", osID).UpdateColumn("status", status).Error; err != nil {
+		t.Fatalf("erro ao alterar status: %v", err)
+	}
+}
+
+func TestIsValidStatusTransition(t *testing.T) {
+	casos := []struct {
+		atual, novo string
+		valida      bool
+	}{
+		{"aberta", "emandamento", true},
+		{"aberta", "cancelada", true},
+		{"aberta", "concluida", false},
+		{"aberta", "entregue", false},
+		{"emandamento", "concluida", true},
+		{"emandamento", "cancelada", true},
+		{"emandamento", "aberta", false},
+		{"concluida", "entregue", true},
+		{"concluida", "cancelada", false},
+		{"entregue", "aberta", false},
+		{"cancelada", "aberta", false},
+		{"cancelada", "cancelada", true},
+		{"desconhecido", "aberta", false},
+	}
+	for _, caso := range casos {
+		if got := isValidStatusTransition(caso.atual, caso.novo); got != caso.valida {
+			t.Errorf("isValidStatusTransition(%q, %q) = %v, esperado %v", caso.atual, caso.novo, got, caso.valida)
+		}
+	}
+}
+
+func TestCriarOSSempreAberta(t *testing.T) {
+	a := novoAmbiente(t)
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+
+	os, err := a.os.Criar(&models.OrdemServico{
+		ClienteID: cliente.ID,
+		VeiculoID: veiculo.ID,
+		Descricao: "Troca de óleo",
+		Status:    "concluida",
+	})
+	if err != nil {
+		t.Fatalf("erro ao criar OS: %v", err)
+	}
+	if os.Status != "aberta" {
+		t.Errorf("status = %q, esperado aberta", os.Status)
+	}
+}
+
+func TestCriarOSRejeitaVeiculoDeOutroCliente(t *testing.T) {
+	a := novoAmbiente(t)
+	cliente, _ := a.novoClienteComVeiculo(t)
+	_, veiculoAlheio := a.novoClienteComVeiculo(t)
+
+	_, err := a.os.Criar(&models.OrdemServico{ClienteID: cliente.ID, VeiculoID: veiculoAlheio.ID, Descricao: "Revisão"})
+	if err == nil {
+		t.Error("a OS não deveria aceitar o veículo de outro cliente")
+	}
+}
+
+func TestIniciarServicoExigeMecanicoEOrcamentoAprovado(t *testing.T) {
+	a := novoAmbiente(t)
+	os := a.novaOS(t)
+
+	if _, err := a.os.AtualizarStatus(os.ID, "emandamento"); err == nil {
+		t.Fatal("o serviço não deveria começar sem mecânico")
+	}
+
+	mecanico := a.novoMecanico(t)
+	if _, err := a.os.AtribuirFuncionario(os.ID, mecanico.ID); err != nil {
+		t.Fatalf("erro ao atribuir mecânico: %v", err)
+	}
+	if _, err := a.os.AtualizarStatus(os.ID, "emandamento"); err == nil {
+		t.Fatal("o serviço não deveria começar sem orçamento aprovado")
+	}
+
+	a.criar(t, &models.Orcamento{
+		ClienteID:      os.ClienteID,
+		VeiculoID:      os.VeiculoID,
+		OrdemServicoID: &os.ID,
+		Versao:         1,
+		Status:         models.StatusOrcamentoAprovado,
+	})
+	atualizada, err := a.os.AtualizarStatus(os.ID, "emandamento")
+	if err != nil {
+		t.Fatalf("erro ao iniciar o serviço: %v", err)
+	}
+	if atualizada.Status != "emandamento" {
+		t.Errorf("status = %q, esperado emandamento", atualizada.Status)
+	}
+}
+
+func TestConcluirRegistraDataEBloqueiaAlteracoes(t *testing.T) {
+	a := novoAmbiente(t)
+	os := a.novaOS(t)
+	a.forcarStatus(t, os.ID, "emandamento")
+
+	concluida, err := a.os.ConcluirOS(os.ID)
+	if err != nil {
+		t.Fatalf("erro ao concluir: %v", err)
+	}
+	if concluida.DataConclusao == nil {
+		t.Error("a data de conclusão deveria ser registrada")
+	}
+
+	if _, err := a.os.AtribuirFuncionario(os.ID, a.novoMecanico(t).ID); err == nil {
+		t.Error("não deveria trocar o mecânico de uma OS concluída")
+	}
+	if _, err := a.os.AtualizarStatus(os.ID, "aberta"); err == nil {
+		t.Error("não deveria reabrir uma OS concluída")
+	}
+
+	a.forcarStatus(t, os.ID, "entregue")
+	if _, err := a.os.AtribuirFuncionario(os.ID, a.novoMecanico(t).ID); err == nil {
+		t.Error("não deveria trocar o mecânico de uma OS entregue")
+	}
+}
+
+func TestCancelamentoSoPeloCancelarOS(t *testing.T) {
+	a := novoAmbiente(t)
+	os := a.novaOS(t)
+
+	if _, err := a.os.AtualizarStatus(os.ID, "cancelada"); err == nil {
+		t.Error("AtualizarStatus não deveria cancelar a OS")
+	}
+	usuario := a.novoUsuario(t, models.CargoAdmin)
+	if _, err := a.os.Atualizar(&models.OrdemServico{ID: os.ID, Descricao: os.Descricao, Status: "cancelada"}, &usuario.ID); err == nil {
+		t.Error("Atualizar não deveria cancelar a OS")
+	}
+
+	atual, _ := a.os.BuscarPorID(os.ID)
+	if atual.Status != "aberta" {
+		t.Errorf("status = %q, esperado aberta", atual.Status)
+	}
+}
+
+func TestCancelarOSDevolveItensAoEstoque(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoGerente)
+	peca := a.novaPeca(t, 10, &usuario.ID)
+	os := a.novaOS(t)
+
+	if _, err := a.os.AdicionarItem(os.ID, &models.ItemOrdemServico{EstoqueID: peca.ID, Quantidade: 3}, &usuario.ID); err != nil {
+		t.Fatalf("erro ao adicionar item: %v", err)
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 7 {
+		t.Fatalf("saldo após o item = %d, esperado 7", saldo)
+	}
+
+	cancelada, err := a.os.CancelarOS(os.ID, &usuario.ID)
+	if err != nil {
+		t.Fatalf("erro ao cancelar: %v", err)
+	}
+	if cancelada.Status != "cancelada" {
+		t.Errorf("status = %q, esperado cancelada", cancelada.Status)
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 10 {
+		t.Errorf("saldo após o cancelamento = %d, esperado 10", saldo)
+	}
+
+	movimentacoes, err := a.movimentacao.BuscarPorEstoque(peca.ID, nil, nil)
+	if err != nil {
+		t.Fatalf("erro ao buscar movimentações: %v", err)
+	}
+	// Saldo inicial, saída para a OS e devolução no cancelamento
+	if len(movimentacoes) != 3 {
+		t.Errorf("movimentações = %d, esperado 3", len(movimentacoes))
+	}
+
+	if _, err := a.os.CancelarOS(os.ID, &usuario.ID); err == nil {
+		t.Error("uma OS cancelada não deveria ser cancelada de novo")
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 10 {
+		t.Errorf("o segundo cancelamento não deveria devolver peças (saldo %d)", saldo)
+	}
+}
+
+func TestCancelarOSConcluidaFalha(t *testing.T) {
+	a := novoAmbiente(t)
+	os := a.novaOS(t)
+	a.forcarStatus(t, os.ID, "concluida")
+
+	if _, err := a.os.CancelarOS(os.ID, nil); err == nil {
+		t.Error("uma OS concluída não deveria ser cancelada")
+	}
+}
+
+// osEmDatas abre uma OS para cada data de entrada informada, gravando a data direto no banco
+func (a *ambienteTeste) osEmDatas(t *testing.T, datas ...time.Time) []uint {
+	t.Helper()
+	ids := make([]uint, len(datas))
+	for i, data := range datas {
+		os := a.novaOS(t)
+		if err := a.db.Model(&models.OrdemServico{}).Where("id = ?", os.ID).UpdateColumn("data_entrada", data).Error; err != nil {
+			t.Fatalf("erro ao alterar data de entrada: %v", err)
+		}
+		ids[i] = os.ID
+	}
+	return ids
+}
+
+// percorrerCursor lê todas as páginas seguindo o proximoCursor e devolve os ids na ordem recebida
+func (a *ambienteTeste) percorrerCursor(t *testing.T, consulta repositories.Consulta) []uint {
+	t.Helper()
+	consulta.UsarCursor = true
+	var ids []uint
+	for paginas := 0; ; paginas++ {
+		if paginas > 10 {
+			t.Fatal("a paginação por cursor não terminou")
+		}
+		pagina, err := a.os.BuscarTodas(consulta)
+		if err != nil {
+			t.Fatalf("erro ao listar: %v", err)
+		}
+		for _, os := range pagina.Itens {
+			ids = append(ids, os.ID)
+		}
+		if pagina.ProximoCursor == "" {
+			return ids
+		}
+		consulta.Cursor = pagina.ProximoCursor
+	}
+}
+
+func TestPaginacaoPorCursorDesempataPeloID(t *testing.T) {
+	a := novoAmbiente(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	// Três OS com a mesma data de entrada atravessam a divisa entre as páginas
+	ids := a.osEmDatas(t, base, base.Add(time.Hour), base.Add(time.Hour), base.Add(time.Hour), base.Add(2*time.Hour))
+
+	crescente := a.percorrerCursor(t, repositories.Consulta{TamanhoPagina: 2, Ordenacao: "dataEntrada"})
+	esperado := []uint{ids[0], ids[1], ids[2], ids[3], ids[4]}
+	if fmt.Sprint(crescente) != fmt.Sprint(esperado) {
+		t.Errorf("ordem crescente = %v, esperado %v", crescente, esperado)
+	}
+
+	decrescente := a.percorrerCursor(t, repositories.Consulta{TamanhoPagina: 2})
+	esperado = []uint{ids[4], ids[3], ids[2], ids[1], ids[0]}
+	if fmt.Sprint(decrescente) != fmt.Sprint(esperado) {
+		t.Errorf("ordem decrescente = %v, esperado %v", decrescente, esperado)
+	}
+}
+
+func TestPaginacaoPorCursorNaoRepeteComNovasOS(t *testing.T) {
+	a := novoAmbiente(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	ids := a.osEmDatas(t, base, base.Add(time.Hour), base.Add(2*time.Hour))
+
+	consulta := repositories.Consulta{TamanhoPagina: 2, UsarCursor: true}
+	primeira, err := a.os.BuscarTodas(consulta)
+	if err != nil {
+		t.Fatalf("erro ao listar: %v", err)
+	}
+	if len(primeira.Itens) != 2 || primeira.Total != 3 || primeira.ProximoCursor == "" {
+		t.Fatalf("primeira página com %d itens de %d, cursor %q", len(primeira.Itens), primeira.Total, primeira.ProximoCursor)
+	}
+
+	// Uma OS aberta durante a navegação entra no topo e não empurra registros para a página seguinte
+	a.osEmDatas(t, base.Add(3*time.Hour))
+
+	consulta.Cursor = primeira.ProximoCursor
+	segunda, err := a.os.BuscarTodas(consulta)
+	if err != nil {
+		t.Fatalf("erro ao listar: %v", err)
+	}
+	if len(segunda.Itens) != 1 || segunda.Itens[0].ID != ids[0] || segunda.ProximoCursor != "" {
+		t.Errorf("segunda página = %d itens, esperado apenas a OS %d e sem próximo cursor", len(segunda.Itens), ids[0])
+	}
+}
+
+func TestPaginacaoPorCursorAplicaFiltros(t *testing.T) {
+	a := novoAmbiente(t)
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	ids := a.osEmDatas(t, base, base.AddDate(0, 0, 1), base.AddDate(0, 0, 1).Add(time.Hour), base.AddDate(0, 0, 2))
+	a.forcarStatus(t, ids[2], "emandamento")
+
+	recebidos := a.percorrerCursor(t, repositories.Consulta{
+		TamanhoPagina: 1,
+		Ordenacao:     "id",
+		Filtros:       map[string]string{"status": "aberta", "dataInicio": "2026-03-11", "dataFim": "2026-03-12"},
+	})
+	esperado := []uint{ids[1], ids[3]}
+	if fmt.Sprint(recebidos) != fmt.Sprint(esperado) {
+		t.Errorf("ids = %v, esperado %v", recebidos, esperado)
+	}
+}
+
+func TestPaginacaoPorCursorRejeitaParametrosInvalidos(t *testing.T) {
+	a := novoAmbiente(t)
+
+	invalidas := map[string]repositories.Consulta{
+		"ordenação sem suporte": {UsarCursor: true, Ordenacao: "valorTotal"},
+		"cursor malformado":     {UsarCursor: true, Cursor: "não-é-base64"},
+		"cursor sem id":         {UsarCursor: true, Cursor: "eyJ2IjoxfQ"},
+		"filtro desconhecido":   {UsarCursor: true, Filtros: map[string]string{"senha": "x"}},
+	}
+	for nome, consulta := range invalidas {
+		if _, err := a.os.BuscarTodas(consulta); err == nil {
+			t.Errorf("%s: a consulta deveria ser rejeitada", nome)
+		}
+	}
+}
+
+func TestAtualizarOSPreservaValoresGravadosPorOutrasOperacoes(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	os := a.osComPecas(t)
+	mecanico := a.novoMecanico(t)
+
+	// A cópia lida antes do pagamento e de uma nova peça é a que o formulário de edição envia de volta
+	copia := *os
+	if _, err := a.caixa.Abrir(0, "", &operador.ID); err != nil {
+		t.Fatalf("erro ao abrir caixa: %v", err)
+	}
+	if _, err := a.receber(t, os.ID, 30, operador); err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+	peca := a.novaPeca(t, 5, nil)
+	if _, err := a.os.AdicionarItem(os.ID, &models.ItemOrdemServico{EstoqueID: peca.ID, Quantidade: 1}, nil); err != nil {
+		t.Fatalf("erro ao adicionar item: %v", err)
+	}
+
+	copia.Descricao = "Revisão completa"
+	copia.FuncionarioID = &mecanico.ID
+	atualizada, err := a.os.Atualizar(&copia, &operador.ID)
+	if err != nil {
+		t.Fatalf("erro ao atualizar: %v", err)
+	}
+	if atualizada.ValorPago != 30 || atualizada.ValorPecas != 105 || atualizada.StatusFinanceiro != models.StatusFinanceiroParcial {
+		t.Errorf("pago %v, peças %v, situação %q; esperado 30, 105 e parcial",
+			atualizada.ValorPago, atualizada.ValorPecas, atualizada.StatusFinanceiro)
+	}
+	if atualizada.Descricao != "Revisão completa" || atualizada.Funcionario == nil || atualizada.Funcionario.ID != mecanico.ID {
+		t.Error("a descrição e o mecânico deveriam ser gravados")
+	}
+
+	if _, err := a.os.AtribuirFuncionario(os.ID, a.novoMecanico(t).ID); err != nil {
+		t.Fatalf("erro ao trocar o mecânico: %v", err)
+	}
+	if salva, _ := a.os.BuscarPorID(os.ID); salva.ValorPago != 30 || salva.ValorPecas != 105 {
+		t.Errorf("a troca de mecânico alterou os valores: pago %v, peças %v", salva.ValorPago, salva.ValorPecas)
+	}
+}
+
+func TestAtualizarItemSemValorPositivoUsaOPrecoDeVenda(t *testing.T) {
+	a := novoAmbiente(t)
+	os := a.osComPecas(t)
+	item := os.ItensUtilizados[0]
+
+	atualizado, err := a.os.AtualizarItem(os.ID, &models.ItemOrdemServico{ID: item.ID, Quantidade: 3, ValorUnitario: -35}, nil)
+	if err != nil {
+		t.Fatalf("erro ao atualizar item: %v", err)
+	}
+	if atualizado.ValorUnitario != 35 || atualizado.ValorTotal != 105 {
+		t.Errorf("valor unitário %v e total %v, esperado o preço de venda 35 e total 105", atualizado.ValorUnitario, atualizado.ValorTotal)
+	}
+	if salva, _ := a.os.BuscarPorID(os.ID); salva.ValorPecas != 105 {
+		t.Errorf("valor de peças = %v, esperado 105", salva.ValorPecas)
+	}
+}
+
+// mecanicoDeFerias cria um mecânico cujo usuário vinculado está de férias
+func (a *ambienteTeste) mecanicoDeFerias(t *testing.T) *models.Funcionario {
+	t.Helper()
+	usuario := a.novoUsuario(t, models.CargoMecanico)
+	if err := a.db.Model(usuario).UpdateColumn("ferias", true).Error; err != nil {
+		t.Fatalf("erro ao marcar as férias: %v", err)
+	}
+	mecanico := a.novoMecanico(t)
+	if _, err := a.funcionario.VincularUsuario(mecanico.ID, usuario.ID); err != nil {
+		t.Fatalf("erro ao vincular o usuário: %v", err)
+	}
+	return mecanico
+}
+
+func TestAtribuirMecanicoExigeFuncionarioDisponivel(t *testing.T) {
+	a := novoAmbiente(t)
+	os := a.novaOS(t)
+	deFerias := a.mecanicoDeFerias(t)
+
+	if _, err := a.os.AtribuirFuncionario(os.ID, 9999); err == nil {
+		t.Error("não deveria atribuir um funcionário inexistente")
+	}
+	if _, err := a.os.AtribuirFuncionario(os.ID, deFerias.ID); err == nil {
+		t.Error("não deveria atribuir um mecânico de férias")
+	}
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	if _, err := a.os.Criar(&models.OrdemServico{
+		ClienteID:     cliente.ID,
+		VeiculoID:     veiculo.ID,
+		Descricao:     "Troca de óleo",
+		DataEntrada:   time.Now(),
+		FuncionarioID: &deFerias.ID,
+	}); err == nil {
+		t.Error("a OS não deveria ser aberta já atribuída a um mecânico de férias")
+	}
+
+	primeiro := a.novoMecanico(t)
+	segundo := a.novoMecanico(t)
+	if _, err := a.os.AtribuirFuncionario(os.ID, primeiro.ID); err != nil {
+		t.Fatalf("erro ao atribuir: %v", err)
+	}
+	trocada, err := a.os.AtribuirFuncionario(os.ID, segundo.ID)
+	if err != nil {
+		t.Fatalf("erro ao trocar o mecânico: %v", err)
+	}
+	if trocada.FuncionarioID == nil || *trocada.FuncionarioID != segundo.ID {
+		t.Errorf("FuncionarioID = %v, esperado %d", trocada.FuncionarioID, segundo.ID)
+	}
+
+	a.forcarStatus(t, os.ID, "concluida")
+	if _, err := a.os.AtribuirFuncionario(os.ID, primeiro.ID); err == nil {
+		t.Error("o responsável de uma OS concluída não deveria mudar")
+	}
+}
+
+func TestFilaDoMecanicoMostraAsOSEmAberto(t *testing.T) {
+	a := novoAmbiente(t)
+	mecanico := a.novoMecanico(t)
+	outro := a.novoMecanico(t)
+
+	pendente := a.novaOS(t)
+	concluida := a.novaOS(t)
+	a.novaOS(t) // Sem responsável
+	for _, os := range []*models.OrdemServico{pendente, concluida} {
+		if _, err := a.os.AtribuirFuncionario(os.ID, mecanico.ID); err != nil {
+			t.Fatalf("erro ao atribuir: %v", err)
+		}
+	}
+	a.forcarStatus(t, concluida.ID, "concluida")
+
+	fila, err := a.os.BuscarPorFuncionario(mecanico.ID, false)
+	if err != nil {
+		t.Fatalf("erro ao buscar a fila: %v", err)
+	}
+	if len(fila) != 1 || fila[0].ID != pendente.ID {
+		t.Errorf("a fila deveria trazer apenas a OS pendente, trouxe %d", len(fila))
+	}
+	if todas, _ := a.os.BuscarPorFuncionario(mecanico.ID, true); len(todas) != 2 {
+		t.Errorf("com as finalizadas, %d OS, esperado 2", len(todas))
+	}
+	if vazia, _ := a.os.BuscarPorFuncionario(outro.ID, true); len(vazia) != 0 {
+		t.Errorf("o outro mecânico não deveria ter OS, tem %d", len(vazia))
+	}
+	if _, err := a.os.BuscarPorFuncionario(9999, false); err == nil {
+		t.Error("a fila de um funcionário inexistente deveria falhar")
+	}
+}
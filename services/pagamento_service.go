@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// PagamentoService define a interface para o recebimento de pagamentos das ordens de serviço
+type PagamentoService interface {
+	BuscarPorOS(osID uint) ([]models.Pagamento, error)                                                  // Lista os pagamentos de uma OS
+	Registrar(osID uint, pagamento *models.Pagamento, usuarioID *uint) (*models.OrdemServico, error)    // Registra um recebimento
+	Estornar(osID uint, pagamentoID uint, motivo string, usuarioID *uint) (*models.OrdemServico, error) // Estorna um recebimento
+}
+
+// PagamentoServiceImpl implementa a interface PagamentoService
+type PagamentoServiceImpl struct {
+	pagamentoRepo repositories.PagamentoRepository
+	osRepo        repositories.OrdemServicoRepository
+	caixaRepo     repositories.CaixaRepository
+	uow           repositories.UnitOfWork
+}
+
+// NewPagamentoService cria uma nova instância do serviço de pagamentos
+func NewPagamentoService(
+	pagamentoRepo repositories.PagamentoRepository,
+	osRepo repositories.OrdemServicoRepository,
+	caixaRepo repositories.CaixaRepository,
+	uow repositories.UnitOfWork,
+) PagamentoService {
+	return &PagamentoServiceImpl{
+		pagamentoRepo: pagamentoRepo,
+		osRepo:        osRepo,
+		caixaRepo:     caixaRepo,
+		uow:           uow,
+	}
+}
+
+// BuscarPorOS lista os pagamentos não estornados de uma OS
+func (s *PagamentoServiceImpl) BuscarPorOS(osID uint) ([]models.Pagamento, error) {
+	if _, err := s.osRepo.FindByID(osID); err != nil {
+		return nil, errors.New("ordem de serviço não encontrada")
+	}
+	return s.pagamentoRepo.FindByOrdemServicoID(osID)
+}
+
+// Registrar lança um pagamento na OS e recalcula o valor pago e a situação financeira.
+// O valor entra no caixa aberto de quem recebe; a OS fica bloqueada durante a operação
+// para que dois caixas não recebam o mesmo saldo
+func (s *PagamentoServiceImpl) Registrar(osID uint, pagamento *models.Pagamento, usuarioID *uint) (*models.OrdemServico, error) {
+	if usuarioID == nil {
+		return nil, errors.New("usuário não identificado")
+	}
+
+	if err := validarPagamento(pagamento); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+		pagamentoRepo := s.pagamentoRepo.WithTx(tx)
+		caixaRepo := s.caixaRepo.WithTx(tx)
+
+		os, err := osRepo.FindByIDForUpdate(osID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		if os.Status == "cancelada" {
+			return errors.New("não é possível receber pagamentos de uma OS cancelada")
+		}
+
+		if saldo := os.CalcularSaldoDevedor(); pagamento.Valor > saldo {
+			return fmt.Errorf("o valor informado excede o saldo em aberto de %s", formatarMoeda(saldo))
+		}
+
+		caixa, err := caixaRepo.FindAbertoPorUsuarioForUpdate(*usuarioID)
+		if err != nil {
+			return errors.New("abra o caixa antes de registrar pagamentos")
+		}
+
+		pagamento.ID = 0
+		pagamento.OrdemServicoID = os.ID
+		pagamento.RecebidoPorID = usuarioID
+		pagamento.CaixaID = &caixa.ID
+		pagamento.MotivoEstorno = ""
+		pagamento.EstornadoPorID = nil
+		if err := pagamentoRepo.Create(pagamento); err != nil {
+			return errors.New("erro ao registrar pagamento: " + err.Error())
+		}
+
+		if err := caixaRepo.AddMovimentacao(&models.MovimentacaoCaixa{
+			CaixaID:        caixa.ID,
+			Tipo:           models.TipoMovimentacaoCaixaRecebimento,
+			Forma:          pagamento.Forma,
+			Valor:          pagamento.Valor,
+			PagamentoID:    &pagamento.ID,
+			OrdemServicoID: &os.ID,
+			UsuarioID:      usuarioID,
+			Descricao:      "Recebimento da OS " + os.NumeroOS,
+		}); err != nil {
+			return errors.New("erro ao registrar recebimento no caixa: " + err.Error())
+		}
+
+		return recalcularValorPago(osRepo, pagamentoRepo, os)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindByID(osID)
+}
+
+// Estornar desfaz um pagamento, mantendo o registro com o motivo e o responsável pelo estorno.
+// A devolução sai do caixa em que o pagamento entrou, se ainda aberto, ou do caixa aberto de quem estorna
+func (s *PagamentoServiceImpl) Estornar(osID uint, pagamentoID uint, motivo string, usuarioID *uint) (*models.OrdemServico, error) {
+	motivo = strings.TrimSpace(motivo)
+	if motivo == "" {
+		return nil, errors.New("informe o motivo do estorno")
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		osRepo := s.osRepo.WithTx(tx)
+		pagamentoRepo := s.pagamentoRepo.WithTx(tx)
+		caixaRepo := s.caixaRepo.WithTx(tx)
+
+		os, err := osRepo.FindByIDForUpdate(osID)
+		if err != nil {
+			return errors.New("ordem de serviço não encontrada")
+		}
+
+		pagamento, err := pagamentoRepo.FindByID(osID, pagamentoID)
+		if err != nil {
+			return errors.New("pagamento não encontrado")
+		}
+
+		caixa, err := caixaDoEstorno(caixaRepo, pagamento, usuarioID)
+		if err != nil {
+			return err
+		}
+
+		if err := caixaRepo.AddMovimentacao(&models.MovimentacaoCaixa{
+			CaixaID:        caixa.ID,
+			Tipo:           models.TipoMovimentacaoCaixaEstorno,
+			Forma:          pagamento.Forma,
+			Valor:          pagamento.Valor,
+			PagamentoID:    &pagamento.ID,
+			OrdemServicoID: &os.ID,
+			UsuarioID:      usuarioID,
+			Descricao:      "Estorno da OS " + os.NumeroOS + ": " + motivo,
+		}); err != nil {
+			return errors.New("erro ao registrar estorno no caixa: " + err.Error())
+		}
+
+		pagamento.MotivoEstorno = motivo
+		pagamento.EstornadoPorID = usuarioID
+		if err := pagamentoRepo.Update(pagamento); err != nil {
+			return errors.New("erro ao estornar pagamento: " + err.Error())
+		}
+		if err := pagamentoRepo.Delete(pagamento.ID); err != nil {
+			return errors.New("erro ao estornar pagamento: " + err.Error())
+		}
+
+		return recalcularValorPago(osRepo, pagamentoRepo, os)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.osRepo.FindByID(osID)
+}
+
+// caixaDoEstorno escolhe o caixa de onde sai a devolução, bloqueando-o até o fim da transação
+func caixaDoEstorno(caixaRepo repositories.CaixaRepository, pagamento *models.Pagamento, usuarioID *uint) (*models.Caixa, error) {
+	if pagamento.CaixaID != nil {
+		if caixa, err := caixaRepo.FindByIDForUpdate(*pagamento.CaixaID); err == nil && caixa.Aberto() {
+			return caixa, nil
+		}
+	}
+
+	if usuarioID != nil {
+		if caixa, err := caixaRepo.FindAbertoPorUsuarioForUpdate(*usuarioID); err == nil {
+			return caixa, nil
+		}
+	}
+
+	return nil, errors.New("abra o caixa antes de estornar pagamentos")
+}
+
+// isValidFormaPagamento verifica se a forma de pagamento é aceita pela oficina
+func isValidFormaPagamento(forma string) bool {
+	switch forma {
+	case models.FormaPagamentoDinheiro, models.FormaPagamentoPix, models.FormaPagamentoBoleto,
+		models.FormaPagamentoTransferencia, models.FormaPagamentoCartaoCredito, models.FormaPagamentoCartaoDebito:
+		return true
+	}
+	return false
+}
+
+// validarPagamento confere forma, parcelas e comprovante do pagamento
+func validarPagamento(pagamento *models.Pagamento) error {
+	pagamento.Valor = arredondarCentavos(pagamento.Valor)
+	if pagamento.Valor <= 0 {
+		return errors.New("o valor do pagamento deve ser maior que zero")
+	}
+
+	if !isValidFormaPagamento(pagamento.Forma) {
+		return errors.New("forma de pagamento inválida")
+	}
+
+	if pagamento.Parcelas <= 0 {
+		pagamento.Parcelas = 1
+	}
+	if pagamento.Parcelas > 1 && pagamento.Forma != models.FormaPagamentoCartaoCredito {
+		return errors.New("o parcelamento só é permitido no cartão de crédito")
+	}
+
+	cartao := pagamento.Forma == models.FormaPagamentoCartaoCredito || pagamento.Forma == models.FormaPagamentoCartaoDebito
+	pagamento.NSU = strings.TrimSpace(pagamento.NSU)
+	if cartao && pagamento.NSU == "" {
+		return errors.New("informe o NSU do comprovante para pagamentos com cartão")
+	}
+	if !cartao {
+		pagamento.BandeiraCartao = ""
+	}
+
+	return nil
+}
+
+// recalcularValorPago soma os pagamentos válidos e grava o valor pago; o BeforeSave da OS
+// atualiza o status financeiro
+func recalcularValorPago(osRepo repositories.OrdemServicoRepository, pagamentoRepo repositories.PagamentoRepository, os *models.OrdemServico) error {
+	total, err := pagamentoRepo.SomarPorOS(os.ID)
+	if err != nil {
+		return errors.New("erro ao calcular valor pago: " + err.Error())
+	}
+
+	os.ValorPago = arredondarCentavos(total)
+	if err := osRepo.Update(os); err != nil {
+		return errors.New("erro ao atualizar ordem de serviço: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+// osComPecas abre uma OS com duas peças lançadas (total de 70,00)
+func (a *ambienteTeste) osComPecas(t *testing.T) *models.OrdemServico {
+	t.Helper()
+	peca := a.novaPeca(t, 10, nil)
+	os := a.novaOS(t)
+	if _, err := a.os.AdicionarItem(os.ID, &models.ItemOrdemServico{EstoqueID: peca.ID, Quantidade: 2}, nil); err != nil {
+		t.Fatalf("erro ao adicionar item: %v", err)
+	}
+	os, _ = a.os.BuscarPorID(os.ID)
+	return os
+}
+
+// receber registra um pagamento em dinheiro no caixa do operador
+func (a *ambienteTeste) receber(t *testing.T, osID uint, valor float64, operador *models.Usuario) (*models.OrdemServico, error) {
+	t.Helper()
+	return a.pagamento.Registrar(osID, &models.Pagamento{Valor: valor, Forma: models.FormaPagamentoDinheiro}, &operador.ID)
+}
+
+func TestCriarOSIgnoraCamposFinanceiros(t *testing.T) {
+	a := novoAmbiente(t)
+	cliente, veiculo := a.novoClienteComVeiculo(t)
+	liberador := uint(1)
+
+	os, err := a.os.Criar(&models.OrdemServico{
+		ClienteID:          cliente.ID,
+		VeiculoID:          veiculo.ID,
+		Descricao:          "Revisão",
+		ValorPecas:         500,
+		ValorDesconto:      50,
+		ValorPago:          450,
+		StatusFinanceiro:   models.StatusFinanceiroPago,
+		EntregaLiberadaPor: &liberador,
+		MotivoLiberacao:    "cliente conhecido",
+	})
+	if err != nil {
+		t.Fatalf("erro ao criar OS: %v", err)
+	}
+
+	salva, _ := a.os.BuscarPorID(os.ID)
+	if salva.ValorPecas != 0 || salva.ValorDesconto != 0 || salva.ValorPago != 0 || salva.ValorTotal != 0 {
+		t.Errorf("valores = peças %v, desconto %v, pago %v, total %v; esperado tudo zerado",
+			salva.ValorPecas, salva.ValorDesconto, salva.ValorPago, salva.ValorTotal)
+	}
+	if salva.StatusFinanceiro != models.StatusFinanceiroPendente {
+		t.Errorf("status financeiro = %q, esperado pendente", salva.StatusFinanceiro)
+	}
+	if salva.EntregaLiberadaPor != nil || salva.MotivoLiberacao != "" || salva.DataEntrega != nil {
+		t.Error("a liberação de entrega não deveria vir do cadastro da OS")
+	}
+}
+
+func TestPagamentosAtualizamSituacaoFinanceira(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	os := a.osComPecas(t)
+
+	if _, err := a.receber(t, os.ID, 30, operador); err == nil {
+		t.Fatal("o pagamento deveria exigir um caixa aberto")
+	}
+	if _, err := a.caixa.Abrir(0, "", &operador.ID); err != nil {
+		t.Fatalf("erro ao abrir caixa: %v", err)
+	}
+
+	parcial, err := a.receber(t, os.ID, 30, operador)
+	if err != nil {
+		t.Fatalf("erro no primeiro pagamento: %v", err)
+	}
+	if parcial.StatusFinanceiro != models.StatusFinanceiroParcial || parcial.SaldoDevedor != 40 {
+		t.Errorf("situação = %q com saldo %v, esperado parcial com 40", parcial.StatusFinanceiro, parcial.SaldoDevedor)
+	}
+
+	if _, err := a.receber(t, os.ID, 40.01, operador); err == nil {
+		t.Error("o pagamento acima do saldo deveria ser rejeitado")
+	}
+
+	quitada, err := a.receber(t, os.ID, 40, operador)
+	if err != nil {
+		t.Fatalf("erro no segundo pagamento: %v", err)
+	}
+	if quitada.StatusFinanceiro != models.StatusFinanceiroPago || quitada.ValorPago != 70 {
+		t.Errorf("situação = %q com %v pagos, esperado pago com 70", quitada.StatusFinanceiro, quitada.ValorPago)
+	}
+
+	pagamentos, _ := a.pagamento.BuscarPorOS(os.ID)
+	estornada, err := a.pagamento.Estornar(os.ID, pagamentos[0].ID, "Valor lançado errado", &operador.ID)
+	if err != nil {
+		t.Fatalf("erro ao estornar: %v", err)
+	}
+	if estornada.StatusFinanceiro != models.StatusFinanceiroParcial || estornada.ValorPago != 40 {
+		t.Errorf("após o estorno: %q com %v pagos, esperado parcial com 40", estornada.StatusFinanceiro, estornada.ValorPago)
+	}
+}
+
+func TestValidarPagamento(t *testing.T) {
+	casos := []struct {
+		nome      string
+		pagamento models.Pagamento
+		valido    bool
+	}{
+		{"dinheiro", models.Pagamento{Valor: 10, Forma: models.FormaPagamentoDinheiro}, true},
+		{"valor zerado", models.Pagamento{Valor: 0.001, Forma: models.FormaPagamentoDinheiro}, false},
+		{"forma desconhecida", models.Pagamento{Valor: 10, Forma: "cheque"}, false},
+		{"parcelado no pix", models.Pagamento{Valor: 10, Forma: models.FormaPagamentoPix, Parcelas: 3}, false},
+		{"cartão sem NSU", models.Pagamento{Valor: 10, Forma: models.FormaPagamentoCartaoDebito}, false},
+		{"crédito parcelado com NSU", models.Pagamento{Valor: 10, Forma: models.FormaPagamentoCartaoCredito, Parcelas: 3, NSU: "123"}, true},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := validarPagamento(&caso.pagamento)
+			if (err == nil) != caso.valido {
+				t.Errorf("erro = %v, válido esperado = %v", err, caso.valido)
+			}
+		})
+	}
+}
+
+func TestEntregaExigeQuitacaoOuLiberacao(t *testing.T) {
+	a := novoAmbiente(t)
+	gerente := a.novoUsuario(t, models.CargoGerente)
+	os := a.osComPecas(t)
+	a.forcarStatus(t, os.ID, "concluida")
+
+	if _, err := a.os.Entregar(os.ID, false, "", &gerente.ID); err == nil {
+		t.Error("a entrega com saldo em aberto deveria ser bloqueada")
+	}
+	if _, err := a.os.Entregar(os.ID, true, "", &gerente.ID); err == nil {
+		t.Error("a liberação sem pagamento deveria exigir o motivo")
+	}
+
+	entregue, err := a.os.Entregar(os.ID, true, "Cliente paga na sexta", &gerente.ID)
+	if err != nil {
+		t.Fatalf("erro na entrega liberada: %v", err)
+	}
+	if entregue.Status != "entregue" || entregue.EntregaLiberadaPor == nil || *entregue.EntregaLiberadaPor != gerente.ID {
+		t.Error("a entrega deveria registrar quem a liberou")
+	}
+
+	// Liberar de novo não pode trocar quem liberou nem a data da entrega
+	outroGerente := a.novoUsuario(t, models.CargoGerente)
+	if _, err := a.os.Entregar(os.ID, true, "Outro motivo", &outroGerente.ID); err == nil {
+		t.Error("uma OS já entregue não deveria ser entregue de novo")
+	}
+	if salva, _ := a.os.BuscarPorID(os.ID); *salva.EntregaLiberadaPor != gerente.ID || !salva.DataEntrega.Equal(*entregue.DataEntrega) {
+		t.Error("a segunda liberação não deveria alterar a entrega registrada")
+	}
+}
+
+func TestEntregaExigeOSConcluida(t *testing.T) {
+	a := novoAmbiente(t)
+	gerente := a.novoUsuario(t, models.CargoGerente)
+	os := a.osComPecas(t)
+	a.forcarStatus(t, os.ID, "emandamento")
+
+	if _, err := a.os.Entregar(os.ID, true, "Cliente paga na sexta", &gerente.ID); err == nil {
+		t.Error("a liberação não deveria pular a conclusão da OS")
+	}
+}
+
+func TestEntregaDeOSQuitada(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	os := a.osComPecas(t)
+	a.forcarStatus(t, os.ID, "concluida")
+
+	a.caixa.Abrir(0, "", &operador.ID)
+	if _, err := a.receber(t, os.ID, 70, operador); err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+
+	entregue, err := a.os.Entregar(os.ID, false, "", &operador.ID)
+	if err != nil {
+		t.Fatalf("erro na entrega: %v", err)
+	}
+	if entregue.DataEntrega == nil {
+		t.Error("a data de entrega deveria ser registrada")
+	}
+}
+
+func TestCancelarOSComPagamentoExigeEstorno(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	os := a.osComPecas(t)
+	a.caixa.Abrir(0, "", &operador.ID)
+	a.receber(t, os.ID, 20, operador)
+
+	if _, err := a.os.CancelarOS(os.ID, &operador.ID); err == nil {
+		t.Fatal("a OS com pagamento não deveria ser cancelada")
+	}
+
+	pagamentos, _ := a.pagamento.BuscarPorOS(os.ID)
+	if _, err := a.pagamento.Estornar(os.ID, pagamentos[0].ID, "Desistência", &operador.ID); err != nil {
+		t.Fatalf("erro ao estornar: %v", err)
+	}
+	if _, err := a.os.CancelarOS(os.ID, &operador.ID); err != nil {
+		t.Errorf("após o estorno a OS deveria ser cancelada: %v", err)
+	}
+	if _, err := a.receber(t, os.ID, 10, operador); err == nil {
+		t.Error("uma OS cancelada não deveria receber pagamentos")
+	}
+}
+
+func TestDescontoExigePermissaoECabeNoValor(t *testing.T) {
+	a := novoAmbiente(t)
+	gerente := a.novoUsuario(t, models.CargoGerente)
+	atendente := a.novoUsuario(t, models.CargoAtendente)
+	os := a.osComPecas(t)
+
+	comDesconto := func(desconto float64, usuario *models.Usuario) error {
+		_, err := a.os.Atualizar(&models.OrdemServico{ID: os.ID, Descricao: os.Descricao, ValorDesconto: desconto}, &usuario.ID)
+		return err
+	}
+
+	if err := comDesconto(10, atendente); err == nil {
+		t.Error("o atendente não deveria conceder descontos")
+	}
+	if err := comDesconto(-5, gerente); err == nil {
+		t.Error("o desconto negativo deveria ser rejeitado")
+	}
+	if err := comDesconto(70.01, gerente); err == nil {
+		t.Error("o desconto acima de peças e serviços deveria ser rejeitado")
+	}
+	if err := comDesconto(10, gerente); err != nil {
+		t.Fatalf("erro ao conceder desconto: %v", err)
+	}
+	atualizada, _ := a.os.BuscarPorID(os.ID)
+	if atualizada.ValorTotal != 60 {
+		t.Errorf("total = %v, esperado 60", atualizada.ValorTotal)
+	}
+
+	// Com 50 pagos, o total não pode cair abaixo disso
+	a.caixa.Abrir(0, "", &gerente.ID)
+	if _, err := a.receber(t, os.ID, 50, gerente); err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+	if err := comDesconto(25, gerente); err == nil {
+		t.Error("o desconto não deveria deixar o total abaixo do valor pago")
+	}
+
+	// Editar outros campos sem mexer no desconto não exige a permissão
+	if _, err := a.os.Atualizar(&models.OrdemServico{ID: os.ID, Descricao: "Revisão completa", ValorDesconto: 10}, &atendente.ID); err != nil {
+		t.Errorf("editar a OS mantendo o desconto não deveria exigir permissão: %v", err)
+	}
+}
+
+func TestRemoverPecaOuServicoPagoExigeEstorno(t *testing.T) {
+	a := novoAmbiente(t)
+	operador := a.novoUsuario(t, models.CargoAtendente)
+	os := a.osComPecas(t)
+	linha, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{Descricao: "Troca de óleo", Horas: 1, ValorHora: 50})
+	if err != nil {
+		t.Fatalf("erro ao adicionar serviço: %v", err)
+	}
+
+	a.caixa.Abrir(0, "", &operador.ID)
+	if _, err := a.receber(t, os.ID, 100, operador); err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+
+	item := os.ItensUtilizados[0]
+	if err := a.os.RemoverItem(os.ID, item.ID, nil); err == nil {
+		t.Error("a peça não deveria sair deixando o total abaixo do valor pago")
+	}
+	if _, err := a.os.AtualizarItem(os.ID, &models.ItemOrdemServico{ID: item.ID, Quantidade: 1, ValorUnitario: item.ValorUnitario}, nil); err == nil {
+		t.Error("reduzir a peça não deveria deixar o total abaixo do valor pago")
+	}
+	if err := a.os.RemoverServico(os.ID, linha.ID); err == nil {
+		t.Error("o serviço não deveria sair deixando o total abaixo do valor pago")
+	}
+	if salva, _ := a.os.BuscarPorID(os.ID); salva.ValorTotal != 120 || len(salva.ItensUtilizados) != 1 || len(salva.Servicos) != 1 {
+		t.Fatalf("total %v com %d peças e %d serviços; a OS não deveria mudar", salva.ValorTotal, len(salva.ItensUtilizados), len(salva.Servicos))
+	}
+
+	// Com o pagamento estornado e um valor menor recebido, a retirada cabe no que foi pago
+	pagamentos, _ := a.pagamento.BuscarPorOS(os.ID)
+	if _, err := a.pagamento.Estornar(os.ID, pagamentos[0].ID, "Serviço não realizado", &operador.ID); err != nil {
+		t.Fatalf("erro ao estornar: %v", err)
+	}
+	if _, err := a.receber(t, os.ID, 60, operador); err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+	if err := a.os.RemoverServico(os.ID, linha.ID); err != nil {
+		t.Errorf("a retirada que mantém o total acima do valor pago deveria ser aceita: %v", err)
+	}
+}
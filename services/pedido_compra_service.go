@@ -0,0 +1,501 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// FatorReposicao define até quanto do estoque mínimo o pedido gerado automaticamente repõe
+// (ex.: mínimo 5 e fator 2 repõe o item até 10 unidades)
+const FatorReposicao = 2
+
+// RecebimentoItem informa quanto chegou de uma linha do pedido e o custo praticado
+type RecebimentoItem struct {
+	ItemID        uint    `json:"itemId" binding:"required"`
+	Quantidade    int     `json:"quantidade" binding:"required,min=1"`
+	CustoUnitario float64 `json:"custoUnitario"`
+}
+
+// PedidoCompraService define a interface para os serviços de pedidos de compra
+type PedidoCompraService interface {
+	BuscarTodos() ([]models.PedidoCompra, error)
+	BuscarPorStatus(status string) ([]models.PedidoCompra, error)
+	BuscarPorID(id uint) (*models.PedidoCompra, error)
+	Criar(pedido *models.PedidoCompra, usuarioID *uint) (*models.PedidoCompra, error)
+	Atualizar(pedido *models.PedidoCompra) (*models.PedidoCompra, error)
+	Deletar(id uint) error
+	Enviar(id uint) (*models.PedidoCompra, error)
+	Receber(id uint, recebimentos []RecebimentoItem, usuarioID *uint) (*models.PedidoCompra, error)
+	Cancelar(id uint) (*models.PedidoCompra, error)
+	GerarDeBaixoEstoque(usuarioID *uint) ([]models.PedidoCompra, error)
+}
+
+// PedidoCompraServiceImpl implementa a interface PedidoCompraService
+type PedidoCompraServiceImpl struct {
+	pedidoRepo          repositories.PedidoCompraRepository
+	estoqueRepo         repositories.EstoqueRepository
+	fornecedorRepo      repositories.FornecedorRepository
+	movimentacaoService MovimentacaoEstoqueService
+	uow                 repositories.UnitOfWork
+}
+
+// NewPedidoCompraService cria uma nova instância de PedidoCompraService
+func NewPedidoCompraService(
+	pedidoRepo repositories.PedidoCompraRepository,
+	estoqueRepo repositories.EstoqueRepository,
+	fornecedorRepo repositories.FornecedorRepository,
+	movimentacaoService MovimentacaoEstoqueService,
+	uow repositories.UnitOfWork,
+) PedidoCompraService {
+	return &PedidoCompraServiceImpl{
+		pedidoRepo:          pedidoRepo,
+		estoqueRepo:         estoqueRepo,
+		fornecedorRepo:      fornecedorRepo,
+		movimentacaoService: movimentacaoService,
+		uow:                 uow,
+	}
+}
+
+// BuscarTodos retorna todos os pedidos de compra
+func (s *PedidoCompraServiceImpl) BuscarTodos() ([]models.PedidoCompra, error) {
+	return s.pedidoRepo.FindAll()
+}
+
+// BuscarPorStatus retorna os pedidos em um status
+func (s *PedidoCompraServiceImpl) BuscarPorStatus(status string) ([]models.PedidoCompra, error) {
+	if !isValidStatusPedido(status) {
+		return nil, errors.New("status inválido")
+	}
+	return s.pedidoRepo.FindByStatus(status)
+}
+
+// BuscarPorID retorna um pedido com suas linhas
+func (s *PedidoCompraServiceImpl) BuscarPorID(id uint) (*models.PedidoCompra, error) {
+	pedido, err := s.pedidoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("pedido de compra não encontrado")
+	}
+	return pedido, nil
+}
+
+// Criar cadastra um pedido em rascunho com suas linhas
+func (s *PedidoCompraServiceImpl) Criar(pedido *models.PedidoCompra, usuarioID *uint) (*models.PedidoCompra, error) {
+	itens := pedido.Itens
+	pedido.ID = 0
+	pedido.Numero = ""
+	pedido.Status = models.StatusPedidoRascunho
+	pedido.UsuarioID = usuarioID
+	pedido.DataEnvio = nil
+	pedido.DataRecebimento = nil
+	pedido.Fornecedor = nil
+
+	if err := s.validarFornecedor(pedido.FornecedorID); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		if err := s.pedidoRepo.WithTx(tx).Create(pedido); err != nil {
+			return errors.New("erro ao criar pedido de compra: " + err.Error())
+		}
+		return s.gravarItens(tx, pedido, itens)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pedidoRepo.FindByID(pedido.ID)
+}
+
+// Atualizar altera um pedido em rascunho; as linhas enviadas substituem as atuais
+func (s *PedidoCompraServiceImpl) Atualizar(pedido *models.PedidoCompra) (*models.PedidoCompra, error) {
+	if err := s.validarFornecedor(pedido.FornecedorID); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		pedidoRepo := s.pedidoRepo.WithTx(tx)
+
+		existente, err := pedidoRepo.FindByIDForUpdate(pedido.ID)
+		if err != nil {
+			return errors.New("pedido de compra não encontrado")
+		}
+
+		if existente.Status != models.StatusPedidoRascunho {
+			return errors.New("apenas pedidos em rascunho podem ser alterados")
+		}
+
+		existente.FornecedorID = pedido.FornecedorID
+		existente.DataPrevisao = pedido.DataPrevisao
+		existente.Observacoes = pedido.Observacoes
+
+		if err := pedidoRepo.RemoveItens(existente.ID); err != nil {
+			return errors.New("erro ao atualizar itens do pedido: " + err.Error())
+		}
+		return s.gravarItens(tx, existente, pedido.Itens)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pedidoRepo.FindByID(pedido.ID)
+}
+
+// Deletar remove um pedido que ainda está em rascunho
+func (s *PedidoCompraServiceImpl) Deletar(id uint) error {
+	pedido, err := s.pedidoRepo.FindByID(id)
+	if err != nil {
+		return errors.New("pedido de compra não encontrado")
+	}
+
+	if pedido.Status != models.StatusPedidoRascunho {
+		return errors.New("apenas pedidos em rascunho podem ser excluídos; cancele o pedido")
+	}
+
+	if err := s.pedidoRepo.Delete(id); err != nil {
+		return errors.New("erro ao excluir pedido de compra")
+	}
+	return nil
+}
+
+// Enviar marca o pedido como enviado ao fornecedor
+func (s *PedidoCompraServiceImpl) Enviar(id uint) (*models.PedidoCompra, error) {
+	pedido, err := s.pedidoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("pedido de compra não encontrado")
+	}
+
+	if !isValidStatusPedidoTransition(pedido.Status, models.StatusPedidoEnviado) {
+		return nil, fmt.Errorf("transição de status inválida: de %s para %s", pedido.Status, models.StatusPedidoEnviado)
+	}
+
+	if len(pedido.Itens) == 0 {
+		return nil, errors.New("o pedido não possui itens")
+	}
+
+	if pedido.FornecedorID == nil {
+		return nil, errors.New("fornecedor é obrigatório para enviar o pedido")
+	}
+
+	agora := time.Now()
+	pedido.Status = models.StatusPedidoEnviado
+	pedido.DataEnvio = &agora
+	if err := s.pedidoRepo.Update(pedido); err != nil {
+		return nil, errors.New("erro ao enviar pedido de compra: " + err.Error())
+	}
+
+	return pedido, nil
+}
+
+// Receber registra a chegada de mercadorias: dá entrada no estoque pelo serviço de movimentações,
+// atualiza o preço de custo do item e avança o status do pedido
+func (s *PedidoCompraServiceImpl) Receber(id uint, recebimentos []RecebimentoItem, usuarioID *uint) (*models.PedidoCompra, error) {
+	if len(recebimentos) == 0 {
+		return nil, errors.New("informe os itens recebidos")
+	}
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		pedidoRepo := s.pedidoRepo.WithTx(tx)
+		estoqueRepo := s.estoqueRepo.WithTx(tx)
+
+		pedido, err := pedidoRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("pedido de compra não encontrado")
+		}
+
+		if pedido.Status != models.StatusPedidoEnviado && pedido.Status != models.StatusPedidoParcialmenteRecebido {
+			return errors.New("apenas pedidos enviados podem ser recebidos")
+		}
+
+		itens, err := pedidoRepo.FindItens(id)
+		if err != nil {
+			return errors.New("erro ao buscar itens do pedido")
+		}
+
+		linhas := make(map[uint]*models.ItemPedidoCompra, len(itens))
+		for i := range itens {
+			linhas[itens[i].ID] = &itens[i]
+		}
+
+		for _, recebimento := range recebimentos {
+			linha, ok := linhas[recebimento.ItemID]
+			if !ok {
+				return fmt.Errorf("item %d não pertence ao pedido", recebimento.ItemID)
+			}
+			if recebimento.Quantidade <= 0 {
+				return errors.New("a quantidade recebida deve ser maior que zero")
+			}
+			if recebimento.Quantidade > linha.QuantidadePendente() {
+				return fmt.Errorf("quantidade recebida do item %d excede o pendente (%d)", linha.ID, linha.QuantidadePendente())
+			}
+
+			custo := recebimento.CustoUnitario
+			if custo <= 0 {
+				custo = linha.CustoUnitario
+			}
+
+			pedidoID := pedido.ID
+			_, err := s.movimentacaoService.RegistrarNaTransacao(tx, &models.MovimentacaoEstoque{
+				EstoqueID:      linha.EstoqueID,
+				Tipo:           models.TipoMovimentacaoEntrada,
+				Quantidade:     recebimento.Quantidade,
+				CustoUnitario:  custo,
+				UsuarioID:      usuarioID,
+				Motivo:         "Recebimento do pedido " + pedido.Numero,
+				PedidoCompraID: &pedidoID,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Atualizar o preço de custo do item e o preço do fornecedor com o valor da última compra
+			if custo > 0 {
+				estoqueItem, err := estoqueRepo.FindByID(linha.EstoqueID)
+				if err != nil {
+					return errors.New("item de estoque não encontrado")
+				}
+				estoqueItem.PrecoUnitario = custo
+				if err := estoqueRepo.Update(estoqueItem); err != nil {
+					return errors.New("erro ao atualizar preço de custo: " + err.Error())
+				}
+
+				if pedido.FornecedorID != nil {
+					if err := s.atualizarPrecoFornecedor(tx, *pedido.FornecedorID, linha.EstoqueID, custo); err != nil {
+						return err
+					}
+				}
+			}
+
+			linha.QuantidadeRecebida += recebimento.Quantidade
+			if err := pedidoRepo.UpdateItem(linha); err != nil {
+				return errors.New("erro ao atualizar item do pedido: " + err.Error())
+			}
+		}
+
+		// Avançar o status conforme o que ainda falta receber
+		pedido.Status = models.StatusPedidoRecebido
+		for i := range itens {
+			if itens[i].QuantidadePendente() > 0 {
+				pedido.Status = models.StatusPedidoParcialmenteRecebido
+				break
+			}
+		}
+		if pedido.Status == models.StatusPedidoRecebido {
+			agora := time.Now()
+			pedido.DataRecebimento = &agora
+		}
+
+		if err := pedidoRepo.Update(pedido); err != nil {
+			return errors.New("erro ao atualizar pedido de compra: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pedidoRepo.FindByID(id)
+}
+
+// Cancelar cancela o pedido; o que já foi recebido permanece no estoque
+func (s *PedidoCompraServiceImpl) Cancelar(id uint) (*models.PedidoCompra, error) {
+	pedido, err := s.pedidoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("pedido de compra não encontrado")
+	}
+
+	if !isValidStatusPedidoTransition(pedido.Status, models.StatusPedidoCancelado) {
+		return nil, fmt.Errorf("transição de status inválida: de %s para %s", pedido.Status, models.StatusPedidoCancelado)
+	}
+
+	pedido.Status = models.StatusPedidoCancelado
+	if err := s.pedidoRepo.Update(pedido); err != nil {
+		return nil, errors.New("erro ao cancelar pedido de compra: " + err.Error())
+	}
+
+	return pedido, nil
+}
+
+// GerarDeBaixoEstoque cria um pedido em rascunho por fornecedor com os itens abaixo do mínimo.
+// A quantidade sugerida repõe o item até FatorReposicao vezes o mínimo, descontando o que
+// já está pendente em outros pedidos abertos
+func (s *PedidoCompraServiceImpl) GerarDeBaixoEstoque(usuarioID *uint) ([]models.PedidoCompra, error) {
+	itensBaixos, err := s.estoqueRepo.FindBaixoEstoque()
+	if err != nil {
+		return nil, errors.New("erro ao buscar itens com estoque baixo")
+	}
+
+	pendentes, err := s.pedidoRepo.QuantidadesPendentes([]string{
+		models.StatusPedidoRascunho,
+		models.StatusPedidoEnviado,
+		models.StatusPedidoParcialmenteRecebido,
+	})
+	if err != nil {
+		return nil, errors.New("erro ao buscar pedidos em aberto")
+	}
+
+	// Agrupar as linhas por fornecedor principal; itens sem fornecedor ficam juntos (chave 0)
+	// em um rascunho que precisa ter o fornecedor definido antes do envio
+	porFornecedor := make(map[uint][]models.ItemPedidoCompra)
+	for _, item := range itensBaixos {
+		quantidade := item.EstoqueMinimo*FatorReposicao - item.Quantidade - pendentes[item.ID]
+		if quantidade <= 0 {
+			continue
+		}
+
+		var fornecedorID uint
+		if item.FornecedorID != nil {
+			fornecedorID = *item.FornecedorID
+		}
+		porFornecedor[fornecedorID] = append(porFornecedor[fornecedorID], models.ItemPedidoCompra{
+			EstoqueID:  item.ID,
+			Quantidade: quantidade,
+		})
+	}
+
+	fornecedores := make([]uint, 0, len(porFornecedor))
+	for fornecedorID := range porFornecedor {
+		fornecedores = append(fornecedores, fornecedorID)
+	}
+	sort.Slice(fornecedores, func(i, j int) bool { return fornecedores[i] < fornecedores[j] })
+
+	var ids []uint
+	err = s.uow.Executar(func(tx *gorm.DB) error {
+		for _, fornecedorID := range fornecedores {
+			pedido := &models.PedidoCompra{
+				Status:      models.StatusPedidoRascunho,
+				UsuarioID:   usuarioID,
+				Observacoes: "Gerado automaticamente a partir dos itens com estoque baixo",
+			}
+			if fornecedorID != 0 {
+				id := fornecedorID
+				pedido.FornecedorID = &id
+			}
+			if err := s.pedidoRepo.WithTx(tx).Create(pedido); err != nil {
+				return errors.New("erro ao criar pedido de compra: " + err.Error())
+			}
+			if err := s.gravarItens(tx, pedido, porFornecedor[fornecedorID]); err != nil {
+				return err
+			}
+			ids = append(ids, pedido.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pedidos := make([]models.PedidoCompra, 0, len(ids))
+	for _, id := range ids {
+		pedido, err := s.pedidoRepo.FindByID(id)
+		if err != nil {
+			return nil, errors.New("erro ao buscar pedido gerado")
+		}
+		pedidos = append(pedidos, *pedido)
+	}
+
+	return pedidos, nil
+}
+
+// gravarItens valida e grava as linhas de um pedido, recalculando o valor total.
+// Sem custo informado, usa o preço do fornecedor do pedido ou, na falta dele, o custo atual do item
+func (s *PedidoCompraServiceImpl) gravarItens(tx *gorm.DB, pedido *models.PedidoCompra, itens []models.ItemPedidoCompra) error {
+	pedidoRepo := s.pedidoRepo.WithTx(tx)
+	estoqueRepo := s.estoqueRepo.WithTx(tx)
+	fornecedorRepo := s.fornecedorRepo.WithTx(tx)
+
+	pedido.ValorTotal = 0
+	for i := range itens {
+		item := itens[i]
+		if item.Quantidade <= 0 {
+			return errors.New("a quantidade de cada item deve ser maior que zero")
+		}
+
+		estoqueItem, err := estoqueRepo.FindByID(item.EstoqueID)
+		if err != nil {
+			return fmt.Errorf("item de estoque %d não encontrado", item.EstoqueID)
+		}
+
+		item.ID = 0
+		item.PedidoCompraID = pedido.ID
+		item.QuantidadeRecebida = 0
+		item.Item = nil
+		if item.CustoUnitario <= 0 && pedido.FornecedorID != nil {
+			if precoFornecedor, err := fornecedorRepo.FindItem(*pedido.FornecedorID, item.EstoqueID); err == nil && precoFornecedor.Preco > 0 {
+				item.CustoUnitario = precoFornecedor.Preco
+			}
+		}
+		if item.CustoUnitario <= 0 {
+			item.CustoUnitario = estoqueItem.PrecoUnitario
+		}
+
+		if err := pedidoRepo.AddItem(&item); err != nil {
+			return errors.New("erro ao adicionar item ao pedido: " + err.Error())
+		}
+		pedido.ValorTotal += item.ValorTotal
+	}
+
+	if err := pedidoRepo.Update(pedido); err != nil {
+		return errors.New("erro ao atualizar valor do pedido: " + err.Error())
+	}
+	return nil
+}
+
+// validarFornecedor verifica se o fornecedor informado existe
+func (s *PedidoCompraServiceImpl) validarFornecedor(fornecedorID *uint) error {
+	if fornecedorID == nil {
+		return nil
+	}
+	if _, err := s.fornecedorRepo.FindByID(*fornecedorID); err != nil {
+		return errors.New("fornecedor não encontrado")
+	}
+	return nil
+}
+
+// atualizarPrecoFornecedor registra o custo da última compra como preço da peça no fornecedor
+func (s *PedidoCompraServiceImpl) atualizarPrecoFornecedor(tx *gorm.DB, fornecedorID uint, estoqueID uint, custo float64) error {
+	fornecedorRepo := s.fornecedorRepo.WithTx(tx)
+
+	item, err := fornecedorRepo.FindItem(fornecedorID, estoqueID)
+	if err != nil {
+		item = &models.FornecedorItem{FornecedorID: fornecedorID, EstoqueID: estoqueID}
+	}
+	item.Preco = custo
+
+	if err := fornecedorRepo.SaveItem(item); err != nil {
+		return errors.New("erro ao atualizar preço do fornecedor: " + err.Error())
+	}
+	return nil
+}
+
+// isValidStatusPedido verifica se o status informado existe
+func isValidStatusPedido(status string) bool {
+	switch status {
+	case models.StatusPedidoRascunho, models.StatusPedidoEnviado, models.StatusPedidoParcialmenteRecebido,
+		models.StatusPedidoRecebido, models.StatusPedidoCancelado:
+		return true
+	}
+	return false
+}
+
+// isValidStatusPedidoTransition verifica se a transição de status do pedido é permitida
+func isValidStatusPedidoTransition(atual, novo string) bool {
+	transicoes := map[string][]string{
+		models.StatusPedidoRascunho:             {models.StatusPedidoEnviado, models.StatusPedidoCancelado},
+		models.StatusPedidoEnviado:              {models.StatusPedidoParcialmenteRecebido, models.StatusPedidoRecebido, models.StatusPedidoCancelado},
+		models.StatusPedidoParcialmenteRecebido: {models.StatusPedidoRecebido, models.StatusPedidoCancelado},
+	}
+
+	for _, permitido := range transicoes[atual] {
+		if permitido == novo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+// novoFornecedor grava um fornecedor ativo
+func (a *ambienteTeste) novoFornecedor(t *testing.T, nome string) *models.Fornecedor {
+	t.Helper()
+	fornecedor := &models.Fornecedor{Nome: nome, Ativo: true}
+	a.criar(t, fornecedor)
+	return fornecedor
+}
+
+// pecaComMinimo grava uma peça com saldo, estoque mínimo e fornecedor principal informados
+func (a *ambienteTeste) pecaComMinimo(t *testing.T, quantidade, minimo int, fornecedorID *uint) *models.Estoque {
+	t.Helper()
+	var total int64
+	a.db.Model(&models.Estoque{}).Count(&total)
+	peca := &models.Estoque{
+		Nome:          "Pastilha de freio",
+		Codigo:        fmt.Sprintf("PF-%d", total+1),
+		Quantidade:    quantidade,
+		EstoqueMinimo: minimo,
+		PrecoUnitario: 15,
+		PrecoVenda:    30,
+		FornecedorID:  fornecedorID,
+	}
+	a.criar(t, peca)
+	return peca
+}
+
+func TestReceberPedidoDaEntradaNoEstoque(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoGerente)
+	fornecedor := a.novoFornecedor(t, "Auto Peças Central")
+	peca := a.pecaComMinimo(t, 2, 5, &fornecedor.ID)
+
+	pedido, err := a.compra.Criar(&models.PedidoCompra{
+		FornecedorID: &fornecedor.ID,
+		Itens:        []models.ItemPedidoCompra{{EstoqueID: peca.ID, Quantidade: 10, CustoUnitario: 18}},
+	}, &usuario.ID)
+	if err != nil {
+		t.Fatalf("erro ao criar o pedido: %v", err)
+	}
+	if pedido.ValorTotal != 180 {
+		t.Errorf("valor do pedido = %.2f, esperado 180", pedido.ValorTotal)
+	}
+	linha := pedido.Itens[0].ID
+
+	if _, err := a.compra.Receber(pedido.ID, []RecebimentoItem{{ItemID: linha, Quantidade: 1}}, &usuario.ID); err == nil {
+		t.Fatal("um rascunho não deveria ser recebido antes do envio")
+	}
+	if _, err := a.compra.Enviar(pedido.ID); err != nil {
+		t.Fatalf("erro ao enviar: %v", err)
+	}
+
+	parcial, err := a.compra.Receber(pedido.ID, []RecebimentoItem{{ItemID: linha, Quantidade: 4, CustoUnitario: 22}}, &usuario.ID)
+	if err != nil {
+		t.Fatalf("erro ao receber: %v", err)
+	}
+	if parcial.Status != models.StatusPedidoParcialmenteRecebido {
+		t.Errorf("status = %q, esperado %q", parcial.Status, models.StatusPedidoParcialmenteRecebido)
+	}
+	atual := a.saldoPeca(t, peca.ID)
+	if atual.Quantidade != 6 || atual.PrecoUnitario != 22 {
+		t.Errorf("saldo %d e custo %.2f, esperado 6 e 22", atual.Quantidade, atual.PrecoUnitario)
+	}
+	var precoFornecedor models.FornecedorItem
+	a.db.Where("fornecedor_id = ? AND estoque_id = ?", fornecedor.ID, peca.ID).First(&precoFornecedor)
+	if precoFornecedor.Preco != 22 {
+		t.Errorf("preço no fornecedor = %.2f, esperado o da última compra (22)", precoFornecedor.Preco)
+	}
+
+	if _, err := a.compra.Receber(pedido.ID, []RecebimentoItem{{ItemID: linha, Quantidade: 7}}, &usuario.ID); err == nil {
+		t.Error("não deveria receber mais do que o pendente")
+	}
+	if saldo := a.saldoPeca(t, peca.ID).Quantidade; saldo != 6 {
+		t.Errorf("o recebimento recusado não deveria mexer no saldo (saldo %d)", saldo)
+	}
+
+	// Sem custo informado, vale o custo da linha do pedido
+	recebido, err := a.compra.Receber(pedido.ID, []RecebimentoItem{{ItemID: linha, Quantidade: 6}}, &usuario.ID)
+	if err != nil {
+		t.Fatalf("erro ao receber o restante: %v", err)
+	}
+	if recebido.Status != models.StatusPedidoRecebido || recebido.DataRecebimento == nil {
+		t.Errorf("status = %q e data de recebimento %v, esperado recebido com data", recebido.Status, recebido.DataRecebimento)
+	}
+	atual = a.saldoPeca(t, peca.ID)
+	if atual.Quantidade != 12 || atual.PrecoUnitario != 18 {
+		t.Errorf("saldo %d e custo %.2f, esperado 12 e 18", atual.Quantidade, atual.PrecoUnitario)
+	}
+
+	var entradas int64
+	a.db.Model(&models.MovimentacaoEstoque{}).
+		Where("pedido_compra_id = ? AND tipo = ?", pedido.ID, models.TipoMovimentacaoEntrada).Count(&entradas)
+	if entradas != 2 {
+		t.Errorf("%d entradas ligadas ao pedido, esperado 2", entradas)
+	}
+
+	if _, err := a.compra.Receber(pedido.ID, []RecebimentoItem{{ItemID: linha, Quantidade: 1}}, &usuario.ID); err == nil {
+		t.Error("um pedido já recebido não deveria aceitar novo recebimento")
+	}
+}
+
+func TestGerarPedidosDeBaixoEstoquePorFornecedor(t *testing.T) {
+	a := novoAmbiente(t)
+	central := a.novoFornecedor(t, "Auto Peças Central")
+	norte := a.novoFornecedor(t, "Distribuidora Norte")
+
+	daCentral := a.pecaComMinimo(t, 1, 5, &central.ID)
+	a.pecaComMinimo(t, 8, 5, &central.ID) // Acima do mínimo
+	doNorte := a.pecaComMinimo(t, 0, 4, &norte.ID)
+	semFornecedor := a.pecaComMinimo(t, 1, 3, nil)
+
+	pedidos, err := a.compra.GerarDeBaixoEstoque(nil)
+	if err != nil {
+		t.Fatalf("erro ao gerar os pedidos: %v", err)
+	}
+	if len(pedidos) != 3 {
+		t.Fatalf("%d pedidos gerados, esperado um por fornecedor e um sem fornecedor", len(pedidos))
+	}
+
+	// Cada item é reposto até o dobro do mínimo
+	esperado := map[uint]int{daCentral.ID: 9, doNorte.ID: 8, semFornecedor.ID: 5}
+	for _, pedido := range pedidos {
+		if pedido.Status != models.StatusPedidoRascunho {
+			t.Errorf("pedido %s com status %q, esperado rascunho", pedido.Numero, pedido.Status)
+		}
+		if len(pedido.Itens) != 1 {
+			t.Errorf("pedido %s com %d linhas, esperado 1", pedido.Numero, len(pedido.Itens))
+			continue
+		}
+		item := pedido.Itens[0]
+		if item.Quantidade != esperado[item.EstoqueID] {
+			t.Errorf("peça %d pedida %d vezes, esperado %d", item.EstoqueID, item.Quantidade, esperado[item.EstoqueID])
+		}
+		peca := a.saldoPeca(t, item.EstoqueID)
+		if (pedido.FornecedorID == nil) != (peca.FornecedorID == nil) ||
+			(pedido.FornecedorID != nil && *pedido.FornecedorID != *peca.FornecedorID) {
+			t.Errorf("peça %d pedida ao fornecedor %v, esperado o principal %v", item.EstoqueID, pedido.FornecedorID, peca.FornecedorID)
+		}
+	}
+
+	// O que já está pedido conta como reposição
+	novos, err := a.compra.GerarDeBaixoEstoque(nil)
+	if err != nil {
+		t.Fatalf("erro ao gerar novamente: %v", err)
+	}
+	if len(novos) != 0 {
+		t.Errorf("%d pedidos repetidos para itens já pedidos", len(novos))
+	}
+}
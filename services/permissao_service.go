@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// PermissaoService define a interface para a política de permissões por cargo
+type PermissaoService interface {
+	SincronizarCatalogo() error                                         // Cadastra permissões novas do catálogo com seus valores padrão
+	BuscarCatalogo() ([]models.Permissao, error)                        // Lista todas as permissões existentes
+	BuscarMapeamentos() (map[string][]string, error)                    // Retorna o mapa cargo -> permissões
+	BuscarPorCargo(cargo string) ([]string, error)                      // Lista as permissões de um cargo
+	AtualizarCargo(cargo string, permissoes []string) ([]string, error) // Substitui as permissões de um cargo
+	CargoTemPermissao(cargo, permissao string) (bool, error)            // Verifica se um cargo possui a permissão
+	UsuarioTemPermissao(usuarioID uint, permissao string) (bool, error) // Verifica a permissão a partir do cargo do usuário
+	VerificarAtribuicaoCargo(usuarioID uint, cargo string) error        // Verifica se o usuário pode atribuir o cargo a outra pessoa
+	VerificarGestaoUsuario(usuarioID, alvoID uint) error                // Verifica se o usuário pode alterar a conta de outra pessoa
+}
+
+// PermissaoServiceImpl implementa a interface PermissaoService
+// Mantém em memória o mapa de permissões para evitar consultas a cada requisição
+type PermissaoServiceImpl struct {
+	permissaoRepo repositories.PermissaoRepository
+	usuarioRepo   repositories.UsuarioRepository
+
+	mu      sync.RWMutex
+	cache   map[string]map[string]bool // nil enquanto não carregado
+	geracao uint64                     // Incrementada a cada invalidação, para descartar cargas já desatualizadas
+}
+
+// NewPermissaoService cria uma nova instância do serviço de permissões
+func NewPermissaoService(permissaoRepo repositories.PermissaoRepository, usuarioRepo repositories.UsuarioRepository) PermissaoService {
+	return &PermissaoServiceImpl{
+		permissaoRepo: permissaoRepo,
+		usuarioRepo:   usuarioRepo,
+	}
+}
+
+// SincronizarCatalogo cadastra no banco as permissões do catálogo que ainda não existem,
+// concedendo-as aos cargos definidos em models.PermissoesPadraoPorCargo.
+// Permissões já existentes não são alteradas, preservando as edições feitas pelos administradores.
+func (s *PermissaoServiceImpl) SincronizarCatalogo() error {
+	for _, p := range models.CatalogoPermissoes {
+		if _, err := s.permissaoRepo.FindByCodigo(p.Codigo); err == nil {
+			continue
+		}
+
+		permissao := p
+		if err := s.permissaoRepo.Create(&permissao); err != nil {
+			return errors.New("erro ao cadastrar permissão " + p.Codigo + ": " + err.Error())
+		}
+
+		for cargo, codigos := range models.PermissoesPadraoPorCargo {
+			for _, codigo := range codigos {
+				if codigo != p.Codigo {
+					continue
+				}
+				vinculo := models.CargoPermissao{Cargo: cargo, PermissaoCodigo: codigo}
+				if err := s.permissaoRepo.CreateCargoPermissao(&vinculo); err != nil {
+					return errors.New("erro ao conceder permissão padrão: " + err.Error())
+				}
+			}
+		}
+	}
+
+	s.invalidarCache()
+	return nil
+}
+
+// BuscarCatalogo retorna todas as permissões cadastradas
+func (s *PermissaoServiceImpl) BuscarCatalogo() ([]models.Permissao, error) {
+	permissoes, err := s.permissaoRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("erro ao buscar permissões")
+	}
+	return permissoes, nil
+}
+
+// BuscarMapeamentos retorna as permissões agrupadas por cargo
+func (s *PermissaoServiceImpl) BuscarMapeamentos() (map[string][]string, error) {
+	vinculos, err := s.permissaoRepo.FindAllCargoPermissoes()
+	if err != nil {
+		return nil, errors.New("erro ao buscar permissões dos cargos")
+	}
+
+	mapa := make(map[string][]string)
+	for _, v := range vinculos {
+		mapa[v.Cargo] = append(mapa[v.Cargo], v.PermissaoCodigo)
+	}
+	return mapa, nil
+}
+
+// BuscarPorCargo retorna os códigos das permissões de um cargo
+// O cargo admin recebe sempre o catálogo completo
+func (s *PermissaoServiceImpl) BuscarPorCargo(cargo string) ([]string, error) {
+	cargo = models.NormalizarCargo(cargo)
+	if cargo == "" {
+		return nil, errors.New("cargo é obrigatório")
+	}
+
+	if cargo == models.CargoAdmin {
+		permissoes, err := s.permissaoRepo.FindAll()
+		if err != nil {
+			return nil, errors.New("erro ao buscar permissões")
+		}
+		codigos := make([]string, len(permissoes))
+		for i, p := range permissoes {
+			codigos[i] = p.Codigo
+		}
+		return codigos, nil
+	}
+
+	vinculos, err := s.permissaoRepo.FindByCargo(cargo)
+	if err != nil {
+		return nil, errors.New("erro ao buscar permissões do cargo")
+	}
+
+	codigos := make([]string, len(vinculos))
+	for i, v := range vinculos {
+		codigos[i] = v.PermissaoCodigo
+	}
+	return codigos, nil
+}
+
+// AtualizarCargo substitui o conjunto de permissões de um cargo
+// Todas as permissões informadas devem existir no catálogo
+func (s *PermissaoServiceImpl) AtualizarCargo(cargo string, permissoes []string) ([]string, error) {
+	cargo = models.NormalizarCargo(cargo)
+	if cargo == "" {
+		return nil, errors.New("cargo é obrigatório")
+	}
+
+	// O admin possui todas as permissões e não pode ser editado, evitando que o sistema fique sem administrador
+	if cargo == models.CargoAdmin {
+		return nil, errors.New("as permissões do cargo admin não podem ser alteradas")
+	}
+
+	// Remover duplicadas e validar os códigos informados
+	unicos := make(map[string]bool)
+	codigos := make([]string, 0, len(permissoes))
+	for _, codigo := range permissoes {
+		if unicos[codigo] {
+			continue
+		}
+		if _, err := s.permissaoRepo.FindByCodigo(codigo); err != nil {
+			return nil, errors.New("permissão desconhecida: " + codigo)
+		}
+		unicos[codigo] = true
+		codigos = append(codigos, codigo)
+	}
+	sort.Strings(codigos)
+
+	if err := s.permissaoRepo.ReplaceCargo(cargo, codigos); err != nil {
+		return nil, errors.New("erro ao atualizar permissões do cargo")
+	}
+
+	s.invalidarCache()
+	return codigos, nil
+}
+
+// CargoTemPermissao verifica se o cargo informado possui a permissão
+func (s *PermissaoServiceImpl) CargoTemPermissao(cargo, permissao string) (bool, error) {
+	cargo = models.NormalizarCargo(cargo)
+	if cargo == models.CargoAdmin {
+		return true, nil
+	}
+
+	mapa, err := s.carregarCache()
+	if err != nil {
+		return false, err
+	}
+	return mapa[cargo][permissao], nil
+}
+
+// UsuarioTemPermissao busca o cargo atual do usuário e verifica a permissão
+// O cargo é lido do banco para que mudanças de cargo valham sem precisar de novo login
+func (s *PermissaoServiceImpl) UsuarioTemPermissao(usuarioID uint, permissao string) (bool, error) {
+	usuario, err := s.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return false, errors.New("usuário não encontrado")
+	}
+	return s.CargoTemPermissao(usuario.Cargo, permissao)
+}
+
+// VerificarAtribuicaoCargo impede a escalada de privilégios: atribuir um cargo exige a gestão de permissões,
+// e o cargo admin só pode ser atribuído por outro admin
+func (s *PermissaoServiceImpl) VerificarAtribuicaoCargo(usuarioID uint, cargo string) error {
+	usuario, err := s.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	if models.NormalizarCargo(cargo) == models.CargoAdmin {
+		if models.NormalizarCargo(usuario.Cargo) != models.CargoAdmin {
+			return errors.New("apenas um administrador pode atribuir o cargo admin")
+		}
+		return nil
+	}
+
+	permitido, err := s.CargoTemPermissao(usuario.Cargo, models.PermPermissoesGerenciar)
+	if err != nil {
+		return err
+	}
+	if !permitido {
+		return errors.New("atribuir cargos exige a permissão " + models.PermPermissoesGerenciar)
+	}
+	return nil
+}
+
+// VerificarGestaoUsuario completa VerificarAtribuicaoCargo para as contas já existentes: a conta de um admin
+// só pode ser alterada, desativada, excluída, desbloqueada ou ter as sessões encerradas por outro admin.
+// Sem isso, quem gerencia usuários poderia trocar o e-mail de um admin e tomar a conta pela redefinição de senha.
+// Alvos inexistentes passam, para que a rota responda que o usuário não foi encontrado
+func (s *PermissaoServiceImpl) VerificarGestaoUsuario(usuarioID, alvoID uint) error {
+	if usuarioID == alvoID {
+		return nil
+	}
+	alvo, err := s.usuarioRepo.FindByID(alvoID)
+	if err != nil || models.NormalizarCargo(alvo.Cargo) != models.CargoAdmin {
+		return nil
+	}
+
+	usuario, err := s.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+	if models.NormalizarCargo(usuario.Cargo) != models.CargoAdmin {
+		return errors.New("apenas um administrador pode alterar a conta de outro administrador")
+	}
+	return nil
+}
+
+// carregarCache retorna o mapa de permissões em memória, carregando-o do banco se necessário.
+// Uma invalidação ocorrida durante a leitura do banco descarta o mapa lido, que pode estar desatualizado
+func (s *PermissaoServiceImpl) carregarCache() (map[string]map[string]bool, error) {
+	s.mu.RLock()
+	mapa := s.cache
+	geracao := s.geracao
+	s.mu.RUnlock()
+	if mapa != nil {
+		return mapa, nil
+	}
+
+	vinculos, err := s.permissaoRepo.FindAllCargoPermissoes()
+	if err != nil {
+		return nil, errors.New("erro ao carregar permissões")
+	}
+
+	mapa = make(map[string]map[string]bool)
+	for _, v := range vinculos {
+		if mapa[v.Cargo] == nil {
+			mapa[v.Cargo] = make(map[string]bool)
+		}
+		mapa[v.Cargo][v.PermissaoCodigo] = true
+	}
+
+	s.mu.Lock()
+	if s.geracao == geracao {
+		s.cache = mapa
+	}
+	s.mu.Unlock()
+	return mapa, nil
+}
+
+// invalidarCache descarta o mapa em memória após alterações nas permissões
+func (s *PermissaoServiceImpl) invalidarCache() {
+	s.mu.Lock()
+	s.cache = nil
+	s.geracao++
+	s.mu.Unlock()
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+func TestUsuarioTemPermissaoSegueOCargo(t *testing.T) {
+	a := novoAmbiente(t)
+	admin := a.novoUsuario(t, models.CargoAdmin)
+	mecanico := a.novoUsuario(t, "Mecânico")
+
+	casos := []struct {
+		nome      string
+		usuario   *models.Usuario
+		permissao string
+		esperado  bool
+	}{
+		{"admin tem todo o catálogo", admin, models.PermPermissoesGerenciar, true},
+		{"cargo com acento usa as permissões padrão", mecanico, models.PermOrdensServicoEscrever, true},
+		{"permissão fora do cargo", mecanico, models.PermFinanceiroReceber, false},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			permitido, err := a.permissao.UsuarioTemPermissao(caso.usuario.ID, caso.permissao)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if permitido != caso.esperado {
+				t.Errorf("permitido = %v, esperado %v", permitido, caso.esperado)
+			}
+		})
+	}
+
+	if _, err := a.permissao.UsuarioTemPermissao(9999, models.PermClientesLer); err == nil {
+		t.Error("usuário inexistente deveria retornar erro")
+	}
+}
+
+func TestAtualizarCargoInvalidaOCache(t *testing.T) {
+	a := novoAmbiente(t)
+	mecanico := a.novoUsuario(t, models.CargoMecanico)
+
+	// Carrega o cache antes da alteração
+	if permitido, _ := a.permissao.UsuarioTemPermissao(mecanico.ID, models.PermCaixaOperar); permitido {
+		t.Fatal("o mecânico não deveria operar o caixa por padrão")
+	}
+
+	if _, err := a.permissao.AtualizarCargo(models.CargoMecanico, []string{models.PermCaixaOperar, models.PermCaixaOperar}); err != nil {
+		t.Fatalf("erro ao atualizar cargo: %v", err)
+	}
+
+	permitido, err := a.permissao.UsuarioTemPermissao(mecanico.ID, models.PermCaixaOperar)
+	if err != nil || !permitido {
+		t.Errorf("a permissão concedida deveria valer sem reiniciar o servidor (permitido=%v, err=%v)", permitido, err)
+	}
+	if permitido, _ := a.permissao.UsuarioTemPermissao(mecanico.ID, models.PermOrdensServicoLer); permitido {
+		t.Error("as permissões anteriores do cargo deveriam ter sido substituídas")
+	}
+}
+
+func TestAtualizarCargoRejeitaAdminEPermissaoDesconhecida(t *testing.T) {
+	a := novoAmbiente(t)
+
+	if _, err := a.permissao.AtualizarCargo(models.CargoAdmin, nil); err == nil {
+		t.Error("as permissões do admin não deveriam ser editáveis")
+	}
+	if _, err := a.permissao.AtualizarCargo(models.CargoGerente, []string{"inexistente:ler"}); err == nil {
+		t.Error("permissão fora do catálogo deveria ser rejeitada")
+	}
+}
+
+func TestVerificarAtribuicaoCargo(t *testing.T) {
+	a := novoAmbiente(t)
+	admin := a.novoUsuario(t, models.CargoAdmin)
+	gerente := a.novoUsuario(t, models.CargoGerente)
+	atendente := a.novoUsuario(t, models.CargoAtendente)
+
+	// O gerente não gerencia permissões por padrão; concede-se para testar o limite do cargo admin
+	if _, err := a.permissao.AtualizarCargo(models.CargoGerente, []string{models.PermPermissoesGerenciar}); err != nil {
+		t.Fatalf("erro ao atualizar cargo: %v", err)
+	}
+
+	casos := []struct {
+		nome     string
+		usuario  *models.Usuario
+		cargo    string
+		permitir bool
+	}{
+		{"admin atribui admin", admin, models.CargoAdmin, true},
+		{"admin atribui mecânico", admin, models.CargoMecanico, true},
+		{"gerente com gestão de permissões atribui mecânico", gerente, models.CargoMecanico, true},
+		{"gerente não atribui admin", gerente, "Admin", false},
+		{"atendente não atribui cargos", atendente, models.CargoMecanico, false},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := a.permissao.VerificarAtribuicaoCargo(caso.usuario.ID, caso.cargo)
+			if (err == nil) != caso.permitir {
+				t.Errorf("erro = %v, permitir = %v", err, caso.permitir)
+			}
+		})
+	}
+}
+
+func TestVerificarGestaoUsuario(t *testing.T) {
+	a := novoAmbiente(t)
+	admin := a.novoUsuario(t, models.CargoAdmin)
+	outroAdmin := a.novoUsuario(t, models.CargoAdmin)
+	gerente := a.novoUsuario(t, models.CargoGerente)
+	atendente := a.novoUsuario(t, models.CargoAtendente)
+
+	casos := []struct {
+		nome     string
+		usuario  *models.Usuario
+		alvoID   uint
+		permitir bool
+	}{
+		{"admin altera outro admin", admin, outroAdmin.ID, true},
+		{"admin altera gerente", admin, gerente.ID, true},
+		{"gerente altera atendente", gerente, atendente.ID, true},
+		{"gerente não altera admin", gerente, admin.ID, false},
+		{"atendente não altera admin", atendente, outroAdmin.ID, false},
+		{"admin altera a própria conta", admin, admin.ID, true},
+		{"alvo inexistente fica para a rota", gerente, 9999, true},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := a.permissao.VerificarGestaoUsuario(caso.usuario.ID, caso.alvoID)
+			if (err == nil) != caso.permitir {
+				t.Errorf("erro = %v, permitir = %v", err, caso.permitir)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// Espera imposta a uma conta após cada falha de login: começa em um segundo e dobra a cada falha, até o máximo
+const (
+	atrasoLoginInicial = time.Second
+	atrasoLoginMaximo  = 30 * time.Second
+)
+
+// ProtecaoLoginService define a interface da proteção do login contra tentativas repetidas:
+// espera progressiva e bloqueio temporário por conta e por IP, além do histórico de acessos
+type ProtecaoLoginService interface {
+	Verificar(email, ip string) (time.Duration, error) // Retorna a espera se o login estiver bloqueado; zero libera a tentativa
+	Registrar(tentativa *models.TentativaAcesso) error // Grava o resultado da tentativa e atualiza as falhas
+	Desbloquear(usuarioID uint) error                  // Zera as falhas da conta do usuário
+	BuscarAcessos(usuarioID uint, consulta repositories.Consulta) (*models.Pagina[models.TentativaAcesso], error)
+}
+
+// ProtecaoLoginServiceImpl implementa a interface ProtecaoLoginService
+type ProtecaoLoginServiceImpl struct {
+	acessoRepo        repositories.AcessoRepository
+	usuarioRepo       repositories.UsuarioRepository
+	uow               repositories.UnitOfWork
+	limiteFalhasConta int           // Falhas seguidas de uma conta até o bloqueio
+	limiteFalhasIP    int           // Falhas de um IP, somando todas as contas, até o bloqueio
+	bloqueio          time.Duration // Duração do bloqueio; falhas mais antigas que isso deixam de contar
+}
+
+// NewProtecaoLoginService cria uma nova instância do serviço de proteção do login
+func NewProtecaoLoginService(
+	acessoRepo repositories.AcessoRepository,
+	usuarioRepo repositories.UsuarioRepository,
+	uow repositories.UnitOfWork,
+	limiteFalhasConta, limiteFalhasIP int,
+	bloqueio time.Duration,
+) ProtecaoLoginService {
+	return &ProtecaoLoginServiceImpl{
+		acessoRepo:        acessoRepo,
+		usuarioRepo:       usuarioRepo,
+		uow:               uow,
+		limiteFalhasConta: limiteFalhasConta,
+		limiteFalhasIP:    limiteFalhasIP,
+		bloqueio:          bloqueio,
+	}
+}
+
+// Verificar confere se a conta e o IP podem tentar o login agora. Só os alvos que já falharam têm controle;
+// para uma conta com falhas, a tentativa liberada já reserva a espera que ela terá se falhar de novo,
+// para que requisições simultâneas não escapem do atraso
+func (s *ProtecaoLoginServiceImpl) Verificar(email, ip string) (time.Duration, error) {
+	agora := time.Now()
+	var espera time.Duration
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		acessoRepo := s.acessoRepo.WithTx(tx)
+
+		// A conta é sempre bloqueada antes do IP, na mesma ordem de Registrar, para não haver deadlock
+		conta, err := s.controleExistente(acessoRepo, models.ControleLoginConta, normalizarEmailLogin(email), agora)
+		if err != nil {
+			return err
+		}
+		if conta != nil {
+			espera = conta.Espera(agora)
+		}
+
+		if ip != "" {
+			origem, err := s.controleExistente(acessoRepo, models.ControleLoginIP, limitarTexto(ip, 45), agora)
+			if err != nil {
+				return err
+			}
+			if origem != nil && origem.Espera(agora) > espera {
+				espera = origem.Espera(agora)
+			}
+		}
+		if espera > 0 || conta == nil || conta.Falhas == 0 {
+			return nil
+		}
+
+		proxima := agora.Add(atrasoLogin(conta.Falhas + 1))
+		conta.ProximaTentativa = &proxima
+		return acessoRepo.UpdateControle(conta)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return espera, nil
+}
+
+// controleExistente busca o controle do alvo sem criá-lo, já descontando as falhas fora da janela.
+// Retorna nil para os alvos sem falhas registradas
+func (s *ProtecaoLoginServiceImpl) controleExistente(acessoRepo repositories.AcessoRepository, tipo, chave string, agora time.Time) (*models.ControleLogin, error) {
+	controle, err := acessoRepo.FindControleForUpdate(tipo, chave)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("erro ao verificar as tentativas de login: " + err.Error())
+	}
+	s.descartarFalhasAntigas(controle, agora)
+	return controle, nil
+}
+
+// Registrar grava a tentativa no histórico. O sucesso zera as falhas da conta; credenciais inválidas
+// somam uma falha à conta e ao IP. As demais recusas (conta inativa, login bloqueado) só ficam no histórico
+func (s *ProtecaoLoginServiceImpl) Registrar(tentativa *models.TentativaAcesso) error {
+	agora := time.Now()
+	tentativa.Email = normalizarEmailLogin(tentativa.Email)
+	tentativa.IP = limitarTexto(tentativa.IP, 45)
+	tentativa.UserAgent = limitarTexto(tentativa.UserAgent, 255)
+
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		acessoRepo := s.acessoRepo.WithTx(tx)
+		if err := acessoRepo.CreateTentativa(tentativa); err != nil {
+			return errors.New("erro ao registrar a tentativa de login: " + err.Error())
+		}
+
+		if tentativa.Sucesso {
+			// As falhas do IP continuam: um login válido não libera quem testa outras contas do mesmo endereço
+			return acessoRepo.DeleteControle(models.ControleLoginConta, tentativa.Email)
+		}
+		if tentativa.Motivo != models.MotivoAcessoCredenciaisInvalidas {
+			return nil
+		}
+
+		if err := s.somarFalha(acessoRepo, models.ControleLoginConta, tentativa.Email, s.limiteFalhasConta, agora); err != nil {
+			return err
+		}
+		if tentativa.IP != "" {
+			return s.somarFalha(acessoRepo, models.ControleLoginIP, tentativa.IP, s.limiteFalhasIP, agora)
+		}
+		return nil
+	})
+	if err != nil || tentativa.Sucesso || tentativa.Motivo != models.MotivoAcessoCredenciaisInvalidas {
+		return err
+	}
+
+	// Cada falha cria controles novos; os que saíram da janela são descartados aqui, para que tentativas
+	// com e-mails e IPs aleatórios não façam a tabela crescer sem limite
+	if _, err := s.acessoRepo.DeleteControlesExpirados(agora.Add(-s.bloqueio)); err != nil {
+		log.Printf("Erro ao descartar os controles de login expirados: %v", err)
+	}
+	return nil
+}
+
+// somarFalha conta uma falha do alvo, criando o controle na primeira, aplicando a espera progressiva (só nas contas) e o bloqueio ao atingir o limite
+func (s *ProtecaoLoginServiceImpl) somarFalha(acessoRepo repositories.AcessoRepository, tipo, chave string, limite int, agora time.Time) error {
+	controle, err := acessoRepo.FindOrCreateControleForUpdate(tipo, chave)
+	if err != nil {
+		return errors.New("erro ao registrar a falha de login: " + err.Error())
+	}
+	s.descartarFalhasAntigas(controle, agora)
+
+	controle.Falhas++
+	controle.UltimaFalha = &agora
+	controle.ProximaTentativa = nil
+	if tipo == models.ControleLoginConta {
+		// Um IP compartilhado (a rede da oficina) não deve atrasar todos os funcionários por causa de um deles
+		proxima := agora.Add(atrasoLogin(controle.Falhas))
+		controle.ProximaTentativa = &proxima
+	}
+	if controle.Falhas >= limite {
+		bloqueadoAte := agora.Add(s.bloqueio)
+		controle.BloqueadoAte = &bloqueadoAte
+	}
+	return acessoRepo.UpdateControle(controle)
+}
+
+// descartarFalhasAntigas zera o controle quando a última falha saiu da janela (o que também encerra o bloqueio)
+func (s *ProtecaoLoginServiceImpl) descartarFalhasAntigas(controle *models.ControleLogin, agora time.Time) {
+	if controle.UltimaFalha != nil && agora.Sub(*controle.UltimaFalha) >= s.bloqueio {
+		controle.Falhas = 0
+		controle.UltimaFalha = nil
+		controle.BloqueadoAte = nil
+	}
+}
+
+// Desbloquear zera as falhas da conta do usuário, liberando o login antes do fim do bloqueio.
+// O bloqueio do IP, se houver, continua valendo
+func (s *ProtecaoLoginServiceImpl) Desbloquear(usuarioID uint) error {
+	usuario, err := s.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+	return s.acessoRepo.DeleteControle(models.ControleLoginConta, normalizarEmailLogin(usuario.Email))
+}
+
+// BuscarAcessos lista o histórico de logins do usuário, dos mais recentes para os mais antigos
+func (s *ProtecaoLoginServiceImpl) BuscarAcessos(usuarioID uint, consulta repositories.Consulta) (*models.Pagina[models.TentativaAcesso], error) {
+	return s.acessoRepo.FindTentativasPorUsuario(usuarioID, consulta)
+}
+
+// atrasoLogin retorna a espera após a enésima falha seguida
+func atrasoLogin(falhas int) time.Duration {
+	if falhas <= 0 {
+		return 0
+	}
+	atraso := atrasoLoginInicial
+	for i := 1; i < falhas && atraso < atrasoLoginMaximo; i++ {
+		atraso *= 2
+	}
+	if atraso > atrasoLoginMaximo {
+		atraso = atrasoLoginMaximo
+	}
+	return atraso
+}
+
+// normalizarEmailLogin identifica a conta pelo e-mail informado, sem diferenciar maiúsculas
+func normalizarEmailLogin(email string) string {
+	return limitarTexto(strings.ToLower(strings.TrimSpace(email)), 100)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// liberarEspera descarta a espera progressiva, simulando que o usuário aguardou antes de tentar de novo
+func (a *ambienteTeste) liberarEspera(t *testing.T) {
+	t.Helper()
+	if err := a.db.Model(&models.ControleLogin{}).Where("1 = 1").Update("proxima_tentativa", nil).Error; err != nil {
+		t.Fatalf("erro ao liberar a espera: %v", err)
+	}
+}
+
+// tentarLogin passa pela verificação e registra a tentativa; sem motivo, registra um sucesso
+func (a *ambienteTeste) tentarLogin(t *testing.T, email, ip string, usuarioID *uint, motivo string) {
+	t.Helper()
+	a.liberarEspera(t)
+	if espera, err := a.protecao.Verificar(email, ip); err != nil || espera > 0 {
+		t.Fatalf("a tentativa deveria ser liberada: espera %v, erro %v", espera, err)
+	}
+	err := a.protecao.Registrar(&models.TentativaAcesso{
+		UsuarioID: usuarioID, Email: email, IP: ip, UserAgent: "Navegador", Sucesso: motivo == "", Motivo: motivo,
+	})
+	if err != nil {
+		t.Fatalf("erro ao registrar a tentativa: %v", err)
+	}
+}
+
+// esperaLogin consulta quanto falta para a conta e o IP poderem tentar de novo
+func (a *ambienteTeste) esperaLogin(t *testing.T, email, ip string) time.Duration {
+	t.Helper()
+	espera, err := a.protecao.Verificar(email, ip)
+	if err != nil {
+		t.Fatalf("erro ao verificar: %v", err)
+	}
+	return espera
+}
+
+func TestFalhasDeLoginAumentamAEspera(t *testing.T) {
+	a := novoAmbiente(t)
+	const email = "ana@oficina.com"
+
+	a.tentarLogin(t, email, "10.0.0.1", nil, models.MotivoAcessoCredenciaisInvalidas)
+	primeira := a.esperaLogin(t, email, "10.0.0.1")
+	if primeira <= 0 || primeira > atrasoLoginInicial {
+		t.Fatalf("espera após a primeira falha = %v, esperado até %v", primeira, atrasoLoginInicial)
+	}
+
+	a.tentarLogin(t, email, "10.0.0.1", nil, models.MotivoAcessoCredenciaisInvalidas)
+	if segunda := a.esperaLogin(t, email, "10.0.0.1"); segunda <= primeira {
+		t.Errorf("espera após a segunda falha = %v, esperado maior que %v", segunda, primeira)
+	}
+
+	// O e-mail é o mesmo com outra grafia; outra conta no mesmo IP não espera
+	if a.esperaLogin(t, " ANA@oficina.com", "10.0.0.2") == 0 {
+		t.Error("a espera deveria valer para o e-mail sem diferenciar maiúsculas")
+	}
+	if espera := a.esperaLogin(t, "beto@oficina.com", "10.0.0.1"); espera != 0 {
+		t.Errorf("outra conta no mesmo IP não deveria esperar (espera %v)", espera)
+	}
+}
+
+func TestVerificarReservaATentativa(t *testing.T) {
+	a := novoAmbiente(t)
+	a.tentarLogin(t, "ana@oficina.com", "10.0.0.1", nil, models.MotivoAcessoCredenciaisInvalidas)
+	a.liberarEspera(t)
+
+	// Duas requisições simultâneas: a segunda chega antes de a primeira registrar o resultado
+	if espera := a.esperaLogin(t, "ana@oficina.com", "10.0.0.1"); espera != 0 {
+		t.Fatalf("a tentativa após a espera deveria ser liberada (espera %v)", espera)
+	}
+	if a.esperaLogin(t, "ana@oficina.com", "10.0.0.2") == 0 {
+		t.Error("a tentativa em andamento deveria atrasar a seguinte da mesma conta")
+	}
+}
+
+// controlesLogin conta as linhas de controle de falhas gravadas
+func (a *ambienteTeste) controlesLogin(t *testing.T) int64 {
+	t.Helper()
+	var total int64
+	if err := a.db.Model(&models.ControleLogin{}).Count(&total).Error; err != nil {
+		t.Fatalf("erro ao contar os controles: %v", err)
+	}
+	return total
+}
+
+func TestControleDeLoginSoParaQuemFalhou(t *testing.T) {
+	a := novoAmbiente(t)
+
+	// Contas e IPs sem falhas passam sem criar controle nem reservar espera
+	for i := 0; i < 3; i++ {
+		if espera := a.esperaLogin(t, "ana@oficina.com", "10.0.0.1"); espera != 0 {
+			t.Fatalf("a conta sem falhas não deveria esperar (espera %v)", espera)
+		}
+	}
+	if total := a.controlesLogin(t); total != 0 {
+		t.Fatalf("%d controles gravados, esperado nenhum antes da primeira falha", total)
+	}
+
+	a.tentarLogin(t, "ana@oficina.com", "10.0.0.1", nil, models.MotivoAcessoCredenciaisInvalidas)
+	if total := a.controlesLogin(t); total != 2 {
+		t.Fatalf("%d controles gravados, esperado o da conta e o do IP", total)
+	}
+
+	// As falhas que saíram da janela são descartadas na falha seguinte, de qualquer alvo
+	a.db.Model(&models.ControleLogin{}).Where("1 = 1").Update("ultima_falha", time.Now().Add(-16*time.Minute))
+	a.tentarLogin(t, "beto@oficina.com", "10.0.0.2", nil, models.MotivoAcessoCredenciaisInvalidas)
+	var chaves []string
+	a.db.Model(&models.ControleLogin{}).Order("chave").Pluck("chave", &chaves)
+	if len(chaves) != 2 || chaves[0] != "10.0.0.2" || chaves[1] != "beto@oficina.com" {
+		t.Errorf("controles restantes %v, esperado apenas os da falha recente", chaves)
+	}
+}
+
+func TestBloqueioDaContaEDesbloqueio(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	for i := 0; i < 3; i++ {
+		a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, models.MotivoAcessoCredenciaisInvalidas)
+	}
+	a.liberarEspera(t)
+	if espera := a.esperaLogin(t, usuario.Email, "10.0.0.2"); espera < 14*time.Minute {
+		t.Fatalf("espera = %v, esperado o bloqueio de 15 minutos", espera)
+	}
+
+	if err := a.protecao.Desbloquear(usuario.ID); err != nil {
+		t.Fatalf("erro ao desbloquear: %v", err)
+	}
+	if espera := a.esperaLogin(t, usuario.Email, "10.0.0.2"); espera != 0 {
+		t.Errorf("após o desbloqueio o login deveria ser liberado (espera %v)", espera)
+	}
+	if err := a.protecao.Desbloquear(9999); err == nil {
+		t.Error("o desbloqueio de um usuário inexistente deveria falhar")
+	}
+}
+
+func TestBloqueioTerminaEZeraAsFalhas(t *testing.T) {
+	a := novoAmbiente(t)
+	const email = "ana@oficina.com"
+	for i := 0; i < 3; i++ {
+		a.tentarLogin(t, email, "10.0.0.1", nil, models.MotivoAcessoCredenciaisInvalidas)
+	}
+
+	// Simula a passagem dos 15 minutos do bloqueio
+	recuo := -16 * time.Minute
+	var controles []models.ControleLogin
+	a.db.Find(&controles)
+	for _, controle := range controles {
+		ultima, bloqueio := controle.UltimaFalha.Add(recuo), time.Now().Add(recuo)
+		controle.UltimaFalha, controle.ProximaTentativa = &ultima, nil
+		if controle.BloqueadoAte != nil {
+			controle.BloqueadoAte = &bloqueio
+		}
+		a.db.Save(&controle)
+	}
+
+	// Uma nova falha recomeça a contagem em vez de bloquear de novo
+	a.tentarLogin(t, email, "10.0.0.1", nil, models.MotivoAcessoCredenciaisInvalidas)
+	if espera := a.esperaLogin(t, email, "10.0.0.1"); espera <= 0 || espera > atrasoLoginInicial {
+		t.Errorf("espera = %v, esperado o atraso da primeira falha", espera)
+	}
+}
+
+func TestBloqueioDoIPEmVariasContas(t *testing.T) {
+	a := novoAmbiente(t)
+	contas := []string{"a@oficina.com", "b@oficina.com", "c@oficina.com", "d@oficina.com", "e@oficina.com"}
+	for _, email := range contas {
+		a.tentarLogin(t, email, "10.0.0.9", nil, models.MotivoAcessoCredenciaisInvalidas)
+	}
+
+	if espera := a.esperaLogin(t, "f@oficina.com", "10.0.0.9"); espera < 14*time.Minute {
+		t.Errorf("espera = %v, esperado o bloqueio do IP", espera)
+	}
+	if espera := a.esperaLogin(t, "f@oficina.com", "10.0.0.10"); espera != 0 {
+		t.Errorf("a mesma conta em outro IP não deveria esperar (espera %v)", espera)
+	}
+}
+
+func TestLoginComSucessoZeraAsFalhasDaConta(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, models.MotivoAcessoCredenciaisInvalidas)
+	a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, models.MotivoAcessoCredenciaisInvalidas)
+	a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, "")
+	// A recusa de uma conta inativa não é uma tentativa de adivinhar a senha
+	a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, models.MotivoAcessoUsuarioInativo)
+	a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, models.MotivoAcessoCredenciaisInvalidas)
+
+	a.liberarEspera(t)
+	if espera := a.esperaLogin(t, usuario.Email, "10.0.0.1"); espera != 0 {
+		t.Errorf("após o sucesso uma única falha não deveria bloquear (espera %v)", espera)
+	}
+
+	acessos, err := a.protecao.BuscarAcessos(usuario.ID, repositories.Consulta{})
+	if err != nil {
+		t.Fatalf("erro ao listar os acessos: %v", err)
+	}
+	if acessos.Total != 5 || acessos.Itens[0].Motivo != models.MotivoAcessoCredenciaisInvalidas {
+		t.Errorf("histórico com %d tentativas, esperado 5 com a mais recente primeiro", acessos.Total)
+	}
+	sucessos, _ := a.protecao.BuscarAcessos(usuario.ID, repositories.Consulta{Filtros: map[string]string{"sucesso": "true"}})
+	if sucessos.Total != 1 || sucessos.Itens[0].IP != "10.0.0.1" || sucessos.Itens[0].UserAgent != "Navegador" {
+		t.Errorf("filtro de sucesso com %d tentativas, esperado 1 com IP e navegador", sucessos.Total)
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// intervaloPedidosSenha é o tempo mínimo entre dois e-mails de redefinição para o mesmo usuário
+const intervaloPedidosSenha = time.Minute
+
+// RedefinicaoSenhaService define a interface para a troca de senha esquecida por um link enviado por e-mail
+type RedefinicaoSenhaService interface {
+	Solicitar(email, ip string)              // Envia o link em segundo plano; não revela se o e-mail tem cadastro
+	Redefinir(token, novaSenha string) error // Troca a senha e encerra as sessões abertas
+}
+
+// RedefinicaoSenhaServiceImpl implementa a interface RedefinicaoSenhaService
+type RedefinicaoSenhaServiceImpl struct {
+	redefinicaoRepo repositories.RedefinicaoSenhaRepository
+	usuarioRepo     repositories.UsuarioRepository
+	sessaoRepo      repositories.SessaoRepository
+	acessoRepo      repositories.AcessoRepository
+	uow             repositories.UnitOfWork
+	senhas          senhasUsuario // Mesma política de senhas do cadastro e da troca
+	mailer          utils.Mailer
+	urlApp          string         // Endereço do frontend, onde fica a tela de nova senha
+	validade        time.Duration  // Prazo para usar o link
+	pedidos         sync.WaitGroup // Pedidos ainda em processamento
+}
+
+// NewRedefinicaoSenhaService cria uma nova instância do serviço de redefinição de senha
+func NewRedefinicaoSenhaService(
+	redefinicaoRepo repositories.RedefinicaoSenhaRepository,
+	usuarioRepo repositories.UsuarioRepository,
+	sessaoRepo repositories.SessaoRepository,
+	acessoRepo repositories.AcessoRepository,
+	historicoSenhaRepo repositories.HistoricoSenhaRepository,
+	uow repositories.UnitOfWork,
+	politicaSenha *utils.PoliticaSenha,
+	mailer utils.Mailer,
+	urlApp string,
+	validade time.Duration,
+) RedefinicaoSenhaService {
+	return &RedefinicaoSenhaServiceImpl{
+		redefinicaoRepo: redefinicaoRepo,
+		usuarioRepo:     usuarioRepo,
+		sessaoRepo:      sessaoRepo,
+		acessoRepo:      acessoRepo,
+		uow:             uow,
+		senhas:          senhasUsuario{politica: politicaSenha, historicoRepo: historicoSenhaRepo},
+		mailer:          mailer,
+		urlApp:          strings.TrimRight(strings.TrimSpace(urlApp), "/"),
+		validade:        validade,
+	}
+}
+
+// Solicitar registra o pedido em segundo plano e retorna sem esperar por ele. Qualquer e-mail segue o mesmo
+// caminho, para que nem a resposta nem o tempo dela (a consulta, a gravação e o envio pelo SMTP) revelem
+// quais contas existem; as falhas ficam no log para o suporte
+func (s *RedefinicaoSenhaServiceImpl) Solicitar(email, ip string) {
+	s.pedidos.Add(1)
+	go func() {
+		defer s.pedidos.Done()
+		if err := s.processarPedido(email, ip); err != nil {
+			log.Printf("Erro ao processar o pedido de redefinição de senha: %v", err)
+		}
+	}()
+}
+
+// processarPedido gera um link de uso único e o envia ao e-mail do usuário, invalidando os links anteriores.
+// E-mails sem cadastro, contas sem acesso e pedidos repetidos em menos de um minuto são ignorados
+func (s *RedefinicaoSenhaServiceImpl) processarPedido(email, ip string) error {
+	usuario, err := s.usuarioRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil || !usuario.PodeAcessar() {
+		return nil
+	}
+
+	recentes, err := s.redefinicaoRepo.ContarDesde(usuario.ID, time.Now().Add(-intervaloPedidosSenha))
+	if err != nil {
+		return errors.New("erro ao consultar os pedidos de redefinição: " + err.Error())
+	}
+	if recentes > 0 {
+		return nil
+	}
+
+	token, err := utils.GerarTokenAleatorio(32)
+	if err != nil {
+		return errors.New("erro ao gerar o link de redefinição")
+	}
+	redefinicao := &models.RedefinicaoSenha{
+		UsuarioID: usuario.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiraEm:  time.Now().Add(s.validade),
+		IP:        limitarTexto(ip, 45),
+	}
+
+	err = s.uow.Executar(func(tx *gorm.DB) error {
+		redefinicaoRepo := s.redefinicaoRepo.WithTx(tx)
+		if err := redefinicaoRepo.InvalidarPendentes(usuario.ID); err != nil {
+			return errors.New("erro ao invalidar os links anteriores: " + err.Error())
+		}
+		if err := redefinicaoRepo.Create(redefinicao); err != nil {
+			return errors.New("erro ao registrar o pedido de redefinição: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Enviar(s.mensagemRedefinicao(usuario, token)); err != nil {
+		return fmt.Errorf("erro ao enviar o e-mail do usuário %d: %v", usuario.ID, err)
+	}
+	return nil
+}
+
+// Redefinir troca a senha do usuário do link, gravando apenas o hash da senha nova;
+// o link é consumido e as sessões abertas e o bloqueio de login da conta são encerrados na mesma transação
+func (s *RedefinicaoSenhaServiceImpl) Redefinir(token, novaSenha string) error {
+	if strings.TrimSpace(token) == "" || novaSenha == "" {
+		return errors.New("token e nova senha são obrigatórios")
+	}
+
+	agora := time.Now()
+	return s.uow.Executar(func(tx *gorm.DB) error {
+		redefinicaoRepo := s.redefinicaoRepo.WithTx(tx)
+		usuarioRepo := s.usuarioRepo.WithTx(tx)
+
+		redefinicao, err := redefinicaoRepo.FindByHashForUpdate(utils.HashToken(token))
+		if err != nil || redefinicao.UsadoEm != nil || !agora.Before(redefinicao.ExpiraEm) {
+			return errors.New("link de redefinição inválido ou expirado")
+		}
+
+		usuario, err := usuarioRepo.FindByID(redefinicao.UsuarioID)
+		if err != nil || !usuario.PodeAcessar() {
+			return errors.New("link de redefinição inválido ou expirado")
+		}
+
+		historicoRepo := s.senhas.historicoRepo.WithTx(tx)
+		if err := s.senhas.validar(historicoRepo, usuario, novaSenha); err != nil {
+			return err
+		}
+
+		if err := usuario.DefinirSenha(novaSenha); err != nil {
+			return errors.New("erro ao criptografar a senha")
+		}
+		if err := usuarioRepo.Update(usuario); err != nil {
+			return errors.New("erro ao atualizar senha")
+		}
+		if err := s.senhas.registrar(historicoRepo, usuario); err != nil {
+			return err
+		}
+
+		if err := redefinicaoRepo.InvalidarPendentes(usuario.ID); err != nil {
+			return errors.New("erro ao invalidar o link de redefinição: " + err.Error())
+		}
+		if err := s.sessaoRepo.WithTx(tx).RevogarPorUsuario(usuario.ID, models.MotivoSessaoSenhaRedefinida); err != nil {
+			return errors.New("erro ao encerrar as sessões: " + err.Error())
+		}
+		return s.acessoRepo.WithTx(tx).DeleteControle(models.ControleLoginConta, normalizarEmailLogin(usuario.Email))
+	})
+}
+
+// mensagemRedefinicao monta o e-mail com o link de redefinição; sem APP_URL, envia apenas o código
+func (s *RedefinicaoSenhaServiceImpl) mensagemRedefinicao(usuario *models.Usuario, token string) utils.Mensagem {
+	link := "Código: " + token
+	if s.urlApp != "" {
+		link = s.urlApp + "/redefinir-senha?token=" + token
+	}
+
+	return utils.Mensagem{
+		Para:    usuario.Email,
+		Assunto: "Redefinição de senha",
+		Corpo: fmt.Sprintf("Olá, %s.\n\n"+
+			"Recebemos um pedido para redefinir a sua senha. Use o link abaixo em até %d minutos:\n\n"+
+			"%s\n\n"+
+			"O link vale para uma única troca. Se você não fez o pedido, ignore este e-mail; a sua senha continua a mesma.\n",
+			usuario.Nome, int(s.validade.Minutes()), link),
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/utils"
+)
+
+// solicitarRedefinicao faz o pedido e espera o processamento em segundo plano terminar
+func (a *ambienteTeste) solicitarRedefinicao(email, ip string) {
+	a.redefinicao.Solicitar(email, ip)
+	a.redefinicao.(*RedefinicaoSenhaServiceImpl).pedidos.Wait()
+}
+
+// tokenDoEmail extrai o token do link do último e-mail enviado
+func (a *ambienteTeste) tokenDoEmail(t *testing.T) string {
+	t.Helper()
+	if len(a.mailer.enviados) == 0 {
+		t.Fatal("nenhum e-mail enviado")
+	}
+	corpo := a.mailer.enviados[len(a.mailer.enviados)-1].Corpo
+	const prefixo = "https://oficina.test/redefinir-senha?token="
+	inicio := strings.Index(corpo, prefixo)
+	if inicio < 0 {
+		t.Fatalf("link de redefinição ausente no e-mail: %q", corpo)
+	}
+	return strings.Fields(corpo[inicio+len(prefixo):])[0]
+}
+
+// senhaAtual confere a senha gravada do usuário
+func (a *ambienteTeste) senhaAtual(t *testing.T, usuarioID uint, senha string) bool {
+	t.Helper()
+	var usuario models.Usuario
+	if err := a.db.First(&usuario, usuarioID).Error; err != nil {
+		t.Fatalf("erro ao buscar o usuário: %v", err)
+	}
+	return usuario.CompareSenha(senha)
+}
+
+func TestSolicitarRedefinicaoNaoRevelaCadastro(t *testing.T) {
+	a := novoAmbiente(t)
+	inativo := a.novoUsuario(t, models.CargoAtendente)
+	a.db.Model(inativo).Update("ativo", false)
+
+	for _, email := range []string{"ninguem@oficina.com", inativo.Email} {
+		a.solicitarRedefinicao(email, "10.0.0.1")
+	}
+	if len(a.mailer.enviados) != 0 {
+		t.Errorf("%d e-mails enviados, esperado nenhum para contas inexistentes ou inativas", len(a.mailer.enviados))
+	}
+}
+
+func TestRedefinirSenhaPeloLink(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	tokens := a.entrar(t, usuario)
+
+	a.solicitarRedefinicao(" "+usuario.Email, "10.0.0.1")
+	token := a.tokenDoEmail(t)
+	if a.mailer.enviados[0].Para != usuario.Email {
+		t.Errorf("e-mail enviado para %q, esperado %q", a.mailer.enviados[0].Para, usuario.Email)
+	}
+
+	var pedido models.RedefinicaoSenha
+	a.db.First(&pedido)
+	if pedido.TokenHash == token || len(pedido.TokenHash) != 64 || pedido.IP != "10.0.0.1" {
+		t.Error("o banco deveria guardar apenas o SHA-256 do token, com o IP do pedido")
+	}
+
+	if err := a.redefinicao.Redefinir(token, "novaSenha123"); err != nil {
+		t.Fatalf("erro ao redefinir: %v", err)
+	}
+	if !a.senhaAtual(t, usuario.ID, "novaSenha123") || a.senhaAtual(t, usuario.ID, "senha123") {
+		t.Error("a nova senha deveria ser gravada com hash e a antiga deixar de valer")
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, tokens), usuario.ID); err == nil {
+		t.Error("as sessões abertas antes da troca deveriam ser encerradas")
+	}
+
+	if err := a.redefinicao.Redefinir(token, "outraSenha456"); err == nil {
+		t.Error("o link não deveria ser usado duas vezes")
+	}
+	if err := a.redefinicao.Redefinir("token-inventado", "outraSenha456"); err == nil {
+		t.Error("um token desconhecido deveria ser recusado")
+	}
+}
+
+func TestRedefinirAplicaAPoliticaDeSenha(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	a.solicitarRedefinicao(usuario.Email, "10.0.0.1")
+	token := a.tokenDoEmail(t)
+
+	for _, senha := range []string{"curta", "senha123"} {
+		if err := a.redefinicao.Redefinir(token, senha); err == nil {
+			t.Errorf("a senha %q deveria ser recusada", senha)
+		}
+	}
+	// A recusa não consome o link
+	if err := a.redefinicao.Redefinir(token, "novaSenha123"); err != nil {
+		t.Fatalf("o link deveria continuar válido após a senha recusada: %v", err)
+	}
+	if a.senhasNoHistorico(t, usuario.ID) != 1 {
+		t.Error("a senha redefinida deveria entrar no histórico")
+	}
+}
+
+func TestRedefinicaoExpiraEDaLugarAoPedidoNovo(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	a.solicitarRedefinicao(usuario.Email, "")
+	primeiro := a.tokenDoEmail(t)
+
+	// Um segundo pedido logo em seguida é ignorado
+	a.solicitarRedefinicao(usuario.Email, "")
+	if len(a.mailer.enviados) != 1 {
+		t.Fatalf("%d e-mails enviados, esperado 1 no intervalo mínimo entre pedidos", len(a.mailer.enviados))
+	}
+
+	// Passado o intervalo, o pedido novo invalida o link anterior
+	a.db.Model(&models.RedefinicaoSenha{}).Where("1 = 1").Update("created_at", time.Now().Add(-2*time.Minute))
+	a.solicitarRedefinicao(usuario.Email, "")
+	segundo := a.tokenDoEmail(t)
+	if err := a.redefinicao.Redefinir(primeiro, "novaSenha123"); err == nil {
+		t.Error("o link anterior deveria ser invalidado pelo pedido novo")
+	}
+
+	a.db.Model(&models.RedefinicaoSenha{}).Where("1 = 1").Update("expira_em", time.Now().Add(-time.Second))
+	if err := a.redefinicao.Redefinir(segundo, "novaSenha123"); err == nil {
+		t.Error("o link expirado deveria ser recusado")
+	}
+	if !a.senhaAtual(t, usuario.ID, "senha123") {
+		t.Error("a senha não deveria mudar com links inválidos")
+	}
+}
+
+func TestRedefinirSenhaLiberaOLoginBloqueado(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	for i := 0; i < 3; i++ {
+		a.tentarLogin(t, usuario.Email, "10.0.0.1", &usuario.ID, models.MotivoAcessoCredenciaisInvalidas)
+	}
+
+	a.solicitarRedefinicao(usuario.Email, "")
+	if err := a.redefinicao.Redefinir(a.tokenDoEmail(t), "novaSenha123"); err != nil {
+		t.Fatalf("erro ao redefinir: %v", err)
+	}
+	if espera := a.esperaLogin(t, usuario.Email, "10.0.0.2"); espera != 0 {
+		t.Errorf("após a troca da senha o login deveria ser liberado (espera %v)", espera)
+	}
+}
+
+// mailerLento segura o envio até ser liberado, como um servidor SMTP demorado
+type mailerLento struct {
+	liberar chan struct{}
+}
+
+func (m *mailerLento) Enviar(mensagem utils.Mensagem) error {
+	<-m.liberar
+	return nil
+}
+
+func TestSolicitarNaoEsperaOEnvio(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	servico := a.redefinicao.(*RedefinicaoSenhaServiceImpl)
+	mailer := &mailerLento{liberar: make(chan struct{})}
+	servico.mailer = mailer
+
+	respondido := make(chan struct{})
+	go func() {
+		servico.Solicitar(usuario.Email, "10.0.0.1")
+		close(respondido)
+	}()
+	select {
+	case <-respondido:
+	case <-time.After(5 * time.Second):
+		t.Error("o pedido de uma conta existente não deveria esperar o envio do e-mail")
+	}
+
+	close(mailer.liberar)
+	servico.pedidos.Wait()
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// senhasUsuario aplica a política de senhas e mantém o histórico usado contra a reutilização.
+// É compartilhado pelo cadastro, pela troca e pela redefinição de senha, para que as regras sejam as mesmas
+type senhasUsuario struct {
+	politica      *utils.PoliticaSenha
+	historicoRepo repositories.HistoricoSenhaRepository
+}
+
+// validar confere a senha nova. Para contas já cadastradas, ela também não pode repetir
+// a senha atual nem as últimas do histórico
+func (s senhasUsuario) validar(historicoRepo repositories.HistoricoSenhaRepository, usuario *models.Usuario, senha string) error {
+	if err := s.politica.Validar(senha, usuario.Email); err != nil {
+		return err
+	}
+	if usuario.ID == 0 || s.politica.Historico == 0 {
+		return nil
+	}
+
+	historico, err := historicoRepo.FindUltimos(usuario.ID, s.politica.Historico)
+	if err != nil {
+		return errors.New("erro ao consultar o histórico de senhas: " + err.Error())
+	}
+	// A senha atual entra na comparação mesmo se foi definida antes de existir o histórico
+	hashes := []string{usuario.Senha}
+	for _, anterior := range historico {
+		hashes = append(hashes, anterior.SenhaHash)
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(senha)) == nil {
+			return &utils.ErroPoliticaSenha{Motivo: fmt.Sprintf("a senha não pode repetir nenhuma das últimas %d", s.politica.Historico)}
+		}
+	}
+	return nil
+}
+
+// registrar grava o hash da senha recém-definida e descarta os que já não contam para a reutilização
+func (s senhasUsuario) registrar(historicoRepo repositories.HistoricoSenhaRepository, usuario *models.Usuario) error {
+	if s.politica.Historico == 0 {
+		return nil
+	}
+	if err := historicoRepo.Create(&models.HistoricoSenha{UsuarioID: usuario.ID, SenhaHash: usuario.Senha}); err != nil {
+		return errors.New("erro ao registrar o histórico de senhas: " + err.Error())
+	}
+	return historicoRepo.DeleteAlemDe(usuario.ID, s.politica.Historico)
+}
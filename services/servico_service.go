@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+)
+
+// ServicoService define a interface para operações do catálogo de serviços
+type ServicoService interface {
+	BuscarTodos(categoria string) ([]models.Servico, error)     // Retorna o catálogo, opcionalmente de uma categoria
+	BuscarPorID(id uint) (*models.Servico, error)               // Busca um serviço pelo ID
+	Criar(servico *models.Servico) (*models.Servico, error)     // Cadastra um novo serviço
+	Atualizar(servico *models.Servico) (*models.Servico, error) // Atualiza os dados de um serviço
+	Deletar(id uint) error                                      // Remove um serviço do catálogo (soft delete)
+}
+
+// ServicoServiceImpl implementa a interface ServicoService
+type ServicoServiceImpl struct {
+	servicoRepo repositories.ServicoRepository
+}
+
+// NewServicoService cria uma nova instância do serviço do catálogo de serviços
+func NewServicoService(servicoRepo repositories.ServicoRepository) ServicoService {
+	return &ServicoServiceImpl{
+		servicoRepo: servicoRepo,
+	}
+}
+
+// BuscarTodos retorna os serviços do catálogo
+func (s *ServicoServiceImpl) BuscarTodos(categoria string) ([]models.Servico, error) {
+	if categoria != "" {
+		return s.servicoRepo.FindByCategoria(categoria)
+	}
+	return s.servicoRepo.FindAll()
+}
+
+// BuscarPorID busca um serviço pelo seu ID
+func (s *ServicoServiceImpl) BuscarPorID(id uint) (*models.Servico, error) {
+	servico, err := s.servicoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("serviço não encontrado")
+	}
+	return servico, nil
+}
+
+// Criar cadastra um novo serviço no catálogo garantindo nome único
+func (s *ServicoServiceImpl) Criar(servico *models.Servico) (*models.Servico, error) {
+	if err := s.validarDados(servico); err != nil {
+		return nil, err
+	}
+
+	servico.Ativo = true
+	if err := s.servicoRepo.Create(servico); err != nil {
+		return nil, errors.New("erro ao criar serviço: " + err.Error())
+	}
+
+	return servico, nil
+}
+
+// Atualizar altera os dados de um serviço; as OS já lançadas mantêm o valor da época
+func (s *ServicoServiceImpl) Atualizar(servico *models.Servico) (*models.Servico, error) {
+	existente, err := s.servicoRepo.FindByID(servico.ID)
+	if err != nil {
+		return nil, errors.New("serviço não encontrado")
+	}
+
+	if err := s.validarDados(servico); err != nil {
+		return nil, err
+	}
+
+	servico.CreatedAt = existente.CreatedAt
+	if err := s.servicoRepo.Update(servico); err != nil {
+		return nil, errors.New("erro ao atualizar serviço: " + err.Error())
+	}
+
+	return servico, nil
+}
+
+// Deletar remove um serviço do catálogo (soft delete)
+func (s *ServicoServiceImpl) Deletar(id uint) error {
+	if _, err := s.servicoRepo.FindByID(id); err != nil {
+		return errors.New("serviço não encontrado")
+	}
+
+	if err := s.servicoRepo.Delete(id); err != nil {
+		return errors.New("erro ao excluir serviço")
+	}
+	return nil
+}
+
+// validarDados aplica as validações comuns a criação e atualização
+func (s *ServicoServiceImpl) validarDados(servico *models.Servico) error {
+	servico.Nome = strings.Join(strings.Fields(servico.Nome), " ")
+	if servico.Nome == "" {
+		return errors.New("nome do serviço é obrigatório")
+	}
+
+	if servico.HorasPadrao <= 0 {
+		return errors.New("as horas padrão devem ser maiores que zero")
+	}
+
+	if servico.ValorHora < 0 {
+		return errors.New("o valor da hora não pode ser negativo")
+	}
+
+	if existente, err := s.servicoRepo.FindByNome(servico.Nome); err == nil && existente.ID != servico.ID {
+		return fmt.Errorf("já existe um serviço com o nome %s", existente.Nome)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+
+	"OficinaMecanica/models"
+)
+
+// novoServico cadastra um serviço no catálogo pelo serviço
+func (a *ambienteTeste) novoServico(t *testing.T, nome string, horas, valorHora float64) *models.Servico {
+	t.Helper()
+	servico, err := a.servico.Criar(&models.Servico{Nome: nome, Categoria: "mecanica", HorasPadrao: horas, ValorHora: valorHora})
+	if err != nil {
+		t.Fatalf("erro ao cadastrar o serviço %s: %v", nome, err)
+	}
+	return servico
+}
+
+// valorServicoDaOS relê o valor de mão de obra gravado na OS
+func (a *ambienteTeste) valorServicoDaOS(t *testing.T, osID uint) float64 {
+	t.Helper()
+	os, err := a.os.BuscarPorID(osID)
+	if err != nil {
+		t.Fatalf("erro ao buscar a OS: %v", err)
+	}
+	return os.ValorServico
+}
+
+func TestCatalogoDeServicosValidaOsDados(t *testing.T) {
+	a := novoAmbiente(t)
+	a.novoServico(t, "Alinhamento", 1.5, 80)
+	a.novoServico(t, "Lavagem", 1, 30)
+	if _, err := a.servico.Criar(&models.Servico{Nome: "Troca de óleo", Categoria: "lubrificacao", HorasPadrao: 0.5, ValorHora: 60}); err != nil {
+		t.Fatalf("erro ao cadastrar: %v", err)
+	}
+
+	invalidos := map[string]models.Servico{
+		"nome repetido com outros espaços": {Nome: "  Alinhamento ", HorasPadrao: 1, ValorHora: 80},
+		"sem nome":                         {Nome: " ", HorasPadrao: 1},
+		"sem horas padrão":                 {Nome: "Balanceamento", HorasPadrao: 0, ValorHora: 80},
+		"valor da hora negativo":           {Nome: "Balanceamento", HorasPadrao: 1, ValorHora: -1},
+	}
+	for nome, servico := range invalidos {
+		servico := servico
+		if _, err := a.servico.Criar(&servico); err == nil {
+			t.Errorf("%s: o serviço deveria ser recusado", nome)
+		}
+	}
+
+	lubrificacao, err := a.servico.BuscarTodos("lubrificacao")
+	if err != nil || len(lubrificacao) != 1 || lubrificacao[0].Nome != "Troca de óleo" {
+		t.Errorf("a busca por categoria trouxe %d serviços (err=%v), esperado só a troca de óleo", len(lubrificacao), err)
+	}
+}
+
+func TestLinhasDeServicoDefinemOValorDeMaoDeObra(t *testing.T) {
+	a := novoAmbiente(t)
+	alinhamento := a.novoServico(t, "Alinhamento", 1.5, 80)
+	oleo := a.novoServico(t, "Troca de óleo", 0.5, 60)
+	responsavel := a.novoMecanico(t)
+	auxiliar := a.novoMecanico(t)
+	os := a.novaOS(t)
+	if _, err := a.os.AtribuirFuncionario(os.ID, responsavel.ID); err != nil {
+		t.Fatalf("erro ao atribuir o mecânico: %v", err)
+	}
+
+	// Sem horas, valor ou mecânico, a linha usa o catálogo e o responsável pela OS
+	primeira, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{ServicoID: &alinhamento.ID})
+	if err != nil {
+		t.Fatalf("erro ao lançar o serviço: %v", err)
+	}
+	if primeira.Descricao != "Alinhamento" || primeira.Horas != 1.5 || primeira.ValorTotal != 120 {
+		t.Errorf("linha = %q, %.2fh, %.2f; esperado Alinhamento, 1,5h, 120,00", primeira.Descricao, primeira.Horas, primeira.ValorTotal)
+	}
+	if primeira.FuncionarioID == nil || *primeira.FuncionarioID != responsavel.ID {
+		t.Errorf("mecânico da linha = %v, esperado o responsável %d", primeira.FuncionarioID, responsavel.ID)
+	}
+
+	segunda, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{ServicoID: &oleo.ID, Horas: 1, FuncionarioID: &auxiliar.ID})
+	if err != nil {
+		t.Fatalf("erro ao lançar o serviço: %v", err)
+	}
+	if segunda.ValorTotal != 60 || *segunda.FuncionarioID != auxiliar.ID {
+		t.Errorf("linha = %.2f com o mecânico %d, esperado 60,00 com o auxiliar", segunda.ValorTotal, *segunda.FuncionarioID)
+	}
+	if valor := a.valorServicoDaOS(t, os.ID); valor != 180 {
+		t.Errorf("valor de serviços = %.2f, esperado 180", valor)
+	}
+
+	// O valor de mão de obra não é digitado na OS
+	atual, _ := a.os.BuscarPorID(os.ID)
+	atual.ValorServico = 999
+	if _, err := a.os.Atualizar(atual, nil); err != nil {
+		t.Fatalf("erro ao atualizar a OS: %v", err)
+	}
+	if valor := a.valorServicoDaOS(t, os.ID); valor != 180 {
+		t.Errorf("valor de serviços = %.2f depois de digitado na OS, esperado 180", valor)
+	}
+
+	primeira.Horas = 2
+	if _, err := a.os.AtualizarServico(os.ID, primeira); err != nil {
+		t.Fatalf("erro ao atualizar a linha: %v", err)
+	}
+
+	// Mudar o preço do catálogo não altera o que já foi lançado
+	alinhamento.ValorHora = 100
+	if _, err := a.servico.Atualizar(alinhamento); err != nil {
+		t.Fatalf("erro ao atualizar o catálogo: %v", err)
+	}
+	if valor := a.valorServicoDaOS(t, os.ID); valor != 220 {
+		t.Errorf("valor de serviços = %.2f, esperado 220 (2h x 80,00 + 60,00)", valor)
+	}
+
+	if err := a.os.RemoverServico(os.ID, segunda.ID); err != nil {
+		t.Fatalf("erro ao remover a linha: %v", err)
+	}
+	if valor := a.valorServicoDaOS(t, os.ID); valor != 160 {
+		t.Errorf("valor de serviços = %.2f, esperado 160", valor)
+	}
+
+	if _, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{Horas: 1, ValorHora: 50}); err == nil {
+		t.Error("a linha avulsa sem descrição deveria ser recusada")
+	}
+	a.db.Model(oleo).UpdateColumn("ativo", false)
+	if _, err := a.os.AdicionarServico(os.ID, &models.ServicoOrdemServico{ServicoID: &oleo.ID}); err == nil {
+		t.Error("um serviço inativo não deveria ser lançado")
+	}
+}
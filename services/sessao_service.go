@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
+)
+
+// SessaoService define a interface para as sessões de login: emissão, renovação e revogação dos tokens
+type SessaoService interface {
+	Iniciar(usuario *models.Usuario, ip, userAgent string) (*models.ParTokens, error) // Abre a sessão após o login
+	Renovar(tokenRenovacao, ip, userAgent string) (*models.ParTokens, error)          // Troca o token de renovação por um novo par
+	Validar(chave string, usuarioID uint) error                                       // Confere a sessão do token de acesso
+	BuscarAtivas(usuarioID uint) ([]models.Sessao, error)
+	Encerrar(chave string) error                       // Logout da sessão atual
+	EncerrarTodas(usuarioID uint, motivo string) error // Logout de todos os dispositivos
+}
+
+// SessaoServiceImpl implementa a interface SessaoService
+type SessaoServiceImpl struct {
+	sessaoRepo     repositories.SessaoRepository
+	usuarioRepo    repositories.UsuarioRepository
+	uow            repositories.UnitOfWork
+	validadeAcesso time.Duration // Duração do token de acesso
+	validadeSessao time.Duration // Prazo para renovar; cada renovação o estende
+}
+
+// NewSessaoService cria uma nova instância do serviço de sessões
+func NewSessaoService(
+	sessaoRepo repositories.SessaoRepository,
+	usuarioRepo repositories.UsuarioRepository,
+	uow repositories.UnitOfWork,
+	validadeAcesso, validadeSessao time.Duration,
+) SessaoService {
+	return &SessaoServiceImpl{
+		sessaoRepo:     sessaoRepo,
+		usuarioRepo:    usuarioRepo,
+		uow:            uow,
+		validadeAcesso: validadeAcesso,
+		validadeSessao: validadeSessao,
+	}
+}
+
+// Iniciar abre uma sessão para o usuário já autenticado e emite o primeiro par de tokens
+func (s *SessaoServiceImpl) Iniciar(usuario *models.Usuario, ip, userAgent string) (*models.ParTokens, error) {
+	chave, err := utils.GerarTokenAleatorio(16)
+	if err != nil {
+		return nil, errors.New("erro ao gerar a sessão")
+	}
+
+	sessao := &models.Sessao{
+		Chave:     chave,
+		UsuarioID: usuario.ID,
+		IP:        limitarTexto(ip, 45),
+		UserAgent: limitarTexto(userAgent, 255),
+		ExpiraEm:  time.Now().Add(s.validadeSessao),
+	}
+
+	var renovacao string
+	err = s.uow.Executar(func(tx *gorm.DB) error {
+		sessaoRepo := s.sessaoRepo.WithTx(tx)
+		if err := sessaoRepo.Create(sessao); err != nil {
+			return errors.New("erro ao registrar a sessão: " + err.Error())
+		}
+		renovacao, err = s.emitirTokenRenovacao(sessaoRepo, sessao)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.montarPar(usuario, sessao, renovacao)
+}
+
+// Renovar troca um token de renovação válido por um novo par. O token apresentado deixa de valer;
+// se um token já usado voltar a aparecer, ele foi copiado, e a sessão inteira é encerrada
+func (s *SessaoServiceImpl) Renovar(tokenRenovacao, ip, userAgent string) (*models.ParTokens, error) {
+	tokenRenovacao = strings.TrimSpace(tokenRenovacao)
+	if tokenRenovacao == "" {
+		return nil, errors.New("token de renovação não informado")
+	}
+
+	var (
+		sessao    *models.Sessao
+		usuario   *models.Usuario
+		renovacao string
+		reuso     bool
+	)
+	err := s.uow.Executar(func(tx *gorm.DB) error {
+		sessaoRepo := s.sessaoRepo.WithTx(tx)
+
+		token, err := sessaoRepo.FindTokenByHashForUpdate(utils.HashToken(tokenRenovacao))
+		if err != nil {
+			return errors.New("token de renovação inválido")
+		}
+		sessao, err = sessaoRepo.FindByIDForUpdate(token.SessaoID)
+		if err != nil {
+			return errors.New("token de renovação inválido")
+		}
+
+		agora := time.Now()
+		if token.UsadoEm != nil {
+			// A revogação precisa ser gravada, então a transação termina sem erro e o erro é devolvido depois
+			if sessao.RevogadaEm == nil {
+				sessao.RevogadaEm = &agora
+				sessao.MotivoRevogacao = models.MotivoSessaoReusoToken
+				if err := sessaoRepo.Update(sessao); err != nil {
+					return errors.New("erro ao encerrar a sessão: " + err.Error())
+				}
+			}
+			reuso = true
+			return nil
+		}
+		if !sessao.Ativa(agora) || !agora.Before(token.ExpiraEm) {
+			return errors.New("sessão expirada ou encerrada; faça login novamente")
+		}
+
+		usuario, err = s.usuarioRepo.WithTx(tx).FindByID(sessao.UsuarioID)
+		if err != nil || !usuario.PodeAcessar() {
+			return errors.New("usuário inativo ou inexistente")
+		}
+
+		token.UsadoEm = &agora
+		if err := sessaoRepo.UpdateToken(token); err != nil {
+			return errors.New("erro ao renovar a sessão: " + err.Error())
+		}
+
+		sessao.ExpiraEm = agora.Add(s.validadeSessao)
+		if ip != "" {
+			sessao.IP = limitarTexto(ip, 45)
+		}
+		if userAgent != "" {
+			sessao.UserAgent = limitarTexto(userAgent, 255)
+		}
+		if err := sessaoRepo.Update(sessao); err != nil {
+			return errors.New("erro ao renovar a sessão: " + err.Error())
+		}
+
+		renovacao, err = s.emitirTokenRenovacao(sessaoRepo, sessao)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reuso {
+		return nil, errors.New("token de renovação já utilizado; a sessão foi encerrada por segurança")
+	}
+
+	return s.montarPar(usuario, sessao, renovacao)
+}
+
+// Validar confere se a sessão do token de acesso pertence ao usuário e continua aberta
+func (s *SessaoServiceImpl) Validar(chave string, usuarioID uint) error {
+	sessao, err := s.sessaoRepo.FindByChave(chave)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("sessão não encontrada")
+		}
+		return errors.New("erro ao consultar a sessão: " + err.Error())
+	}
+	if sessao.UsuarioID != usuarioID {
+		return errors.New("sessão não pertence ao usuário do token")
+	}
+	if !sessao.Ativa(time.Now()) {
+		return errors.New("sessão encerrada")
+	}
+	return nil
+}
+
+// BuscarAtivas lista as sessões abertas do usuário
+func (s *SessaoServiceImpl) BuscarAtivas(usuarioID uint) ([]models.Sessao, error) {
+	return s.sessaoRepo.FindAtivasPorUsuario(usuarioID)
+}
+
+// Encerrar faz o logout da sessão informada; encerrar uma sessão já encerrada não é erro
+func (s *SessaoServiceImpl) Encerrar(chave string) error {
+	sessao, err := s.sessaoRepo.FindByChave(chave)
+	if err != nil {
+		return errors.New("sessão não encontrada")
+	}
+	if sessao.RevogadaEm != nil {
+		return nil
+	}
+
+	agora := time.Now()
+	sessao.RevogadaEm = &agora
+	sessao.MotivoRevogacao = models.MotivoSessaoLogout
+	if err := s.sessaoRepo.Update(sessao); err != nil {
+		return errors.New("erro ao encerrar a sessão: " + err.Error())
+	}
+	return nil
+}
+
+// EncerrarTodas faz o logout de todas as sessões do usuário
+func (s *SessaoServiceImpl) EncerrarTodas(usuarioID uint, motivo string) error {
+	if err := s.sessaoRepo.RevogarPorUsuario(usuarioID, motivo); err != nil {
+		return errors.New("erro ao encerrar as sessões: " + err.Error())
+	}
+	return nil
+}
+
+// emitirTokenRenovacao gera o próximo token de renovação da sessão, gravando apenas o hash
+func (s *SessaoServiceImpl) emitirTokenRenovacao(sessaoRepo repositories.SessaoRepository, sessao *models.Sessao) (string, error) {
+	token, err := utils.GerarTokenAleatorio(32)
+	if err != nil {
+		return "", errors.New("erro ao gerar o token de renovação")
+	}
+
+	err = sessaoRepo.CreateToken(&models.TokenRenovacao{
+		SessaoID:  sessao.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiraEm:  sessao.ExpiraEm,
+	})
+	if err != nil {
+		return "", errors.New("erro ao registrar o token de renovação: " + err.Error())
+	}
+	return token, nil
+}
+
+// montarPar gera o token de acesso da sessão e o junta ao token de renovação
+func (s *SessaoServiceImpl) montarPar(usuario *models.Usuario, sessao *models.Sessao, renovacao string) (*models.ParTokens, error) {
+	token, err := utils.GerarToken(*usuario, sessao.Chave, s.validadeAcesso)
+	if err != nil {
+		return nil, errors.New("erro ao gerar token")
+	}
+	return &models.ParTokens{
+		Token:          token,
+		ExpiraEm:       time.Now().Add(s.validadeAcesso),
+		TokenRenovacao: renovacao,
+	}, nil
+}
+
+// limitarTexto corta o texto no tamanho da coluna
+func limitarTexto(texto string, limite int) string {
+	runas := []rune(texto)
+	if len(runas) <= limite {
+		return texto
+	}
+	return string(runas[:limite])
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"OficinaMecanica/models"
+)
+
+// chaveDoToken extrai a chave da sessão do token de acesso gerado nos testes ("acesso:<usuário>:<sessão>")
+func chaveDoToken(t *testing.T, tokens *models.ParTokens) string {
+	t.Helper()
+	partes := strings.Split(tokens.Token, ":")
+	if len(partes) != 3 {
+		t.Fatalf("token de acesso inesperado: %q", tokens.Token)
+	}
+	return partes[2]
+}
+
+// entrar abre uma sessão para o usuário
+func (a *ambienteTeste) entrar(t *testing.T, usuario *models.Usuario) *models.ParTokens {
+	t.Helper()
+	tokens, err := a.sessao.Iniciar(usuario, "10.0.0.1", "Navegador")
+	if err != nil {
+		t.Fatalf("erro ao abrir a sessão: %v", err)
+	}
+	return tokens
+}
+
+func TestSessaoGravaApenasOHashDoToken(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	tokens := a.entrar(t, usuario)
+
+	if tokens.TokenRenovacao == "" || !tokens.ExpiraEm.After(time.Now()) {
+		t.Fatalf("par de tokens incompleto: %+v", tokens)
+	}
+	var gravados []models.TokenRenovacao
+	a.db.Find(&gravados)
+	if len(gravados) != 1 || gravados[0].TokenHash == tokens.TokenRenovacao || len(gravados[0].TokenHash) != 64 {
+		t.Error("o banco deveria guardar apenas o SHA-256 do token de renovação")
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, tokens), usuario.ID); err != nil {
+		t.Errorf("a sessão recém-aberta deveria ser válida: %v", err)
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, tokens), usuario.ID+1); err == nil {
+		t.Error("a sessão não deveria valer para outro usuário")
+	}
+}
+
+func TestRenovarRotacionaOToken(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	primeiro := a.entrar(t, usuario)
+
+	segundo, err := a.sessao.Renovar(primeiro.TokenRenovacao, "", "")
+	if err != nil {
+		t.Fatalf("erro ao renovar: %v", err)
+	}
+	if segundo.TokenRenovacao == primeiro.TokenRenovacao {
+		t.Error("a renovação deveria emitir um novo token de renovação")
+	}
+	if chaveDoToken(t, segundo) != chaveDoToken(t, primeiro) {
+		t.Error("a renovação deveria manter a mesma sessão")
+	}
+
+	terceiro, err := a.sessao.Renovar(segundo.TokenRenovacao, "", "")
+	if err != nil {
+		t.Fatalf("erro na segunda renovação: %v", err)
+	}
+
+	// O token já usado reaparece: alguém o copiou, então a sessão inteira cai
+	if _, err := a.sessao.Renovar(primeiro.TokenRenovacao, "", ""); err == nil {
+		t.Fatal("um token de renovação já usado deveria ser recusado")
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, terceiro), usuario.ID); err == nil {
+		t.Error("o reuso do token deveria encerrar a sessão")
+	}
+	if _, err := a.sessao.Renovar(terceiro.TokenRenovacao, "", ""); err == nil {
+		t.Error("o token mais recente da sessão encerrada também deveria ser recusado")
+	}
+
+	var sessao models.Sessao
+	a.db.First(&sessao)
+	if sessao.MotivoRevogacao != models.MotivoSessaoReusoToken {
+		t.Errorf("motivo = %q, esperado o reuso do token", sessao.MotivoRevogacao)
+	}
+}
+
+func TestRenovarRecusaSessaoExpiradaOuTokenDesconhecido(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	tokens := a.entrar(t, usuario)
+
+	if _, err := a.sessao.Renovar("", "", ""); err == nil {
+		t.Error("a renovação sem token deveria ser recusada")
+	}
+	if _, err := a.sessao.Renovar("token-inventado", "", ""); err == nil {
+		t.Error("um token desconhecido deveria ser recusado")
+	}
+
+	a.db.Model(&models.Sessao{}).Where("1 = 1").UpdateColumn("expira_em", time.Now().Add(-time.Minute))
+	if _, err := a.sessao.Renovar(tokens.TokenRenovacao, "", ""); err == nil {
+		t.Error("a sessão expirada não deveria ser renovada")
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, tokens), usuario.ID); err == nil {
+		t.Error("a sessão expirada não deveria aceitar requisições")
+	}
+}
+
+func TestLogoutEncerraApenasASessaoAtual(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	celular := a.entrar(t, usuario)
+	computador := a.entrar(t, usuario)
+
+	if err := a.sessao.Encerrar(chaveDoToken(t, celular)); err != nil {
+		t.Fatalf("erro no logout: %v", err)
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, celular), usuario.ID); err == nil {
+		t.Error("o token da sessão encerrada deveria ser recusado")
+	}
+	if _, err := a.sessao.Renovar(celular.TokenRenovacao, "", ""); err == nil {
+		t.Error("a sessão encerrada não deveria ser renovada")
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, computador), usuario.ID); err != nil {
+		t.Errorf("a outra sessão deveria continuar aberta: %v", err)
+	}
+	if err := a.sessao.Encerrar(chaveDoToken(t, celular)); err != nil {
+		t.Errorf("repetir o logout não deveria ser erro: %v", err)
+	}
+
+	ativas, _ := a.sessao.BuscarAtivas(usuario.ID)
+	if len(ativas) != 1 {
+		t.Errorf("sessões ativas = %d, esperado 1", len(ativas))
+	}
+}
+
+func TestEncerrarTodasAfetaApenasOUsuario(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	colega := a.novoUsuario(t, models.CargoAtendente)
+	sessoes := []*models.ParTokens{a.entrar(t, usuario), a.entrar(t, usuario)}
+	doColega := a.entrar(t, colega)
+
+	if err := a.sessao.EncerrarTodas(usuario.ID, models.MotivoSessaoLogoutGeral); err != nil {
+		t.Fatalf("erro ao encerrar as sessões: %v", err)
+	}
+	for _, tokens := range sessoes {
+		if err := a.sessao.Validar(chaveDoToken(t, tokens), usuario.ID); err == nil {
+			t.Error("todas as sessões do usuário deveriam ser encerradas")
+		}
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, doColega), colega.ID); err != nil {
+		t.Errorf("a sessão do colega deveria continuar aberta: %v", err)
+	}
+}
+
+func TestDesativarOuExcluirUsuarioRevogaAsSessoes(t *testing.T) {
+	a := novoAmbiente(t)
+	desligado := a.novoUsuario(t, models.CargoMecanico)
+	excluido := a.novoUsuario(t, models.CargoMecanico)
+	tokensDesligado := a.entrar(t, desligado)
+	tokensExcluido := a.entrar(t, excluido)
+
+	if err := a.usuario.AlterarStatus(desligado.ID, false); err != nil {
+		t.Fatalf("erro ao desativar: %v", err)
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, tokensDesligado), desligado.ID); err == nil {
+		t.Error("o token do usuário desativado deveria deixar de valer")
+	}
+	if _, err := a.sessao.Renovar(tokensDesligado.TokenRenovacao, "", ""); err == nil {
+		t.Error("o usuário desativado não deveria renovar a sessão")
+	}
+
+	if err := a.usuario.Deletar(excluido.ID); err != nil {
+		t.Fatalf("erro ao excluir: %v", err)
+	}
+	if err := a.sessao.Validar(chaveDoToken(t, tokensExcluido), excluido.ID); err == nil {
+		t.Error("o token do usuário excluído deveria deixar de valer")
+	}
+}
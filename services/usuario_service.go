@@ -2,16 +2,21 @@ package services
 
 import (
 	"errors"
+	"sync"
 	"time"
 
+	"gorm.io/gorm"
+
 	"OficinaMecanica/models"
 	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
 )
 
 // UsuarioService define a interface para operações relacionadas a usuários
 // Esta interface permite que possamos substituir a implementação real por mocks em testes
 type UsuarioService interface {
-	BuscarTodos() ([]models.Usuario, error)                          // Retorna todos os usuários cadastrados
+	// Retorna uma página dos usuários cadastrados, com filtros e ordenação
+	BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Usuario], error)
 	BuscarPorID(id uint) (*models.Usuario, error)                    // Busca um usuário pelo ID
 	BuscarPorEmail(email string) (*models.Usuario, error)            // Busca um usuário pelo email
 	Criar(usuario *models.Usuario) (*models.Usuario, error)          // Cria um novo usuário
@@ -22,25 +27,54 @@ type UsuarioService interface {
 	AlterarSenha(id uint, senhaAtual, novaSenha string) error        // Altera a senha do usuário
 	AlterarStatus(id uint, ativo bool) error                         // Ativa ou desativa um usuário
 	AtualizarAvatar(id uint, avatarPath string) error                // Atualiza o avatar do usuário
+	BuscarAutenticado(id uint) (*models.Usuario, error)              // Carrega o usuário do token, recusando contas sem acesso
+	RecriptografarSenha(id uint, senha string) error                 // Refaz o hash com o custo atual após um login válido
+}
+
+// validadeCacheUsuario é por quanto tempo o usuário carregado pelo AuthMiddleware é reaproveitado.
+// Alterações feitas por este serviço invalidam a entrada na hora; as demais aparecem após o prazo
+const validadeCacheUsuario = 30 * time.Second
+
+// usuarioEmCache é uma entrada do cache de usuários autenticados
+type usuarioEmCache struct {
+	usuario  models.Usuario
+	expiraEm time.Time
 }
 
 // UsuarioServiceImpl implementa a interface UsuarioService
 // Contém a instância do repositório que será usada para operações de persistência
 type UsuarioServiceImpl struct {
 	usuarioRepo repositories.UsuarioRepository // Repositório de usuários injetado
+	sessaoRepo  repositories.SessaoRepository  // Sessões encerradas quando a conta é desativada ou excluída
+	uow         repositories.UnitOfWork
+	senhas      senhasUsuario // Política de senhas e histórico contra a reutilização
+
+	mu      sync.Mutex
+	cache   map[uint]usuarioEmCache // Usuários autenticados, para não consultar o banco a cada requisição
+	geracao uint64                  // Incrementada a cada invalidação, para descartar leituras já desatualizadas
 }
 
 // NewUsuarioService cria uma nova instância do serviço de usuários
 // Implementa o padrão de injeção de dependência
-func NewUsuarioService(usuarioRepo repositories.UsuarioRepository) UsuarioService {
+func NewUsuarioService(
+	usuarioRepo repositories.UsuarioRepository,
+	sessaoRepo repositories.SessaoRepository,
+	historicoSenhaRepo repositories.HistoricoSenhaRepository,
+	uow repositories.UnitOfWork,
+	politicaSenha *utils.PoliticaSenha,
+) UsuarioService {
 	return &UsuarioServiceImpl{
 		usuarioRepo: usuarioRepo,
+		sessaoRepo:  sessaoRepo,
+		uow:         uow,
+		senhas:      senhasUsuario{politica: politicaSenha, historicoRepo: historicoSenhaRepo},
+		cache:       make(map[uint]usuarioEmCache),
 	}
 }
 
-// BuscarTodos retorna todos os usuários cadastrados no sistema
-func (s *UsuarioServiceImpl) BuscarTodos() ([]models.Usuario, error) {
-	return s.usuarioRepo.FindAll()
+// BuscarTodos retorna uma página dos usuários cadastrados no sistema
+func (s *UsuarioServiceImpl) BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Usuario], error) {
+	return s.usuarioRepo.FindPaginado(consulta)
 }
 
 // BuscarPorID busca um usuário pelo seu ID
@@ -90,12 +124,24 @@ func (s *UsuarioServiceImpl) Criar(usuario *models.Usuario) (*models.Usuario, er
 		return nil, errors.New("já existe um usuário com este email")
 	}
 
-	// Nota: a senha será criptografada automaticamente pelo hook BeforeCreate do modelo
+	if err := s.senhas.validar(s.senhas.historicoRepo, usuario, usuario.Senha); err != nil {
+		return nil, err
+	}
+
+	// A senha validada é gravada apenas como hash
+	if err := usuario.DefinirSenha(usuario.Senha); err != nil {
+		return nil, errors.New("erro ao criptografar a senha")
+	}
 
-	// Persiste o novo usuário
-	err = s.usuarioRepo.Create(usuario)
+	// Persiste o novo usuário junto com a primeira senha do histórico
+	err = s.uow.Executar(func(tx *gorm.DB) error {
+		if err := s.usuarioRepo.WithTx(tx).Create(usuario); err != nil {
+			return errors.New("erro ao criar usuário")
+		}
+		return s.senhas.registrar(s.senhas.historicoRepo.WithTx(tx), usuario)
+	})
 	if err != nil {
-		return nil, errors.New("erro ao criar usuário")
+		return nil, err
 	}
 
 	return usuario, nil
@@ -130,6 +176,7 @@ func (s *UsuarioServiceImpl) Atualizar(usuario *models.Usuario) (*models.Usuario
 	if err != nil {
 		return nil, errors.New("erro ao atualizar usuário")
 	}
+	s.invalidarCache(usuario.ID)
 
 	return usuario, nil
 }
@@ -148,6 +195,12 @@ func (s *UsuarioServiceImpl) Deletar(id uint) error {
 	if err != nil {
 		return errors.New("erro ao deletar usuário")
 	}
+	s.invalidarCache(id)
+
+	// Os tokens já emitidos deixam de valer na próxima requisição
+	if err := s.sessaoRepo.RevogarPorUsuario(id, models.MotivoSessaoUsuarioInativo); err != nil {
+		return errors.New("usuário excluído, mas houve erro ao encerrar as sessões")
+	}
 
 	return nil
 }
@@ -209,15 +262,41 @@ func (s *UsuarioServiceImpl) AlterarSenha(id uint, senhaAtual, novaSenha string)
 		return errors.New("senha atual incorreta")
 	}
 
-	// Define a nova senha - será criptografada pelo hook BeforeUpdate do modelo
-	usuario.Senha = novaSenha
+	if err := s.senhas.validar(s.senhas.historicoRepo, usuario, novaSenha); err != nil {
+		return err
+	}
 
-	// Persiste a alteração
-	err = s.usuarioRepo.Update(usuario)
+	// Define a nova senha, gravada apenas como hash
+	if err := usuario.DefinirSenha(novaSenha); err != nil {
+		return errors.New("erro ao criptografar a senha")
+	}
+
+	// Persiste a alteração e guarda o hash no histórico
+	return s.uow.Executar(func(tx *gorm.DB) error {
+		if err := s.usuarioRepo.WithTx(tx).Update(usuario); err != nil {
+			return errors.New("erro ao atualizar senha")
+		}
+		return s.senhas.registrar(s.senhas.historicoRepo.WithTx(tx), usuario)
+	})
+}
+
+// RecriptografarSenha refaz o hash da senha com o custo bcrypt configurado, quando o atual for menor.
+// Só o login tem a senha em texto para isso; a senha continua a mesma e o histórico não muda
+func (s *UsuarioServiceImpl) RecriptografarSenha(id uint, senha string) error {
+	usuario, err := s.usuarioRepo.FindByID(id)
 	if err != nil {
-		return errors.New("erro ao atualizar senha")
+		return errors.New("usuário não encontrado")
+	}
+	if !usuario.PrecisaNovoHash() || !usuario.CompareSenha(senha) {
+		return nil
 	}
 
+	if err := usuario.DefinirSenha(senha); err != nil {
+		return errors.New("erro ao criptografar a senha")
+	}
+	if err := s.usuarioRepo.Update(usuario); err != nil {
+		return errors.New("erro ao atualizar o hash da senha")
+	}
 	return nil
 }
 
@@ -237,6 +316,14 @@ func (s *UsuarioServiceImpl) AlterarStatus(id uint, ativo bool) error {
 	if err != nil {
 		return errors.New("erro ao alterar status do usuário")
 	}
+	s.invalidarCache(id)
+
+	// Desativar encerra as sessões abertas; os tokens já emitidos deixam de valer na próxima requisição
+	if !ativo {
+		if err := s.sessaoRepo.RevogarPorUsuario(id, models.MotivoSessaoUsuarioInativo); err != nil {
+			return errors.New("usuário desativado, mas houve erro ao encerrar as sessões")
+		}
+	}
 
 	return nil
 }
@@ -249,5 +336,51 @@ func (s *UsuarioServiceImpl) AtualizarAvatar(id uint, avatarPath string) error {
 		return err
 	}
 	usuario.Avatar = avatarPath
-	return s.usuarioRepo.Update(usuario)
+	if err := s.usuarioRepo.Update(usuario); err != nil {
+		return err
+	}
+	s.invalidarCache(id)
+	return nil
+}
+
+// BuscarAutenticado carrega o usuário identificado no token de acesso (sem o hash da senha), reaproveitando
+// por alguns segundos a última leitura. Contas excluídas, desativadas ou em situação que bloqueia o acesso são recusadas
+func (s *UsuarioServiceImpl) BuscarAutenticado(id uint) (*models.Usuario, error) {
+	agora := time.Now()
+
+	s.mu.Lock()
+	entrada, ok := s.cache[id]
+	geracao := s.geracao
+	s.mu.Unlock()
+
+	if !ok || !agora.Before(entrada.expiraEm) {
+		usuario, err := s.usuarioRepo.FindByID(id)
+		if err != nil {
+			return nil, errors.New("usuário não encontrado")
+		}
+		usuario.Senha = ""
+		entrada = usuarioEmCache{usuario: *usuario, expiraEm: agora.Add(validadeCacheUsuario)}
+
+		// Uma invalidação durante a leitura indica que ela pode estar desatualizada: vale só para esta requisição
+		s.mu.Lock()
+		if s.geracao == geracao {
+			s.cache[id] = entrada
+		}
+		s.mu.Unlock()
+	}
+
+	if !entrada.usuario.PodeAcessar() {
+		return nil, errors.New("usuário inativo")
+	}
+	// Cópia, para que alterações feitas pela requisição não cheguem ao cache
+	usuario := entrada.usuario
+	return &usuario, nil
+}
+
+// invalidarCache descarta o usuário do cache de autenticação, para que a próxima requisição releia o banco
+func (s *UsuarioServiceImpl) invalidarCache(id uint) {
+	s.mu.Lock()
+	delete(s.cache, id)
+	s.geracao++
+	s.mu.Unlock()
 }
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"OficinaMecanica/models"
+	"OficinaMecanica/utils"
+)
+
+func TestBuscarAutenticadoRecusaContaSemAcesso(t *testing.T) {
+	a := novoAmbiente(t)
+	ativo := a.novoUsuario(t, models.CargoAtendente)
+	desligado := a.novoUsuario(t, models.CargoMecanico)
+	a.db.Model(desligado).Update("status", "Desligado")
+	excluido := a.novoUsuario(t, models.CargoMecanico)
+	a.db.Delete(excluido)
+
+	usuario, err := a.usuario.BuscarAutenticado(ativo.ID)
+	if err != nil {
+		t.Fatalf("o usuário ativo deveria ser carregado: %v", err)
+	}
+	if usuario.ID != ativo.ID || usuario.Senha != "" {
+		t.Error("o usuário autenticado deveria vir sem o hash da senha")
+	}
+
+	if _, err := a.usuario.BuscarAutenticado(desligado.ID); err == nil {
+		t.Error("o usuário desligado não deveria ser aceito")
+	}
+	if _, err := a.usuario.BuscarAutenticado(excluido.ID); err == nil {
+		t.Error("o usuário excluído não deveria ser aceito")
+	}
+	if _, err := a.usuario.BuscarAutenticado(9999); err == nil {
+		t.Error("um usuário inexistente não deveria ser aceito")
+	}
+}
+
+func TestDesativarUsuarioInvalidaOCache(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	if _, err := a.usuario.BuscarAutenticado(usuario.ID); err != nil {
+		t.Fatalf("erro ao carregar o usuário: %v", err)
+	}
+	if err := a.usuario.AlterarStatus(usuario.ID, false); err != nil {
+		t.Fatalf("erro ao desativar: %v", err)
+	}
+	// A leitura anterior está em cache, mas a desativação precisa valer na requisição seguinte
+	if _, err := a.usuario.BuscarAutenticado(usuario.ID); err == nil {
+		t.Error("o usuário desativado não deveria continuar aceito pelo cache")
+	}
+
+	if err := a.usuario.AlterarStatus(usuario.ID, true); err != nil {
+		t.Fatalf("erro ao reativar: %v", err)
+	}
+	if _, err := a.usuario.BuscarAutenticado(usuario.ID); err != nil {
+		t.Errorf("o usuário reativado deveria voltar a ser aceito: %v", err)
+	}
+}
+
+func TestSessaoNaoRenovaParaUsuarioDesligado(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	tokens := a.entrar(t, usuario)
+
+	a.db.Model(usuario).Update("status", models.StatusUsuarioDesligado)
+	if _, err := a.sessao.Renovar(tokens.TokenRenovacao, "", ""); err == nil {
+		t.Error("a sessão de um usuário desligado não deveria ser renovada")
+	}
+}
+
+func TestAlterarSenhaGravaOHash(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	if err := a.usuario.AlterarSenha(usuario.ID, "errada", "novaSenha123"); err == nil {
+		t.Error("a troca deveria exigir a senha atual correta")
+	}
+	if err := a.usuario.AlterarSenha(usuario.ID, "senha123", "novaSenha123"); err != nil {
+		t.Fatalf("erro ao alterar a senha: %v", err)
+	}
+	if !a.senhaAtual(t, usuario.ID, "novaSenha123") {
+		t.Error("a nova senha deveria ser gravada com hash")
+	}
+
+	// Salvar o usuário sem mexer na senha não criptografa o hash de novo
+	if err := a.usuario.AlterarStatus(usuario.ID, true); err != nil {
+		t.Fatalf("erro ao salvar o usuário: %v", err)
+	}
+	if !a.senhaAtual(t, usuario.ID, "novaSenha123") {
+		t.Error("a senha não deveria mudar ao salvar outros campos")
+	}
+}
+
+// senhasNoHistorico conta os hashes guardados contra a reutilização
+func (a *ambienteTeste) senhasNoHistorico(t *testing.T, usuarioID uint) int64 {
+	t.Helper()
+	var total int64
+	if err := a.db.Model(&models.HistoricoSenha{}).Where("usuario_id = ?", usuarioID).Count(&total).Error; err != nil {
+		t.Fatalf("erro ao contar o histórico de senhas: %v", err)
+	}
+	return total
+}
+
+func TestCriarAplicaAPoliticaDeSenha(t *testing.T) {
+	a := novoAmbiente(t)
+
+	for _, senha := range []string{"curta1", "somenteletras", "Senha1234", "Carla2024x"} {
+		_, err := a.usuario.Criar(&models.Usuario{Nome: "Carla", Email: "carla@oficina.com", Senha: senha})
+		var erroSenha *utils.ErroPoliticaSenha
+		if !errors.As(err, &erroSenha) {
+			t.Errorf("a senha %q deveria ser recusada pela política (erro %v)", senha, err)
+		}
+	}
+
+	usuario, err := a.usuario.Criar(&models.Usuario{Nome: "Carla", Email: "carla@oficina.com", Senha: "Motor2024x"})
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	if a.senhasNoHistorico(t, usuario.ID) != 1 {
+		t.Error("a senha do cadastro deveria entrar no histórico")
+	}
+}
+
+func TestAlterarSenhaRecusaReutilizacao(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	if err := a.usuario.AlterarSenha(usuario.ID, "senha123", "fraca"); err == nil {
+		t.Error("a nova senha deveria passar pela política")
+	}
+	// A senha atual vale mesmo sem histórico, como nas contas anteriores à política
+	if err := a.usuario.AlterarSenha(usuario.ID, "senha123", "senha123"); err == nil {
+		t.Error("a senha atual não deveria ser aceita como nova")
+	}
+
+	atual := "senha123"
+	for _, nova := range []string{"novaSenha123", "outraSenha456", "terceira789A", "quarta789AB"} {
+		if err := a.usuario.AlterarSenha(usuario.ID, atual, nova); err != nil {
+			t.Fatalf("erro ao trocar para %q: %v", nova, err)
+		}
+		atual = nova
+	}
+	if total := a.senhasNoHistorico(t, usuario.ID); total != 3 {
+		t.Errorf("histórico com %d senhas, esperado as 3 mais recentes", total)
+	}
+
+	if err := a.usuario.AlterarSenha(usuario.ID, atual, "outraSenha456"); err == nil {
+		t.Error("uma das últimas senhas não deveria ser reutilizada")
+	}
+	if err := a.usuario.AlterarSenha(usuario.ID, atual, "novaSenha123"); err != nil {
+		t.Errorf("a senha que saiu do histórico deveria voltar a ser aceita: %v", err)
+	}
+}
+
+func TestRecriptografarSenhaComCustoMaior(t *testing.T) {
+	custoOriginal := models.CustoSenha
+	defer func() { models.CustoSenha = custoOriginal }()
+
+	models.CustoSenha = bcrypt.MinCost
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+	if usuario.PrecisaNovoHash() {
+		t.Fatal("o hash recém-gerado não deveria precisar ser refeito")
+	}
+
+	models.CustoSenha = bcrypt.MinCost + 1
+	custo := func() int {
+		var gravado models.Usuario
+		a.db.First(&gravado, usuario.ID)
+		valor, _ := bcrypt.Cost([]byte(gravado.Senha))
+		return valor
+	}
+
+	if err := a.usuario.RecriptografarSenha(usuario.ID, "errada"); err != nil || custo() != bcrypt.MinCost {
+		t.Errorf("uma senha errada não deveria refazer o hash (erro %v)", err)
+	}
+	if err := a.usuario.RecriptografarSenha(usuario.ID, "senha123"); err != nil {
+		t.Fatalf("erro ao recriptografar: %v", err)
+	}
+	if custo() != bcrypt.MinCost+1 || !a.senhaAtual(t, usuario.ID, "senha123") {
+		t.Error("o hash deveria ser refeito com o custo novo, mantendo a senha")
+	}
+}
+
+func TestHashEnviadoComoSenhaNaoEGravadoDireto(t *testing.T) {
+	a := novoAmbiente(t)
+	usuario := a.novoUsuario(t, models.CargoAtendente)
+
+	// O hash de uma senha fraca passaria pela política se fosse gravado como está
+	hash, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	if err := a.usuario.AlterarSenha(usuario.ID, "senha123", string(hash)); err != nil {
+		t.Fatalf("erro ao alterar a senha: %v", err)
+	}
+	if a.senhaAtual(t, usuario.ID, "123456") || !a.senhaAtual(t, usuario.ID, string(hash)) {
+		t.Error("o valor enviado deveria ser tratado como senha em texto e criptografado")
+	}
+
+	criado, err := a.usuario.Criar(&models.Usuario{Nome: "Carla", Email: "carla@oficina.com", Senha: string(hash)})
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	if a.senhaAtual(t, criado.ID, "123456") {
+		t.Error("o cadastro não deveria aceitar um hash pronto como senha")
+	}
+}
@@ -5,10 +5,11 @@ import (
 
 	"OficinaMecanica/models"
 	"OficinaMecanica/repositories"
+	"OficinaMecanica/utils"
 )
 
 type VeiculoService interface {
-	BuscarTodos() ([]models.Veiculo, error)
+	BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Veiculo], error)
 	BuscarPorID(id uint) (*models.Veiculo, error)
 	Criar(veiculo *models.Veiculo) (*models.Veiculo, error)
 	Atualizar(veiculo *models.Veiculo) (*models.Veiculo, error)
@@ -22,14 +23,15 @@ type VeiculoServiceImpl struct {
 	clienteRepo repositories.ClienteRepositoryGorm
 }
 
-func NewVeiculoService(veiculoRepo repositories.VeiculoRepository) VeiculoService {
+func NewVeiculoService(veiculoRepo repositories.VeiculoRepository, clienteRepo repositories.ClienteRepositoryGorm) VeiculoService {
 	return &VeiculoServiceImpl{
 		veiculoRepo: veiculoRepo,
+		clienteRepo: clienteRepo,
 	}
 }
 
-func (s *VeiculoServiceImpl) BuscarTodos() ([]models.Veiculo, error) {
-	return s.veiculoRepo.FindAll()
+func (s *VeiculoServiceImpl) BuscarTodos(consulta repositories.Consulta) (*models.Pagina[models.Veiculo], error) {
+	return s.veiculoRepo.FindPaginado(consulta)
 }
 
 func (s *VeiculoServiceImpl) BuscarPorID(id uint) (*models.Veiculo, error) {
@@ -42,16 +44,24 @@ func (s *VeiculoServiceImpl) BuscarPorID(id uint) (*models.Veiculo, error) {
 
 func (s *VeiculoServiceImpl) Criar(veiculo *models.Veiculo) (*models.Veiculo, error) {
 	// Validações antes de criar o veículo
-	if veiculo.Placa == "" {
-		return nil, errors.New("placa do veículo é obrigatória")
+	if err := s.normalizarIdentificacao(veiculo); err != nil {
+		return nil, err
 	}
 
-	// Verificar se já existe veículo com a mesma placa
-	existente, err := s.veiculoRepo.FindByPlaca(veiculo.Placa)
+	// Verificar se já existe veículo com a mesma placa, em qualquer um dos padrões
+	existente, err := s.buscarPorPlacaEquivalente(veiculo.Placa)
 	if err == nil && existente != nil {
 		return nil, errors.New("já existe um veículo com esta placa")
 	}
 
+	// Verificar se já existe veículo com o mesmo chassi
+	if veiculo.Chassi != nil {
+		existente, err := s.veiculoRepo.FindByChassi(*veiculo.Chassi)
+		if err == nil && existente != nil {
+			return nil, errors.New("já existe um veículo com este chassi")
+		}
+	}
+
 	// Verificar se o cliente existe
 	if veiculo.ClienteID > 0 {
 		_, err := s.clienteRepo.FindByID(veiculo.ClienteID)
@@ -70,22 +80,33 @@ func (s *VeiculoServiceImpl) Criar(veiculo *models.Veiculo) (*models.Veiculo, er
 
 func (s *VeiculoServiceImpl) Atualizar(veiculo *models.Veiculo) (*models.Veiculo, error) {
 	// Verificar se o veículo existe
-	_, err := s.veiculoRepo.FindByID(veiculo.ID)
+	atual, err := s.veiculoRepo.FindByID(veiculo.ID)
 	if err != nil {
 		return nil, errors.New("veículo não encontrado")
 	}
 
+	// A quilometragem só muda pelas leituras de km
+	veiculo.KmAtual = atual.KmAtual
+
 	// Validações
-	if veiculo.Placa == "" {
-		return nil, errors.New("placa do veículo é obrigatória")
+	if err := s.normalizarIdentificacao(veiculo); err != nil {
+		return nil, err
 	}
 
-	// Verificar se existe outro veículo com a mesma placa
-	existente, err := s.veiculoRepo.FindByPlaca(veiculo.Placa)
+	// Verificar se existe outro veículo com a mesma placa, em qualquer um dos padrões
+	existente, err := s.buscarPorPlacaEquivalente(veiculo.Placa)
 	if err == nil && existente != nil && existente.ID != veiculo.ID {
 		return nil, errors.New("já existe outro veículo com esta placa")
 	}
 
+	// Verificar se existe outro veículo com o mesmo chassi
+	if veiculo.Chassi != nil {
+		existente, err := s.veiculoRepo.FindByChassi(*veiculo.Chassi)
+		if err == nil && existente != nil && existente.ID != veiculo.ID {
+			return nil, errors.New("já existe outro veículo com este chassi")
+		}
+	}
+
 	// Verificar se o cliente existe
 	if veiculo.ClienteID > 0 {
 		_, err := s.clienteRepo.FindByID(veiculo.ClienteID)
@@ -118,11 +139,12 @@ func (s *VeiculoServiceImpl) Deletar(id uint) error {
 }
 
 func (s *VeiculoServiceImpl) BuscarPorPlaca(placa string) (*models.Veiculo, error) {
+	placa = utils.NormalizarPlaca(placa)
 	if placa == "" {
 		return nil, errors.New("placa não pode ser vazia")
 	}
 
-	veiculo, err := s.veiculoRepo.FindByPlaca(placa)
+	veiculo, err := s.buscarPorPlacaEquivalente(placa)
 	if err != nil {
 		return nil, errors.New("veículo não encontrado")
 	}
@@ -148,3 +170,41 @@ func (s *VeiculoServiceImpl) BuscarPorClienteID(clienteID uint) ([]models.Veicul
 
 	return veiculos, nil
 }
+
+// normalizarIdentificacao valida e grava a placa e o chassi no formato normalizado,
+// para que "abc-1234" e "ABC1234" sejam reconhecidos como o mesmo veículo
+func (s *VeiculoServiceImpl) normalizarIdentificacao(veiculo *models.Veiculo) error {
+	placa, err := utils.ValidarPlaca(veiculo.Placa)
+	if err != nil {
+		return err
+	}
+	veiculo.Placa = placa
+
+	if veiculo.Chassi != nil {
+		if utils.NormalizarChassi(*veiculo.Chassi) == "" {
+			veiculo.Chassi = nil
+			return nil
+		}
+		chassi, err := utils.ValidarChassi(*veiculo.Chassi)
+		if err != nil {
+			return err
+		}
+		veiculo.Chassi = &chassi
+	}
+	return nil
+}
+
+// buscarPorPlacaEquivalente busca o veículo pela placa normalizada e, se não encontrar,
+// pela grafia equivalente no outro padrão (ABC1234 e ABC1C34 são o mesmo veículo)
+func (s *VeiculoServiceImpl) buscarPorPlacaEquivalente(placa string) (*models.Veiculo, error) {
+	veiculo, err := s.veiculoRepo.FindByPlaca(placa)
+	if err == nil {
+		return veiculo, nil
+	}
+
+	equivalente := utils.PlacaEquivalente(placa)
+	if equivalente == "" {
+		return nil, err
+	}
+	return s.veiculoRepo.FindByPlaca(equivalente)
+}
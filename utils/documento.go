@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidarCPF verifica os dígitos verificadores do CPF e retorna-o no formato 000.000.000-00
+func ValidarCPF(cpf string) (string, error) {
+	digitos := make([]int, 0, 11)
+	for _, r := range cpf {
+		if r >= '0' && r <= '9' {
+			digitos = append(digitos, int(r-'0'))
+		}
+	}
+
+	if len(digitos) != 11 {
+		return "", errors.New("CPF deve conter 11 dígitos")
+	}
+
+	// Sequências repetidas (ex.: 111.111.111-11) passam no cálculo, mas são inválidas
+	repetido := true
+	for _, d := range digitos[1:] {
+		if d != digitos[0] {
+			repetido = false
+			break
+		}
+	}
+	if repetido {
+		return "", errors.New("CPF inválido")
+	}
+
+	for posicao := 9; posicao <= 10; posicao++ {
+		soma := 0
+		for i := 0; i < posicao; i++ {
+			soma += digitos[i] * (posicao + 1 - i)
+		}
+		verificador := (soma * 10) % 11
+		if verificador == 10 {
+			verificador = 0
+		}
+		if verificador != digitos[posicao] {
+			return "", errors.New("CPF inválido")
+		}
+	}
+
+	return fmt.Sprintf("%d%d%d.%d%d%d.%d%d%d-%d%d",
+		digitos[0], digitos[1], digitos[2], digitos[3], digitos[4], digitos[5],
+		digitos[6], digitos[7], digitos[8], digitos[9], digitos[10]), nil
+}
+
+// ValidarCNPJ verifica os dígitos verificadores do CNPJ e retorna-o no formato 00.000.000/0000-00
+func ValidarCNPJ(cnpj string) (string, error) {
+	digitos := make([]int, 0, 14)
+	for _, r := range cnpj {
+		if r >= '0' && r <= '9' {
+			digitos = append(digitos, int(r-'0'))
+		}
+	}
+
+	if len(digitos) != 14 {
+		return "", errors.New("CNPJ deve conter 14 dígitos")
+	}
+
+	// Sequências repetidas (ex.: 11.111.111/1111-11) passam no cálculo, mas são inválidas
+	repetido := true
+	for _, d := range digitos[1:] {
+		if d != digitos[0] {
+			repetido = false
+			break
+		}
+	}
+	if repetido {
+		return "", errors.New("CNPJ inválido")
+	}
+
+	pesos := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for posicao := 12; posicao <= 13; posicao++ {
+		soma := 0
+		for i := 0; i < posicao; i++ {
+			soma += digitos[i] * pesos[len(pesos)-posicao+i]
+		}
+		verificador := soma % 11
+		if verificador < 2 {
+			verificador = 0
+		} else {
+			verificador = 11 - verificador
+		}
+		if verificador != digitos[posicao] {
+			return "", errors.New("CNPJ inválido")
+		}
+	}
+
+	return fmt.Sprintf("%d%d.%d%d%d.%d%d%d/%d%d%d%d-%d%d",
+		digitos[0], digitos[1], digitos[2], digitos[3], digitos[4], digitos[5], digitos[6],
+		digitos[7], digitos[8], digitos[9], digitos[10], digitos[11], digitos[12], digitos[13]), nil
+}
+
+// ApenasDigitos remove pontos, traços, barras e qualquer outro caractere que não seja número
+func ApenasDigitos(valor string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, valor)
+}
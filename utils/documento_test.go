@@ -0,0 +1,69 @@
+package utils
+
+import "testing"
+
+func TestValidarCPF(t *testing.T) {
+	casos := []struct {
+		nome      string
+		cpf       string
+		formatado string
+		valido    bool
+	}{
+		{"apenas dígitos", "52998224725", "529.982.247-25", true},
+		{"já formatado", "111.444.777-35", "111.444.777-35", true},
+		{"com espaços e traços", " 111 444 777 35 ", "111.444.777-35", true},
+		{"primeiro dígito verificador errado", "529.982.247-35", "", false},
+		{"segundo dígito verificador errado", "529.982.247-26", "", false},
+		{"sequência repetida", "111.111.111-11", "", false},
+		{"dígitos a menos", "5299822472", "", false},
+		{"dígitos a mais", "529982247250", "", false},
+		{"vazio", "", "", false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			formatado, err := ValidarCPF(caso.cpf)
+			if (err == nil) != caso.valido {
+				t.Fatalf("ValidarCPF(%q) erro = %v, válido esperado = %v", caso.cpf, err, caso.valido)
+			}
+			if formatado != caso.formatado {
+				t.Errorf("ValidarCPF(%q) = %q, esperado %q", caso.cpf, formatado, caso.formatado)
+			}
+		})
+	}
+}
+
+func TestValidarCNPJ(t *testing.T) {
+	casos := []struct {
+		nome      string
+		cnpj      string
+		formatado string
+		valido    bool
+	}{
+		{"apenas dígitos", "11222333000181", "11.222.333/0001-81", true},
+		{"já formatado", "11.444.777/0001-61", "11.444.777/0001-61", true},
+		{"primeiro dígito verificador errado", "11.222.333/0001-91", "", false},
+		{"segundo dígito verificador errado", "11.222.333/0001-82", "", false},
+		{"sequência repetida", "11.111.111/1111-11", "", false},
+		{"CPF no lugar do CNPJ", "529.982.247-25", "", false},
+		{"vazio", "", "", false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			formatado, err := ValidarCNPJ(caso.cnpj)
+			if (err == nil) != caso.valido {
+				t.Fatalf("ValidarCNPJ(%q) erro = %v, válido esperado = %v", caso.cnpj, err, caso.valido)
+			}
+			if formatado != caso.formatado {
+				t.Errorf("ValidarCNPJ(%q) = %q, esperado %q", caso.cnpj, formatado, caso.formatado)
+			}
+		})
+	}
+}
+
+func TestApenasDigitos(t *testing.T) {
+	if got := ApenasDigitos("11.222.333/0001-81"); got != "11222333000181" {
+		t.Errorf("ApenasDigitos = %q", got)
+	}
+}
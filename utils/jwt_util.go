@@ -1,6 +1,11 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -12,13 +17,13 @@ import (
 // Variável que pode ser substituída em testes
 var GerarTokenFn = gerarTokenImpl
 
-// GerarToken gera um token JWT para um usuário
-func GerarToken(usuario models.Usuario) (string, error) {
-	return GerarTokenFn(usuario)
+// GerarToken gera o token de acesso JWT de um usuário para a sessão informada
+func GerarToken(usuario models.Usuario, chaveSessao string, validade time.Duration) (string, error) {
+	return GerarTokenFn(usuario, chaveSessao, validade)
 }
 
 // Implementação real da geração de token
-func gerarTokenImpl(usuario models.Usuario) (string, error) {
+func gerarTokenImpl(usuario models.Usuario, chaveSessao string, validade time.Duration) (string, error) {
 	config, err := configs.LoadConfig()
 	if err != nil {
 		return "", err
@@ -28,7 +33,8 @@ func gerarTokenImpl(usuario models.Usuario) (string, error) {
 		"user_id":         usuario.ID,
 		"nome de usuário": usuario.Nome,
 		"cargo":           usuario.Cargo,
-		"exp":             time.Now().Add(time.Hour * 24).Unix(), // Expira em 24 horas
+		"sid":             chaveSessao, // Sessão conferida a cada requisição; encerrá-la invalida o token
+		"exp":             time.Now().Add(validade).Unix(),
 		"issued_at":       time.Now().Unix(),
 	}
 
@@ -44,6 +50,10 @@ func ValidarToken(tokenString string) (*jwt.Token, error) {
 	}
 
 	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Sem esta verificação um token com "alg" trocado seria validado com a chave errada
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("método de assinatura inesperado")
+		}
 		return []byte(config.JWTSecret), nil
 	})
 }
@@ -60,4 +70,31 @@ func ExtrairUserID(token *jwt.Token) (int, bool) {
 	}
 
 	return int(userID), true
-}
\ No newline at end of file
+}
+
+// ExtrairSessao retorna a chave da sessão gravada no token de acesso
+func ExtrairSessao(token *jwt.Token) (string, bool) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", false
+	}
+
+	chave, ok := claims["sid"].(string)
+	return chave, ok && chave != ""
+}
+
+// GerarTokenAleatorio gera um token opaco com a quantidade de bytes aleatórios informada,
+// codificado para uso seguro em URLs
+func GerarTokenAleatorio(bytes int) (string, error) {
+	dados := make([]byte, bytes)
+	if _, err := rand.Read(dados); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(dados), nil
+}
+
+// HashToken calcula o SHA-256 de um token opaco; só o hash é gravado no banco
+func HashToken(token string) string {
+	soma := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(soma[:])
+}
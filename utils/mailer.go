@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"OficinaMecanica/configs"
+)
+
+// remetentePadrao é usado quando SMTP_FROM não está configurado
+const remetentePadrao = "Oficina Mecânica <nao-responda@oficina.local>"
+
+// Mensagem é um e-mail em texto simples
+type Mensagem struct {
+	Para    string
+	Assunto string
+	Corpo   string
+}
+
+// Mailer envia e-mails. Os serviços dependem só desta interface; a implementação é escolhida
+// pela configuração (NovoMailer) e pode ser trocada nos testes
+type Mailer interface {
+	Enviar(mensagem Mensagem) error
+}
+
+// NovoMailer retorna o envio por SMTP quando SMTP_HOST está configurado; sem ele, os e-mails
+// são gravados em arquivo, o que basta para o desenvolvimento local
+func NovoMailer(config configs.Config) Mailer {
+	remetente := strings.TrimSpace(config.SMTPFrom)
+	if remetente == "" {
+		remetente = remetentePadrao
+	}
+
+	if strings.TrimSpace(config.SMTPHost) == "" {
+		diretorio := config.MailOutboxDir
+		if diretorio == "" {
+			diretorio = configs.MailOutboxDirPadrao
+		}
+		return NewMailerArquivo(diretorio, remetente)
+	}
+
+	porta := config.SMTPPort
+	if porta == "" {
+		porta = "587"
+	}
+	return NewMailerSMTP(config.SMTPHost, porta, config.SMTPUser, config.SMTPPassword, remetente)
+}
+
+// MailerSMTP envia os e-mails por um servidor SMTP, com STARTTLS quando o servidor oferece
+type MailerSMTP struct {
+	host      string
+	porta     string
+	usuario   string
+	senha     string
+	remetente string
+}
+
+// NewMailerSMTP cria o envio por SMTP; sem usuário, o servidor é usado sem autenticação
+func NewMailerSMTP(host, porta, usuario, senha, remetente string) Mailer {
+	return &MailerSMTP{host: host, porta: porta, usuario: usuario, senha: senha, remetente: remetente}
+}
+
+// Enviar entrega a mensagem ao servidor SMTP
+func (m *MailerSMTP) Enviar(mensagem Mensagem) error {
+	de, dados, err := montarMensagem(m.remetente, mensagem)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.usuario != "" {
+		auth = smtp.PlainAuth("", m.usuario, m.senha, m.host)
+	}
+	if err := smtp.SendMail(net.JoinHostPort(m.host, m.porta), auth, de, []string{mensagem.Para}, dados); err != nil {
+		return errors.New("erro ao enviar o e-mail: " + err.Error())
+	}
+	return nil
+}
+
+// MailerArquivo grava cada e-mail como um arquivo .eml na pasta configurada, em vez de enviá-lo
+type MailerArquivo struct {
+	diretorio string
+	remetente string
+}
+
+// NewMailerArquivo cria o envio em arquivo, usado no desenvolvimento local e nos testes
+func NewMailerArquivo(diretorio, remetente string) Mailer {
+	return &MailerArquivo{diretorio: diretorio, remetente: remetente}
+}
+
+// Enviar grava a mensagem em um arquivo novo e registra o caminho no log
+func (m *MailerArquivo) Enviar(mensagem Mensagem) error {
+	_, dados, err := montarMensagem(m.remetente, mensagem)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.diretorio, 0o700); err != nil {
+		return errors.New("erro ao criar a pasta de e-mails: " + err.Error())
+	}
+	sufixo, err := GerarTokenAleatorio(6)
+	if err != nil {
+		return err
+	}
+	caminho := filepath.Join(m.diretorio, fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), sufixo))
+	// O conteúdo pode ter links de uso único, então só o dono do processo lê o arquivo
+	if err := os.WriteFile(caminho, dados, 0o600); err != nil {
+		return errors.New("erro ao gravar o e-mail: " + err.Error())
+	}
+
+	log.Printf("E-mail para %s gravado em %s", mensagem.Para, caminho)
+	return nil
+}
+
+// montarMensagem valida os endereços e monta o e-mail no formato RFC 5322, com o assunto e o corpo
+// codificados para aceitar acentos. Retorna também o endereço do remetente para o envelope SMTP
+func montarMensagem(remetente string, mensagem Mensagem) (string, []byte, error) {
+	de, err := mail.ParseAddress(remetente)
+	if err != nil {
+		return "", nil, errors.New("remetente de e-mail inválido: " + remetente)
+	}
+	// ParseAddress recusa quebras de linha, o que impede injetar cabeçalhos pelo destinatário
+	para, err := mail.ParseAddress(mensagem.Para)
+	if err != nil {
+		return "", nil, errors.New("destinatário de e-mail inválido")
+	}
+	assunto := strings.NewReplacer("\r", " ", "\n", " ").Replace(mensagem.Assunto)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", de.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", para.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", assunto))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	corpo := quotedprintable.NewWriter(&buf)
+	if _, err := corpo.Write([]byte(strings.ReplaceAll(mensagem.Corpo, "\n", "\r\n"))); err != nil {
+		return "", nil, err
+	}
+	if err := corpo.Close(); err != nil {
+		return "", nil, err
+	}
+	return de.Address, buf.Bytes(), nil
+}
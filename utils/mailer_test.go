@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"io"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"OficinaMecanica/configs"
+)
+
+func TestMailerArquivoGravaOEmail(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	diretorio := filepath.Join(t.TempDir(), "outbox")
+	mailer := NewMailerArquivo(diretorio, "Oficina <nao-responda@oficina.local>")
+	err := mailer.Enviar(Mensagem{Para: "ana@oficina.com", Assunto: "Redefinição de senha", Corpo: "Olá, Ana.\nUse o código."})
+	if err != nil {
+		t.Fatalf("erro ao gravar o e-mail: %v", err)
+	}
+
+	arquivos, _ := filepath.Glob(filepath.Join(diretorio, "*.eml"))
+	if len(arquivos) != 1 {
+		t.Fatalf("%d arquivos gravados, esperado 1", len(arquivos))
+	}
+	dados, _ := os.Open(arquivos[0])
+	defer dados.Close()
+	mensagem, err := mail.ReadMessage(dados)
+	if err != nil {
+		t.Fatalf("o arquivo deveria ser um e-mail válido: %v", err)
+	}
+
+	assunto, _ := new(mime.WordDecoder).DecodeHeader(mensagem.Header.Get("Subject"))
+	if assunto != "Redefinição de senha" || mensagem.Header.Get("To") != "<ana@oficina.com>" {
+		t.Errorf("cabeçalhos = assunto %q, para %q", assunto, mensagem.Header.Get("To"))
+	}
+	corpo, _ := io.ReadAll(quotedprintable.NewReader(mensagem.Body))
+	if string(corpo) != "Olá, Ana.\r\nUse o código." {
+		t.Errorf("corpo = %q", corpo)
+	}
+}
+
+func TestMailerRecusaDestinatarioInvalido(t *testing.T) {
+	mailer := NewMailerArquivo(t.TempDir(), "Oficina <nao-responda@oficina.local>")
+	for _, para := range []string{"", "sem-arroba", "ana@oficina.com\r\nBcc: todos@oficina.com"} {
+		if err := mailer.Enviar(Mensagem{Para: para, Assunto: "Teste"}); err == nil {
+			t.Errorf("o destinatário %q deveria ser recusado", para)
+		}
+	}
+}
+
+func TestNovoMailerEscolheAImplementacao(t *testing.T) {
+	if _, ok := NovoMailer(configs.Config{}).(*MailerArquivo); !ok {
+		t.Error("sem SMTP_HOST os e-mails deveriam ser gravados em arquivo")
+	}
+	smtp, ok := NovoMailer(configs.Config{SMTPHost: "smtp.oficina.com"}).(*MailerSMTP)
+	if !ok || smtp.porta != "587" || !strings.Contains(smtp.remetente, "@") {
+		t.Error("com SMTP_HOST o envio deveria usar o SMTP com a porta e o remetente padrão")
+	}
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"OficinaMecanica/configs"
+)
+
+// tamanhoMaximoSenha é o limite do bcrypt: bytes além disso seriam ignorados no hash
+const tamanhoMaximoSenha = 72
+
+// senhasComuns são recusadas em qualquer configuração, além das listadas em SENHA_LISTA_BLOQUEADA
+var senhasComuns = []string{
+	"123456", "1234567", "12345678", "123456789", "1234567890", "123123", "111111", "000000",
+	"abc123", "abcd1234", "qwerty", "qwerty123", "asdfgh", "password", "password1", "passw0rd",
+	"senha", "senha123", "senha1234", "minhasenha", "mudar123", "mudarsenha", "trocar123",
+	"admin", "admin123", "administrador", "teste", "teste123", "oficina", "oficina123",
+	"mecanica", "brasil", "flamengo", "corinthians", "palmeiras", "iloveyou", "letmein", "welcome",
+}
+
+// ErroPoliticaSenha indica uma senha recusada pela política, para que os controllers respondam 400
+type ErroPoliticaSenha struct {
+	Motivo string
+}
+
+func (e *ErroPoliticaSenha) Error() string {
+	return e.Motivo
+}
+
+// PoliticaSenha reúne as regras aplicadas a toda senha nova: cadastro, troca e redefinição
+type PoliticaSenha struct {
+	TamanhoMinimo  int
+	ClassesMinimas int // Entre minúsculas, maiúsculas, números e símbolos
+	Historico      int // Senhas anteriores que não podem ser reutilizadas; zero desliga a verificação
+	bloqueadas     map[string]bool
+}
+
+// NovaPoliticaSenha monta a política a partir da configuração, lendo a lista de senhas proibidas se informada
+func NovaPoliticaSenha(config configs.Config) (*PoliticaSenha, error) {
+	politica := &PoliticaSenha{
+		TamanhoMinimo:  config.TamanhoMinimoSenha(),
+		ClassesMinimas: config.ClassesMinimasSenha(),
+		Historico:      config.HistoricoSenhas(),
+		bloqueadas:     make(map[string]bool, len(senhasComuns)),
+	}
+	politica.Bloquear(senhasComuns...)
+
+	if config.SenhaListaBloqueada == "" {
+		return politica, nil
+	}
+	arquivo, err := os.Open(config.SenhaListaBloqueada)
+	if err != nil {
+		return nil, errors.New("erro ao abrir a lista de senhas bloqueadas: " + err.Error())
+	}
+	defer arquivo.Close()
+
+	leitor := bufio.NewScanner(arquivo)
+	for leitor.Scan() {
+		politica.Bloquear(leitor.Text())
+	}
+	if err := leitor.Err(); err != nil {
+		return nil, errors.New("erro ao ler a lista de senhas bloqueadas: " + err.Error())
+	}
+	return politica, nil
+}
+
+// Bloquear acrescenta senhas à lista de proibidas, sem diferenciar maiúsculas
+func (p *PoliticaSenha) Bloquear(senhas ...string) {
+	if p.bloqueadas == nil {
+		p.bloqueadas = make(map[string]bool)
+	}
+	for _, senha := range senhas {
+		if senha = strings.ToLower(strings.TrimSpace(senha)); senha != "" {
+			p.bloqueadas[senha] = true
+		}
+	}
+}
+
+// Validar confere o tamanho, a combinação de caracteres e a lista de senhas proibidas.
+// A senha também não pode conter o e-mail do usuário (a parte antes do @)
+func (p *PoliticaSenha) Validar(senha, email string) error {
+	if utf8.RuneCountInString(senha) < p.TamanhoMinimo {
+		return &ErroPoliticaSenha{fmt.Sprintf("a senha deve ter pelo menos %d caracteres", p.TamanhoMinimo)}
+	}
+	if len(senha) > tamanhoMaximoSenha {
+		return &ErroPoliticaSenha{fmt.Sprintf("a senha deve ter no máximo %d bytes", tamanhoMaximoSenha)}
+	}
+
+	var minuscula, maiuscula, numero, simbolo bool
+	for _, r := range senha {
+		switch {
+		case unicode.IsLower(r):
+			minuscula = true
+		case unicode.IsUpper(r):
+			maiuscula = true
+		case unicode.IsDigit(r):
+			numero = true
+		default:
+			simbolo = true
+		}
+	}
+	classes := 0
+	for _, presente := range []bool{minuscula, maiuscula, numero, simbolo} {
+		if presente {
+			classes++
+		}
+	}
+	if classes < p.ClassesMinimas {
+		return &ErroPoliticaSenha{fmt.Sprintf("a senha deve combinar pelo menos %d tipos de caractere entre minúsculas, maiúsculas, números e símbolos", p.ClassesMinimas)}
+	}
+
+	normalizada := strings.ToLower(senha)
+	if p.bloqueadas[normalizada] {
+		return &ErroPoliticaSenha{"a senha é muito comum; escolha outra"}
+	}
+	if usuario, _, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@"); ok && len(usuario) >= 3 && strings.Contains(normalizada, usuario) {
+		return &ErroPoliticaSenha{"a senha não pode conter o e-mail"}
+	}
+	return nil
+}
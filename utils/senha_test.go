@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"OficinaMecanica/configs"
+)
+
+func TestPoliticaSenhaValidar(t *testing.T) {
+	politica, err := NovaPoliticaSenha(configs.Config{})
+	if err != nil {
+		t.Fatalf("erro ao montar a política padrão: %v", err)
+	}
+
+	casos := []struct {
+		nome   string
+		senha  string
+		email  string
+		valida bool
+	}{
+		{"letras e números", "motor2024x", "ana@oficina.com", true},
+		{"maiúsculas e símbolos", "Embreagem!", "ana@oficina.com", true},
+		{"acentos contam como letras", "CâmbioÓleo", "ana@oficina.com", true},
+		{"curta demais", "ab12", "ana@oficina.com", false},
+		{"só minúsculas", "embreagem", "ana@oficina.com", false},
+		{"só números", "2024202420", "ana@oficina.com", false},
+		{"senha comum", "senha1234", "ana@oficina.com", false},
+		{"senha comum em maiúsculas", "QWERTY123", "ana@oficina.com", false},
+		{"contém o e-mail", "Mariana2024", "mariana@oficina.com", false},
+		{"e-mail curto demais para conferir", "Ana2024xyz", "an@oficina.com", true},
+		{"acima de 72 bytes", strings.Repeat("a1", 37), "ana@oficina.com", false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := politica.Validar(caso.senha, caso.email)
+			if (err == nil) != caso.valida {
+				t.Fatalf("Validar(%q) erro = %v, válida esperado = %v", caso.senha, err, caso.valida)
+			}
+			var erroSenha *ErroPoliticaSenha
+			if err != nil && !errors.As(err, &erroSenha) {
+				t.Errorf("Validar(%q) deveria retornar ErroPoliticaSenha, retornou %T", caso.senha, err)
+			}
+		})
+	}
+}
+
+func TestPoliticaSenhaComListaBloqueada(t *testing.T) {
+	arquivo := filepath.Join(t.TempDir(), "bloqueadas.txt")
+	if err := os.WriteFile(arquivo, []byte("Oficina2024\n\n  Retifica-99  \n"), 0o600); err != nil {
+		t.Fatalf("erro ao gravar a lista: %v", err)
+	}
+
+	politica, err := NovaPoliticaSenha(configs.Config{SenhaTamanhoMinimo: 6, SenhaClassesMinimas: 3, SenhaListaBloqueada: arquivo})
+	if err != nil {
+		t.Fatalf("erro ao montar a política: %v", err)
+	}
+	if politica.TamanhoMinimo != 6 || politica.ClassesMinimas != 3 {
+		t.Errorf("política %+v, esperado tamanho 6 e 3 classes", politica)
+	}
+	for _, senha := range []string{"oFICINA2024", "retifica-99"} {
+		if err := politica.Validar(senha, "ana@oficina.com"); err == nil {
+			t.Errorf("%q está na lista e deveria ser recusada sem diferenciar maiúsculas", senha)
+		}
+	}
+	if err := politica.Validar("Retifica990", "ana@oficina.com"); err != nil {
+		t.Errorf("uma senha fora da lista deveria ser aceita: %v", err)
+	}
+
+	if _, err := NovaPoliticaSenha(configs.Config{SenhaListaBloqueada: filepath.Join(t.TempDir(), "inexistente.txt")}); err == nil {
+		t.Error("uma lista inexistente deveria impedir a montagem da política")
+	}
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	placaAntiga   = regexp.MustCompile(`^[A-Z]{3}[0-9]{4}$`)           // ABC1234
+	placaMercosul = regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z][0-9]{2}$`) // ABC1D23
+	formatoChassi = regexp.MustCompile(`^[A-HJ-NPR-Z0-9]{17}$`)        // VIN sem I, O e Q
+)
+
+// valoresChassi e pesosChassi seguem a tabela de transliteração da ISO 3779 para o dígito verificador do VIN
+var (
+	valoresChassi = map[rune]int{
+		'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+		'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+		'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+	}
+	pesosChassi = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+// NormalizarPlaca deixa a placa em maiúsculas e sem hífen, espaços ou pontos ("abc-1234" vira "ABC1234")
+func NormalizarPlaca(placa string) string {
+	return apenasAlfanumericos(placa)
+}
+
+// ValidarPlaca normaliza a placa e confere se ela está no padrão antigo (ABC1234) ou no Mercosul (ABC1D23)
+func ValidarPlaca(placa string) (string, error) {
+	normalizada := NormalizarPlaca(placa)
+	if normalizada == "" {
+		return "", errors.New("placa do veículo é obrigatória")
+	}
+	if !placaAntiga.MatchString(normalizada) && !placaMercosul.MatchString(normalizada) {
+		return "", errors.New("placa inválida: use o padrão antigo (ABC1234) ou o Mercosul (ABC1D23)")
+	}
+	return normalizada, nil
+}
+
+// PlacaEquivalente retorna a outra grafia da mesma placa na conversão para o Mercosul,
+// em que o segundo dígito vira letra (0=A, 1=B, ..., 9=J): ABC1234 equivale a ABC1C34.
+// Retorna vazio quando a placa não está em nenhum dos dois padrões
+func PlacaEquivalente(placa string) string {
+	normalizada := NormalizarPlaca(placa)
+	letras := []rune(normalizada)
+
+	switch {
+	case placaAntiga.MatchString(normalizada):
+		letras[4] = 'A' + (letras[4] - '0')
+	case placaMercosul.MatchString(normalizada) && letras[4] <= 'J':
+		letras[4] = '0' + (letras[4] - 'A')
+	default:
+		return ""
+	}
+	return string(letras)
+}
+
+// NormalizarChassi deixa o chassi em maiúsculas e sem espaços ou separadores
+func NormalizarChassi(chassi string) string {
+	return apenasAlfanumericos(chassi)
+}
+
+// ValidarChassi normaliza o chassi (VIN) e confere o formato de 17 caracteres e o dígito verificador da 9ª posição
+func ValidarChassi(chassi string) (string, error) {
+	normalizado := NormalizarChassi(chassi)
+	if len(normalizado) != 17 {
+		return "", errors.New("chassi inválido: o número deve ter 17 caracteres")
+	}
+	if !formatoChassi.MatchString(normalizado) {
+		return "", errors.New("chassi inválido: as letras I, O e Q não são usadas")
+	}
+
+	soma := 0
+	for i, c := range normalizado {
+		valor, ok := valoresChassi[c]
+		if !ok {
+			valor = int(c - '0')
+		}
+		soma += valor * pesosChassi[i]
+	}
+
+	esperado := byte('0' + soma%11)
+	if soma%11 == 10 {
+		esperado = 'X'
+	}
+	if normalizado[8] != esperado {
+		return "", errors.New("chassi inválido: dígito verificador não confere")
+	}
+	return normalizado, nil
+}
+
+// apenasAlfanumericos remove tudo que não for letra ou número e converte para maiúsculas
+func apenasAlfanumericos(valor string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, valor)
+}
@@ -0,0 +1,76 @@
+package utils
+
+import "testing"
+
+func TestValidarPlaca(t *testing.T) {
+	casos := []struct {
+		nome        string
+		placa       string
+		normalizada string
+		valida      bool
+	}{
+		{"padrão antigo", "ABC1234", "ABC1234", true},
+		{"padrão antigo com hífen e minúsculas", "abc-1234", "ABC1234", true},
+		{"Mercosul", "BRA2E19", "BRA2E19", true},
+		{"Mercosul com espaços", " bra 2e19 ", "BRA2E19", true},
+		{"letra no lugar de número", "ABCD234", "", false},
+		{"Mercosul com letra na posição errada", "BRA21E9", "", false},
+		{"curta demais", "AB1234", "", false},
+		{"vazia", " - ", "", false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			normalizada, err := ValidarPlaca(caso.placa)
+			if (err == nil) != caso.valida {
+				t.Fatalf("ValidarPlaca(%q) erro = %v, válida esperada = %v", caso.placa, err, caso.valida)
+			}
+			if normalizada != caso.normalizada {
+				t.Errorf("ValidarPlaca(%q) = %q, esperado %q", caso.placa, normalizada, caso.normalizada)
+			}
+		})
+	}
+}
+
+func TestPlacaEquivalente(t *testing.T) {
+	casos := map[string]string{
+		"ABC1234":  "ABC1C34",
+		"abc-1c34": "ABC1234",
+		"ABC1034":  "ABC1A34",
+		"ABC1K34":  "", // Letras depois de J não vêm da conversão de uma placa antiga
+		"XYZ":      "",
+	}
+	for placa, esperada := range casos {
+		if got := PlacaEquivalente(placa); got != esperada {
+			t.Errorf("PlacaEquivalente(%q) = %q, esperado %q", placa, got, esperada)
+		}
+	}
+}
+
+func TestValidarChassi(t *testing.T) {
+	casos := []struct {
+		nome        string
+		chassi      string
+		normalizado string
+		valido      bool
+	}{
+		{"dígito verificador X", "1M8GDM9AXKP042788", "1M8GDM9AXKP042788", true},
+		{"minúsculas e separadores", "1m8gdm9a-xkp 042788", "1M8GDM9AXKP042788", true},
+		{"dígito verificador numérico", "11111111111111111", "11111111111111111", true},
+		{"dígito verificador errado", "1M8GDM9A1KP042788", "", false},
+		{"letra O não permitida", "1M8GDM9AXKP0O2788", "", false},
+		{"tamanho errado", "1M8GDM9AXKP04278", "", false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			normalizado, err := ValidarChassi(caso.chassi)
+			if (err == nil) != caso.valido {
+				t.Fatalf("ValidarChassi(%q) erro = %v, válido esperado = %v", caso.chassi, err, caso.valido)
+			}
+			if normalizado != caso.normalizado {
+				t.Errorf("ValidarChassi(%q) = %q, esperado %q", caso.chassi, normalizado, caso.normalizado)
+			}
+		})
+	}
+}